@@ -0,0 +1,41 @@
+/*
+Copyright 2025 James.Liu.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+// Standard condition types maintained on RedisInstance, RedisMasterReplica,
+// RedisSentinel and RedisCluster
+const (
+	// ConditionReady is True when every Redis pod runs the latest spec and is ready
+	ConditionReady = "Ready"
+	// ConditionAvailable is True when enough pods are ready to serve clients
+	ConditionAvailable = "Available"
+	// ConditionProgressing is True while pods are created, rolled out or scaled
+	ConditionProgressing = "Progressing"
+	// ConditionDegraded is True when resources are missing, an operation failed
+	// or pods on the latest spec are not ready
+	ConditionDegraded = "Degraded"
+	// ConditionConfigApplied is True when the rendered configuration is valid
+	// and every pod runs with it
+	ConditionConfigApplied = "ConfigApplied"
+	// ConditionStorageReady is True when every PersistentVolumeClaim is bound
+	// and no volume expansion is pending
+	ConditionStorageReady = "StorageReady"
+)
+
+// ConditionBindingReady is maintained on the Redis aggregated view and is True
+// when the connection secret carries everything applications need to connect
+const ConditionBindingReady = "BindingReady"
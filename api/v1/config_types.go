@@ -20,29 +20,92 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
-// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
-
 // ConfigSpec defines the desired state of Config
 type ConfigSpec struct {
-	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
-	// The following markers will use OpenAPI v3 schema to validate the value
-	// More info: https://book.kubebuilder.io/reference/markers/crd-validation.html
+	// redis.conf directives shared by every workload referencing this profile,
+	// directives set inline on a workload take precedence
+	// +optional
+	Redis map[string]string `json:"redis,omitempty"`
+
+	// Sentinel directives, only used by RedisSentinel
+	// +optional
+	Sentinel map[string]string `json:"sentinel,omitempty"`
+
+	// Cluster directives, only used by RedisCluster and applied on top of the redis section
+	// +optional
+	Cluster map[string]string `json:"cluster,omitempty"`
+}
+
+// ConfigConsumer describes a workload referencing the profile
+type ConfigConsumer struct {
+	// Kind of the workload
+	Kind string `json:"kind"`
+
+	// Name of the workload
+	Name string `json:"name"`
 
-	// foo is an example field of Config. Edit config_types.go to remove/update
+	// Revision of the profile currently applied by the workload
 	// +optional
-	Foo *string `json:"foo,omitempty"`
+	AppliedRevision string `json:"appliedRevision,omitempty"`
+
+	// Rollout state of the current revision on the workload
+	State ConfigRolloutState `json:"state"`
 }
 
 // ConfigStatus defines the observed state of Config.
 type ConfigStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	// Conditions represent the latest available observations of the resource's current state
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Ready indicates whether every consumer runs the current revision
+	Ready string `json:"ready,omitempty"`
+
+	// Status represents the current phase of the profile
+	Status string `json:"status,omitempty"`
+
+	// LastConditionMessage contains the message from the last condition
+	LastConditionMessage string `json:"lastConditionMessage,omitempty"`
+
+	// Revision of the current directives, changes whenever a directive changes
+	Revision string `json:"revision,omitempty"`
+
+	// Generation of the spec the status was computed from
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Workloads referencing this profile
+	// +optional
+	Consumers []ConfigConsumer `json:"consumers,omitempty"`
 }
 
+// ConfigPhase represents the phase of Config
+type ConfigPhase string
+
+const (
+	ConfigPhaseUnused     ConfigPhase = "Unused"
+	ConfigPhaseRollingOut ConfigPhase = "RollingOut"
+	ConfigPhaseApplied    ConfigPhase = "Applied"
+	ConfigPhaseInvalid    ConfigPhase = "Invalid"
+)
+
+// ConfigRolloutState represents the rollout state of a profile revision on one workload
+type ConfigRolloutState string
+
+const (
+	// ConfigRolloutPending 工作负载尚未应用当前版本
+	ConfigRolloutPending ConfigRolloutState = "Pending"
+	// ConfigRolloutRollingOut 工作负载已应用当前版本，Pod 仍在滚动更新
+	ConfigRolloutRollingOut ConfigRolloutState = "RollingOut"
+	// ConfigRolloutApplied 工作负载已应用当前版本并就绪
+	ConfigRolloutApplied ConfigRolloutState = "Applied"
+)
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=rcfg
+// +kubebuilder:printcolumn:name="STATUS",type=string,JSONPath=`.status.status`,description="Status of the resource"
+// +kubebuilder:printcolumn:name="REVISION",type=string,JSONPath=`.status.revision`,description="Revision of the directives"
+// +kubebuilder:printcolumn:name="AGE",type=date,JSONPath=`.metadata.creationTimestamp`,description="Age of the resource"
+// +kubebuilder:printcolumn:name="MESSAGE",type=string,JSONPath=`.status.lastConditionMessage`,description="Message of the resource"
 
 // Config is the Schema for the configs API
 type Config struct {
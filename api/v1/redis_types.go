@@ -17,6 +17,7 @@ limitations under the License.
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -55,6 +56,11 @@ type RedisStatus struct {
 
 	// Conditions from the underlying resource
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Binding references the Secret holding connection information in the servicebinding.io format,
+	// which makes the view a Provisioned Service
+	// +optional
+	Binding *corev1.LocalObjectReference `json:"binding,omitempty"`
 }
 
 // RedisResourceStatus contains type-specific status information
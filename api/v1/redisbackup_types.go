@@ -0,0 +1,233 @@
+/*
+Copyright 2025 James.Liu.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RedisBackupSpec defines the desired state of RedisBackup
+type RedisBackupSpec struct {
+	// Redis workload to back up
+	TargetRef RedisWorkloadRef `json:"targetRef"`
+
+	// Where the RDB files are copied to
+	Destination BackupDestination `json:"destination"`
+}
+
+// BackupDestination defines where backup files are stored, exactly one of pvc or s3 must be set
+// +kubebuilder:validation:XValidation:rule="has(self.pvc) != has(self.s3)",message="exactly one of pvc or s3 must be set"
+type BackupDestination struct {
+	// Copy the RDB files to a PersistentVolumeClaim
+	// +optional
+	PVC *PVCBackupDestination `json:"pvc,omitempty"`
+
+	// Upload the RDB files to an S3-compatible object store
+	// +optional
+	S3 *S3BackupDestination `json:"s3,omitempty"`
+}
+
+// PVCBackupDestination defines a PersistentVolumeClaim backup destination
+type PVCBackupDestination struct {
+	// Name of the PersistentVolumeClaim in the backup namespace
+	// +kubebuilder:validation:MinLength=1
+	ClaimName string `json:"claimName"`
+
+	// Directory inside the volume
+	// +optional
+	Path string `json:"path,omitempty"`
+}
+
+// S3BackupDestination defines an S3-compatible backup destination
+type S3BackupDestination struct {
+	ObjectStoreSpec `json:",inline"`
+
+	// Bucket name
+	// +kubebuilder:validation:MinLength=1
+	Bucket string `json:"bucket"`
+
+	// Key prefix inside the bucket
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+}
+
+// ObjectStoreSpec defines how to connect to an S3-compatible object store
+type ObjectStoreSpec struct {
+	// Endpoint URL including the scheme, e.g. https://s3.amazonaws.com or http://minio.minio:9000
+	// +kubebuilder:validation:Pattern=`^https?://`
+	Endpoint string `json:"endpoint"`
+
+	// Secret holding accessKeyId and secretAccessKey
+	CredentialsSecret corev1.LocalObjectReference `json:"credentialsSecret"`
+
+	// Skip TLS certificate verification of the endpoint
+	// +optional
+	Insecure bool `json:"insecure,omitempty"`
+
+	// Image containing the MinIO client
+	// +kubebuilder:default="minio/mc:RELEASE.2024-11-21T17-21-54Z"
+	// +optional
+	Image string `json:"image,omitempty"`
+}
+
+// RestoreSource defines where the initial data of a new workload is restored from
+// +kubebuilder:validation:XValidation:rule="has(self.backup) != has(self.url)",message="exactly one of backup or url must be set"
+// +kubebuilder:validation:XValidation:rule="!has(self.url) || has(self.s3)",message="s3 is required when url is set"
+type RestoreSource struct {
+	// Name of a completed single-shard RedisBackup in the same namespace
+	// +optional
+	Backup string `json:"backup,omitempty"`
+
+	// Object store URL of an RDB file, e.g. s3://bucket/path/dump.rdb
+	// +kubebuilder:validation:Pattern=`^s3://[^/]+/.+`
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// Object store used to download url
+	// +optional
+	S3 *ObjectStoreSpec `json:"s3,omitempty"`
+}
+
+// ClusterRestoreSource defines the backup a new RedisCluster is restored from
+type ClusterRestoreSource struct {
+	// Name of a completed RedisBackup of a RedisCluster in the same namespace,
+	// the slot ranges recorded for each shard become the slot layout of the new cluster
+	// +kubebuilder:validation:MinLength=1
+	Backup string `json:"backup"`
+
+	// Allow restoring onto a different number of masters than the backup has shards,
+	// slots are rebalanced evenly across the masters after the data is loaded
+	// +optional
+	AllowReshard bool `json:"allowReshard,omitempty"`
+}
+
+// RedisBackupStatus defines the observed state of RedisBackup.
+type RedisBackupStatus struct {
+	// Conditions represent the latest available observations of the resource's current state
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Ready indicates whether the backup has completed
+	Ready string `json:"ready,omitempty"`
+
+	// Status represents the current phase of the backup
+	Status string `json:"status,omitempty"`
+
+	// LastConditionMessage contains the message from the last condition
+	LastConditionMessage string `json:"lastConditionMessage,omitempty"`
+
+	// Time the backup started
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// Time the backup completed
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Duration of the backup
+	Duration string `json:"duration,omitempty"`
+
+	// Total size of all RDB files in bytes
+	Size int64 `json:"size,omitempty"`
+
+	// SHA-256 checksum of the RDB file, only set for single-shard workloads
+	Checksum string `json:"checksum,omitempty"`
+
+	// RDB file of every shard
+	// +optional
+	Shards []BackupShardStatus `json:"shards,omitempty"`
+
+	// Redis image of the backed up workload, reused by the Job that deletes the backup files
+	// +optional
+	Image string `json:"image,omitempty"`
+}
+
+// BackupShardStatus defines the backup of a single shard
+type BackupShardStatus struct {
+	// Shard index, 0 for non-cluster workloads
+	Index int32 `json:"index"`
+
+	// Pod the RDB file was taken from
+	Pod string `json:"pod"`
+
+	// Hash slot ranges served by the shard, only set for RedisCluster
+	// +optional
+	Slots string `json:"slots,omitempty"`
+
+	// Location of the RDB file, e.g. s3://bucket/prefix/file.rdb or pvc://claim/path/file.rdb
+	Location string `json:"location,omitempty"`
+
+	// Size of the RDB file in bytes
+	Size int64 `json:"size,omitempty"`
+
+	// SHA-256 checksum of the RDB file
+	Checksum string `json:"checksum,omitempty"`
+
+	// Name of the Job that copies the RDB file
+	Job string `json:"job,omitempty"`
+}
+
+// RedisBackupPhase represents the phase of RedisBackup
+type RedisBackupPhase string
+
+const (
+	RedisBackupPhasePending      RedisBackupPhase = "Pending"
+	RedisBackupPhaseSnapshotting RedisBackupPhase = "Snapshotting"
+	RedisBackupPhaseUploading    RedisBackupPhase = "Uploading"
+	RedisBackupPhaseCompleted    RedisBackupPhase = "Completed"
+	RedisBackupPhaseFailed       RedisBackupPhase = "Failed"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=rb
+// +kubebuilder:printcolumn:name="STATUS",type=string,JSONPath=`.status.status`,description="Status of the resource"
+// +kubebuilder:printcolumn:name="TARGET",type=string,JSONPath=`.spec.targetRef.name`,description="Target workload"
+// +kubebuilder:printcolumn:name="SIZE",type=integer,JSONPath=`.status.size`,description="Size in bytes"
+// +kubebuilder:printcolumn:name="DURATION",type=string,JSONPath=`.status.duration`,description="Duration of the backup"
+// +kubebuilder:printcolumn:name="AGE",type=date,JSONPath=`.metadata.creationTimestamp`,description="Age of the resource"
+// +kubebuilder:printcolumn:name="MESSAGE",type=string,JSONPath=`.status.lastConditionMessage`,description="Message of the resource"
+
+// RedisBackup is the Schema for the redisbackups API
+type RedisBackup struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec defines the desired state of RedisBackup
+	// +required
+	Spec RedisBackupSpec `json:"spec"`
+
+	// status defines the observed state of RedisBackup
+	// +optional
+	Status RedisBackupStatus `json:"status,omitempty,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// RedisBackupList contains a list of RedisBackup
+type RedisBackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RedisBackup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RedisBackup{}, &RedisBackupList{})
+}
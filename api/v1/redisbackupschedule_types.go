@@ -0,0 +1,162 @@
+/*
+Copyright 2025 James.Liu.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RedisBackupScheduleSpec defines the desired state of RedisBackupSchedule
+type RedisBackupScheduleSpec struct {
+	// Cron expression in UTC, e.g. "0 2 * * *" or "@daily"
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+
+	// Suspend stops creating new backups, existing backups are kept
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// Redis workload to back up
+	TargetRef RedisWorkloadRef `json:"targetRef"`
+
+	// Where the RDB files are copied to
+	Destination BackupDestination `json:"destination"`
+
+	// Which completed backups to keep, older backups and their files are deleted
+	// +kubebuilder:default={}
+	// +optional
+	Retention BackupRetention `json:"retention,omitempty"`
+}
+
+// BackupRetention defines how many backups are kept, a backup is kept if any rule selects it
+type BackupRetention struct {
+	// Keep the most recent N completed backups, defaults to 7
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=7
+	// +optional
+	KeepLast *int32 `json:"keepLast,omitempty"`
+
+	// Keep the newest completed backup of each of the last N days
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	KeepDaily int32 `json:"keepDaily,omitempty"`
+
+	// Keep the newest completed backup of each of the last N ISO weeks
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	KeepWeekly int32 `json:"keepWeekly,omitempty"`
+}
+
+// RedisBackupScheduleStatus defines the observed state of RedisBackupSchedule.
+type RedisBackupScheduleStatus struct {
+	// Conditions represent the latest available observations of the resource's current state
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Ready indicates whether the last backup succeeded
+	Ready string `json:"ready,omitempty"`
+
+	// Status represents the current phase of the schedule
+	Status string `json:"status,omitempty"`
+
+	// LastConditionMessage contains the message from the last condition
+	LastConditionMessage string `json:"lastConditionMessage,omitempty"`
+
+	// Last time a backup was created
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+
+	// Next time a backup will be created
+	// +optional
+	NextScheduleTime *metav1.Time `json:"nextScheduleTime,omitempty"`
+
+	// Backup that is currently running
+	// +optional
+	ActiveBackup string `json:"activeBackup,omitempty"`
+
+	// Most recent completed backup
+	// +optional
+	LastSuccessfulBackup string `json:"lastSuccessfulBackup,omitempty"`
+
+	// Completion time of the most recent completed backup
+	// +optional
+	LastSuccessTime *metav1.Time `json:"lastSuccessTime,omitempty"`
+
+	// Most recent failed backup
+	// +optional
+	LastFailedBackup string `json:"lastFailedBackup,omitempty"`
+
+	// Time the most recent failed backup was created
+	// +optional
+	LastFailureTime *metav1.Time `json:"lastFailureTime,omitempty"`
+}
+
+// RedisBackupSchedulePhase represents the phase of RedisBackupSchedule
+type RedisBackupSchedulePhase string
+
+const (
+	RedisBackupSchedulePhaseActive    RedisBackupSchedulePhase = "Active"
+	RedisBackupSchedulePhaseSuspended RedisBackupSchedulePhase = "Suspended"
+	RedisBackupSchedulePhaseInvalid   RedisBackupSchedulePhase = "Invalid"
+)
+
+const (
+	// RedisBackupScheduleLabel 记录由哪个 RedisBackupSchedule 创建了 RedisBackup
+	RedisBackupScheduleLabel = "redis.github.com/backup-schedule"
+
+	// RedisBackupArtifactsFinalizer 删除 RedisBackup 时同时删除备份文件
+	RedisBackupArtifactsFinalizer = "redis.github.com/backup-artifacts-finalizer"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=rbs
+// +kubebuilder:printcolumn:name="SCHEDULE",type=string,JSONPath=`.spec.schedule`,description="Cron schedule"
+// +kubebuilder:printcolumn:name="STATUS",type=string,JSONPath=`.status.status`,description="Status of the resource"
+// +kubebuilder:printcolumn:name="LAST SUCCESS",type=date,JSONPath=`.status.lastSuccessTime`,description="Completion time of the last successful backup"
+// +kubebuilder:printcolumn:name="NEXT",type=date,JSONPath=`.status.nextScheduleTime`,description="Next scheduled backup"
+// +kubebuilder:printcolumn:name="AGE",type=date,JSONPath=`.metadata.creationTimestamp`,description="Age of the resource"
+// +kubebuilder:printcolumn:name="MESSAGE",type=string,JSONPath=`.status.lastConditionMessage`,description="Message of the resource"
+
+// RedisBackupSchedule is the Schema for the redisbackupschedules API
+type RedisBackupSchedule struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec defines the desired state of RedisBackupSchedule
+	// +required
+	Spec RedisBackupScheduleSpec `json:"spec"`
+
+	// status defines the observed state of RedisBackupSchedule
+	// +optional
+	Status RedisBackupScheduleStatus `json:"status,omitempty,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// RedisBackupScheduleList contains a list of RedisBackupSchedule
+type RedisBackupScheduleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RedisBackupSchedule `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RedisBackupSchedule{}, &RedisBackupScheduleList{})
+}
@@ -25,6 +25,7 @@ import (
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
 // RedisClusterSpec defines the desired state of RedisCluster
+// +kubebuilder:validation:XValidation:rule="has(self.restoreFrom) == has(oldSelf.restoreFrom)",message="restoreFrom can only be set at creation"
 type RedisClusterSpec struct {
 	// Redis image to use
 	Image string `json:"image"`
@@ -55,6 +56,15 @@ type RedisClusterSpec struct {
 	// +optional
 	Security SecuritySpec `json:"security,omitempty"`
 
+	// Monitoring configuration: redis_exporter sidecar and generated alerting rules
+	// +optional
+	Monitoring *MonitoringSpec `json:"monitoring,omitempty"`
+
+	// Config profile in the same namespace providing shared directives,
+	// directives set inline on this resource take precedence
+	// +optional
+	ConfigRef *corev1.LocalObjectReference `json:"configRef,omitempty"`
+
 	// Node selector for pod assignment
 	// +optional
 	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
@@ -66,6 +76,11 @@ type RedisClusterSpec struct {
 	// Affinity for pod assignment
 	// +optional
 	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
+	// Restore the initial data from a RedisBackup when the cluster is created
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="restoreFrom is immutable"
+	// +optional
+	RestoreFrom *ClusterRestoreSource `json:"restoreFrom,omitempty"`
 }
 
 // ClusterConfig defines Redis cluster-specific configuration
@@ -113,6 +128,14 @@ type RedisClusterStatus struct {
 
 	// Service name for the cluster
 	ServiceName string `json:"serviceName,omitempty"`
+
+	// Progress of the last password rotation
+	// +optional
+	PasswordRotation *PasswordRotationStatus `json:"passwordRotation,omitempty"`
+
+	// Revision of the referenced Config profile applied to the workload
+	// +optional
+	ConfigRevision string `json:"configRevision,omitempty"`
 }
 
 // ClusterStatus defines the status of the Redis cluster
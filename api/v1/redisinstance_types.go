@@ -25,6 +25,7 @@ import (
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
 // RedisInstanceSpec defines the desired state of RedisInstance
+// +kubebuilder:validation:XValidation:rule="has(self.restoreFrom) == has(oldSelf.restoreFrom)",message="restoreFrom can only be set at creation"
 type RedisInstanceSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
@@ -44,6 +45,24 @@ type RedisInstanceSpec struct {
 	Storage StorageSpec `json:"storage,omitempty"`
 
 	Config map[string]string `json:"config,omitempty"`
+
+	// Security configuration
+	// +optional
+	Security SecuritySpec `json:"security,omitempty"`
+
+	// Monitoring configuration: redis_exporter sidecar and generated alerting rules
+	// +optional
+	Monitoring *MonitoringSpec `json:"monitoring,omitempty"`
+
+	// Config profile in the same namespace providing shared directives,
+	// directives set inline on this resource take precedence
+	// +optional
+	ConfigRef *corev1.LocalObjectReference `json:"configRef,omitempty"`
+
+	// Restore the data from a backup when the workload is created
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="restoreFrom is immutable"
+	// +optional
+	RestoreFrom *RestoreSource `json:"restoreFrom,omitempty"`
 }
 
 type StorageSpec struct {
@@ -65,6 +84,13 @@ type RedisInstanceStatus struct {
 	Ready                string             `json:"ready,omitempty"`
 	Status               string             `json:"status,omitempty"`
 	LastConditionMessage string             `json:"lastConditionMessage,omitempty"`
+	// 最近一次密码轮换的进度
+	// +optional
+	PasswordRotation *PasswordRotationStatus `json:"passwordRotation,omitempty"`
+
+	// Revision of the referenced Config profile applied to the workload
+	// +optional
+	ConfigRevision string `json:"configRevision,omitempty"`
 }
 
 type RedisPhase string
@@ -25,6 +25,7 @@ import (
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
 // RedisMasterReplicaSpec defines the desired state of RedisMasterReplica
+// +kubebuilder:validation:XValidation:rule="has(self.restoreFrom) == has(oldSelf.restoreFrom)",message="restoreFrom can only be set at creation"
 type RedisMasterReplicaSpec struct {
 	// Redis image to use
 	Image string `json:"image"`
@@ -48,6 +49,20 @@ type RedisMasterReplicaSpec struct {
 	// Security configuration
 	// +optional
 	Security SecuritySpec `json:"security,omitempty"`
+
+	// Monitoring configuration: redis_exporter sidecar and generated alerting rules
+	// +optional
+	Monitoring *MonitoringSpec `json:"monitoring,omitempty"`
+
+	// Config profile in the same namespace providing shared directives,
+	// directives set inline on this resource take precedence
+	// +optional
+	ConfigRef *corev1.LocalObjectReference `json:"configRef,omitempty"`
+
+	// Restore the data from a backup when the workload is created
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="restoreFrom is immutable"
+	// +optional
+	RestoreFrom *RestoreSource `json:"restoreFrom,omitempty"`
 }
 
 // MasterSpec defines the master node configuration
@@ -100,16 +115,192 @@ type SecuritySpec struct {
 	TLS *TLSSpec `json:"tls,omitempty"`
 }
 
+// +kubebuilder:validation:XValidation:rule="!self.enabled || (has(self.secretName) && self.secretName != '')",message="secretName is required when TLS is enabled"
+
 // TLSSpec defines TLS configuration
 type TLSSpec struct {
 	// Enable TLS
 	Enabled bool `json:"enabled"`
 
-	// Secret containing TLS certificates
+	// Secret containing TLS certificates (tls.crt, tls.key and ca.crt)
 	// +optional
 	SecretName string `json:"secretName,omitempty"`
+
+	// Require clients to present a certificate signed by ca.crt
+	// +optional
+	ClientAuth bool `json:"clientAuth,omitempty"`
+
+	// Additional plaintext port, disabled when unset
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	PlaintextPort int32 `json:"plaintextPort,omitempty"`
+}
+
+// MonitoringSpec defines a redis_exporter sidecar compatible with the community exporter metric names
+// and the alerting rules generated for the workload
+type MonitoringSpec struct {
+	// Inject the exporter sidecar into every Redis pod
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Exporter image
+	// +kubebuilder:default="oliver006/redis_exporter:v1.62.0"
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Port the exporter serves metrics on
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +kubebuilder:default=9121
+	// +optional
+	Port int32 `json:"port,omitempty"`
+
+	// Resources of the exporter container
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// ServiceMonitor created when the Prometheus Operator CRDs are installed
+	// +optional
+	ServiceMonitor ServiceMonitorSpec `json:"serviceMonitor,omitempty"`
+
+	// PrometheusRule with default alerts for the workload topology, independent of the exporter sidecar
+	// +optional
+	Alerts *AlertsSpec `json:"alerts,omitempty"`
+
+	// Periodic harvesting of SLOWLOG and LATENCY LATEST from every Redis node, independent of the exporter sidecar
+	// +optional
+	Slowlog *SlowlogSpec `json:"slowlog,omitempty"`
 }
 
+// SlowlogSpec defines how the operator harvests the slowlog and the latency monitor of every Redis node
+type SlowlogSpec struct {
+	// Harvest the slowlog and latency events into metrics and the <name>-slowlog ConfigMap.
+	// The slowlog of every node is reset after harvesting
+	Enabled bool `json:"enabled"`
+
+	// Minimum time between two harvests, bounded below by the reconcile interval
+	// +kubebuilder:validation:Pattern=`^([0-9]+(ms|s|m|h))+$`
+	// +kubebuilder:validation:XValidation:rule="duration(self) >= duration('1s')",message="interval must be a duration of at least 1s"
+	// +kubebuilder:default="1m"
+	// +optional
+	Interval string `json:"interval,omitempty"`
+
+	// Number of most recent slow entries kept in the ConfigMap
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:default=20
+	// +optional
+	TopN int32 `json:"topN,omitempty"`
+
+	// latency-monitor-threshold in milliseconds set on every node, 0 keeps the configured value
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	LatencyMonitorThresholdMillis int32 `json:"latencyMonitorThresholdMillis,omitempty"`
+}
+
+// AlertsSpec defines the PrometheusRule generated for a workload from the operator metrics
+type AlertsSpec struct {
+	// Create the PrometheusRule when the Prometheus Operator CRDs are installed
+	Enabled bool `json:"enabled"`
+
+	// Labels added to every alert so that Alertmanager can route them to the owning team
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Labels added to the PrometheusRule so that the ruleSelector of a Prometheus can match it
+	// +optional
+	RuleLabels map[string]string `json:"ruleLabels,omitempty"`
+
+	// How long a condition must hold before a warning alert fires
+	// +kubebuilder:validation:Pattern=`^([0-9]+(ms|s|m|h))+$`
+	// +kubebuilder:default="5m"
+	// +optional
+	For string `json:"for,omitempty"`
+
+	// Seconds since the last replica ack above which replication is considered lagging
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=30
+	// +optional
+	ReplicationLagSeconds int32 `json:"replicationLagSeconds,omitempty"`
+
+	// Percentage of maxmemory above which memory usage alerts fire
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:default=90
+	// +optional
+	MemoryUsagePercent int32 `json:"memoryUsagePercent,omitempty"`
+
+	// Percentage of PVC capacity above which storage alerts fire
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:default=85
+	// +optional
+	StorageUsagePercent int32 `json:"storageUsagePercent,omitempty"`
+}
+
+// ServiceMonitorSpec defines the ServiceMonitor created for the exporter
+type ServiceMonitorSpec struct {
+	// Do not create a ServiceMonitor even when the CRD is installed
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+
+	// Scrape interval, the Prometheus default is used when unset
+	// +kubebuilder:validation:Pattern=`^([0-9]+(ms|s|m|h))+$`
+	// +optional
+	Interval string `json:"interval,omitempty"`
+
+	// Additional labels so that the serviceMonitorSelector of a Prometheus can match it
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// RedisWorkloadRef references a Redis workload managed by this operator
+type RedisWorkloadRef struct {
+	// Kind of the target workload
+	// +kubebuilder:validation:Enum=RedisInstance;RedisMasterReplica;RedisSentinel;RedisCluster
+	Kind string `json:"kind"`
+
+	// Name of the target workload in the same namespace
+	Name string `json:"name"`
+}
+
+// PasswordRotationStatus defines the progress of a zero-downtime password rotation
+type PasswordRotationStatus struct {
+	// Current rotation phase
+	Phase PasswordRotationPhase `json:"phase,omitempty"`
+
+	// resourceVersion of the password Secret that is being or has been rotated to
+	SecretResourceVersion string `json:"secretResourceVersion,omitempty"`
+
+	// Human readable description of the current step
+	Message string `json:"message,omitempty"`
+
+	// Time the rotation started
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// Last time the phase changed
+	// +optional
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// PasswordRotationPhase represents the phase of a password rotation
+type PasswordRotationPhase string
+
+const (
+	// PasswordRotationAddingPassword the new password is added next to the old one and masterauth is updated
+	PasswordRotationAddingPassword PasswordRotationPhase = "AddingPassword"
+	// PasswordRotationUpdatingSentinels sentinels monitoring the workload switch auth-pass to the new password
+	PasswordRotationUpdatingSentinels PasswordRotationPhase = "UpdatingSentinels"
+	// PasswordRotationWaitingForReconnect replicas and sentinels reconnect with the new password
+	PasswordRotationWaitingForReconnect PasswordRotationPhase = "WaitingForReconnect"
+	// PasswordRotationRemovingPassword the old password is removed from the default user
+	PasswordRotationRemovingPassword PasswordRotationPhase = "RemovingPassword"
+	// PasswordRotationCompleted only the new password is accepted
+	PasswordRotationCompleted PasswordRotationPhase = "Completed"
+)
+
 // RedisMasterReplicaStatus defines the observed state of RedisMasterReplica.
 type RedisMasterReplicaStatus struct {
 	// Conditions represent the latest available observations of the resource's current state
@@ -129,6 +320,14 @@ type RedisMasterReplicaStatus struct {
 
 	// Replica status information
 	Replica ReplicaStatus `json:"replica,omitempty"`
+
+	// Progress of the last password rotation
+	// +optional
+	PasswordRotation *PasswordRotationStatus `json:"passwordRotation,omitempty"`
+
+	// Revision of the referenced Config profile applied to the workload
+	// +optional
+	ConfigRevision string `json:"configRevision,omitempty"`
 }
 
 // MasterStatus defines the status of the master node
@@ -49,20 +49,76 @@ type RedisSentinelSpec struct {
 	// +optional
 	Security SecuritySpec `json:"security,omitempty"`
 
+	// Monitoring configuration: redis_exporter sidecar and generated alerting rules
+	// +optional
+	Monitoring *MonitoringSpec `json:"monitoring,omitempty"`
+
+	// Config profile in the same namespace providing shared directives,
+	// directives set inline on this resource take precedence
+	// +optional
+	ConfigRef *corev1.LocalObjectReference `json:"configRef,omitempty"`
+
 	// Redis configuration for the managed Redis instances
 	// +optional
 	Redis RedisInstanceConfig `json:"redis,omitempty"`
 
 	// Redis Master-Replica reference that this sentinel will monitor (optional, if not using embedded Redis)
+	// Deprecated: use Masters instead. Ignored when Masters is set.
+	// +optional
+	MasterReplicaRef *MasterReplicaRef `json:"masterReplicaRef,omitempty"`
+
+	// Masters lists the masters monitored by this sentinel deployment.
+	// When set, no embedded Redis is created.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Masters []MonitoredMasterSpec `json:"masters,omitempty"`
+}
+
+// MonitoredMasterSpec defines one master monitored by the sentinels
+// +kubebuilder:validation:XValidation:rule="has(self.masterReplicaRef) != has(self.external)",message="exactly one of masterReplicaRef or external must be set"
+type MonitoredMasterSpec struct {
+	// Master name used in the sentinel configuration
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9._-]+$`
+	Name string `json:"name"`
+
+	// Reference to a RedisMasterReplica resource, possibly in another namespace
 	// +optional
 	MasterReplicaRef *MasterReplicaRef `json:"masterReplicaRef,omitempty"`
+
+	// External master outside of this operator
+	// +optional
+	External *ExternalMasterSpec `json:"external,omitempty"`
+
+	// Quorum for this master, defaults to spec.config.quorum
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Quorum int32 `json:"quorum,omitempty"`
+
+	// Secret holding the master password, must live in the sentinel namespace
+	// +optional
+	AuthSecret *corev1.SecretKeySelector `json:"authSecret,omitempty"`
+}
+
+// ExternalMasterSpec defines a master reachable by host and port
+type ExternalMasterSpec struct {
+	// Host name or IP address of the master
+	// +kubebuilder:validation:MinLength=1
+	Host string `json:"host"`
+
+	// Port of the master
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +kubebuilder:default=6379
+	Port int32 `json:"port,omitempty"`
 }
 
 // SentinelConfig defines sentinel-specific configuration
 type SentinelConfig struct {
-	// Quorum for sentinel decisions
+	// Quorum for sentinel decisions, defaults to a majority of the sentinels
 	// +kubebuilder:validation:Minimum=1
-	// +kubebuilder:default=2
+	// +optional
 	Quorum int32 `json:"quorum,omitempty"`
 
 	// Down after milliseconds
@@ -176,8 +232,53 @@ type RedisSentinelStatus struct {
 
 	// Monitored master information
 	MonitoredMaster MonitoredMasterStatus `json:"monitoredMaster,omitempty"`
+
+	// Status of every monitored master
+	// +optional
+	MonitoredMasters []MonitoredMasterStatus `json:"monitoredMasters,omitempty"`
+
+	// Progress of migrating embedded Redis from the legacy master/replica StatefulSets
+	// to the single StatefulSet layout
+	// +optional
+	Migration *EmbeddedMigrationStatus `json:"migration,omitempty"`
+
+	// Progress of the last password rotation of the embedded Redis
+	// +optional
+	PasswordRotation *PasswordRotationStatus `json:"passwordRotation,omitempty"`
+
+	// Revision of the referenced Config profile applied to the workload
+	// +optional
+	ConfigRevision string `json:"configRevision,omitempty"`
 }
 
+// EmbeddedMigrationStatus defines the progress of an embedded Redis layout migration
+type EmbeddedMigrationStatus struct {
+	// Current migration phase
+	Phase EmbeddedMigrationPhase `json:"phase,omitempty"`
+
+	// Human readable description of the current step
+	Message string `json:"message,omitempty"`
+
+	// Last time the phase changed
+	// +optional
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// EmbeddedMigrationPhase represents the phase of an embedded Redis layout migration
+type EmbeddedMigrationPhase string
+
+const (
+	// EmbeddedMigrationSyncing new pods are replicating from the legacy master
+	EmbeddedMigrationSyncing EmbeddedMigrationPhase = "Syncing"
+	// EmbeddedMigrationFailingOver sentinels are promoting the first pod of the new StatefulSet
+	EmbeddedMigrationFailingOver EmbeddedMigrationPhase = "FailingOver"
+	// EmbeddedMigrationResettingSentinels legacy StatefulSets and Services have been removed,
+	// sentinels are reset one at a time to forget the legacy replicas
+	EmbeddedMigrationResettingSentinels EmbeddedMigrationPhase = "ResettingSentinels"
+	// EmbeddedMigrationCompleted legacy StatefulSets and Services have been removed
+	EmbeddedMigrationCompleted EmbeddedMigrationPhase = "Completed"
+)
+
 // MonitoredMasterStatus defines the status of the monitored master
 type MonitoredMasterStatus struct {
 	// Name of the monitored master
@@ -197,6 +298,12 @@ type MonitoredMasterStatus struct {
 
 	// Master status (up/down)
 	Status string `json:"status,omitempty"`
+
+	// Source of the master, e.g. RedisMasterReplica/<namespace>/<name> or External
+	Source string `json:"source,omitempty"`
+
+	// Message explaining why the master could not be monitored
+	Message string `json:"message,omitempty"`
 }
 
 // RedisSentinelPhase represents the phase of RedisSentinel
@@ -214,6 +321,10 @@ const (
 
 const (
 	RedisSentinelFinalizer = "redis.github.com/sentinel-finalizer"
+
+	// AllowedSentinelNamespacesAnnotation lists the namespaces whose RedisSentinels may
+	// monitor the annotated RedisMasterReplica ("*" allows all namespaces)
+	AllowedSentinelNamespacesAnnotation = "redis.github.com/allowed-sentinel-namespaces"
 )
 
 // +kubebuilder:object:root=true
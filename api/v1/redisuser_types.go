@@ -0,0 +1,148 @@
+/*
+Copyright 2025 James.Liu.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RedisUserSpec defines the desired state of RedisUser
+type RedisUserSpec struct {
+	// Redis workload the user is applied to
+	TargetRef RedisWorkloadRef `json:"targetRef"`
+
+	// ACL username, defaults to the name of the RedisUser
+	// +kubebuilder:validation:Pattern=`^[^\s]+$`
+	// +kubebuilder:validation:XValidation:rule="self != 'default'",message="the default user is managed by spec.security of the target"
+	// +optional
+	Username string `json:"username,omitempty"`
+
+	// Secret key holding the user's password
+	PasswordSecret corev1.SecretKeySelector `json:"passwordSecret"`
+
+	// Whether the user is enabled (ACL on/off)
+	// +kubebuilder:default=true
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// Command rules, e.g. "+@read", "-flushall", "+@all"
+	// +optional
+	Commands []string `json:"commands,omitempty"`
+
+	// Key patterns the user may access, e.g. "app:*"
+	// +optional
+	Keys []string `json:"keys,omitempty"`
+
+	// Pub/Sub channel patterns the user may access
+	// +optional
+	Channels []string `json:"channels,omitempty"`
+}
+
+// RedisUserStatus defines the observed state of RedisUser.
+type RedisUserStatus struct {
+	// Conditions represent the latest available observations of the resource's current state
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Ready indicates whether the user is in sync on every node
+	Ready string `json:"ready,omitempty"`
+
+	// Status represents the current phase of the user
+	Status string `json:"status,omitempty"`
+
+	// LastConditionMessage contains the message from the last condition
+	LastConditionMessage string `json:"lastConditionMessage,omitempty"`
+
+	// Number of nodes where the live ACL matches the declared one
+	SyncedNodes int32 `json:"syncedNodes,omitempty"`
+
+	// ACL state on each node of the target
+	// +optional
+	Nodes []RedisUserNodeStatus `json:"nodes,omitempty"`
+}
+
+// RedisUserNodeStatus defines the ACL state of the user on a single node
+type RedisUserNodeStatus struct {
+	// Pod name of the node
+	Pod string `json:"pod"`
+
+	// Whether the live ACL matches the declared one after the last reconcile
+	Synced bool `json:"synced"`
+
+	// Differences found between the declared and live ACL before it was applied
+	// +optional
+	Drift []string `json:"drift,omitempty"`
+
+	// Error message if the ACL could not be read or applied
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// RedisUserPhase represents the phase of RedisUser
+type RedisUserPhase string
+
+const (
+	RedisUserPhasePending RedisUserPhase = "Pending"
+	RedisUserPhaseSynced  RedisUserPhase = "Synced"
+	RedisUserPhaseDrifted RedisUserPhase = "Drifted"
+	RedisUserPhaseFailed  RedisUserPhase = "Failed"
+)
+
+const (
+	RedisUserFinalizer = "redis.github.com/user-finalizer"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=ru
+// +kubebuilder:validation:XValidation:rule="has(self.spec.username) || self.metadata.name != 'default'",message="spec.username is required when the RedisUser is named default"
+// +kubebuilder:printcolumn:name="READY",type=string,JSONPath=`.status.ready`,description="Ready status"
+// +kubebuilder:printcolumn:name="STATUS",type=string,JSONPath=`.status.status`,description="Status of the resource"
+// +kubebuilder:printcolumn:name="TARGET",type=string,JSONPath=`.spec.targetRef.name`,description="Target workload"
+// +kubebuilder:printcolumn:name="SYNCED",type=integer,JSONPath=`.status.syncedNodes`,description="Nodes in sync"
+// +kubebuilder:printcolumn:name="AGE",type=date,JSONPath=`.metadata.creationTimestamp`,description="Age of the resource"
+// +kubebuilder:printcolumn:name="MESSAGE",type=string,JSONPath=`.status.lastConditionMessage`,description="Message of the resource"
+
+// RedisUser is the Schema for the redisusers API
+type RedisUser struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec defines the desired state of RedisUser
+	// +required
+	Spec RedisUserSpec `json:"spec"`
+
+	// status defines the observed state of RedisUser
+	// +optional
+	Status RedisUserStatus `json:"status,omitempty,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// RedisUserList contains a list of RedisUser
+type RedisUserList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RedisUser `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RedisUser{}, &RedisUserList{})
+}
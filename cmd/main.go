@@ -31,10 +31,13 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -42,6 +45,8 @@ import (
 	redisv1 "github.com/ybooks240/redis-operator/api/v1"
 	"github.com/ybooks240/redis-operator/internal/controller"
 	"github.com/ybooks240/redis-operator/internal/metrics"
+	"github.com/ybooks240/redis-operator/internal/tracing"
+	webhookv1 "github.com/ybooks240/redis-operator/internal/webhook/v1"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -67,6 +72,7 @@ func main() {
 	var secureMetrics bool
 	var enableHTTP2 bool
 	var enableMetricsCollection bool
+	var tracingOpts tracing.Options
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -87,6 +93,16 @@ func main() {
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
 	flag.BoolVar(&enableMetricsCollection, "enable-metrics-collection", false,
 		"If set, Redis metrics collection will be enabled. Disable for local development to avoid connection errors.")
+	flag.StringVar(&tracingOpts.Exporter, "tracing-exporter", tracing.ExporterNone,
+		"OpenTelemetry trace exporter for reconcile loops, Kubernetes API calls and Redis commands: "+
+			"none, otlp, stdout or file.")
+	flag.StringVar(&tracingOpts.Endpoint, "tracing-endpoint", "",
+		"The OTLP gRPC endpoint (host:port) of the otlp trace exporter. Defaults to OTEL_EXPORTER_OTLP_ENDPOINT.")
+	flag.BoolVar(&tracingOpts.Insecure, "tracing-insecure", false,
+		"If set, the otlp trace exporter connects without TLS.")
+	flag.StringVar(&tracingOpts.File, "tracing-file", "", "The file the file trace exporter appends spans to.")
+	flag.Float64Var(&tracingOpts.SampleRatio, "tracing-sample-ratio", 1,
+		"The fraction of reconciles to trace, between 0 and 1.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -95,6 +111,35 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	shutdownTracing, err := tracing.Setup(context.Background(), tracingOpts)
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracing")
+		os.Exit(1)
+	}
+	if tracingOpts.Enabled() {
+		setupLog.Info("Tracing enabled", "exporter", tracingOpts.Exporter, "sample-ratio", tracingOpts.SampleRatio)
+	}
+	// 退出前导出剩余的 span
+	stopTracing := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			setupLog.Error(err, "failed to shut down tracing")
+		}
+	}
+
+	// 启用链路追踪时，每次 Kubernetes API 调用记录为协调 span 的子 span
+	var newClient client.NewClientFunc
+	if tracingOpts.Enabled() {
+		newClient = func(config *rest.Config, options client.Options) (client.Client, error) {
+			c, err := client.NewWithWatch(config, options)
+			if err != nil {
+				return nil, err
+			}
+			return tracing.WrapClient(c), nil
+		}
+	}
+
 	// if the enable-http2 flag is false (the default), http/2 should be disabled
 	// due to its vulnerabilities. More specifically, disabling http/2 will
 	// prevent from being vulnerable to the HTTP/2 Stream Cancellation and
@@ -191,6 +236,7 @@ func main() {
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "f30df314.github.com",
+		NewClient:              newClient,
 		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
 		// when the Manager ends. This requires the binary to immediately end when the
 		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
@@ -209,8 +255,9 @@ func main() {
 	}
 
 	if err = (&controller.ConfigReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("config-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Config")
 		os.Exit(1)
@@ -222,6 +269,29 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "Redis")
 		os.Exit(1)
 	}
+	// nolint:goconst
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = webhookv1.SetupRedisInstanceWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "RedisInstance")
+			os.Exit(1)
+		}
+		if err = webhookv1.SetupRedisMasterReplicaWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "RedisMasterReplica")
+			os.Exit(1)
+		}
+		if err = webhookv1.SetupRedisSentinelWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "RedisSentinel")
+			os.Exit(1)
+		}
+		if err = webhookv1.SetupRedisClusterWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "RedisCluster")
+			os.Exit(1)
+		}
+		if err = webhookv1.SetupConfigWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Config")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	if metricsCertWatcher != nil {
@@ -253,6 +323,8 @@ func main() {
 	var metricsManager *metrics.MetricsCollectionManager
 	if enableMetricsCollection {
 		metricsManager = metrics.NewMetricsCollectionManager(30 * time.Second)
+		// Redis 节点指标在抓取时由管理器根据最近一次的收集结果生成
+		ctrlmetrics.Registry.MustRegister(metricsManager)
 		ctx := context.Background()
 		go func() {
 			setupLog.Info("Starting metrics collection manager")
@@ -266,6 +338,7 @@ func main() {
 	if err = (&controller.RedisInstanceReconciler{
 		Client:         mgr.GetClient(),
 		Scheme:         mgr.GetScheme(),
+		Recorder:       mgr.GetEventRecorderFor("redisinstance-controller"),
 		MetricsManager: metricsManager,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "RedisInstance")
@@ -275,6 +348,7 @@ func main() {
 	if err = (&controller.RedisSentinelReconciler{
 		Client:         mgr.GetClient(),
 		Scheme:         mgr.GetScheme(),
+		Recorder:       mgr.GetEventRecorderFor("redissentinel-controller"),
 		MetricsManager: metricsManager,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "RedisSentinel")
@@ -284,6 +358,7 @@ func main() {
 	if err = (&controller.RedisClusterReconciler{
 		Client:         mgr.GetClient(),
 		Scheme:         mgr.GetScheme(),
+		Recorder:       mgr.GetEventRecorderFor("rediscluster-controller"),
 		MetricsManager: metricsManager,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "RedisCluster")
@@ -293,16 +368,48 @@ func main() {
 	if err = (&controller.RedisMasterReplicaReconciler{
 		Client:         mgr.GetClient(),
 		Scheme:         mgr.GetScheme(),
+		Recorder:       mgr.GetEventRecorderFor("redismasterreplica-controller"),
 		MetricsManager: metricsManager,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "RedisMasterReplica")
 		os.Exit(1)
 	}
 
+	if err = (&controller.RedisUserReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "RedisUser")
+		os.Exit(1)
+	}
+
+	if err = (&controller.RedisBackupReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "RedisBackup")
+		os.Exit(1)
+	}
+
+	if err = (&controller.RedisBackupScheduleReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "RedisBackupSchedule")
+		os.Exit(1)
+	}
+
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
 		setupLog.Error(err, "problem running manager")
-		metricsManager.Stop()
+		if metricsManager != nil {
+			metricsManager.Stop()
+		}
+		stopTracing()
 		os.Exit(1)
 	}
+	if metricsManager != nil {
+		metricsManager.Stop()
+	}
+	stopTracing()
 }
@@ -0,0 +1,297 @@
+/*
+Copyright 2025 James.Liu.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	redisv1 "github.com/ybooks240/redis-operator/api/v1"
+)
+
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=prometheusrules,verbs=get;list;watch;create;update;patch;delete
+
+// prometheusRuleGVK Prometheus Operator 的 PrometheusRule，未安装 CRD 时跳过创建
+var prometheusRuleGVK = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "PrometheusRule"}
+
+// 未经过准入 webhook 默认值填充时使用的告警阈值
+const (
+	defaultAlertFor                    = "5m"
+	defaultReplicationLagSeconds int32 = 30
+	defaultMemoryUsagePercent    int32 = 90
+	defaultStorageUsagePercent   int32 = 85
+	// criticalAlertFor 节点或集群不可用类告警的持续时间，不受 AlertsSpec.For 影响
+	criticalAlertFor = "1m"
+)
+
+// alertTopology 生成告警规则所需的工作负载拓扑
+type alertTopology struct {
+	// redisNodes 工作负载是否包含 operator 管理的 Redis 节点
+	redisNodes bool
+	// replication 工作负载是否包含从节点
+	replication bool
+	// cluster 是否为 Redis Cluster
+	cluster bool
+	// pvcPattern 匹配工作负载 PVC 名称的正则表达式，为空时不生成存储告警
+	pvcPattern string
+	// quorums Sentinel 监控的各 master 的 quorum，以 master 名称为键
+	quorums map[string]int32
+}
+
+// newAlertTopology 返回工作负载的拓扑，RedisSentinel 的 quorum 由调用方填充
+func newAlertTopology(kind, name string) alertTopology {
+	quoted := promRegexQuote(name)
+	switch kind {
+	case "RedisInstance":
+		return alertTopology{redisNodes: true, pvcPattern: "redis-data-" + quoted + "-[0-9]+"}
+	case "RedisMasterReplica":
+		return alertTopology{redisNodes: true, replication: true, pvcPattern: "data-" + quoted + "-(master|replica)-[0-9]+"}
+	case "RedisSentinel":
+		return alertTopology{redisNodes: true, replication: true, pvcPattern: "redis-data-" + quoted + "-redis-[0-9]+"}
+	case "RedisCluster":
+		return alertTopology{redisNodes: true, replication: true, cluster: true, pvcPattern: "data-" + quoted + "-[0-9]+"}
+	default:
+		return alertTopology{}
+	}
+}
+
+// promRegexQuote 转义资源名称中的正则元字符，结果可以直接放入 PromQL 的双引号字符串
+func promRegexQuote(s string) string {
+	return strings.ReplaceAll(regexp.QuoteMeta(s), `\`, `\\`)
+}
+
+// alertRule 一条告警规则
+type alertRule struct {
+	alert       string
+	expr        string
+	duration    string
+	severity    string
+	summary     string
+	description string
+}
+
+// prometheusRuleName 返回工作负载 PrometheusRule 的名称
+func prometheusRuleName(name string) string {
+	return name + "-alerts"
+}
+
+// buildAlertRules 根据工作负载拓扑生成默认告警规则，规则只使用 operator 导出的指标和 kubelet 的存储指标
+func buildAlertRules(namespace, name string, alerts *redisv1.AlertsSpec, topology alertTopology) []alertRule {
+	duration := alerts.For
+	if duration == "" {
+		duration = defaultAlertFor
+	}
+	lagSeconds := alerts.ReplicationLagSeconds
+	if lagSeconds <= 0 {
+		lagSeconds = defaultReplicationLagSeconds
+	}
+	memoryPercent := alerts.MemoryUsagePercent
+	if memoryPercent <= 0 {
+		memoryPercent = defaultMemoryUsagePercent
+	}
+	storagePercent := alerts.StorageUsagePercent
+	if storagePercent <= 0 {
+		storagePercent = defaultStorageUsagePercent
+	}
+
+	selector := fmt.Sprintf(`namespace="%s",name="%s"`, namespace, name)
+	var rules []alertRule
+
+	if topology.redisNodes {
+		rules = append(rules,
+			alertRule{
+				alert:       "RedisMasterDown",
+				expr:        fmt.Sprintf(`redis_instance_status{%s,role="master"} == 0`, selector),
+				duration:    criticalAlertFor,
+				severity:    "critical",
+				summary:     "Redis master {{ $labels.pod }} is down",
+				description: "Redis master {{ $labels.pod }} of {{ $labels.namespace }}/{{ $labels.name }} is unreachable.",
+			},
+			alertRule{
+				alert:       "RedisMemoryNearMaxmemory",
+				expr:        fmt.Sprintf(`100 * redis_instance_memory_usage_bytes{%s} / (redis_instance_memory_max_bytes{%s} > 0) > %d`, selector, selector, memoryPercent),
+				duration:    duration,
+				severity:    "warning",
+				summary:     "Redis {{ $labels.pod }} memory is near maxmemory",
+				description: fmt.Sprintf("Redis {{ $labels.pod }} of {{ $labels.namespace }}/{{ $labels.name }} uses {{ $value | humanize }}%% of maxmemory, above %d%%.", memoryPercent),
+			},
+		)
+	}
+
+	if topology.replication {
+		rules = append(rules,
+			alertRule{
+				alert:       "RedisReplicaDown",
+				expr:        fmt.Sprintf(`redis_instance_status{%s,role="replica"} == 0`, selector),
+				duration:    duration,
+				severity:    "warning",
+				summary:     "Redis replica {{ $labels.pod }} is down",
+				description: "Redis replica {{ $labels.pod }} of {{ $labels.namespace }}/{{ $labels.name }} is unreachable.",
+			},
+			alertRule{
+				alert:       "RedisReplicationBroken",
+				expr:        fmt.Sprintf(`redis_instance_master_link_up{%s,role="replica"} == 0`, selector),
+				duration:    duration,
+				severity:    "warning",
+				summary:     "Redis replica {{ $labels.pod }} lost its master link",
+				description: "Redis replica {{ $labels.pod }} of {{ $labels.namespace }}/{{ $labels.name }} is not connected to its master.",
+			},
+			alertRule{
+				alert:       "RedisReplicationLag",
+				expr:        fmt.Sprintf(`redis_instance_replica_lag_seconds{%s} > %d`, selector, lagSeconds),
+				duration:    duration,
+				severity:    "warning",
+				summary:     "Redis replica {{ $labels.replica }} is lagging",
+				description: fmt.Sprintf("Replica {{ $labels.replica }} of master {{ $labels.pod }} in {{ $labels.namespace }}/{{ $labels.name }} has not acknowledged replication for {{ $value }}s, above %ds.", lagSeconds),
+			},
+		)
+	}
+
+	if topology.cluster {
+		rules = append(rules,
+			alertRule{
+				alert:       "RedisClusterStateFail",
+				expr:        fmt.Sprintf(`redis_cluster_state{%s} == 0`, selector),
+				duration:    criticalAlertFor,
+				severity:    "critical",
+				summary:     "Redis Cluster {{ $labels.name }} state is fail",
+				description: "Redis Cluster {{ $labels.namespace }}/{{ $labels.name }} reports cluster_state:fail and rejects queries.",
+			},
+			alertRule{
+				alert:       "RedisClusterSlotsUncovered",
+				expr:        fmt.Sprintf(`redis_cluster_slots_assigned{%s} < 16384 or redis_cluster_slots_fail{%s} > 0`, selector, selector),
+				duration:    criticalAlertFor,
+				severity:    "critical",
+				summary:     "Redis Cluster {{ $labels.name }} has uncovered slots",
+				description: "Some of the 16384 slots of Redis Cluster {{ $labels.namespace }}/{{ $labels.name }} are unassigned or served by failed nodes.",
+			},
+		)
+	}
+
+	if len(topology.quorums) > 0 {
+		// SENTINEL SENTINELS 不包含被查询的 Sentinel 自身，已知的 Sentinel 数量需要加一
+		var quorumExprs []string
+		for _, master := range slices.Sorted(maps.Keys(topology.quorums)) {
+			quorumExprs = append(quorumExprs, fmt.Sprintf(`redis_sentinel_sentinels_total{%s,master_name="%s"} + 1 < %d`, selector, master, topology.quorums[master]))
+		}
+		rules = append(rules,
+			alertRule{
+				alert:       "RedisSentinelMasterDown",
+				expr:        fmt.Sprintf(`redis_sentinel_master_status{%s} == 0`, selector),
+				duration:    criticalAlertFor,
+				severity:    "critical",
+				summary:     "Sentinels report master {{ $labels.master_name }} as down",
+				description: "Sentinels of {{ $labels.namespace }}/{{ $labels.name }} report master {{ $labels.master_name }} as down.",
+			},
+			alertRule{
+				alert:       "RedisSentinelQuorumLost",
+				expr:        strings.Join(quorumExprs, " or "),
+				duration:    criticalAlertFor,
+				severity:    "critical",
+				summary:     "Sentinel quorum lost for master {{ $labels.master_name }}",
+				description: "Only {{ $value }} sentinels of {{ $labels.namespace }}/{{ $labels.name }} monitor master {{ $labels.master_name }}, fewer than the quorum, so failover is impossible.",
+			},
+		)
+	}
+
+	if topology.pvcPattern != "" {
+		pvcSelector := fmt.Sprintf(`namespace="%s",persistentvolumeclaim=~"%s"`, namespace, topology.pvcPattern)
+		rules = append(rules, alertRule{
+			alert:       "RedisPVCNearlyFull",
+			expr:        fmt.Sprintf(`100 * kubelet_volume_stats_used_bytes{%s} / kubelet_volume_stats_capacity_bytes{%s} > %d`, pvcSelector, pvcSelector, storagePercent),
+			duration:    duration,
+			severity:    "warning",
+			summary:     "PVC {{ $labels.persistentvolumeclaim }} is nearly full",
+			description: fmt.Sprintf("PVC {{ $labels.persistentvolumeclaim }} of %s/%s is {{ $value | humanize }}%% full, above %d%%.", namespace, name, storagePercent),
+		})
+	}
+
+	return rules
+}
+
+// ensurePrometheusRule 启用告警且集群安装了 PrometheusRule CRD 时为工作负载创建 PrometheusRule，停用后删除
+func ensurePrometheusRule(ctx context.Context, c client.Client, scheme *runtime.Scheme, recorder record.EventRecorder, owner client.Object, alerts *redisv1.AlertsSpec, topology alertTopology) error {
+	name := prometheusRuleName(owner.GetName())
+	if alerts == nil || !alerts.Enabled {
+		return deleteMonitoringObject(ctx, c, prometheusRuleGVK, owner.GetNamespace(), name)
+	}
+	installed, err := crdInstalled(c, prometheusRuleGVK)
+	if err != nil || !installed {
+		return err
+	}
+
+	rules := []interface{}{}
+	for _, rule := range buildAlertRules(owner.GetNamespace(), owner.GetName(), alerts, topology) {
+		// 自定义标签用于 Alertmanager 路由，不能覆盖告警级别
+		labels := map[string]interface{}{}
+		for key, value := range alerts.Labels {
+			labels[key] = value
+		}
+		labels["severity"] = rule.severity
+		rules = append(rules, map[string]interface{}{
+			"alert":  rule.alert,
+			"expr":   rule.expr,
+			"for":    rule.duration,
+			"labels": labels,
+			"annotations": map[string]interface{}{
+				"summary":     rule.summary,
+				"description": rule.description,
+			},
+		})
+	}
+
+	prometheusRule := &unstructured.Unstructured{}
+	prometheusRule.SetGroupVersionKind(prometheusRuleGVK)
+	prometheusRule.SetName(name)
+	prometheusRule.SetNamespace(owner.GetNamespace())
+	op, err := controllerutil.CreateOrUpdate(ctx, c, prometheusRule, func() error {
+		ruleLabels := maps.Clone(alerts.RuleLabels)
+		if ruleLabels == nil {
+			ruleLabels = map[string]string{}
+		}
+		ruleLabels["app.kubernetes.io/instance"] = owner.GetName()
+		ruleLabels["app.kubernetes.io/managed-by"] = "redis-operator"
+		prometheusRule.SetLabels(ruleLabels)
+
+		groups := []interface{}{map[string]interface{}{
+			"name":  fmt.Sprintf("redis.%s.%s", owner.GetNamespace(), owner.GetName()),
+			"rules": rules,
+		}}
+		if err := unstructured.SetNestedSlice(prometheusRule.Object, groups, "spec", "groups"); err != nil {
+			return err
+		}
+		return controllerutil.SetControllerReference(owner, prometheusRule, scheme)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to ensure prometheus rule: %w", err)
+	}
+	if op == controllerutil.OperationResultCreated {
+		recordCreated(recorder, owner, "PrometheusRule", name)
+	}
+	return nil
+}
@@ -0,0 +1,521 @@
+/*
+Copyright 2025 James.Liu.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	redisv1 "github.com/ybooks240/redis-operator/api/v1"
+	"github.com/ybooks240/redis-operator/internal/tracing"
+	"github.com/ybooks240/redis-operator/internal/utils"
+)
+
+const (
+	// clusterSlotCount Redis Cluster 的槽位总数
+	clusterSlotCount = 16384
+
+	// clusterRestoreBudget 每次协调用于迁移槽位的时间，超出后在下一次协调中继续
+	clusterRestoreBudget = 20 * time.Second
+
+	// clusterMigrateBatch 每次 MIGRATE 迁移的 key 数量
+	clusterMigrateBatch = 100
+
+	// clusterMigrateTimeout MIGRATE 在源节点与目标节点之间传输 key 的超时时间，与 redis-cli --cluster 的默认值一致，
+	// 大 key 的序列化和传输远慢于普通管理命令
+	clusterMigrateTimeout = 60 * time.Second
+)
+
+// errRestoreRefused 备份与集群规格不匹配，恢复不会继续
+var errRestoreRefused = errors.New("restore refused")
+
+// clusterRestoreShard 备份清单中的一个分片
+type clusterRestoreShard struct {
+	Location *restoreLocation
+	Slots    []int
+}
+
+// resolveClusterRestoreSource 读取备份清单并校验槽位布局，
+// 备份未完成时返回普通错误，备份与集群不匹配时返回 errRestoreRefused
+func resolveClusterRestoreSource(ctx context.Context, c client.Client, redisCluster *redisv1.RedisCluster) ([]clusterRestoreShard, error) {
+	source := redisCluster.Spec.RestoreFrom
+	backup := &redisv1.RedisBackup{}
+	if err := c.Get(ctx, types.NamespacedName{Name: source.Backup, Namespace: redisCluster.Namespace}, backup); err != nil {
+		return nil, fmt.Errorf("failed to get RedisBackup %s: %w", source.Backup, err)
+	}
+	if backup.Status.Status != string(redisv1.RedisBackupPhaseCompleted) {
+		return nil, fmt.Errorf("RedisBackup %s is not completed yet", source.Backup)
+	}
+
+	shards := make([]clusterRestoreShard, 0, len(backup.Status.Shards))
+	for _, shard := range backup.Status.Shards {
+		if strings.TrimSpace(shard.Slots) == "" {
+			return nil, fmt.Errorf("%w: RedisBackup %s was not taken from a RedisCluster", errRestoreRefused, source.Backup)
+		}
+		slots, err := parseSlotRanges(strings.Fields(shard.Slots))
+		if err != nil {
+			return nil, fmt.Errorf("%w: RedisBackup %s shard %d: %v", errRestoreRefused, source.Backup, shard.Index, err)
+		}
+		location, err := backupShardLocation(backup, shard.Location)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", errRestoreRefused, err)
+		}
+		shards = append(shards, clusterRestoreShard{Location: location, Slots: slots})
+	}
+	if err := validateSlotMap(shards); err != nil {
+		return nil, fmt.Errorf("%w: RedisBackup %s: %v", errRestoreRefused, source.Backup, err)
+	}
+
+	masters := int(redisCluster.Spec.Masters)
+	nodes := masters * int(1+redisCluster.Spec.ReplicasPerMaster)
+	switch {
+	case len(shards) != masters && !source.AllowReshard:
+		return nil, fmt.Errorf("%w: RedisBackup %s has %d shards but the cluster has %d masters, set allowReshard to restore onto a different number of masters",
+			errRestoreRefused, source.Backup, len(shards), masters)
+	case len(shards) > nodes:
+		return nil, fmt.Errorf("%w: RedisBackup %s has %d shards but the cluster only has %d nodes to load them",
+			errRestoreRefused, source.Backup, len(shards), nodes)
+	}
+	return shards, nil
+}
+
+// parseSlotRanges 解析 CLUSTER NODES 格式的槽位范围，例如 "0-5460" 或 "5461"
+func parseSlotRanges(ranges []string) ([]int, error) {
+	var slots []int
+	for _, slotRange := range ranges {
+		low, high, isRange := strings.Cut(slotRange, "-")
+		if !isRange {
+			high = low
+		}
+		start, err := strconv.Atoi(low)
+		if err != nil {
+			return nil, fmt.Errorf("invalid slot range %q", slotRange)
+		}
+		end, err := strconv.Atoi(high)
+		if err != nil || start < 0 || end >= clusterSlotCount || start > end {
+			return nil, fmt.Errorf("invalid slot range %q", slotRange)
+		}
+		for slot := start; slot <= end; slot++ {
+			slots = append(slots, slot)
+		}
+	}
+	return slots, nil
+}
+
+// validateSlotMap 校验分片的槽位不重叠且覆盖全部槽位
+func validateSlotMap(shards []clusterRestoreShard) error {
+	owners := make([]int, clusterSlotCount)
+	for i := range owners {
+		owners[i] = -1
+	}
+	for i, shard := range shards {
+		for _, slot := range shard.Slots {
+			if owners[slot] >= 0 {
+				return fmt.Errorf("slot %d is recorded for shard %d and shard %d", slot, owners[slot], i)
+			}
+			owners[slot] = i
+		}
+	}
+	for slot, owner := range owners {
+		if owner < 0 {
+			return fmt.Errorf("slot %d is not recorded for any shard", slot)
+		}
+	}
+	return nil
+}
+
+// evenSlotOwners 返回槽位平均分配到 masters 个主节点时每个槽位所属的主节点序号
+func evenSlotOwners(masters int) []int {
+	owners := make([]int, clusterSlotCount)
+	for master := 0; master < masters; master++ {
+		for slot := master * clusterSlotCount / masters; slot < (master+1)*clusterSlotCount/masters; slot++ {
+			owners[slot] = master
+		}
+	}
+	return owners
+}
+
+// applyClusterRestore 数据尚未恢复时为集群 StatefulSet 添加恢复初始化容器，
+// 序号为 i 的 Pod 加载备份中第 i 个分片，没有对应分片的 Pod 以空数据启动
+// config 为追加到集群 redis.conf 的配置，恢复使用其中的 dbfilename 和 appendonly
+func applyClusterRestore(template *corev1.PodTemplateSpec, shards []clusterRestoreShard, image string, config map[string]string) {
+	dataMount := corev1.VolumeMount{Name: "data", MountPath: "/data"}
+
+	var fetch corev1.Container
+	var copyCommand string
+	if store := shards[0].Location.ObjectStore; store != nil {
+		fetch = mcContainer("restore-fetch", store, "cp")
+		copyCommand = strings.Join(mcCommand(store, "cp"), " ") + " 'backup/%s' " + restoreStagingFile
+	} else {
+		fetch = corev1.Container{Name: "restore-fetch", Image: image}
+		copyCommand = "cp '" + restoreSourceDir + "/%s' " + restoreStagingFile
+		fetch.VolumeMounts = append(fetch.VolumeMounts, corev1.VolumeMount{Name: "restore-source", MountPath: restoreSourceDir, ReadOnly: true})
+		template.Spec.Volumes = append(template.Spec.Volumes, corev1.Volume{
+			Name: "restore-source",
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: shards[0].Location.ClaimName, ReadOnly: true},
+			},
+		})
+	}
+
+	script := fmt.Sprintf("set -e\n[ -f %s ] && exit 0\n", restoreMarkerFile)
+	if store := shards[0].Location.ObjectStore; store != nil {
+		script += mcAliasScript(store) + "\n"
+	}
+	script += "case \"${HOSTNAME##*-}\" in\n"
+	for i, shard := range shards {
+		file := shard.Location.Key
+		if shard.Location.ObjectStore == nil {
+			file = path.Clean(shard.Location.Path)
+		}
+		script += fmt.Sprintf("%d) %s ;;\n", i, fmt.Sprintf(copyCommand, file))
+	}
+	script += fmt.Sprintf("*) touch %s ;;\nesac\n", restoreMarkerFile)
+
+	fetch.Command = []string{"sh", "-c", script}
+	fetch.VolumeMounts = append(fetch.VolumeMounts, dataMount)
+	fetch.TerminationMessagePolicy = corev1.TerminationMessageFallbackToLogsOnError
+
+	// 与 configMapForCluster 渲染的 redis.conf 保持一致，默认 dbfilename dump.rdb 且开启 appendonly
+	dbfilename := "dump.rdb"
+	if value, ok := config["dbfilename"]; ok {
+		dbfilename = value
+	}
+	appendonly := config["appendonly"] != "no"
+	restore := restoreDataContainer(image, dataMount, dbfilename, appendonly)
+	template.Spec.InitContainers = append([]corev1.Container{fetch, restore}, template.Spec.InitContainers...)
+}
+
+// reconcileClusterRestore 在恢复数据后按备份的槽位布局组建集群，并把进度写入 Restored 条件，
+// 返回 true 表示恢复仍在进行
+func reconcileClusterRestore(ctx context.Context, c client.Client, redisCluster *redisv1.RedisCluster, logs logr.Logger) bool {
+	if redisCluster.Spec.RestoreFrom == nil || restoreCompleted(redisCluster.Status.Conditions) {
+		return false
+	}
+
+	condition := metav1.Condition{
+		Type:   redisRestoredCondition,
+		Status: metav1.ConditionFalse,
+	}
+	defer func() {
+		meta.SetStatusCondition(&redisCluster.Status.Conditions, condition)
+	}()
+
+	shards, err := resolveClusterRestoreSource(ctx, c, redisCluster)
+	if err != nil {
+		condition.Reason = "WaitingForSource"
+		if errors.Is(err, errRestoreRefused) {
+			condition.Reason = "RestoreRefused"
+		}
+		condition.Message = err.Error()
+		return false
+	}
+
+	workload, err := resolveRedisWorkload(ctx, c, "RedisCluster", redisCluster.Namespace, redisCluster.Name)
+	if err != nil {
+		condition.Reason = "Restoring"
+		condition.Message = err.Error()
+		return true
+	}
+
+	nodes := int(redisCluster.Spec.Masters * (1 + redisCluster.Spec.ReplicasPerMaster))
+	pods := make([]*corev1.Pod, nodes)
+	for i := range workload.Pods {
+		pod := &workload.Pods[i]
+		if message := restoreFailure(pod); message != "" {
+			condition.Reason = "RestoreFailed"
+			condition.Message = fmt.Sprintf("Restoring RedisBackup %s on pod %s failed: %s", redisCluster.Spec.RestoreFrom.Backup, pod.Name, message)
+			return false
+		}
+		ordinal, err := strconv.Atoi(strings.TrimPrefix(pod.Name, redisCluster.Name+"-"))
+		if err == nil && ordinal < nodes && isPodReady(pod) && pod.Status.PodIP != "" {
+			pods[ordinal] = pod
+		}
+	}
+	for _, pod := range pods {
+		if pod == nil {
+			condition.Reason = "Restoring"
+			condition.Message = fmt.Sprintf("Loading RedisBackup %s into %d shards", redisCluster.Spec.RestoreFrom.Backup, len(shards))
+			return true
+		}
+	}
+
+	bootstrapCtx, cancel := context.WithTimeout(ctx, clusterRestoreBudget+time.Minute)
+	defer cancel()
+	message, done, err := bootstrapRestoredCluster(bootstrapCtx, workload, pods, shards, int(redisCluster.Spec.Masters))
+	switch {
+	case errors.Is(err, errRestoreRefused):
+		condition.Reason = "SlotMapMismatch"
+		condition.Message = err.Error()
+		return false
+	case err != nil:
+		logs.Error(err, "Failed to bootstrap restored cluster", "name", redisCluster.Name)
+		condition.Reason = "Bootstrapping"
+		condition.Message = err.Error()
+		return true
+	case done:
+		logs.Info("Cluster restored from backup", "name", redisCluster.Name, "backup", redisCluster.Spec.RestoreFrom.Backup)
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "Restored"
+		condition.Message = fmt.Sprintf("Data restored from RedisBackup %s with %d shards", redisCluster.Spec.RestoreFrom.Backup, len(shards))
+		return false
+	}
+	condition.Reason = "Bootstrapping"
+	condition.Message = message
+	return true
+}
+
+// restoreNode 参与组建集群的节点
+type restoreNode struct {
+	pod    *corev1.Pod
+	client *redis.Client
+	self   utils.ClusterNode
+	known  []utils.ClusterNode
+}
+
+// bootstrapRestoredCluster 分配槽位、组建集群、按需重新分片并设置从节点，
+// 每一步都可重复执行，未完成时返回当前进度
+func bootstrapRestoredCluster(ctx context.Context, workload *redisWorkload, pods []*corev1.Pod, shards []clusterRestoreShard, masters int) (string, bool, error) {
+	nodes := make([]*restoreNode, len(pods))
+	for i, pod := range pods {
+		nodes[i] = &restoreNode{pod: pod, client: workload.client(pod)}
+		defer nodes[i].client.Close()
+	}
+	refresh := func() error {
+		for _, node := range nodes {
+			output, err := node.client.ClusterNodes(ctx).Result()
+			if err != nil {
+				return fmt.Errorf("failed to get cluster nodes from pod %s: %w", node.pod.Name, err)
+			}
+			node.known = utils.ParseClusterNodes(output)
+			node.self = utils.ClusterNode{}
+			for _, known := range node.known {
+				if known.HasFlag("myself") {
+					node.self = known
+				}
+			}
+			if node.self.ID == "" {
+				return fmt.Errorf("pod %s did not report itself in CLUSTER NODES", node.pod.Name)
+			}
+		}
+		return nil
+	}
+	if err := refresh(); err != nil {
+		return "", false, err
+	}
+
+	// 加入集群前校验每个节点加载的数据只属于对应分片，并补齐分片中没有 key 的槽位
+	for i, node := range nodes {
+		if len(node.known) > 1 {
+			continue
+		}
+		var want []int
+		if i < len(shards) {
+			want = shards[i].Slots
+		}
+		wanted := make(map[int]bool, len(want))
+		for _, slot := range want {
+			wanted[slot] = true
+		}
+		owned, err := parseSlotRanges(node.self.Slots)
+		if err != nil {
+			return "", false, err
+		}
+		ownedSet := make(map[int]bool, len(owned))
+		for _, slot := range owned {
+			if !wanted[slot] {
+				return "", false, fmt.Errorf("%w: pod %s holds keys in slot %d which the backup manifest does not record for shard %d", errRestoreRefused, node.pod.Name, slot, i)
+			}
+			ownedSet[slot] = true
+		}
+		var missing []int
+		for _, slot := range want {
+			if !ownedSet[slot] {
+				missing = append(missing, slot)
+			}
+		}
+		if len(missing) > 0 {
+			if err := node.client.ClusterAddSlots(ctx, missing...).Err(); err != nil {
+				return "", false, fmt.Errorf("failed to assign slots to pod %s: %w", node.pod.Name, err)
+			}
+		}
+		// 不同的配置纪元避免节点之间的槽位冲突，节点已加入集群时会返回错误
+		_ = node.client.Do(ctx, "CLUSTER", "SET-CONFIG-EPOCH", i+1).Err()
+	}
+
+	// 从第一个节点向其他节点发送 MEET，握手中的节点按地址跳过
+	seed := nodes[0]
+	met := map[string]bool{}
+	for _, known := range seed.known {
+		met[known.IP()] = true
+	}
+	for _, node := range nodes[1:] {
+		if met[node.pod.Status.PodIP] {
+			continue
+		}
+		if err := seed.client.ClusterMeet(ctx, node.pod.Status.PodIP, strconv.Itoa(defaultRedisPort)).Err(); err != nil {
+			return "", false, fmt.Errorf("failed to meet pod %s: %w", node.pod.Name, err)
+		}
+	}
+	if err := refresh(); err != nil {
+		return "", false, err
+	}
+	for _, node := range nodes {
+		joined := 0
+		for _, known := range node.known {
+			if !known.HasFlag("handshake") {
+				joined++
+			}
+		}
+		if joined < len(nodes) {
+			return fmt.Sprintf("Waiting for pod %s to join the cluster", node.pod.Name), false, nil
+		}
+	}
+	info, err := seed.client.ClusterInfo(ctx).Result()
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get cluster info: %w", err)
+	}
+	if state := utils.ParseInfo(info)["cluster_state"]; state != "ok" {
+		return fmt.Sprintf("Waiting for cluster state to become ok, current state %s", state), false, nil
+	}
+
+	// 分片数与主节点数不同时把槽位平均迁移到前 masters 个节点
+	if len(shards) != masters {
+		moved, remaining, err := reshardRestoredCluster(ctx, workload, nodes, masters)
+		if err != nil {
+			return "", false, err
+		}
+		if remaining > 0 {
+			return fmt.Sprintf("Resharding onto %d masters, moved %d slots, %d slots left", masters, moved, remaining), false, nil
+		}
+	}
+
+	// 序号不小于 masters 的节点成为从节点
+	replicating := true
+	for i := masters; i < len(nodes); i++ {
+		master := nodes[(i-masters)%masters]
+		if nodes[i].self.MasterID == master.self.ID {
+			continue
+		}
+		replicating = false
+		if err := nodes[i].client.ClusterReplicate(ctx, master.self.ID).Err(); err != nil {
+			return "", false, fmt.Errorf("failed to replicate pod %s from pod %s: %w", nodes[i].pod.Name, master.pod.Name, err)
+		}
+	}
+	if !replicating {
+		return "Attaching replicas to their masters", false, nil
+	}
+	return "", true, nil
+}
+
+// reshardRestoredCluster 在时间预算内迁移槽位，返回本次迁移的槽位数和剩余待迁移的槽位数
+func reshardRestoredCluster(ctx context.Context, workload *redisWorkload, nodes []*restoreNode, masters int) (int, int, error) {
+	// 以每个节点自身记录的槽位为准，避免 gossip 传播延迟
+	owners := make([]int, clusterSlotCount)
+	for i, node := range nodes {
+		slots, err := parseSlotRanges(node.self.Slots)
+		if err != nil {
+			return 0, 0, err
+		}
+		for _, slot := range slots {
+			owners[slot] = i
+		}
+	}
+
+	targets := evenSlotOwners(masters)
+	var pending []int
+	for slot, owner := range owners {
+		if owner != targets[slot] {
+			pending = append(pending, slot)
+		}
+	}
+	sort.Ints(pending)
+
+	deadline := time.Now().Add(clusterRestoreBudget)
+	moved := 0
+	for _, slot := range pending {
+		if time.Now().After(deadline) {
+			break
+		}
+		if err := migrateSlot(ctx, workload, slot, nodes[owners[slot]], nodes[targets[slot]]); err != nil {
+			return moved, len(pending) - moved, err
+		}
+		moved++
+	}
+	return moved, len(pending) - moved, nil
+}
+
+// migrateSlot 按 redis-cli --cluster reshard 的步骤把一个槽位及其 key 迁移到目标节点
+func migrateSlot(ctx context.Context, workload *redisWorkload, slot int, source, target *restoreNode) (err error) {
+	// 每个槽位的迁移步骤归入同一个 span，便于分析重新分片的耗时
+	ctx, span := tracing.StartSpan(ctx, "migrate slot",
+		attribute.Int("redis.cluster.slot", slot),
+		attribute.String("redis.cluster.source", source.pod.Name),
+		attribute.String("redis.cluster.target", target.pod.Name),
+	)
+	defer func() { tracing.EndSpan(span, err) }()
+
+	if err := target.client.Do(ctx, "CLUSTER", "SETSLOT", slot, "IMPORTING", source.self.ID).Err(); err != nil {
+		return fmt.Errorf("failed to import slot %d on pod %s: %w", slot, target.pod.Name, err)
+	}
+	if err := source.client.Do(ctx, "CLUSTER", "SETSLOT", slot, "MIGRATING", target.self.ID).Err(); err != nil {
+		return fmt.Errorf("failed to migrate slot %d on pod %s: %w", slot, source.pod.Name, err)
+	}
+	// 客户端等待时间比 MIGRATE 的超时稍长，使传输超时由源节点报告，而不是客户端提前断开
+	migrateClient := source.client.WithTimeout(clusterMigrateTimeout + 5*time.Second)
+	for {
+		keys, err := source.client.ClusterGetKeysInSlot(ctx, slot, clusterMigrateBatch).Result()
+		if err != nil {
+			return fmt.Errorf("failed to get keys of slot %d: %w", slot, err)
+		}
+		if len(keys) == 0 {
+			break
+		}
+		args := []interface{}{"MIGRATE", target.pod.Status.PodIP, defaultRedisPort, "", 0, clusterMigrateTimeout.Milliseconds(), "REPLACE"}
+		if workload.Password != "" {
+			args = append(args, "AUTH", workload.Password)
+		}
+		args = append(args, "KEYS")
+		for _, key := range keys {
+			args = append(args, key)
+		}
+		if err := migrateClient.Do(ctx, args...).Err(); err != nil {
+			return fmt.Errorf("failed to migrate keys of slot %d to pod %s: %w", slot, target.pod.Name, err)
+		}
+	}
+	for _, node := range []*restoreNode{target, source} {
+		if err := node.client.Do(ctx, "CLUSTER", "SETSLOT", slot, "NODE", target.self.ID).Err(); err != nil {
+			return fmt.Errorf("failed to assign slot %d on pod %s: %w", slot, node.pod.Name, err)
+		}
+	}
+	return nil
+}
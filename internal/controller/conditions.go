@@ -0,0 +1,244 @@
+/*
+Copyright 2025 James.Liu.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	redisv1 "github.com/ybooks240/redis-operator/api/v1"
+)
+
+// +kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch
+
+// workloadObservation 工作负载子资源的观测结果，用于计算统一的状态条件
+type workloadObservation struct {
+	// Desired、Ready 和 Updated 为 Redis Pod 的期望数量、就绪数量和已更新到最新版本的数量
+	Desired, Ready, Updated int32
+	// MinAvailable 对外提供服务所需的最少就绪 Pod 数量
+	MinAvailable int32
+	// Missing 缺失的子资源说明，非空时工作负载降级
+	Missing string
+	// Waiting 等待外部条件时的说明，如等待恢复来源
+	Waiting string
+	// Failure 阻止工作负载继续推进的错误，如恢复失败
+	Failure string
+	// ConfigErrors 配置指令的校验错误
+	ConfigErrors []string
+}
+
+// setCondition 设置状态条件，状态不变时 meta.SetStatusCondition 保留原有的 LastTransitionTime
+func setCondition(conditions *[]metav1.Condition, generation int64, conditionType string, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
+
+// parseStorageSize 解析 spec 中的存储容量，无法解析时返回 validation 分类的错误而不是像 resource.MustParse 一样使控制器崩溃
+func parseStorageSize(field, size string) (resource.Quantity, error) {
+	quantity, err := resource.ParseQuantity(size)
+	if err != nil {
+		return resource.Quantity{}, specError(fmt.Errorf("invalid %s %q: %w", field, size, err))
+	}
+	return quantity, nil
+}
+
+// reportInvalidSpec 协调因 spec 无效失败时将 Ready 置为 False、Degraded 置为 True 并写回状态，
+// spec 修正后下一次状态更新会重新计算这些条件
+func reportInvalidSpec(ctx context.Context, c client.Client, obj client.Object, conditions *[]metav1.Condition, specErr error) error {
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+	setCondition(conditions, obj.GetGeneration(), redisv1.ConditionReady, metav1.ConditionFalse, "InvalidSpec", specErr.Error())
+	setCondition(conditions, obj.GetGeneration(), redisv1.ConditionDegraded, metav1.ConditionTrue, "InvalidSpec", specErr.Error())
+	return c.Status().Patch(ctx, obj, patch)
+}
+
+func conditionStatus(value bool) metav1.ConditionStatus {
+	if value {
+		return metav1.ConditionTrue
+	}
+	return metav1.ConditionFalse
+}
+
+// setWorkloadConditions 根据观测结果设置 Ready、Available、Progressing、Degraded 和 ConfigApplied 条件，
+// 返回工作负载是否就绪
+func setWorkloadConditions(conditions *[]metav1.Condition, generation int64, observed workloadObservation) bool {
+	replicas := fmt.Sprintf("%d/%d pods ready, %d/%d updated", observed.Ready, observed.Desired, observed.Updated, observed.Desired)
+	blocked := observed.Missing != "" || observed.Failure != ""
+	rolledOut := observed.Updated >= observed.Desired
+	allReady := observed.Ready >= observed.Desired
+
+	// Available
+	available := !blocked && observed.Waiting == "" && observed.MinAvailable > 0 && observed.Ready >= observed.MinAvailable
+	if available {
+		setCondition(conditions, generation, redisv1.ConditionAvailable, metav1.ConditionTrue, "MinimumPodsAvailable", replicas)
+	} else {
+		setCondition(conditions, generation, redisv1.ConditionAvailable, metav1.ConditionFalse, "MinimumPodsUnavailable",
+			fmt.Sprintf("%s, at least %d required", replicas, observed.MinAvailable))
+	}
+
+	// Progressing
+	switch {
+	case blocked:
+		setCondition(conditions, generation, redisv1.ConditionProgressing, metav1.ConditionFalse, "Blocked", firstNonEmpty(observed.Failure, observed.Missing))
+	case observed.Waiting != "":
+		setCondition(conditions, generation, redisv1.ConditionProgressing, metav1.ConditionTrue, "Waiting", observed.Waiting)
+	case !rolledOut:
+		setCondition(conditions, generation, redisv1.ConditionProgressing, metav1.ConditionTrue, "RollingUpdate", replicas)
+	case !allReady:
+		setCondition(conditions, generation, redisv1.ConditionProgressing, metav1.ConditionTrue, "WaitingForPods", replicas)
+	default:
+		setCondition(conditions, generation, redisv1.ConditionProgressing, metav1.ConditionFalse, "RolloutComplete", replicas)
+	}
+
+	// Degraded，滚动更新过程中 Pod 暂时未就绪不视为降级
+	switch {
+	case observed.Failure != "":
+		setCondition(conditions, generation, redisv1.ConditionDegraded, metav1.ConditionTrue, "Failed", observed.Failure)
+	case observed.Missing != "":
+		setCondition(conditions, generation, redisv1.ConditionDegraded, metav1.ConditionTrue, "ResourceMissing", observed.Missing)
+	case rolledOut && !allReady && observed.Waiting == "":
+		setCondition(conditions, generation, redisv1.ConditionDegraded, metav1.ConditionTrue, "PodsUnavailable", replicas)
+	default:
+		setCondition(conditions, generation, redisv1.ConditionDegraded, metav1.ConditionFalse, "AsExpected", replicas)
+	}
+
+	// ConfigApplied
+	switch {
+	case len(observed.ConfigErrors) > 0:
+		setCondition(conditions, generation, redisv1.ConditionConfigApplied, metav1.ConditionFalse, "InvalidConfig", observed.ConfigErrors[0])
+	case blocked || observed.Waiting != "":
+		setCondition(conditions, generation, redisv1.ConditionConfigApplied, metav1.ConditionFalse, "Pending", firstNonEmpty(observed.Failure, observed.Missing, observed.Waiting))
+	case !rolledOut:
+		setCondition(conditions, generation, redisv1.ConditionConfigApplied, metav1.ConditionFalse, "RollingOut",
+			fmt.Sprintf("%d/%d pods run the latest configuration", observed.Updated, observed.Desired))
+	default:
+		setCondition(conditions, generation, redisv1.ConditionConfigApplied, metav1.ConditionTrue, "Applied", "All pods run the latest configuration")
+	}
+
+	// Ready
+	ready := available && rolledOut && allReady
+	if ready {
+		setCondition(conditions, generation, redisv1.ConditionReady, metav1.ConditionTrue, "AllPodsReady", replicas)
+	} else {
+		setCondition(conditions, generation, redisv1.ConditionReady, metav1.ConditionFalse, "NotReady",
+			firstNonEmpty(observed.Failure, observed.Missing, observed.Waiting, replicas))
+	}
+	return ready
+}
+
+// setStorageReadyCondition 检查 Redis Pod 挂载的 PVC 是否已绑定且没有未完成的扩容
+func setStorageReadyCondition(ctx context.Context, c client.Client, kind string, obj client.Object, conditions *[]metav1.Condition) {
+	generation := obj.GetGeneration()
+	pods, err := listWorkloadRedisPods(ctx, c, kind, obj.GetNamespace(), obj.GetName())
+	if err != nil {
+		setCondition(conditions, generation, redisv1.ConditionStorageReady, metav1.ConditionUnknown, "ListPodsFailed", err.Error())
+		return
+	}
+	if len(pods) == 0 {
+		setCondition(conditions, generation, redisv1.ConditionStorageReady, metav1.ConditionUnknown, "NoPods", "No Redis pods exist yet")
+		return
+	}
+
+	claims := 0
+	for _, pod := range pods {
+		for _, volume := range pod.Spec.Volumes {
+			if volume.PersistentVolumeClaim == nil {
+				continue
+			}
+			claims++
+			pvc := &corev1.PersistentVolumeClaim{}
+			err := c.Get(ctx, types.NamespacedName{Name: volume.PersistentVolumeClaim.ClaimName, Namespace: pod.Namespace}, pvc)
+			if errors.IsNotFound(err) || (err == nil && pvc.Status.Phase != corev1.ClaimBound) {
+				setCondition(conditions, generation, redisv1.ConditionStorageReady, metav1.ConditionFalse, "ClaimPending",
+					fmt.Sprintf("PersistentVolumeClaim %s of pod %s is not bound", volume.PersistentVolumeClaim.ClaimName, pod.Name))
+				return
+			}
+			if err != nil {
+				setCondition(conditions, generation, redisv1.ConditionStorageReady, metav1.ConditionUnknown, "GetClaimFailed", err.Error())
+				return
+			}
+			requested := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+			capacity := pvc.Status.Capacity[corev1.ResourceStorage]
+			if capacity.Cmp(requested) < 0 {
+				setCondition(conditions, generation, redisv1.ConditionStorageReady, metav1.ConditionFalse, "Resizing",
+					fmt.Sprintf("PersistentVolumeClaim %s is expanding from %s to %s", pvc.Name, capacity.String(), requested.String()))
+				return
+			}
+		}
+	}
+
+	if claims == 0 {
+		setCondition(conditions, generation, redisv1.ConditionStorageReady, metav1.ConditionTrue, "EphemeralStorage", "Redis pods do not use PersistentVolumeClaims")
+		return
+	}
+	setCondition(conditions, generation, redisv1.ConditionStorageReady, metav1.ConditionTrue, "Bound",
+		fmt.Sprintf("All %d PersistentVolumeClaims are bound", claims))
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// observeStatefulSets 累加 StatefulSet 的期望、就绪和已更新的 Pod 数量
+func observeStatefulSets(observed *workloadObservation, statefulSets ...*appsv1.StatefulSet) {
+	for _, sts := range statefulSets {
+		desired := int32(1)
+		if sts.Spec.Replicas != nil {
+			desired = *sts.Spec.Replicas
+		}
+		observed.Desired += desired
+		observed.Ready += sts.Status.ReadyReplicas
+		observed.Updated += sts.Status.UpdatedReplicas
+	}
+}
+
+// setUpdatingConditions 资源开始更新时标记 Progressing，等待下一次状态更新重新计算其他条件
+func setUpdatingConditions(conditions *[]metav1.Condition, generation int64, message string) {
+	setCondition(conditions, generation, redisv1.ConditionProgressing, metav1.ConditionTrue, "Updating", message)
+	setCondition(conditions, generation, redisv1.ConditionReady, metav1.ConditionFalse, "Updating", message)
+}
+
+// restoreFailureMessage 恢复数据失败时返回 Restored 条件的消息，否则返回空字符串
+func restoreFailureMessage(conditions []metav1.Condition) string {
+	condition := meta.FindStatusCondition(conditions, redisRestoredCondition)
+	if condition == nil || condition.Status == metav1.ConditionTrue {
+		return ""
+	}
+	switch condition.Reason {
+	case "RestoreFailed", "RestoreRefused", "SlotMapMismatch":
+		return condition.Message
+	}
+	return ""
+}
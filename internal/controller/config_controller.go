@@ -18,51 +18,218 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	redisv1 "github.com/ybooks240/redis-operator/api/v1"
+	"github.com/ybooks240/redis-operator/internal/utils"
 )
 
 // ConfigReconciler reconciles a Config object
 type ConfigReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
 }
 
 // +kubebuilder:rbac:groups=redis.github.com,resources=configs,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=redis.github.com,resources=configs/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=redis.github.com,resources=configs/finalizers,verbs=update
+// +kubebuilder:rbac:groups=redis.github.com,resources=redisinstances;redismasterreplicas;redissentinels;redisclusters,verbs=get;list;watch
 
-// Reconcile is part of the main kubernetes reconciliation loop which aims to
-// move the current state of the cluster closer to the desired state.
-// TODO(user): Modify the Reconcile function to compare the state specified by
-// the Config object against the actual cluster state, and then
-// perform operations to make the cluster state reflect the state specified by
-// the user.
-//
-// For more details, check Reconcile and its Result here:
-// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.21.0/pkg/reconcile
+// Reconcile 计算配置模板的版本，并汇总引用该模板的工作负载及其滚动进度。
+// 配置的生成和应用由各工作负载的控制器完成，规则与内联配置相同
 func (r *ConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logs := logf.FromContext(ctx)
 
 	config := &redisv1.Config{}
 	err := r.Get(ctx, req.NamespacedName, config)
 	if err != nil {
-		logs.Error(err, "unable to fetch Config")
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
-	return ctrl.Result{}, nil
+	revision := configProfileRevision(&config.Spec)
+	// 按最新的指令目录校验模板，工作负载会按各自的 Redis 版本再次校验
+	latestMajor := utils.SupportedRedisMajorVersions[len(utils.SupportedRedisMajorVersions)-1]
+	configErrors := validateConfigProfile(latestMajor, "", &config.Spec)
+	consumers, err := r.configConsumers(ctx, config, revision)
+	if err != nil {
+		logs.Error(err, "Failed to list Config consumers")
+		return ctrl.Result{}, err
+	}
+
+	var previous redisv1.ConfigStatus
+	latestConfig := &redisv1.Config{}
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := r.Get(ctx, types.NamespacedName{Name: config.Name, Namespace: config.Namespace}, latestConfig); err != nil {
+			return err
+		}
+		previous = *latestConfig.Status.DeepCopy()
+		setConfigStatus(&latestConfig.Status, revision, latestConfig.Generation, consumers, configErrors)
+		return r.Status().Update(ctx, latestConfig)
+	})
+	if err != nil {
+		logs.Error(err, "Failed to update Config status")
+		return ctrl.Result{}, err
+	}
+
+	// 记录模板变更和状态变化事件
+	if previous.Revision != "" && previous.Revision != revision {
+		r.Recorder.Eventf(latestConfig, corev1.EventTypeNormal, eventReasonConfigChanged,
+			"Revision changed from %s to %s, rolling out to %d workloads", previous.Revision, revision, len(consumers))
+	}
+	if len(configErrors) > 0 && previous.Status != string(redisv1.ConfigPhaseInvalid) {
+		r.Recorder.Event(latestConfig, corev1.EventTypeWarning, eventReasonInvalidConfig, latestConfig.Status.LastConditionMessage)
+	}
+	recordReadyTransition(r.Recorder, latestConfig, previous.Ready, latestConfig.Status.Ready == "True", latestConfig.Status.LastConditionMessage)
+
+	return ctrl.Result{RequeueAfter: time.Second * 30}, nil
+}
+
+// configConsumers 列出引用配置模板的工作负载，并根据已应用的版本和就绪状态判断滚动进度
+func (r *ConfigReconciler) configConsumers(ctx context.Context, config *redisv1.Config, revision string) ([]redisv1.ConfigConsumer, error) {
+	var consumers []redisv1.ConfigConsumer
+	add := func(kind string, object metav1.Object, ref *corev1.LocalObjectReference, applied, ready string) {
+		if ref == nil || ref.Name != config.Name {
+			return
+		}
+		consumer := redisv1.ConfigConsumer{
+			Kind:            kind,
+			Name:            object.GetName(),
+			AppliedRevision: applied,
+			State:           redisv1.ConfigRolloutApplied,
+		}
+		switch {
+		case applied != revision:
+			consumer.State = redisv1.ConfigRolloutPending
+		case ready != "True":
+			consumer.State = redisv1.ConfigRolloutRollingOut
+		}
+		consumers = append(consumers, consumer)
+	}
+
+	inNamespace := client.InNamespace(config.Namespace)
+	instances := &redisv1.RedisInstanceList{}
+	if err := r.List(ctx, instances, inNamespace); err != nil {
+		return nil, fmt.Errorf("failed to list RedisInstances: %w", err)
+	}
+	for _, instance := range instances.Items {
+		add("RedisInstance", &instance, instance.Spec.ConfigRef, instance.Status.ConfigRevision, instance.Status.Ready)
+	}
+	masterReplicas := &redisv1.RedisMasterReplicaList{}
+	if err := r.List(ctx, masterReplicas, inNamespace); err != nil {
+		return nil, fmt.Errorf("failed to list RedisMasterReplicas: %w", err)
+	}
+	for _, masterReplica := range masterReplicas.Items {
+		add("RedisMasterReplica", &masterReplica, masterReplica.Spec.ConfigRef, masterReplica.Status.ConfigRevision, masterReplica.Status.Ready)
+	}
+	sentinels := &redisv1.RedisSentinelList{}
+	if err := r.List(ctx, sentinels, inNamespace); err != nil {
+		return nil, fmt.Errorf("failed to list RedisSentinels: %w", err)
+	}
+	for _, sentinel := range sentinels.Items {
+		add("RedisSentinel", &sentinel, sentinel.Spec.ConfigRef, sentinel.Status.ConfigRevision, sentinel.Status.Ready)
+	}
+	clusters := &redisv1.RedisClusterList{}
+	if err := r.List(ctx, clusters, inNamespace); err != nil {
+		return nil, fmt.Errorf("failed to list RedisClusters: %w", err)
+	}
+	for _, cluster := range clusters.Items {
+		add("RedisCluster", &cluster, cluster.Spec.ConfigRef, cluster.Status.ConfigRevision, cluster.Status.Ready)
+	}
+
+	sort.Slice(consumers, func(i, j int) bool {
+		if consumers[i].Kind != consumers[j].Kind {
+			return consumers[i].Kind < consumers[j].Kind
+		}
+		return consumers[i].Name < consumers[j].Name
+	})
+	return consumers, nil
+}
+
+// setConfigStatus 根据指令校验结果和工作负载的滚动进度设置配置模板的状态
+func setConfigStatus(status *redisv1.ConfigStatus, revision string, generation int64, consumers []redisv1.ConfigConsumer, configErrors []string) {
+	status.Revision = revision
+	status.ObservedGeneration = generation
+	status.Consumers = consumers
+	meta.SetStatusCondition(&status.Conditions, configValidCondition(configErrors, generation))
+
+	applied := 0
+	for _, consumer := range consumers {
+		if consumer.State == redisv1.ConfigRolloutApplied {
+			applied++
+		}
+	}
+
+	condition := metav1.Condition{
+		Type:   "Ready",
+		Status: metav1.ConditionTrue,
+	}
+	switch {
+	case len(configErrors) > 0:
+		status.Status = string(redisv1.ConfigPhaseInvalid)
+		status.LastConditionMessage = "Invalid configuration: " + configErrors[0]
+		condition.Status = metav1.ConditionFalse
+	case len(consumers) == 0:
+		status.Status = string(redisv1.ConfigPhaseUnused)
+		status.LastConditionMessage = "No workload references this Config"
+	case applied == len(consumers):
+		status.Status = string(redisv1.ConfigPhaseApplied)
+		status.LastConditionMessage = fmt.Sprintf("Revision %s is applied to %d workloads", revision, len(consumers))
+	default:
+		status.Status = string(redisv1.ConfigPhaseRollingOut)
+		status.LastConditionMessage = fmt.Sprintf("Revision %s is applied to %d of %d workloads", revision, applied, len(consumers))
+		condition.Status = metav1.ConditionFalse
+	}
+	status.Ready = string(condition.Status)
+	condition.Reason = status.Status
+	condition.Message = status.LastConditionMessage
+	meta.SetStatusCondition(&status.Conditions, condition)
+}
+
+// configsForWorkload 返回工作负载引用的配置模板，工作负载状态变化后更新滚动进度
+func configsForWorkload(ctx context.Context, obj client.Object) []reconcile.Request {
+	var ref *corev1.LocalObjectReference
+	switch workload := obj.(type) {
+	case *redisv1.RedisInstance:
+		ref = workload.Spec.ConfigRef
+	case *redisv1.RedisMasterReplica:
+		ref = workload.Spec.ConfigRef
+	case *redisv1.RedisSentinel:
+		ref = workload.Spec.ConfigRef
+	case *redisv1.RedisCluster:
+		ref = workload.Spec.ConfigRef
+	}
+	if ref == nil || ref.Name == "" {
+		return nil
+	}
+	return []reconcile.Request{{
+		NamespacedName: types.NamespacedName{Name: ref.Name, Namespace: obj.GetNamespace()},
+	}}
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *ConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&redisv1.Config{}).
+		Watches(&redisv1.RedisInstance{}, handler.EnqueueRequestsFromMapFunc(configsForWorkload)).
+		Watches(&redisv1.RedisMasterReplica{}, handler.EnqueueRequestsFromMapFunc(configsForWorkload)).
+		Watches(&redisv1.RedisSentinel{}, handler.EnqueueRequestsFromMapFunc(configsForWorkload)).
+		Watches(&redisv1.RedisCluster{}, handler.EnqueueRequestsFromMapFunc(configsForWorkload)).
 		Named("config").
-		Complete(r)
+		Complete(instrumentReconciler("Config", r.Client, func() client.Object { return &redisv1.Config{} }, r))
 }
@@ -21,13 +21,17 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	redisv1 "github.com/ybooks240/redis-operator/api/v1"
+	"github.com/ybooks240/redis-operator/internal/utils"
 )
 
 var _ = Describe("Config Controller", func() {
@@ -69,8 +73,9 @@ var _ = Describe("Config Controller", func() {
 		It("should successfully reconcile the resource", func() {
 			By("Reconciling the created resource")
 			controllerReconciler := &ConfigReconciler{
-				Client: k8sClient,
-				Scheme: k8sClient.Scheme(),
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: &record.FakeRecorder{},
 			}
 
 			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
@@ -81,4 +86,176 @@ var _ = Describe("Config Controller", func() {
 			// Example: If you expect a certain status condition after reconciliation, verify it here.
 		})
 	})
+
+	Context("When a workload references the profile", func() {
+		const profileName = "test-profile"
+		const instanceName = "test-profile-instance"
+
+		ctx := context.Background()
+
+		profileKey := types.NamespacedName{Name: profileName, Namespace: "default"}
+		instanceKey := types.NamespacedName{Name: instanceName, Namespace: "default"}
+
+		BeforeEach(func() {
+			profile := &redisv1.Config{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      profileName,
+					Namespace: "default",
+				},
+				Spec: redisv1.ConfigSpec{
+					Redis: map[string]string{
+						"maxmemory":        "100mb",
+						"maxmemory-policy": "volatile-lru",
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, profile)).To(Succeed())
+
+			instance := &redisv1.RedisInstance{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      instanceName,
+					Namespace: "default",
+				},
+				Spec: redisv1.RedisInstanceSpec{
+					Image:     "redis:7.0",
+					Storage:   redisv1.StorageSpec{Size: "1Gi", StorageClassName: "standard"},
+					Config:    map[string]string{"maxmemory": "200mb"},
+					ConfigRef: &corev1.LocalObjectReference{Name: profileName},
+				},
+			}
+			Expect(k8sClient.Create(ctx, instance)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			instance := &redisv1.RedisInstance{}
+			Expect(k8sClient.Get(ctx, instanceKey, instance)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, instance)).To(Succeed())
+
+			profile := &redisv1.Config{}
+			Expect(k8sClient.Get(ctx, profileKey, profile)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, profile)).To(Succeed())
+		})
+
+		It("should merge the profile and report the consumer", func() {
+			instanceReconciler := &RedisInstanceReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: &record.FakeRecorder{},
+			}
+			_, err := instanceReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: instanceKey})
+			Expect(err).NotTo(HaveOccurred())
+
+			configMap := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(ctx, instanceKey, configMap)).To(Succeed())
+			Expect(configMap.Data["redis.conf"]).To(ContainSubstring("maxmemory 200mb"))
+			Expect(configMap.Data["redis.conf"]).To(ContainSubstring("maxmemory-policy volatile-lru"))
+
+			configReconciler := &ConfigReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: &record.FakeRecorder{},
+			}
+			_, err = configReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: profileKey})
+			Expect(err).NotTo(HaveOccurred())
+
+			profile := &redisv1.Config{}
+			Expect(k8sClient.Get(ctx, profileKey, profile)).To(Succeed())
+			Expect(profile.Status.Revision).To(Equal(configProfileRevision(&profile.Spec)))
+			Expect(profile.Status.Consumers).To(HaveLen(1))
+			Expect(profile.Status.Consumers[0].Kind).To(Equal("RedisInstance"))
+			Expect(profile.Status.Consumers[0].AppliedRevision).To(Equal(profile.Status.Revision))
+			Expect(profile.Status.Consumers[0].State).To(Equal(redisv1.ConfigRolloutRollingOut))
+			Expect(profile.Status.Status).To(Equal(string(redisv1.ConfigPhaseRollingOut)))
+		})
+	})
+
+	Context("When validating redis.conf directives", func() {
+		const instanceName = "test-invalid-config"
+		ctx := context.Background()
+		instanceKey := types.NamespacedName{Name: instanceName, Namespace: "default"}
+
+		AfterEach(func() {
+			instance := &redisv1.RedisInstance{}
+			if err := k8sClient.Get(ctx, instanceKey, instance); err == nil {
+				Expect(k8sClient.Delete(ctx, instance)).To(Succeed())
+			}
+		})
+
+		It("should report unknown directives and invalid values with their path", func() {
+			errs := utils.ValidateRedisConfig(7, map[string]string{
+				"maxmemroy":        "100mb",
+				"maxmemory-policy": "lru",
+				"hz":               "1000",
+				"appendonly":       "true",
+				"save":             "900 1 300",
+				"maxmemory":        "1gb",
+			}, "spec.config")
+			Expect(errs).To(ConsistOf(
+				`spec.config[appendonly]: invalid value "true", must be yes or no`,
+				`spec.config[hz]: invalid value 1000, must be between 1 and 500`,
+				`spec.config[maxmemory-policy]: invalid value "lru", must be one of volatile-lru, allkeys-lru, volatile-lfu, allkeys-lfu, volatile-random, allkeys-random, volatile-ttl, noeviction`,
+				`spec.config[maxmemroy]: unknown directive for Redis 7, did you mean "maxmemory"?`,
+				`spec.config[save]: invalid value "900 1 300", must be pairs of <seconds> <changes>`,
+			))
+		})
+
+		It("should use the catalogue of the image's major version", func() {
+			Expect(utils.RedisMajorVersion("redis:6.2.14-alpine")).To(Equal(6))
+			Expect(utils.RedisMajorVersion("registry:5000/redis:7.2")).To(Equal(7))
+			Expect(utils.RedisMajorVersion("redis:latest")).To(Equal(7))
+			Expect(utils.RedisMajorVersion("redis")).To(Equal(7))
+
+			config := map[string]string{"hash-max-listpack-entries": "128"}
+			Expect(utils.ValidateRedisConfig(7, config, "spec.config")).To(BeEmpty())
+			Expect(utils.ValidateRedisConfig(6, config, "spec.config")).To(ConsistOf(
+				"spec.config[hash-max-listpack-entries]: directive requires Redis 7 or later, image is Redis 6",
+			))
+			Expect(utils.RedisDirectiveCatalogue(7)["maxmemory"].Runtime).To(BeTrue())
+			Expect(utils.RedisDirectiveCatalogue(7)["cluster-enabled"].Runtime).To(BeFalse())
+		})
+
+		It("should validate sentinel directives", func() {
+			Expect(utils.ValidateSentinelConfig(7, map[string]string{
+				"sentinel resolve-hostnames": "yes",
+				"loglevel":                   "notice",
+			}, "spec.sentinel")).To(BeEmpty())
+			Expect(utils.ValidateSentinelConfig(7, map[string]string{
+				"sentinel resolve-hostname": "yes",
+			}, "spec.sentinel")).To(ConsistOf(
+				`spec.sentinel[sentinel resolve-hostname]: unknown directive for Redis 7, did you mean "sentinel resolve-hostnames"?`,
+			))
+		})
+
+		It("should skip resource updates and report the ConfigValid condition", func() {
+			instance := &redisv1.RedisInstance{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      instanceName,
+					Namespace: "default",
+				},
+				Spec: redisv1.RedisInstanceSpec{
+					Image:   "redis:7.0",
+					Storage: redisv1.StorageSpec{Size: "1Gi", StorageClassName: "standard"},
+					Config:  map[string]string{"maxmemroy": "200mb"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, instance)).To(Succeed())
+
+			instanceReconciler := &RedisInstanceReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: &record.FakeRecorder{},
+			}
+			_, err := instanceReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: instanceKey})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = k8sClient.Get(ctx, instanceKey, &corev1.ConfigMap{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+
+			Expect(k8sClient.Get(ctx, instanceKey, instance)).To(Succeed())
+			condition := meta.FindStatusCondition(instance.Status.Conditions, configValidConditionType)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Message).To(ContainSubstring(`spec.config[maxmemroy]`))
+		})
+	})
 })
@@ -0,0 +1,75 @@
+/*
+Copyright 2025 James.Liu.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	redisv1 "github.com/ybooks240/redis-operator/api/v1"
+)
+
+// resolveConfigProfile 读取工作负载引用的配置模板，未引用时返回空模板
+func resolveConfigProfile(ctx context.Context, c client.Client, namespace string, ref *corev1.LocalObjectReference) (*redisv1.ConfigSpec, error) {
+	if ref == nil || ref.Name == "" {
+		return &redisv1.ConfigSpec{}, nil
+	}
+	profile := &redisv1.Config{}
+	if err := c.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, profile); err != nil {
+		return nil, fmt.Errorf("failed to get Config %s: %w", ref.Name, err)
+	}
+	return &profile.Spec, nil
+}
+
+// configProfileRevision 计算配置模板的版本，任意指令变化时版本随之变化
+func configProfileRevision(spec *redisv1.ConfigSpec) string {
+	// map 按 key 排序序列化，结果稳定
+	data, _ := json.Marshal(spec)
+	return fmt.Sprintf("%x", sha256.Sum256(data))[:10]
+}
+
+// renderedConfigRevision 返回协调时实际渲染的配置模板版本，未引用配置模板时返回空
+// 状态更新使用该版本，而不是重新读取配置模板，避免把期间修改的模板版本误记为已应用
+func renderedConfigRevision(ref *corev1.LocalObjectReference, profile *redisv1.ConfigSpec) string {
+	if ref == nil || ref.Name == "" {
+		return ""
+	}
+	return configProfileRevision(profile)
+}
+
+// mergeProfileConfig 按顺序合并配置模板的各个部分，最后合并工作负载的内联配置
+func mergeProfileConfig(inline map[string]string, sections ...map[string]string) map[string]string {
+	merged := map[string]string{}
+	for _, section := range sections {
+		for key, value := range section {
+			merged[key] = value
+		}
+	}
+	for key, value := range inline {
+		merged[key] = value
+	}
+	if len(merged) == 0 {
+		return inline
+	}
+	return merged
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2025 James.Liu.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	redisv1 "github.com/ybooks240/redis-operator/api/v1"
+	"github.com/ybooks240/redis-operator/internal/utils"
+)
+
+// configValidConditionType 配置指令校验结果的状态条件
+const configValidConditionType = "ConfigValid"
+
+// validateConfigProfile 校验配置模板各部分的指令，major 为使用该模板的 Redis 主版本
+func validateConfigProfile(major int, name string, spec *redisv1.ConfigSpec) []string {
+	prefix := "spec"
+	if name != "" {
+		prefix = fmt.Sprintf("Config %s", name)
+	}
+	var errs []string
+	errs = append(errs, utils.ValidateRedisConfig(major, spec.Redis, prefix+".redis")...)
+	errs = append(errs, utils.ValidateRedisConfig(major, spec.Cluster, prefix+".cluster")...)
+	errs = append(errs, utils.ValidateSentinelConfig(major, spec.Sentinel, prefix+".sentinel")...)
+	return errs
+}
+
+// workloadConfigErrors 按工作负载镜像的 Redis 主版本校验内联配置和引用的配置模板
+func workloadConfigErrors(ctx context.Context, c client.Client, obj client.Object) []string {
+	var errs []string
+	var major int
+	var configRef *corev1.LocalObjectReference
+
+	switch workload := obj.(type) {
+	case *redisv1.RedisInstance:
+		major, configRef = utils.RedisMajorVersion(workload.Spec.Image), workload.Spec.ConfigRef
+		errs = append(errs, utils.ValidateRedisConfig(major, workload.Spec.Config, "spec.config")...)
+	case *redisv1.RedisMasterReplica:
+		major, configRef = utils.RedisMajorVersion(workload.Spec.Image), workload.Spec.ConfigRef
+		errs = append(errs, utils.ValidateRedisConfig(major, workload.Spec.Config, "spec.config")...)
+		errs = append(errs, utils.ValidateRedisConfig(major, workload.Spec.Master.Config, "spec.master.config")...)
+		errs = append(errs, utils.ValidateRedisConfig(major, workload.Spec.Replica.Config, "spec.replica.config")...)
+	case *redisv1.RedisSentinel:
+		major, configRef = utils.RedisMajorVersion(workload.Spec.Image), workload.Spec.ConfigRef
+		errs = append(errs, utils.ValidateSentinelConfig(major, workload.Spec.Config.AdditionalConfig, "spec.config.additionalConfig")...)
+		errs = append(errs, utils.ValidateRedisConfig(major, workload.Spec.Redis.Config, "spec.redis.config")...)
+		errs = append(errs, utils.ValidateRedisConfig(major, workload.Spec.Redis.Master.Config, "spec.redis.master.config")...)
+		errs = append(errs, utils.ValidateRedisConfig(major, workload.Spec.Redis.Replica.Config, "spec.redis.replica.config")...)
+	case *redisv1.RedisCluster:
+		major, configRef = utils.RedisMajorVersion(workload.Spec.Image), workload.Spec.ConfigRef
+		errs = append(errs, utils.ValidateRedisConfig(major, workload.Spec.Config.AdditionalConfig, "spec.config.additionalConfig")...)
+	}
+
+	if configRef == nil || configRef.Name == "" {
+		return errs
+	}
+	profile, err := resolveConfigProfile(ctx, c, obj.GetNamespace(), configRef)
+	if err != nil {
+		return append(errs, fmt.Sprintf("spec.configRef: %v", err))
+	}
+	return append(errs, validateConfigProfile(major, configRef.Name, profile)...)
+}
+
+// configValidCondition 根据校验错误生成状态条件
+func configValidCondition(errs []string, generation int64) metav1.Condition {
+	if len(errs) > 0 {
+		return metav1.Condition{
+			Type:               configValidConditionType,
+			Status:             metav1.ConditionFalse,
+			Reason:             "InvalidDirectives",
+			Message:            strings.Join(errs, "; "),
+			ObservedGeneration: generation,
+		}
+	}
+	return metav1.Condition{
+		Type:               configValidConditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             "DirectivesValid",
+		Message:            "All configuration directives are valid",
+		ObservedGeneration: generation,
+	}
+}
+
+// setConfigValidCondition 校验工作负载的配置指令并更新状态条件，返回校验错误
+func setConfigValidCondition(ctx context.Context, c client.Client, obj client.Object, conditions *[]metav1.Condition) []string {
+	errs := workloadConfigErrors(ctx, c, obj)
+	meta.SetStatusCondition(conditions, configValidCondition(errs, obj.GetGeneration()))
+	return errs
+}
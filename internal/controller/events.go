@@ -0,0 +1,106 @@
+/*
+Copyright 2025 James.Liu.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+
+	redisv1 "github.com/ybooks240/redis-operator/api/v1"
+)
+
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// 事件原因，作为稳定的标识供告警规则和 kubectl describe 使用
+const (
+	// eventReasonCreated 创建了子资源
+	eventReasonCreated = "Created"
+	// eventReasonConfigChanged 渲染后的配置发生变化
+	eventReasonConfigChanged = "ConfigChanged"
+	// eventReasonInvalidConfig 配置指令校验失败，跳过资源更新
+	eventReasonInvalidConfig = "InvalidConfig"
+	// eventReasonRollingUpdate 开始滚动更新 StatefulSet
+	eventReasonRollingUpdate = "RollingUpdate"
+	// eventReasonStatefulSetRecreated 删除并重建 StatefulSet
+	eventReasonStatefulSetRecreated = "StatefulSetRecreated"
+	// eventReasonStorageExpanded 扩容了 PVC
+	eventReasonStorageExpanded = "StorageExpanded"
+	// eventReasonStorageShrinkRejected 拒绝缩小存储
+	eventReasonStorageShrinkRejected = "StorageShrinkRejected"
+	// eventReasonRestorePending 恢复来源未就绪，推迟创建 StatefulSet
+	eventReasonRestorePending = "RestorePending"
+	// eventReasonFailover Sentinel 监控的 master 地址发生切换
+	eventReasonFailover = "Failover"
+	// eventReasonMasterDown Sentinel 判定 master 下线
+	eventReasonMasterDown = "MasterDown"
+	// eventReasonReady 工作负载变为就绪
+	eventReasonReady = "Ready"
+	// eventReasonNotReady 工作负载变为未就绪
+	eventReasonNotReady = "NotReady"
+	// eventReasonCleanup 删除资源时清理了子资源
+	eventReasonCleanup = "CleanedUp"
+	// eventReasonCleanupFailed 删除资源时清理子资源失败
+	eventReasonCleanupFailed = "CleanupFailed"
+	// eventReasonReconcileFailed 创建或更新子资源失败
+	eventReasonReconcileFailed = "ReconcileFailed"
+)
+
+// recordCreated 记录创建子资源的事件
+func recordCreated(recorder record.EventRecorder, obj runtime.Object, child, name string) {
+	recorder.Eventf(obj, corev1.EventTypeNormal, eventReasonCreated, "Created %s %s", child, name)
+}
+
+// recordReadyTransition Ready 状态变化时记录事件，previous 为更新前的 Status.Ready
+func recordReadyTransition(recorder record.EventRecorder, obj runtime.Object, previous string, ready bool, message string) {
+	switch {
+	case ready && previous != "True":
+		recorder.Event(obj, corev1.EventTypeNormal, eventReasonReady, message)
+	case !ready && previous == "True":
+		recorder.Event(obj, corev1.EventTypeWarning, eventReasonNotReady, message)
+	}
+}
+
+// recordMasterTransitions 比较 Sentinel 监控的 master 前后状态，记录故障转移和下线事件
+func recordMasterTransitions(recorder record.EventRecorder, obj runtime.Object, previous, current []redisv1.MonitoredMasterStatus) {
+	known := make(map[string]redisv1.MonitoredMasterStatus, len(previous))
+	for _, master := range previous {
+		known[master.Name] = master
+	}
+	for _, master := range current {
+		// 只有前后两次都从 Sentinel 查询到状态时地址才可比较
+		old, ok := known[master.Name]
+		if !ok || !sentinelReported(old) || !sentinelReported(master) {
+			continue
+		}
+		oldAddr, addr := fmt.Sprintf("%s:%d", old.IP, old.Port), fmt.Sprintf("%s:%d", master.IP, master.Port)
+		if oldAddr != addr {
+			recorder.Eventf(obj, corev1.EventTypeWarning, eventReasonFailover,
+				"Master %s failed over from %s to %s", master.Name, oldAddr, addr)
+		}
+		if master.Status == "down" && old.Status != "down" {
+			recorder.Eventf(obj, corev1.EventTypeWarning, eventReasonMasterDown,
+				"Sentinels report master %s at %s as down", master.Name, addr)
+		}
+	}
+}
+
+func sentinelReported(master redisv1.MonitoredMasterStatus) bool {
+	return master.Status == "up" || master.Status == "down"
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2025 James.Liu.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	redisv1 "github.com/ybooks240/redis-operator/api/v1"
+	"github.com/ybooks240/redis-operator/internal/metrics"
+	"github.com/ybooks240/redis-operator/internal/utils"
+)
+
+// syncRedisPodCollectors 为工作负载的每个 Redis Pod 注册指标收集器，直接采集 Pod 而不是经过 Service 随机选择节点
+// 已删除 Pod 的收集器会被回收，retain 为同一资源下需要保留的其他收集器，例如 Sentinel 和 Cluster 收集器
+func syncRedisPodCollectors(ctx context.Context, c client.Client, manager *metrics.MetricsCollectionManager, kind string, obj client.Object, security redisv1.SecuritySpec, retain ...string) error {
+	pods, err := listWorkloadRedisPods(ctx, c, kind, obj.GetNamespace(), obj.GetName())
+	if err != nil {
+		return err
+	}
+	password, err := utils.ReadPassword(ctx, c, obj.GetNamespace(), security, obj.GetName())
+	if err != nil {
+		return fmt.Errorf("failed to read %s password: %w", kind, err)
+	}
+	tlsConfig, err := utils.ClientTLSConfig(ctx, c, obj.GetNamespace(), security.TLS)
+	if err != nil {
+		return fmt.Errorf("failed to load %s TLS certificate: %w", kind, err)
+	}
+
+	for i := range pods {
+		pod := &pods[i]
+		retain = append(retain, pod.Name)
+		// 尚未分配 IP 的 Pod 保留已有收集器，由收集器上报节点不可用
+		if pod.Status.PodIP == "" {
+			continue
+		}
+		manager.SyncRedisCollector(
+			metrics.CollectorKey{UID: obj.GetUID(), Pod: pod.Name},
+			metrics.Endpoint{
+				Addrs:     []string{fmt.Sprintf("%s:%d", pod.Status.PodIP, defaultRedisPort)},
+				Password:  password,
+				TLSConfig: tlsConfig,
+			},
+			obj.GetNamespace(),
+			obj.GetName(),
+			redisPodRole(obj, pod.Name),
+		)
+	}
+	manager.RetainCollectors(obj.GetUID(), retain...)
+	return nil
+}
+
+// redisPodRole 根据资源状态返回 Pod 的角色，收集器在节点不可用时用它作为 role 标签
+// 状态中没有记录的 Pod 返回空字符串，收集器保持最后已知的角色
+func redisPodRole(obj client.Object, pod string) string {
+	switch workload := obj.(type) {
+	case *redisv1.RedisInstance:
+		return "master"
+	case *redisv1.RedisMasterReplica:
+		if workload.Status.Master.PodName == "" {
+			return ""
+		}
+		if workload.Status.Master.PodName == pod {
+			return "master"
+		}
+		return "replica"
+	}
+	return ""
+}
@@ -0,0 +1,230 @@
+/*
+Copyright 2025 James.Liu.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	redisv1 "github.com/ybooks240/redis-operator/api/v1"
+	"github.com/ybooks240/redis-operator/internal/utils"
+)
+
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors,verbs=get;list;watch;create;update;patch;delete
+
+// serviceMonitorGVK Prometheus Operator 的 ServiceMonitor，未安装 CRD 时跳过创建
+var serviceMonitorGVK = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "ServiceMonitor"}
+
+// metricsServiceName 返回 exporter metrics Service 和 ServiceMonitor 的名称
+func metricsServiceName(name string) string {
+	return name + "-metrics"
+}
+
+// metricsServiceLabels 返回 metrics Service 的标签，ServiceMonitor 通过这些标签选择 Service
+func metricsServiceLabels(name string) map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/name":       "redis",
+		"app.kubernetes.io/instance":   name,
+		"app.kubernetes.io/component":  "metrics",
+		"app.kubernetes.io/managed-by": "redis-operator",
+	}
+}
+
+// ensureMonitoring 确保工作负载的 exporter 密码文件、metrics Service、ServiceMonitor、PrometheusRule 和慢日志读取与监控配置一致
+func ensureMonitoring(ctx context.Context, c client.Client, scheme *runtime.Scheme, recorder record.EventRecorder, kind string, owner client.Object,
+	security redisv1.SecuritySpec, monitoring *redisv1.MonitoringSpec, topology alertTopology) error {
+	if err := ensureExporterAuthSecret(ctx, c, scheme, owner, security, monitoring); err != nil {
+		return err
+	}
+	if err := ensureExporterService(ctx, c, scheme, recorder, kind, owner, monitoring); err != nil {
+		return err
+	}
+	var alerts *redisv1.AlertsSpec
+	var slowlog *redisv1.SlowlogSpec
+	if monitoring != nil {
+		alerts = monitoring.Alerts
+		slowlog = monitoring.Slowlog
+	}
+	if err := ensurePrometheusRule(ctx, c, scheme, recorder, owner, alerts, topology); err != nil {
+		return err
+	}
+	return ensureSlowlog(ctx, c, scheme, recorder, kind, owner, slowlog)
+}
+
+// ensureExporterService 启用 exporter sidecar 时为工作负载创建 metrics Service，
+// 集群安装了 ServiceMonitor CRD 时同时创建 ServiceMonitor；停用后删除两者
+func ensureExporterService(ctx context.Context, c client.Client, scheme *runtime.Scheme, recorder record.EventRecorder, kind string, owner client.Object, monitoring *redisv1.MonitoringSpec) error {
+	name := metricsServiceName(owner.GetName())
+	service := &corev1.Service{}
+	service.Name = name
+	service.Namespace = owner.GetNamespace()
+
+	if !utils.MonitoringEnabled(monitoring) {
+		if err := deleteIfExists(ctx, c, service); err != nil {
+			return fmt.Errorf("failed to delete metrics service: %w", err)
+		}
+		return deleteMonitoringObject(ctx, c, serviceMonitorGVK, owner.GetNamespace(), name)
+	}
+
+	selector, err := workloadRedisPodLabels(kind, owner.GetName())
+	if err != nil {
+		return err
+	}
+	labels := metricsServiceLabels(owner.GetName())
+	op, err := controllerutil.CreateOrUpdate(ctx, c, service, func() error {
+		service.Labels = labels
+		service.Spec.Selector = selector
+		service.Spec.Ports = []corev1.ServicePort{{
+			Name:       utils.ExporterPortName,
+			Port:       utils.ExporterPort(monitoring),
+			TargetPort: intstr.FromString(utils.ExporterPortName),
+			Protocol:   corev1.ProtocolTCP,
+		}}
+		return controllerutil.SetControllerReference(owner, service, scheme)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to ensure metrics service: %w", err)
+	}
+	if op == controllerutil.OperationResultCreated {
+		recordCreated(recorder, owner, "Service", name)
+	}
+
+	if monitoring.ServiceMonitor.Disabled {
+		return deleteMonitoringObject(ctx, c, serviceMonitorGVK, owner.GetNamespace(), name)
+	}
+	installed, err := crdInstalled(c, serviceMonitorGVK)
+	if err != nil || !installed {
+		return err
+	}
+
+	serviceMonitor := &unstructured.Unstructured{}
+	serviceMonitor.SetGroupVersionKind(serviceMonitorGVK)
+	serviceMonitor.SetName(name)
+	serviceMonitor.SetNamespace(owner.GetNamespace())
+	op, err = controllerutil.CreateOrUpdate(ctx, c, serviceMonitor, func() error {
+		// 自定义标签用于匹配 Prometheus 的 serviceMonitorSelector，不能覆盖选择 Service 的标签
+		smLabels := maps.Clone(monitoring.ServiceMonitor.Labels)
+		if smLabels == nil {
+			smLabels = map[string]string{}
+		}
+		maps.Copy(smLabels, labels)
+		serviceMonitor.SetLabels(smLabels)
+
+		matchLabels := map[string]interface{}{}
+		for key, value := range labels {
+			matchLabels[key] = value
+		}
+		endpoint := map[string]interface{}{"port": utils.ExporterPortName}
+		if monitoring.ServiceMonitor.Interval != "" {
+			endpoint["interval"] = monitoring.ServiceMonitor.Interval
+		}
+		spec := map[string]interface{}{
+			"selector":          map[string]interface{}{"matchLabels": matchLabels},
+			"namespaceSelector": map[string]interface{}{"matchNames": []interface{}{owner.GetNamespace()}},
+			"endpoints":         []interface{}{endpoint},
+		}
+		if err := unstructured.SetNestedField(serviceMonitor.Object, spec, "spec"); err != nil {
+			return err
+		}
+		return controllerutil.SetControllerReference(owner, serviceMonitor, scheme)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to ensure service monitor: %w", err)
+	}
+	if op == controllerutil.OperationResultCreated {
+		recordCreated(recorder, owner, "ServiceMonitor", name)
+	}
+	return nil
+}
+
+// ensureExporterAuthSecret 启用 exporter 和认证时，将工作负载的密码同步到 exporter 的密码文件 Secret
+// exporter 从挂载的文件读取密码，密码轮换时 kubelet 刷新文件，轮换进入 RemovingPassword 后 exporter 仍能认证
+func ensureExporterAuthSecret(ctx context.Context, c client.Client, scheme *runtime.Scheme, owner client.Object, security redisv1.SecuritySpec, monitoring *redisv1.MonitoringSpec) error {
+	secret := &corev1.Secret{}
+	secret.Name = utils.ExporterAuthSecretName(owner.GetName())
+	secret.Namespace = owner.GetNamespace()
+
+	if !utils.MonitoringEnabled(monitoring) || !security.AuthEnabled {
+		if err := deleteIfExists(ctx, c, secret); err != nil {
+			return fmt.Errorf("failed to delete exporter auth secret: %w", err)
+		}
+		return nil
+	}
+
+	password, err := utils.ReadPassword(ctx, c, owner.GetNamespace(), security, owner.GetName())
+	if err != nil {
+		return fmt.Errorf("failed to read password for exporter: %w", err)
+	}
+	passwordFile, err := utils.ExporterPasswordFile(security.TLS, password)
+	if err != nil {
+		return err
+	}
+	if _, err := controllerutil.CreateOrUpdate(ctx, c, secret, func() error {
+		secret.Data = map[string][]byte{utils.ExporterPasswordFileKey: passwordFile}
+		return controllerutil.SetControllerReference(owner, secret, scheme)
+	}); err != nil {
+		return fmt.Errorf("failed to ensure exporter auth secret: %w", err)
+	}
+	return nil
+}
+
+// deleteIfExists 对象存在时删除，避免每次协调都向 API server 发送删除请求；未安装 CRD 时视为已删除
+func deleteIfExists(ctx context.Context, c client.Client, obj client.Object) error {
+	if err := c.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil
+		}
+		return err
+	}
+	if err := c.Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// crdInstalled 检查集群是否安装了 Prometheus Operator 的 CRD
+func crdInstalled(c client.Client, gvk schema.GroupVersionKind) (bool, error) {
+	_, err := c.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+	if meta.IsNoMatchError(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// deleteMonitoringObject 删除 Prometheus Operator 的资源，未安装 CRD 时视为已删除
+func deleteMonitoringObject(ctx context.Context, c client.Client, gvk schema.GroupVersionKind, namespace, name string) error {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	obj.SetName(name)
+	obj.SetNamespace(namespace)
+	if err := deleteIfExists(ctx, c, obj); err != nil {
+		return fmt.Errorf("failed to delete %s: %w", gvk.Kind, err)
+	}
+	return nil
+}
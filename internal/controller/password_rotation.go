@@ -0,0 +1,505 @@
+/*
+Copyright 2025 James.Liu.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/redis/go-redis/v9"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	redisv1 "github.com/ybooks240/redis-operator/api/v1"
+	"github.com/ybooks240/redis-operator/internal/utils"
+)
+
+const (
+	// passwordPropagationDelay 删除旧密码前的最短等待时间，确保 kubelet 已刷新挂载的密码文件，
+	// 探针和重启的 Pod 使用新密码
+	passwordPropagationDelay = 2 * time.Minute
+
+	// rotationCommandTimeout 密码轮换中单个节点命令的超时时间
+	rotationCommandTimeout = 5 * time.Second
+)
+
+// passwordRotator 执行单个工作负载的密码轮换
+// 轮换由密码 Secret 的 resourceVersion 变化触发，依次执行：
+// 1. 在 default 用户上追加新密码并更新 masterauth，断开复制连接使副本使用新密码重连
+// 2. 更新监控该工作负载的 Sentinel 的 auth-pass
+// 3. 等待复制链路和 Sentinel 使用新密码恢复连接
+// 4. 删除旧密码
+// Redis 当前接受的密码保存在 <name>-redis-auth-active Secret 中，用于连接尚未切换的节点
+type passwordRotator struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Kind     string
+	Owner    client.Object
+	Security redisv1.SecuritySpec
+	logs     logr.Logger
+
+	tlsConfig *tls.Config
+	active    string
+	desired   string
+}
+
+// sentinelMonitor 监控工作负载 master 的 Sentinel 及其使用的 master 名称
+type sentinelMonitor struct {
+	Sentinel   *redisv1.RedisSentinel
+	MasterName string
+}
+
+// reconcilePasswordRotation 检查密码 Secret 是否变化，并推进密码轮换流程
+// current 为工作负载状态中记录的轮换进度，每一步的结果都会写回状态
+func reconcilePasswordRotation(ctx context.Context, c client.Client, scheme *runtime.Scheme, kind string, owner client.Object,
+	security redisv1.SecuritySpec, current *redisv1.PasswordRotationStatus, logs logr.Logger) error {
+	ref := utils.PasswordSecretRef(security, owner.GetName())
+	if ref == nil {
+		return nil
+	}
+
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: owner.GetNamespace()}, secret); err != nil {
+		return fmt.Errorf("failed to get password secret: %w", err)
+	}
+	desired, ok := secret.Data[ref.Key]
+	if !ok {
+		return fmt.Errorf("key %s not found in secret %s/%s", ref.Key, owner.GetNamespace(), ref.Name)
+	}
+
+	rotator := &passwordRotator{
+		Client:   c,
+		Scheme:   scheme,
+		Kind:     kind,
+		Owner:    owner,
+		Security: security,
+		logs:     logs.WithValues("rotation", kind+"/"+owner.GetName()),
+		desired:  string(desired),
+	}
+
+	status := &redisv1.PasswordRotationStatus{}
+	if current != nil {
+		status = current.DeepCopy()
+	}
+
+	// 首次启用认证时记录当前密码，此时无需轮换
+	activeSecret := &corev1.Secret{}
+	err := c.Get(ctx, types.NamespacedName{Name: utils.ActivePasswordSecretName(owner.GetName()), Namespace: owner.GetNamespace()}, activeSecret)
+	if errors.IsNotFound(err) {
+		if err := rotator.saveActivePassword(ctx); err != nil {
+			return err
+		}
+		rotator.setPhase(status, redisv1.PasswordRotationCompleted, "Initial password recorded")
+		status.SecretResourceVersion = secret.ResourceVersion
+		return rotator.patchStatus(ctx, status)
+	} else if err != nil {
+		return err
+	}
+	rotator.active = string(activeSecret.Data[utils.PasswordSecretKey])
+
+	inProgress := status.Phase != "" && status.Phase != redisv1.PasswordRotationCompleted
+	switch {
+	case !inProgress && status.SecretResourceVersion == secret.ResourceVersion:
+		return nil
+	case !inProgress && rotator.active == rotator.desired:
+		// Secret 被修改但密码未变化
+		status.SecretResourceVersion = secret.ResourceVersion
+		rotator.setPhase(status, redisv1.PasswordRotationCompleted, "Password unchanged")
+		return rotator.patchStatus(ctx, status)
+	case !inProgress:
+		rotator.logs.Info("Password secret changed, starting rotation", "secret", ref.Name)
+		rotator.startRotation(status, secret.ResourceVersion, "Adding new password")
+	case status.SecretResourceVersion != secret.ResourceVersion:
+		// 轮换过程中 Secret 再次变化，从头开始，最后一步会清除中间密码
+		rotator.logs.Info("Password secret changed during rotation, restarting", "secret", ref.Name)
+		rotator.startRotation(status, secret.ResourceVersion, "Password secret changed during rotation, restarting")
+	}
+
+	rotator.tlsConfig, err = utils.ClientTLSConfig(ctx, c, owner.GetNamespace(), security.TLS)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+
+	for status.Phase != redisv1.PasswordRotationCompleted {
+		var next redisv1.PasswordRotationPhase
+		var done bool
+		var message string
+
+		switch status.Phase {
+		case redisv1.PasswordRotationAddingPassword:
+			next = redisv1.PasswordRotationUpdatingSentinels
+			done, message, err = rotator.addPassword(ctx)
+		case redisv1.PasswordRotationUpdatingSentinels:
+			next = redisv1.PasswordRotationWaitingForReconnect
+			done, message, err = rotator.updateSentinels(ctx)
+		case redisv1.PasswordRotationWaitingForReconnect:
+			next = redisv1.PasswordRotationRemovingPassword
+			done, message, err = rotator.waitForReconnect(ctx, status)
+		case redisv1.PasswordRotationRemovingPassword:
+			next = redisv1.PasswordRotationCompleted
+			done, message, err = rotator.removeOldPassword(ctx)
+		default:
+			return fmt.Errorf("unknown password rotation phase %q", status.Phase)
+		}
+
+		if err != nil {
+			status.Message = fmt.Sprintf("%s failed: %v", status.Phase, err)
+			if patchErr := rotator.patchStatus(ctx, status); patchErr != nil {
+				return patchErr
+			}
+			return err
+		}
+		if !done {
+			status.Message = message
+			return rotator.patchStatus(ctx, status)
+		}
+		rotator.logs.Info("Password rotation step completed", "phase", status.Phase, "next", next)
+		rotator.setPhase(status, next, message)
+	}
+
+	return rotator.patchStatus(ctx, status)
+}
+
+// startRotation 将状态重置为轮换的第一步
+func (p *passwordRotator) startRotation(status *redisv1.PasswordRotationStatus, resourceVersion, message string) {
+	now := metav1.Now()
+	status.StartTime = &now
+	status.SecretResourceVersion = resourceVersion
+	p.setPhase(status, redisv1.PasswordRotationAddingPassword, message)
+}
+
+// setPhase 更新轮换阶段和切换时间
+func (p *passwordRotator) setPhase(status *redisv1.PasswordRotationStatus, phase redisv1.PasswordRotationPhase, message string) {
+	now := metav1.Now()
+	status.Phase = phase
+	status.Message = message
+	status.LastTransitionTime = &now
+}
+
+// patchStatus 以 merge patch 写入轮换状态，不影响控制器更新的其他状态字段
+func (p *passwordRotator) patchStatus(ctx context.Context, status *redisv1.PasswordRotationStatus) error {
+	data, err := json.Marshal(map[string]interface{}{
+		"status": map[string]interface{}{"passwordRotation": status},
+	})
+	if err != nil {
+		return err
+	}
+	owner, ok := p.Owner.DeepCopyObject().(client.Object)
+	if !ok {
+		return fmt.Errorf("unexpected owner type %T", p.Owner)
+	}
+	return p.Status().Patch(ctx, owner, client.RawPatch(types.MergePatchType, data))
+}
+
+// addPassword 在所有节点的 default 用户上同时保留新旧密码，并将 masterauth 切换为新密码
+// 副本断开与 master 的连接，使用新的 masterauth 重新建立复制
+func (p *passwordRotator) addPassword(ctx context.Context) (bool, string, error) {
+	pods, err := listWorkloadRedisPods(ctx, p.Client, p.Kind, p.Owner.GetNamespace(), p.Owner.GetName())
+	if err != nil {
+		return false, "", err
+	}
+	for i := range pods {
+		if !isPodReady(&pods[i]) || pods[i].Status.PodIP == "" {
+			return false, fmt.Sprintf("Waiting for pod %s to be ready before adding the new password", pods[i].Name), nil
+		}
+	}
+
+	for i := range pods {
+		pod := &pods[i]
+		err := p.withNode(ctx, pod, func(nodeCtx context.Context, node *redis.Client) error {
+			if err := node.Do(nodeCtx, "ACL", "SETUSER", "default", "on", ">"+p.active, ">"+p.desired).Err(); err != nil {
+				return fmt.Errorf("ACL SETUSER default: %w", err)
+			}
+			if err := node.ConfigSet(nodeCtx, "masterauth", p.desired).Err(); err != nil {
+				return fmt.Errorf("CONFIG SET masterauth: %w", err)
+			}
+			return nil
+		})
+		if err != nil {
+			return false, "", fmt.Errorf("pod %s: %w", pod.Name, err)
+		}
+	}
+
+	// 所有节点都接受新密码后再断开复制连接，副本重连时使用新的 masterauth
+	for i := range pods {
+		pod := &pods[i]
+		err := p.withNode(ctx, pod, func(nodeCtx context.Context, node *redis.Client) error {
+			role, err := replicationField(nodeCtx, node, "role")
+			if err != nil || role != "slave" {
+				return err
+			}
+			return node.ClientKillByFilter(nodeCtx, "TYPE", "master").Err()
+		})
+		if err != nil {
+			return false, "", fmt.Errorf("pod %s: failed to reconnect replication: %w", pod.Name, err)
+		}
+	}
+
+	return true, fmt.Sprintf("New password added on %d nodes", len(pods)), nil
+}
+
+// updateSentinels 将监控该工作负载的 Sentinel 的 auth-pass 切换为新密码
+func (p *passwordRotator) updateSentinels(ctx context.Context) (bool, string, error) {
+	monitors, err := p.sentinelMonitors(ctx)
+	if err != nil {
+		return false, "", err
+	}
+
+	updated := 0
+	for _, monitor := range monitors {
+		err := p.forEachSentinel(ctx, monitor, func(sentinelCtx context.Context, sentinelClient *redis.SentinelClient) error {
+			return sentinelClient.Set(sentinelCtx, monitor.MasterName, "auth-pass", p.desired).Err()
+		})
+		if err != nil {
+			return false, "", fmt.Errorf("sentinel %s/%s: %w", monitor.Sentinel.Namespace, monitor.Sentinel.Name, err)
+		}
+		updated++
+	}
+
+	return true, fmt.Sprintf("Updated auth-pass on %d sentinel groups", updated), nil
+}
+
+// waitForReconnect 等待所有副本的复制链路和 Sentinel 的连接恢复
+func (p *passwordRotator) waitForReconnect(ctx context.Context, status *redisv1.PasswordRotationStatus) (bool, string, error) {
+	if status.StartTime != nil && time.Since(status.StartTime.Time) < passwordPropagationDelay {
+		return false, "Waiting for kubelet to refresh the mounted password", nil
+	}
+
+	pods, err := listWorkloadRedisPods(ctx, p.Client, p.Kind, p.Owner.GetNamespace(), p.Owner.GetName())
+	if err != nil {
+		return false, "", err
+	}
+	for i := range pods {
+		pod := &pods[i]
+		if !isPodReady(pod) || pod.Status.PodIP == "" {
+			return false, fmt.Sprintf("Waiting for pod %s to be ready", pod.Name), nil
+		}
+		linkDown := false
+		err := p.withNode(ctx, pod, func(nodeCtx context.Context, node *redis.Client) error {
+			role, err := replicationField(nodeCtx, node, "role")
+			if err != nil || role != "slave" {
+				return err
+			}
+			linkStatus, err := replicationField(nodeCtx, node, "master_link_status")
+			linkDown = linkStatus != "up"
+			return err
+		})
+		if err != nil {
+			return false, "", fmt.Errorf("pod %s: %w", pod.Name, err)
+		}
+		if linkDown {
+			return false, fmt.Sprintf("Waiting for replica %s to reconnect to its master", pod.Name), nil
+		}
+	}
+
+	monitors, err := p.sentinelMonitors(ctx)
+	if err != nil {
+		return false, "", err
+	}
+	for _, monitor := range monitors {
+		disconnected := ""
+		err := p.forEachSentinel(ctx, monitor, func(sentinelCtx context.Context, sentinelClient *redis.SentinelClient) error {
+			info, err := sentinelClient.Master(sentinelCtx, monitor.MasterName).Result()
+			if err != nil {
+				return err
+			}
+			for _, flag := range strings.Split(info["flags"], ",") {
+				if flag == "disconnected" || flag == "s_down" || flag == "o_down" {
+					disconnected = flag
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return false, "", fmt.Errorf("sentinel %s/%s: %w", monitor.Sentinel.Namespace, monitor.Sentinel.Name, err)
+		}
+		if disconnected != "" {
+			return false, fmt.Sprintf("Waiting for sentinel %s/%s to reconnect to master %s (%s)",
+				monitor.Sentinel.Namespace, monitor.Sentinel.Name, monitor.MasterName, disconnected), nil
+		}
+	}
+
+	return true, "Replication and sentinels reconnected with the new password", nil
+}
+
+// removeOldPassword 从所有节点删除旧密码，并记录新密码为当前生效密码
+func (p *passwordRotator) removeOldPassword(ctx context.Context) (bool, string, error) {
+	pods, err := listWorkloadRedisPods(ctx, p.Client, p.Kind, p.Owner.GetNamespace(), p.Owner.GetName())
+	if err != nil {
+		return false, "", err
+	}
+	for i := range pods {
+		pod := &pods[i]
+		// 未就绪的 Pod 重启后只会加载新密码
+		if !isPodReady(pod) || pod.Status.PodIP == "" {
+			continue
+		}
+		err := p.withNode(ctx, pod, func(nodeCtx context.Context, node *redis.Client) error {
+			return node.Do(nodeCtx, "ACL", "SETUSER", "default", "resetpass", ">"+p.desired).Err()
+		})
+		if err != nil {
+			return false, "", fmt.Errorf("pod %s: %w", pod.Name, err)
+		}
+	}
+
+	if err := p.saveActivePassword(ctx); err != nil {
+		return false, "", err
+	}
+	return true, "Password rotated", nil
+}
+
+// saveActivePassword 将新密码记录为 Redis 当前生效的密码
+func (p *passwordRotator) saveActivePassword(ctx context.Context) error {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      utils.ActivePasswordSecretName(p.Owner.GetName()),
+			Namespace: p.Owner.GetNamespace(),
+		},
+	}
+	_, err := controllerutil.CreateOrUpdate(ctx, p.Client, secret, func() error {
+		secret.Labels = map[string]string{
+			"app.kubernetes.io/instance":   p.Owner.GetName(),
+			"app.kubernetes.io/managed-by": "redis-operator",
+		}
+		secret.Type = corev1.SecretTypeOpaque
+		secret.Data = map[string][]byte{utils.PasswordSecretKey: []byte(p.desired)}
+		return controllerutil.SetControllerReference(p.Owner, secret, p.Scheme)
+	})
+	return err
+}
+
+// withNode 连接节点执行操作，依次尝试旧密码和新密码
+func (p *passwordRotator) withNode(ctx context.Context, pod *corev1.Pod, fn func(context.Context, *redis.Client) error) error {
+	nodeCtx, cancel := context.WithTimeout(ctx, rotationCommandTimeout)
+	defer cancel()
+
+	var lastErr error
+	for _, password := range []string{p.active, p.desired} {
+		node := newRedisClient(&redis.Options{
+			Addr:      fmt.Sprintf("%s:%d", pod.Status.PodIP, defaultRedisPort),
+			Password:  password,
+			TLSConfig: p.tlsConfig,
+		})
+		lastErr = node.Ping(nodeCtx).Err()
+		if lastErr == nil {
+			lastErr = fn(nodeCtx, node)
+			node.Close()
+			return lastErr
+		}
+		node.Close()
+	}
+	return lastErr
+}
+
+// forEachSentinel 对 Sentinel 的每个就绪 Pod 执行操作
+func (p *passwordRotator) forEachSentinel(ctx context.Context, monitor sentinelMonitor, fn func(context.Context, *redis.SentinelClient) error) error {
+	pods := &corev1.PodList{}
+	if err := p.List(ctx, pods, client.InNamespace(monitor.Sentinel.Namespace), client.MatchingLabels{
+		"app":       "redis-sentinel",
+		"component": "sentinel",
+		"instance":  monitor.Sentinel.Name,
+	}); err != nil {
+		return fmt.Errorf("failed to list sentinel pods: %w", err)
+	}
+	tlsConfig, err := utils.ClientTLSConfig(ctx, p.Client, monitor.Sentinel.Namespace, monitor.Sentinel.Spec.Security.TLS)
+	if err != nil {
+		return fmt.Errorf("failed to load sentinel TLS certificate: %w", err)
+	}
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if !isPodReady(pod) || pod.Status.PodIP == "" {
+			continue
+		}
+		sentinelClient := newSentinelClient(&redis.Options{
+			Addr:      fmt.Sprintf("%s:26379", pod.Status.PodIP),
+			TLSConfig: tlsConfig,
+		})
+		sentinelCtx, cancel := context.WithTimeout(ctx, sentinelQueryTimeout)
+		err := fn(sentinelCtx, sentinelClient)
+		cancel()
+		sentinelClient.Close()
+		if err != nil {
+			return fmt.Errorf("pod %s: %w", pod.Name, err)
+		}
+	}
+	return nil
+}
+
+// sentinelMonitors 返回监控该工作负载 master 且使用其密码 Secret 的 Sentinel
+// 显式配置了其他 authSecret 的 Sentinel 由用户自行维护
+func (p *passwordRotator) sentinelMonitors(ctx context.Context) ([]sentinelMonitor, error) {
+	switch p.Kind {
+	case "RedisSentinel":
+		sentinel, ok := p.Owner.(*redisv1.RedisSentinel)
+		if !ok || len(monitoredMasters(sentinel)) > 0 {
+			return nil, nil
+		}
+		masterName := sentinel.Spec.Redis.MasterName
+		if masterName == "" {
+			masterName = defaultSentinelMasterName
+		}
+		return []sentinelMonitor{{Sentinel: sentinel, MasterName: masterName}}, nil
+	case "RedisMasterReplica":
+		secretName := utils.PasswordSecretRef(p.Security, p.Owner.GetName()).Name
+		sentinels := &redisv1.RedisSentinelList{}
+		if err := p.List(ctx, sentinels, client.InNamespace(p.Owner.GetNamespace())); err != nil {
+			return nil, fmt.Errorf("failed to list sentinels: %w", err)
+		}
+		var monitors []sentinelMonitor
+		for i := range sentinels.Items {
+			sentinel := &sentinels.Items[i]
+			for _, master := range monitoredMasters(sentinel) {
+				ref := master.MasterReplicaRef
+				if ref == nil || ref.Name != p.Owner.GetName() || masterReplicaRefNamespace(sentinel, ref) != p.Owner.GetNamespace() {
+					continue
+				}
+				if master.AuthSecret != nil && master.AuthSecret.Name != secretName {
+					continue
+				}
+				monitors = append(monitors, sentinelMonitor{Sentinel: sentinel, MasterName: master.Name})
+			}
+		}
+		return monitors, nil
+	default:
+		return nil, nil
+	}
+}
+
+// replicationField 读取 INFO replication 中的字段
+func replicationField(ctx context.Context, node *redis.Client, field string) (string, error) {
+	info, err := node.Info(ctx, "replication").Result()
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(info, "\r\n") {
+		if value, found := strings.CutPrefix(line, field+":"); found {
+			return value, nil
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,268 @@
+/*
+Copyright 2025 James.Liu.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+	"sync"
+	"syscall"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	redisv1 "github.com/ybooks240/redis-operator/api/v1"
+	"github.com/ybooks240/redis-operator/internal/metrics"
+	"github.com/ybooks240/redis-operator/internal/tracing"
+)
+
+// 协调结果，作为 redis_operator_reconcile_total 的 result 标签
+const (
+	reconcileResultSuccess = "success"
+	reconcileResultRequeue = "requeue"
+	reconcileResultError   = "error"
+)
+
+// 协调错误分类，作为 redis_operator_reconcile_errors_total 的 error_type 标签
+const (
+	reconcileErrorAPIConflict      = "api-conflict"
+	reconcileErrorValidation       = "validation"
+	reconcileErrorRedisUnreachable = "redis-unreachable"
+	reconcileErrorStorage          = "storage"
+	reconcileErrorAPI              = "api"
+	reconcileErrorOther            = "other"
+)
+
+// periodicResync 控制器定期检查状态的间隔，短于该间隔的重新入队视为未完成的协调
+const periodicResync = 30 * time.Second
+
+// classifiedError 带有分类的协调错误，用于无法从错误类型判断分类的场景
+type classifiedError struct {
+	errorType string
+	err       error
+}
+
+func (e *classifiedError) Error() string { return e.err.Error() }
+
+func (e *classifiedError) Unwrap() error { return e.err }
+
+// specError 将 spec 中无法解析的值标记为 validation 分类，未启用准入 webhook 时这类值可以写入集群
+func specError(err error) error {
+	return &classifiedError{errorType: reconcileErrorValidation, err: err}
+}
+
+// isSpecError 检查协调错误是否由无效的 spec 引起
+func isSpecError(err error) bool {
+	var classified *classifiedError
+	return errors.As(err, &classified) && classified.errorType == reconcileErrorValidation
+}
+
+// storageError 将 PVC 扩容、缩容等存储错误标记为 storage 分类
+func storageError(err error) error {
+	return &classifiedError{errorType: reconcileErrorStorage, err: err}
+}
+
+// classifyReconcileError 返回协调错误的分类
+func classifyReconcileError(err error) string {
+	var classified *classifiedError
+	var status apierrors.APIStatus
+	var urlErr *url.Error
+	switch {
+	case errors.As(err, &classified):
+		return classified.errorType
+	case apierrors.IsConflict(err):
+		return reconcileErrorAPIConflict
+	case apierrors.IsInvalid(err) || apierrors.IsBadRequest(err):
+		return reconcileErrorValidation
+	// 访问 API Server 的网络错误由 client-go 包装为 url.Error，不属于 Redis 连接失败
+	case errors.As(err, &status) || errors.As(err, &urlErr):
+		return reconcileErrorAPI
+	case isRedisUnreachable(err):
+		return reconcileErrorRedisUnreachable
+	default:
+		return reconcileErrorOther
+	}
+}
+
+// isRedisUnreachable 检查是否为连接 Redis 失败或超时
+func isRedisUnreachable(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// instrumentedReconciler 包装协调器，为每次协调创建 trace，记录协调的耗时、结果和错误分类，以及资源的阶段变化和恢复就绪的耗时
+type instrumentedReconciler struct {
+	kind   string
+	client client.Client
+	// newObject 创建被协调资源的空对象，用于读取资源状态
+	newObject  func() client.Object
+	reconciler reconcile.Reconciler
+
+	mu sync.Mutex
+	// observed 每个资源最近一次观察到的状态，缓存中的状态可能滞后，阶段变化与上次观察比较而不是与协调前比较
+	observed map[types.NamespacedName]observedState
+}
+
+// observedState 资源的状态阶段和 Ready 条件
+type observedState struct {
+	phase string
+	ready *metav1.Condition
+}
+
+// instrumentReconciler 返回记录协调指标的协调器，kind 作为指标的 controller 标签
+func instrumentReconciler(kind string, c client.Client, newObject func() client.Object, r reconcile.Reconciler) reconcile.Reconciler {
+	return &instrumentedReconciler{
+		kind:       kind,
+		client:     c,
+		newObject:  newObject,
+		reconciler: r,
+		observed:   make(map[types.NamespacedName]observedState),
+	}
+}
+
+// Reconcile 实现 reconcile.Reconciler
+func (ir *instrumentedReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	// 每次协调是一个 trace，Kubernetes API 调用和 Redis 命令是其中的子 span
+	ctx, span := tracing.StartSpan(ctx, "Reconcile "+ir.kind,
+		attribute.String("k8s.kind", ir.kind),
+		semconv.K8SNamespaceName(req.Namespace),
+		attribute.String("k8s.object.name", req.Name),
+	)
+	defer func() { tracing.EndSpan(span, err) }()
+	if span.SpanContext().IsSampled() {
+		ctx = logf.IntoContext(ctx, logf.FromContext(ctx).WithValues("traceID", span.SpanContext().TraceID().String()))
+	}
+
+	if obj := ir.get(ctx, req); obj != nil {
+		ir.observe(req.NamespacedName, obj)
+	}
+
+	start := time.Now()
+	result, err = ir.reconciler.Reconcile(ctx, req)
+	duration := time.Since(start).Seconds()
+
+	// 资源已删除时清理协调指标，不再写入新的指标序列
+	obj := ir.get(ctx, req)
+	if obj == nil {
+		ir.mu.Lock()
+		delete(ir.observed, req.NamespacedName)
+		ir.mu.Unlock()
+		metrics.DeleteResourceMetrics(ir.kind, req.Namespace, req.Name)
+		return result, err
+	}
+
+	outcome := reconcileResultSuccess
+	switch {
+	case err != nil:
+		outcome = reconcileResultError
+		metrics.RecordReconcileError(ir.kind, req.Namespace, req.Name, classifyReconcileError(err))
+	case result.Requeue || result.RequeueAfter > 0 && result.RequeueAfter < periodicResync:
+		outcome = reconcileResultRequeue
+	}
+	metrics.RecordReconcile(ir.kind, req.Namespace, req.Name, outcome, duration)
+	span.SetAttributes(attribute.String("reconcile.result", outcome))
+
+	ir.observe(req.NamespacedName, obj)
+	return result, err
+}
+
+// get 读取资源，不存在或读取失败时返回 nil
+func (ir *instrumentedReconciler) get(ctx context.Context, req ctrl.Request) client.Object {
+	obj := ir.newObject()
+	if err := ir.client.Get(ctx, req.NamespacedName, obj); err != nil {
+		return nil
+	}
+	return obj
+}
+
+// observe 与上次观察到的状态比较，记录阶段变化；资源变为就绪时记录从 Ready 条件变为 False 起的耗时
+// 控制器启动后第一次观察到的资源只记录状态
+func (ir *instrumentedReconciler) observe(key types.NamespacedName, obj client.Object) {
+	phase, conditions, ok := resourcePhase(obj)
+	if !ok {
+		return
+	}
+	current := observedState{phase: phase}
+	if ready := meta.FindStatusCondition(conditions, redisv1.ConditionReady); ready != nil {
+		current.ready = ready.DeepCopy()
+	}
+
+	ir.mu.Lock()
+	previous, seen := ir.observed[key]
+	ir.observed[key] = current
+	ir.mu.Unlock()
+	if !seen {
+		return
+	}
+
+	from := previous.phase
+	if from == "" {
+		from = "None"
+	}
+	if current.phase != "" && current.phase != previous.phase {
+		metrics.RecordPhaseTransition(ir.kind, from, current.phase)
+	}
+
+	wasReady := previous.ready != nil && previous.ready.Status == metav1.ConditionTrue
+	isReady := current.ready != nil && current.ready.Status == metav1.ConditionTrue
+	if wasReady || !isReady {
+		return
+	}
+	// 新建的资源还没有 Ready 条件，从创建时间开始计算
+	since := obj.GetCreationTimestamp().Time
+	if previous.ready != nil {
+		since = previous.ready.LastTransitionTime.Time
+	}
+	metrics.ObserveTimeToReady(ir.kind, from, time.Since(since).Seconds())
+}
+
+// resourcePhase 返回资源的状态阶段和状态条件
+func resourcePhase(obj client.Object) (string, []metav1.Condition, bool) {
+	switch o := obj.(type) {
+	case *redisv1.RedisInstance:
+		return o.Status.Status, o.Status.Conditions, true
+	case *redisv1.RedisMasterReplica:
+		return o.Status.Status, o.Status.Conditions, true
+	case *redisv1.RedisSentinel:
+		return o.Status.Status, o.Status.Conditions, true
+	case *redisv1.RedisCluster:
+		return o.Status.Status, o.Status.Conditions, true
+	case *redisv1.Redis:
+		return o.Status.Status, o.Status.Conditions, true
+	case *redisv1.RedisBackup:
+		return o.Status.Status, o.Status.Conditions, true
+	case *redisv1.RedisBackupSchedule:
+		return o.Status.Status, o.Status.Conditions, true
+	case *redisv1.RedisUser:
+		return o.Status.Status, o.Status.Conditions, true
+	case *redisv1.Config:
+		return o.Status.Status, o.Status.Conditions, true
+	default:
+		return "", nil, false
+	}
+}
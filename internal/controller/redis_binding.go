@@ -0,0 +1,255 @@
+/*
+Copyright 2025 James.Liu.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	redisv1 "github.com/ybooks240/redis-operator/api/v1"
+	"github.com/ybooks240/redis-operator/internal/utils"
+)
+
+const (
+	// bindingSecretType 连接 Secret 的类型，符合 servicebinding.io 规范中 "servicebinding.io/<type>" 的约定
+	bindingSecretType corev1.SecretType = "servicebinding.io/redis"
+	// bindingType 和 bindingProvider 为 servicebinding.io 规范要求的 type 和 provider 条目
+	bindingType     = "redis"
+	bindingProvider = "redis-operator"
+
+	// 连接 Secret 中的键名，与 Spring Cloud Bindings 的 Redis 绑定保持一致
+	bindingHostKey            = "host"
+	bindingPortKey            = "port"
+	bindingPasswordKey        = "password"
+	bindingSSLKey             = "ssl"
+	bindingURIKey             = "uri"
+	bindingURLKey             = "url"
+	bindingSentinelMasterKey  = "sentinel.master"
+	bindingSentinelMastersKey = "sentinel.masters"
+	bindingSentinelNodesKey   = "sentinel.nodes"
+	bindingClusterNodesKey    = "cluster.nodes"
+
+	sentinelPort = 26379
+)
+
+// connectionSecretName 返回聚合视图连接 Secret 的名称
+func connectionSecretName(name string) string {
+	return name + "-connection"
+}
+
+// connectionInfo 应用连接工作负载所需的信息
+type connectionInfo struct {
+	// Host 和 Port 为单点访问地址，sentinel 模式下为空，客户端通过 sentinel 发现 master
+	Host string
+	Port int32
+	// SentinelMaster、SentinelMasters 和 SentinelNodes 仅用于 sentinel 模式
+	// SentinelMaster 为默认连接的 master，SentinelMasters 为全部被监控的 master
+	SentinelMaster  string
+	SentinelMasters []string
+	SentinelNodes   []string
+	// ClusterNodes 仅用于 cluster 模式，作为客户端发现集群拓扑的种子节点
+	ClusterNodes []string
+	// Password 保存密码的 Secret，未启用认证时为 nil
+	Password *corev1.SecretKeySelector
+	// TLS 工作负载的 TLS 配置，Secret 位于工作负载所在的命名空间
+	TLS *redisv1.TLSSpec
+}
+
+// serviceHost 返回 Service 在集群内的 DNS 名称
+func serviceHost(service, namespace string) string {
+	return fmt.Sprintf("%s.%s.svc.cluster.local", service, namespace)
+}
+
+// instanceConnectionInfo 返回 RedisInstance 的连接信息
+func instanceConnectionInfo(instance *redisv1.RedisInstance) *connectionInfo {
+	return &connectionInfo{
+		Host:     serviceHost(instance.Name, instance.Namespace),
+		Port:     defaultRedisPort,
+		Password: utils.PasswordSecretRef(instance.Spec.Security, instance.Name),
+		TLS:      instance.Spec.Security.TLS,
+	}
+}
+
+// masterReplicaConnectionInfo 返回 RedisMasterReplica 的连接信息，地址指向 master Service
+func masterReplicaConnectionInfo(masterReplica *redisv1.RedisMasterReplica) *connectionInfo {
+	return &connectionInfo{
+		Host:     serviceHost(masterReplica.Name+"-master-service", masterReplica.Namespace),
+		Port:     defaultRedisPort,
+		Password: utils.PasswordSecretRef(masterReplica.Spec.Security, masterReplica.Name),
+		TLS:      masterReplica.Spec.Security.TLS,
+	}
+}
+
+// clusterConnectionInfo 返回 RedisCluster 的连接信息，种子节点为 StatefulSet 各 Pod 在 headless Service 下的 DNS 名称
+// Pod 重建后 IP 会变化而 DNS 名称不变；StatefulSet 不存在或未关联 headless Service 时使用 Service 地址
+func clusterConnectionInfo(cluster *redisv1.RedisCluster, statefulSet *appsv1.StatefulSet) *connectionInfo {
+	serviceName := cluster.Name + "-service"
+	host := serviceHost(serviceName, cluster.Namespace)
+	var nodes []string
+	if statefulSet != nil && statefulSet.Spec.ServiceName == serviceName && statefulSet.Spec.Replicas != nil {
+		for i := range *statefulSet.Spec.Replicas {
+			podHost := fmt.Sprintf("%s-%d.%s", statefulSet.Name, i, host)
+			nodes = append(nodes, net.JoinHostPort(podHost, strconv.Itoa(defaultRedisPort)))
+		}
+	}
+	if len(nodes) == 0 {
+		nodes = []string{net.JoinHostPort(host, strconv.Itoa(defaultRedisPort))}
+	}
+	return &connectionInfo{
+		Host:         host,
+		Port:         defaultRedisPort,
+		ClusterNodes: nodes,
+		Password:     utils.PasswordSecretRef(cluster.Spec.Security, cluster.Name),
+		TLS:          cluster.Spec.Security.TLS,
+	}
+}
+
+// sentinelConnectionInfo 返回 RedisSentinel 的连接信息
+// 嵌入式 Redis 使用 sentinel 自身的密码；监控多个 master 时发布全部 master 名称，
+// 默认连接的 master 和密码取第一个 master，密码来自其 authSecret
+func sentinelConnectionInfo(sentinel *redisv1.RedisSentinel) *connectionInfo {
+	info := &connectionInfo{
+		SentinelNodes: []string{net.JoinHostPort(serviceHost(sentinel.Name+"-sentinel-service", sentinel.Namespace), strconv.Itoa(sentinelPort))},
+		TLS:           sentinel.Spec.Security.TLS,
+	}
+	if masters := monitoredMasters(sentinel); len(masters) > 0 {
+		for _, master := range masters {
+			info.SentinelMasters = append(info.SentinelMasters, master.Name)
+		}
+		info.SentinelMaster = masters[0].Name
+		info.Password = masters[0].AuthSecret
+		return info
+	}
+	info.SentinelMaster = sentinel.Spec.Redis.MasterName
+	if info.SentinelMaster == "" {
+		info.SentinelMaster = defaultSentinelMasterName
+	}
+	info.SentinelMasters = []string{info.SentinelMaster}
+	info.Password = utils.PasswordSecretRef(sentinel.Spec.Security, sentinel.Name)
+	return info
+}
+
+// ensureConnectionSecret 为聚合视图发布 servicebinding.io 格式的连接 Secret，记录到 status.binding 并设置 BindingReady 条件
+// info 为 nil（底层资源不存在）时删除连接 Secret
+// 视图与工作负载不在同一命名空间时不复制密码，避免通过创建视图读取其他命名空间的密码，此时 BindingReady 为 False
+func (r *RedisReconciler) ensureConnectionSecret(ctx context.Context, redis *redisv1.Redis, namespace string, info *connectionInfo) error {
+	secret := &corev1.Secret{}
+	secret.Name = connectionSecretName(redis.Name)
+	secret.Namespace = redis.Namespace
+
+	if info == nil {
+		redis.Status.Binding = nil
+		setCondition(&redis.Status.Conditions, redis.Generation, redisv1.ConditionBindingReady, metav1.ConditionFalse,
+			"ResourceNotFound", "The underlying Redis resource does not exist")
+		if err := r.Delete(ctx, secret); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete connection secret: %w", err)
+		}
+		return nil
+	}
+
+	data, err := r.bindingData(ctx, redis, namespace, info)
+	if err != nil {
+		return err
+	}
+
+	// Secret 类型创建后不可修改，视图自身的 Secret 类型不同时删除后重建
+	existing := &corev1.Secret{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(secret), existing); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get connection secret: %w", err)
+	} else if err == nil && existing.Type != bindingSecretType {
+		if !metav1.IsControlledBy(existing, redis) {
+			return fmt.Errorf("secret %s already exists with type %s and is not managed by this view", existing.Name, existing.Type)
+		}
+		if err := r.Delete(ctx, existing, client.Preconditions{UID: &existing.UID}); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete connection secret with type %s: %w", existing.Type, err)
+		}
+	}
+
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, secret, func() error {
+		secret.Type = bindingSecretType
+		secret.Data = data
+		return controllerutil.SetControllerReference(redis, secret, r.Scheme)
+	}); err != nil {
+		return fmt.Errorf("failed to ensure connection secret: %w", err)
+	}
+
+	redis.Status.Binding = &corev1.LocalObjectReference{Name: secret.Name}
+	if info.Password != nil && namespace != redis.Namespace {
+		setCondition(&redis.Status.Conditions, redis.Generation, redisv1.ConditionBindingReady, metav1.ConditionFalse, "PasswordOmitted",
+			fmt.Sprintf("Password is not copied from namespace %s, applications must read it from secret %s/%s", namespace, namespace, info.Password.Name))
+	} else {
+		setCondition(&redis.Status.Conditions, redis.Generation, redisv1.ConditionBindingReady, metav1.ConditionTrue, "Published", "Connection secret is up to date")
+	}
+	return nil
+}
+
+// bindingData 生成连接 Secret 的内容
+func (r *RedisReconciler) bindingData(ctx context.Context, redis *redisv1.Redis, namespace string, info *connectionInfo) (map[string][]byte, error) {
+	tlsEnabled := info.TLS != nil && info.TLS.Enabled
+	data := map[string][]byte{
+		"type":        []byte(bindingType),
+		"provider":    []byte(bindingProvider),
+		bindingSSLKey: []byte(strconv.FormatBool(tlsEnabled)),
+	}
+
+	scheme := "redis"
+	if tlsEnabled {
+		scheme = "rediss"
+		tlsSecret, err := utils.LoadTLSSecret(ctx, r.Client, namespace, info.TLS)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load TLS secret: %w", err)
+		}
+		data[utils.TLSCAKey] = tlsSecret.Data[utils.TLSCAKey]
+	}
+
+	if info.Host != "" {
+		port := strconv.Itoa(int(info.Port))
+		// uri 为 servicebinding.io 规范的条目，url 为 Spring Cloud Bindings 读取的条目，均不包含密码
+		uri := scheme + "://" + net.JoinHostPort(info.Host, port)
+		data[bindingHostKey] = []byte(info.Host)
+		data[bindingPortKey] = []byte(port)
+		data[bindingURIKey] = []byte(uri)
+		data[bindingURLKey] = []byte(uri)
+	}
+	if len(info.SentinelNodes) > 0 {
+		data[bindingSentinelMasterKey] = []byte(info.SentinelMaster)
+		data[bindingSentinelMastersKey] = []byte(strings.Join(info.SentinelMasters, ","))
+		data[bindingSentinelNodesKey] = []byte(strings.Join(info.SentinelNodes, ","))
+	}
+	if len(info.ClusterNodes) > 0 {
+		data[bindingClusterNodesKey] = []byte(strings.Join(info.ClusterNodes, ","))
+	}
+
+	if info.Password != nil && namespace == redis.Namespace {
+		password, err := utils.ReadSecretValue(ctx, r.Client, namespace, info.Password)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read password: %w", err)
+		}
+		data[bindingPasswordKey] = []byte(password)
+	}
+	return data, nil
+}
@@ -21,6 +21,8 @@ import (
 	"fmt"
 	"time"
 
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -48,6 +50,8 @@ type RedisReconciler struct {
 // +kubebuilder:rbac:groups=redis.github.com,resources=redisinstances,verbs=get;list;watch
 // +kubebuilder:rbac:groups=redis.github.com,resources=redismasterreplicas,verbs=get;list;watch
 // +kubebuilder:rbac:groups=redis.github.com,resources=redissentinels,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -113,6 +117,7 @@ func (r *RedisReconciler) doUpdateRedisStatus(ctx context.Context, redis *redisv
 // updateFromRedisCluster 从 RedisCluster 更新状态
 func (r *RedisReconciler) updateFromRedisCluster(ctx context.Context, redis *redisv1.Redis, namespace string) error {
 	cluster := &redisv1.RedisCluster{}
+	var info *connectionInfo
 	err := r.Get(ctx, types.NamespacedName{Name: redis.Spec.ResourceName, Namespace: namespace}, cluster)
 	if err != nil {
 		if errors.IsNotFound(err) {
@@ -123,6 +128,15 @@ func (r *RedisReconciler) updateFromRedisCluster(ctx context.Context, redis *red
 			return err
 		}
 	} else {
+		// 种子节点使用 StatefulSet 的 Pod DNS 名称，StatefulSet 尚未创建时使用 Service 地址
+		statefulSet := &appsv1.StatefulSet{}
+		if err := r.Get(ctx, types.NamespacedName{Name: cluster.Name, Namespace: namespace}, statefulSet); err != nil {
+			if !errors.IsNotFound(err) {
+				return err
+			}
+			statefulSet = nil
+		}
+		info = clusterConnectionInfo(cluster, statefulSet)
 		redis.Status.Type = "cluster"
 		redis.Status.Ready = cluster.Status.Ready
 		redis.Status.Status = cluster.Status.Status
@@ -137,12 +151,16 @@ func (r *RedisReconciler) updateFromRedisCluster(ctx context.Context, redis *red
 			},
 		}
 	}
+	if err := r.ensureConnectionSecret(ctx, redis, namespace, info); err != nil {
+		return err
+	}
 	return r.Status().Update(ctx, redis)
 }
 
 // updateFromRedisInstance 从 RedisInstance 更新状态
 func (r *RedisReconciler) updateFromRedisInstance(ctx context.Context, redis *redisv1.Redis, namespace string) error {
 	instance := &redisv1.RedisInstance{}
+	var info *connectionInfo
 	err := r.Get(ctx, types.NamespacedName{Name: redis.Spec.ResourceName, Namespace: namespace}, instance)
 	if err != nil {
 		if errors.IsNotFound(err) {
@@ -153,6 +171,7 @@ func (r *RedisReconciler) updateFromRedisInstance(ctx context.Context, redis *re
 			return err
 		}
 	} else {
+		info = instanceConnectionInfo(instance)
 		redis.Status.Type = "instance"
 		redis.Status.Ready = instance.Status.Ready
 		redis.Status.Status = instance.Status.Status
@@ -165,12 +184,16 @@ func (r *RedisReconciler) updateFromRedisInstance(ctx context.Context, redis *re
 			},
 		}
 	}
+	if err := r.ensureConnectionSecret(ctx, redis, namespace, info); err != nil {
+		return err
+	}
 	return r.Status().Update(ctx, redis)
 }
 
 // updateFromRedisMasterReplica 从 RedisMasterReplica 更新状态
 func (r *RedisReconciler) updateFromRedisMasterReplica(ctx context.Context, redis *redisv1.Redis, namespace string) error {
 	masterReplica := &redisv1.RedisMasterReplica{}
+	var info *connectionInfo
 	err := r.Get(ctx, types.NamespacedName{Name: redis.Spec.ResourceName, Namespace: namespace}, masterReplica)
 	if err != nil {
 		if errors.IsNotFound(err) {
@@ -181,6 +204,7 @@ func (r *RedisReconciler) updateFromRedisMasterReplica(ctx context.Context, redi
 			return err
 		}
 	} else {
+		info = masterReplicaConnectionInfo(masterReplica)
 		redis.Status.Type = "masterreplica"
 		redis.Status.Ready = masterReplica.Status.Ready
 		redis.Status.Status = masterReplica.Status.Status
@@ -195,12 +219,16 @@ func (r *RedisReconciler) updateFromRedisMasterReplica(ctx context.Context, redi
 			},
 		}
 	}
+	if err := r.ensureConnectionSecret(ctx, redis, namespace, info); err != nil {
+		return err
+	}
 	return r.Status().Update(ctx, redis)
 }
 
 // updateFromRedisSentinel 从 RedisSentinel 更新状态
 func (r *RedisReconciler) updateFromRedisSentinel(ctx context.Context, redis *redisv1.Redis, namespace string) error {
 	sentinel := &redisv1.RedisSentinel{}
+	var info *connectionInfo
 	err := r.Get(ctx, types.NamespacedName{Name: redis.Spec.ResourceName, Namespace: namespace}, sentinel)
 	if err != nil {
 		if errors.IsNotFound(err) {
@@ -211,6 +239,7 @@ func (r *RedisReconciler) updateFromRedisSentinel(ctx context.Context, redis *re
 			return err
 		}
 	} else {
+		info = sentinelConnectionInfo(sentinel)
 		redis.Status.Type = "sentinel"
 		redis.Status.Ready = sentinel.Status.Ready
 		redis.Status.Status = sentinel.Status.Status
@@ -225,6 +254,9 @@ func (r *RedisReconciler) updateFromRedisSentinel(ctx context.Context, redis *re
 			},
 		}
 	}
+	if err := r.ensureConnectionSecret(ctx, redis, namespace, info); err != nil {
+		return err
+	}
 	return r.Status().Update(ctx, redis)
 }
 
@@ -232,11 +264,12 @@ func (r *RedisReconciler) updateFromRedisSentinel(ctx context.Context, redis *re
 func (r *RedisReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&redisv1.Redis{}).
+		Owns(&corev1.Secret{}).
 		Watches(&redisv1.RedisCluster{}, handler.EnqueueRequestsFromMapFunc(r.mapToRedisRequests)).
 		Watches(&redisv1.RedisInstance{}, handler.EnqueueRequestsFromMapFunc(r.mapToRedisRequests)).
 		Watches(&redisv1.RedisMasterReplica{}, handler.EnqueueRequestsFromMapFunc(r.mapToRedisRequests)).
 		Watches(&redisv1.RedisSentinel{}, handler.EnqueueRequestsFromMapFunc(r.mapToRedisRequests)).
-		Complete(r)
+		Complete(instrumentReconciler("Redis", r.Client, func() client.Object { return &redisv1.Redis{} }, r))
 }
 
 // mapToRedisRequests 将其他 Redis 资源的变化映射到对应的 Redis 聚合资源
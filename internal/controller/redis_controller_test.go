@@ -21,8 +21,11 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -81,4 +84,127 @@ var _ = Describe("Redis Controller", func() {
 			// Example: If you expect a certain status condition after reconciliation, verify it here.
 		})
 	})
+
+	Context("When publishing the connection secret", func() {
+		const instanceName = "test-binding-instance"
+		const viewName = "test-binding-view"
+
+		ctx := context.Background()
+
+		viewNamespacedName := types.NamespacedName{
+			Name:      viewName,
+			Namespace: "default",
+		}
+
+		BeforeEach(func() {
+			password := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: instanceName + "-password", Namespace: "default"},
+				Data:       map[string][]byte{"password": []byte("s3cret")},
+			}
+			Expect(k8sClient.Create(ctx, password)).To(Succeed())
+
+			instance := &redisv1.RedisInstance{
+				ObjectMeta: metav1.ObjectMeta{Name: instanceName, Namespace: "default"},
+				Spec: redisv1.RedisInstanceSpec{
+					Image:   "redis:7.0",
+					Storage: redisv1.StorageSpec{Size: "1Gi", StorageClassName: "standard"},
+					Security: redisv1.SecuritySpec{
+						AuthEnabled: true,
+						PasswordSecret: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: password.Name},
+							Key:                  "password",
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, instance)).To(Succeed())
+
+			view := &redisv1.Redis{
+				ObjectMeta: metav1.ObjectMeta{Name: viewName, Namespace: "default"},
+				Spec:       redisv1.RedisSpec{Type: "instance", ResourceName: instanceName},
+			}
+			Expect(k8sClient.Create(ctx, view)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(k8sClient.Delete(ctx, &redisv1.Redis{ObjectMeta: metav1.ObjectMeta{Name: viewName, Namespace: "default"}})).To(Succeed())
+			Expect(k8sClient.Delete(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: instanceName + "-password", Namespace: "default"}})).To(Succeed())
+			instance := &redisv1.RedisInstance{ObjectMeta: metav1.ObjectMeta{Name: instanceName, Namespace: "default"}}
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, instance))).To(Succeed())
+		})
+
+		It("should publish host, port and password and remove them when the instance is gone", func() {
+			controllerReconciler := &RedisReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: viewNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			secretKey := types.NamespacedName{Name: connectionSecretName(viewName), Namespace: "default"}
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, secretKey, secret)).To(Succeed())
+			Expect(secret.Type).To(Equal(bindingSecretType))
+			Expect(secret.Data).To(HaveKeyWithValue("type", []byte("redis")))
+			Expect(secret.Data).To(HaveKeyWithValue("provider", []byte("redis-operator")))
+			Expect(secret.Data).To(HaveKeyWithValue("host", []byte(instanceName+".default.svc.cluster.local")))
+			Expect(secret.Data).To(HaveKeyWithValue("port", []byte("6379")))
+			Expect(secret.Data).To(HaveKeyWithValue("password", []byte("s3cret")))
+			Expect(secret.Data).To(HaveKeyWithValue("ssl", []byte("false")))
+			Expect(secret.Data).To(HaveKeyWithValue("uri", []byte("redis://"+instanceName+".default.svc.cluster.local:6379")))
+			Expect(secret.Data).NotTo(HaveKey("ca.crt"))
+
+			view := &redisv1.Redis{}
+			Expect(k8sClient.Get(ctx, viewNamespacedName, view)).To(Succeed())
+			Expect(view.Status.Binding).NotTo(BeNil())
+			Expect(view.Status.Binding.Name).To(Equal(secret.Name))
+
+			By("deleting the underlying instance")
+			instance := &redisv1.RedisInstance{ObjectMeta: metav1.ObjectMeta{Name: instanceName, Namespace: "default"}}
+			Expect(k8sClient.Delete(ctx, instance)).To(Succeed())
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: viewNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, secretKey, &corev1.Secret{}))).To(BeTrue())
+			Expect(k8sClient.Get(ctx, viewNamespacedName, view)).To(Succeed())
+			Expect(view.Status.Binding).To(BeNil())
+		})
+
+		It("should describe sentinel and cluster topologies", func() {
+			sentinel := &redisv1.RedisSentinel{ObjectMeta: metav1.ObjectMeta{Name: "cache", Namespace: "apps"}}
+			info := sentinelConnectionInfo(sentinel)
+			Expect(info.Host).To(BeEmpty())
+			Expect(info.SentinelMaster).To(Equal(defaultSentinelMasterName))
+			Expect(info.SentinelNodes).To(ConsistOf("cache-sentinel-service.apps.svc.cluster.local:26379"))
+
+			Expect(info.SentinelMasters).To(Equal([]string{defaultSentinelMasterName}))
+
+			sentinel.Spec.Masters = []redisv1.MonitoredMasterSpec{{Name: "orders"}, {Name: "payments"}}
+			info = sentinelConnectionInfo(sentinel)
+			Expect(info.SentinelMaster).To(Equal("orders"))
+			Expect(info.SentinelMasters).To(Equal([]string{"orders", "payments"}))
+
+			cluster := &redisv1.RedisCluster{ObjectMeta: metav1.ObjectMeta{Name: "shards", Namespace: "apps"}}
+			Expect(clusterConnectionInfo(cluster, nil).ClusterNodes).To(ConsistOf("shards-service.apps.svc.cluster.local:6379"))
+
+			By("using a StatefulSet created before it was bound to the headless service")
+			statefulSet := &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "shards", Namespace: "apps"},
+				Spec:       appsv1.StatefulSetSpec{Replicas: int32Ptr(3)},
+			}
+			Expect(clusterConnectionInfo(cluster, statefulSet).ClusterNodes).To(ConsistOf("shards-service.apps.svc.cluster.local:6379"))
+
+			statefulSet.Spec.ServiceName = "shards-service"
+			Expect(clusterConnectionInfo(cluster, statefulSet).ClusterNodes).To(Equal([]string{
+				"shards-0.shards-service.apps.svc.cluster.local:6379",
+				"shards-1.shards-service.apps.svc.cluster.local:6379",
+				"shards-2.shards-service.apps.svc.cluster.local:6379",
+			}))
+		})
+	})
 })
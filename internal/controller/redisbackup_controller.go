@@ -0,0 +1,790 @@
+/*
+Copyright 2025 James.Liu.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/redis/go-redis/v9"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	redisv1 "github.com/ybooks240/redis-operator/api/v1"
+	"github.com/ybooks240/redis-operator/internal/utils"
+)
+
+const (
+	// redisBackupCompletedCondition 备份已完成
+	redisBackupCompletedCondition = "Completed"
+
+	// backupSnapshotTimeout 等待 BGSAVE 完成的最长时间
+	backupSnapshotTimeout = 30 * time.Minute
+
+	// backupCommandTimeout 备份过程中单次连接节点执行命令（BGSAVE、INFO、CLUSTER NODES）的超时时间
+	backupCommandTimeout = 10 * time.Second
+
+	// defaultMCImage 未指定镜像时上传和删除备份文件使用的 MinIO 客户端镜像，与 CRD 默认值保持一致
+	defaultMCImage = "minio/mc:RELEASE.2024-11-21T17-21-54Z"
+
+	// backupLabel 备份 Job 上记录所属 RedisBackup 的标签
+	backupLabel = "redis.github.com/backup"
+
+	backupWorkDir   = "/work"
+	backupSourceDir = "/source"
+	backupTargetDir = "/backup"
+)
+
+// RedisBackupReconciler reconciles a RedisBackup object
+type RedisBackupReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// backupResult 备份 Job 通过 termination message 返回的 RDB 文件信息
+type backupResult struct {
+	Size     int64  `json:"size"`
+	Checksum string `json:"checksum"`
+}
+
+// +kubebuilder:rbac:groups=redis.github.com,resources=redisbackups,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=redis.github.com,resources=redisbackups/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=redis.github.com,resources=redisbackups/finalizers,verbs=update
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+
+// Reconcile 推进 RedisBackup 的备份流程：选择节点、BGSAVE、由 Job 复制 RDB 文件到目标位置
+func (r *RedisBackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logs := logf.FromContext(ctx)
+
+	redisBackup := &redisv1.RedisBackup{}
+	if err := r.Get(ctx, req.NamespacedName, redisBackup); err != nil {
+		if errors.IsNotFound(err) {
+			logs.Info("RedisBackup not found, ignoring", "name", req.Name)
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if !redisBackup.DeletionTimestamp.IsZero() {
+		if controllerutil.ContainsFinalizer(redisBackup, redisv1.RedisBackupArtifactsFinalizer) {
+			return r.deleteArtifacts(ctx, redisBackup, logs)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	status := redisBackup.Status.DeepCopy()
+	var requeueAfter time.Duration
+	var err error
+
+	switch redisv1.RedisBackupPhase(status.Status) {
+	case redisv1.RedisBackupPhaseCompleted, redisv1.RedisBackupPhaseFailed:
+		// 备份是一次性操作，结束后不再协调
+		return ctrl.Result{}, nil
+	case "", redisv1.RedisBackupPhasePending:
+		requeueAfter, err = r.startSnapshot(ctx, redisBackup, status, logs)
+	case redisv1.RedisBackupPhaseSnapshotting:
+		requeueAfter, err = r.waitForSnapshot(ctx, redisBackup, status, logs)
+	case redisv1.RedisBackupPhaseUploading:
+		requeueAfter, err = r.waitForUpload(ctx, redisBackup, status, logs)
+	}
+	if err != nil {
+		logs.Error(err, "Backup step failed", "phase", status.Status)
+		setBackupPhase(status, redisv1.RedisBackupPhaseFailed, err.Error())
+	}
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		return r.doUpdateRedisBackupStatus(ctx, redisBackup, status)
+	})
+	if err != nil {
+		logs.Error(err, "Failed to update RedisBackup status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// startSnapshot 为每个分片选择节点并执行 BGSAVE，目标未就绪时保持 Pending
+func (r *RedisBackupReconciler) startSnapshot(ctx context.Context, redisBackup *redisv1.RedisBackup, status *redisv1.RedisBackupStatus, logs logr.Logger) (time.Duration, error) {
+	if status.StartTime == nil {
+		now := metav1.Now()
+		status.StartTime = &now
+	}
+
+	ref := redisBackup.Spec.TargetRef
+	workload, err := resolveRedisWorkload(ctx, r.Client, ref.Kind, redisBackup.Namespace, ref.Name)
+	if err != nil {
+		setBackupPhase(status, redisv1.RedisBackupPhasePending, fmt.Sprintf("Waiting for %s %s: %v", ref.Kind, ref.Name, err))
+		return 30 * time.Second, nil
+	}
+
+	shards, err := selectBackupNodes(ctx, workload)
+	if err != nil {
+		setBackupPhase(status, redisv1.RedisBackupPhasePending, fmt.Sprintf("Waiting for %s %s: %v", ref.Kind, ref.Name, err))
+		return 30 * time.Second, nil
+	}
+
+	// 所有分片同时开始 BGSAVE，使集群各分片的快照时间尽量一致
+	for i := range shards {
+		pod := workloadPod(workload, shards[i].Pod)
+		if err := withWorkloadNode(ctx, workload, pod, func(nodeCtx context.Context, node *redis.Client) error {
+			return startBGSave(nodeCtx, node)
+		}); err != nil {
+			return 0, fmt.Errorf("BGSAVE on pod %s: %w", shards[i].Pod, err)
+		}
+		logs.Info("BGSAVE started", "pod", shards[i].Pod, "shard", shards[i].Index)
+	}
+
+	status.Shards = shards
+	status.Image = workload.Image
+	setBackupPhase(status, redisv1.RedisBackupPhaseSnapshotting, fmt.Sprintf("Waiting for BGSAVE on %d nodes", len(shards)))
+	return 5 * time.Second, nil
+}
+
+// waitForSnapshot 等待所有分片的 BGSAVE 完成，然后为每个分片创建复制 RDB 文件的 Job
+func (r *RedisBackupReconciler) waitForSnapshot(ctx context.Context, redisBackup *redisv1.RedisBackup, status *redisv1.RedisBackupStatus, logs logr.Logger) (time.Duration, error) {
+	if time.Since(status.StartTime.Time) > backupSnapshotTimeout {
+		return 0, fmt.Errorf("BGSAVE did not complete within %s", backupSnapshotTimeout)
+	}
+
+	ref := redisBackup.Spec.TargetRef
+	workload, err := resolveRedisWorkload(ctx, r.Client, ref.Kind, redisBackup.Namespace, ref.Name)
+	if err != nil {
+		return 0, err
+	}
+
+	type rdbFile struct {
+		dir      string
+		filename string
+	}
+	files := make([]rdbFile, len(status.Shards))
+	for i := range status.Shards {
+		shard := &status.Shards[i]
+		pod := workloadPod(workload, shard.Pod)
+		if pod == nil {
+			return 0, fmt.Errorf("pod %s disappeared during the backup", shard.Pod)
+		}
+
+		saved := false
+		err := withWorkloadNode(ctx, workload, pod, func(nodeCtx context.Context, node *redis.Client) error {
+			info, err := node.Info(nodeCtx, "persistence").Result()
+			if err != nil {
+				return err
+			}
+			fields := utils.ParseInfo(info)
+			lastSave, _ := strconv.ParseInt(fields["rdb_last_save_time"], 10, 64)
+			switch {
+			case fields["rdb_bgsave_in_progress"] != "0":
+				return nil
+			case lastSave >= status.StartTime.Unix() && fields["rdb_last_bgsave_status"] == "ok":
+				saved = true
+			default:
+				// BGSAVE 被 AOF 重写推迟或执行失败，重新发起
+				if err := startBGSave(nodeCtx, node); err != nil {
+					return err
+				}
+			}
+
+			config, err := node.ConfigGet(nodeCtx, "dir").Result()
+			if err != nil {
+				return err
+			}
+			files[i].dir = config["dir"]
+			config, err = node.ConfigGet(nodeCtx, "dbfilename").Result()
+			if err != nil {
+				return err
+			}
+			files[i].filename = config["dbfilename"]
+			return nil
+		})
+		if err != nil {
+			return 0, fmt.Errorf("pod %s: %w", shard.Pod, err)
+		}
+		if !saved {
+			setBackupPhase(status, redisv1.RedisBackupPhaseSnapshotting, fmt.Sprintf("Waiting for BGSAVE on pod %s", shard.Pod))
+			return 5 * time.Second, nil
+		}
+	}
+
+	for i := range status.Shards {
+		shard := &status.Shards[i]
+		job := r.jobForBackupShard(redisBackup, workload, shard, workloadPod(workload, shard.Pod), files[i].dir, files[i].filename)
+		if err := controllerutil.SetControllerReference(redisBackup, job, r.Scheme); err != nil {
+			return 0, err
+		}
+		if err := r.Create(ctx, job); err != nil && !errors.IsAlreadyExists(err) {
+			return 0, fmt.Errorf("failed to create backup job %s: %w", job.Name, err)
+		}
+		shard.Job = job.Name
+		logs.Info("Backup job created", "job", job.Name, "pod", shard.Pod)
+	}
+
+	setBackupPhase(status, redisv1.RedisBackupPhaseUploading, fmt.Sprintf("Copying %d RDB files", len(status.Shards)))
+	return 10 * time.Second, nil
+}
+
+// waitForUpload 等待所有备份 Job 完成，并从 termination message 读取文件大小和校验和
+func (r *RedisBackupReconciler) waitForUpload(ctx context.Context, redisBackup *redisv1.RedisBackup, status *redisv1.RedisBackupStatus, logs logr.Logger) (time.Duration, error) {
+	for i := range status.Shards {
+		shard := &status.Shards[i]
+		job := &batchv1.Job{}
+		if err := r.Get(ctx, types.NamespacedName{Name: shard.Job, Namespace: redisBackup.Namespace}, job); err != nil {
+			return 0, fmt.Errorf("failed to get backup job %s: %w", shard.Job, err)
+		}
+		for _, condition := range job.Status.Conditions {
+			if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+				return 0, fmt.Errorf("backup job %s failed: %s", job.Name, condition.Message)
+			}
+		}
+		if job.Status.Succeeded == 0 {
+			setBackupPhase(status, redisv1.RedisBackupPhaseUploading, fmt.Sprintf("Waiting for backup job %s", job.Name))
+			return 10 * time.Second, nil
+		}
+
+		result, err := r.backupJobResult(ctx, job)
+		if err != nil {
+			return 0, err
+		}
+		shard.Size = result.Size
+		shard.Checksum = result.Checksum
+	}
+
+	var size int64
+	for _, shard := range status.Shards {
+		size += shard.Size
+	}
+	status.Size = size
+	if len(status.Shards) == 1 {
+		status.Checksum = status.Shards[0].Checksum
+	}
+	now := metav1.Now()
+	status.CompletionTime = &now
+	status.Duration = now.Sub(status.StartTime.Time).Round(time.Second).String()
+	setBackupPhase(status, redisv1.RedisBackupPhaseCompleted, fmt.Sprintf("Backed up %d shards (%d bytes) in %s", len(status.Shards), size, status.Duration))
+	logs.Info("Backup completed", "size", size, "duration", status.Duration)
+	return 0, nil
+}
+
+// backupJobResult 读取备份 Job 中 snapshot 容器输出的文件信息
+func (r *RedisBackupReconciler) backupJobResult(ctx context.Context, job *batchv1.Job) (*backupResult, error) {
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(job.Namespace), client.MatchingLabels{"job-name": job.Name}); err != nil {
+		return nil, fmt.Errorf("failed to list pods of job %s: %w", job.Name, err)
+	}
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodSucceeded {
+			continue
+		}
+		for _, containerStatus := range pod.Status.InitContainerStatuses {
+			if containerStatus.Name != "snapshot" || containerStatus.State.Terminated == nil {
+				continue
+			}
+			result := &backupResult{}
+			if err := json.Unmarshal([]byte(containerStatus.State.Terminated.Message), result); err != nil {
+				return nil, fmt.Errorf("failed to parse result of job %s: %w", job.Name, err)
+			}
+			return result, nil
+		}
+	}
+	return nil, fmt.Errorf("no succeeded pod found for job %s", job.Name)
+}
+
+// jobForBackupShard 创建复制单个分片 RDB 文件的 Job
+// 节点数据目录位于 PVC 时，Job 调度到同一节点只读挂载该 PVC 复制 dump.rdb；
+// 使用 emptyDir 时无法从其他 Pod 访问文件，改为通过 redis-cli --rdb 从节点获取快照
+func (r *RedisBackupReconciler) jobForBackupShard(redisBackup *redisv1.RedisBackup, workload *redisWorkload, shard *redisv1.BackupShardStatus,
+	pod *corev1.Pod, dir, filename string) *batchv1.Job {
+	fileName := backupFileName(redisBackup, workload.Kind == "RedisCluster", shard.Index)
+	workFile := path.Join(backupWorkDir, fileName)
+
+	template := corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{backupLabel: redisBackup.Name},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			Volumes: []corev1.Volume{{
+				Name:         "work",
+				VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+			}},
+		},
+	}
+
+	snapshot := corev1.Container{
+		Name:         "snapshot",
+		Image:        workload.Image,
+		VolumeMounts: []corev1.VolumeMount{{Name: "work", MountPath: backupWorkDir}},
+	}
+	copyCommand := ""
+	if claimName, subPath, ok := dataVolumeClaim(pod, dir); ok {
+		template.Spec.NodeName = pod.Spec.NodeName
+		template.Spec.Volumes = append(template.Spec.Volumes, corev1.Volume{
+			Name: "source",
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claimName, ReadOnly: true},
+			},
+		})
+		snapshot.VolumeMounts = append(snapshot.VolumeMounts, corev1.VolumeMount{Name: "source", MountPath: backupSourceDir, ReadOnly: true})
+		copyCommand = fmt.Sprintf("cp %q %q", path.Join(backupSourceDir, subPath, filename), workFile)
+	} else {
+		cliArgs := ""
+		if utils.TLSEnabled(workload.Security) {
+			cliArgs = strings.Join(utils.TLSCLIArgs(), " ") + " "
+		}
+		if ref := utils.PasswordSecretRef(workload.Security, workload.Name); ref != nil {
+			snapshot.Env = append(snapshot.Env, corev1.EnvVar{
+				Name:      "REDISCLI_AUTH",
+				ValueFrom: &corev1.EnvVarSource{SecretKeyRef: ref},
+			})
+		}
+		copyCommand = fmt.Sprintf("redis-cli %s-h %s -p %d --rdb %q", cliArgs, pod.Status.PodIP, defaultRedisPort, workFile)
+	}
+	snapshot.Command = []string{"sh", "-c", fmt.Sprintf(
+		`set -e; %s; SIZE=$(wc -c < %[2]q); SUM=$(sha256sum %[2]q | cut -d' ' -f1); printf '{"size":%%s,"checksum":"%%s"}' "$SIZE" "$SUM" > /dev/termination-log`,
+		copyCommand, workFile)}
+	template.Spec.InitContainers = []corev1.Container{snapshot}
+
+	destination := redisBackup.Spec.Destination
+	switch {
+	case destination.PVC != nil:
+		targetDir := path.Join(backupTargetDir, destination.PVC.Path)
+		template.Spec.Volumes = append(template.Spec.Volumes, corev1.Volume{
+			Name: "backup",
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: destination.PVC.ClaimName},
+			},
+		})
+		template.Spec.Containers = []corev1.Container{{
+			Name:    "upload",
+			Image:   workload.Image,
+			Command: []string{"sh", "-c", fmt.Sprintf("mkdir -p %[1]q && cp %[2]q %[1]q/", targetDir, workFile)},
+			VolumeMounts: []corev1.VolumeMount{
+				{Name: "work", MountPath: backupWorkDir},
+				{Name: "backup", MountPath: backupTargetDir},
+			},
+		}}
+		shard.Location = fmt.Sprintf("pvc://%s/%s", destination.PVC.ClaimName, strings.TrimPrefix(path.Join(destination.PVC.Path, fileName), "/"))
+	case destination.S3 != nil:
+		template.Spec.Containers = []corev1.Container{mcContainer("upload", &destination.S3.ObjectStoreSpec, "cp", workFile, "backup/"+path.Join(destination.S3.Bucket, destination.S3.Prefix, fileName))}
+		template.Spec.Containers[0].VolumeMounts = []corev1.VolumeMount{{Name: "work", MountPath: backupWorkDir}}
+		shard.Location = fmt.Sprintf("s3://%s/%s", destination.S3.Bucket, path.Join(destination.S3.Prefix, fileName))
+	}
+
+	utils.MountTLS(&template, workload.Security.TLS)
+
+	backoffLimit := int32(2)
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-shard-%d", redisBackup.Name, shard.Index),
+			Namespace: redisBackup.Namespace,
+			Labels:    map[string]string{backupLabel: redisBackup.Name},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template:     template,
+		},
+	}
+}
+
+// deleteArtifacts 通过 Job 删除备份文件，完成后移除 finalizer
+func (r *RedisBackupReconciler) deleteArtifacts(ctx context.Context, redisBackup *redisv1.RedisBackup, logs logr.Logger) (ctrl.Result, error) {
+	// 删除 PVC 中的文件复用工作负载的 Redis 镜像，优先使用备份时记录的镜像，工作负载可能已被删除
+	image := redisBackup.Status.Image
+	if image == "" && redisBackup.Spec.Destination.PVC != nil {
+		ref := redisBackup.Spec.TargetRef
+		workload, err := resolveRedisWorkload(ctx, r.Client, ref.Kind, redisBackup.Namespace, ref.Name)
+		if err != nil {
+			// 不阻塞删除，文件需要手动清理
+			logs.Error(err, "Cannot determine the cleanup image, leaving backup files in place", "backup", redisBackup.Name)
+			return r.removeArtifactsFinalizer(ctx, redisBackup)
+		}
+		image = workload.Image
+	}
+
+	job := jobForArtifactCleanup(redisBackup, image)
+	if job == nil {
+		return r.removeArtifactsFinalizer(ctx, redisBackup)
+	}
+
+	existing := &batchv1.Job{}
+	err := r.Get(ctx, types.NamespacedName{Name: job.Name, Namespace: job.Namespace}, existing)
+	if errors.IsNotFound(err) {
+		if err := r.Create(ctx, job); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to create cleanup job %s: %w", job.Name, err)
+		}
+		logs.Info("Artifact cleanup job created", "job", job.Name)
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	failed := false
+	for _, condition := range existing.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			// 不阻塞删除，文件需要手动清理
+			logs.Error(fmt.Errorf("%s", condition.Message), "Artifact cleanup job failed, leaving backup files in place", "job", existing.Name)
+			failed = true
+		}
+	}
+	if !failed && existing.Status.Succeeded == 0 {
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+	if !failed {
+		logs.Info("Backup artifacts deleted", "backup", redisBackup.Name)
+	}
+
+	if err := r.Delete(ctx, existing, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !errors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+	return r.removeArtifactsFinalizer(ctx, redisBackup)
+}
+
+// removeArtifactsFinalizer 移除备份文件清理 finalizer
+func (r *RedisBackupReconciler) removeArtifactsFinalizer(ctx context.Context, redisBackup *redisv1.RedisBackup) (ctrl.Result, error) {
+	controllerutil.RemoveFinalizer(redisBackup, redisv1.RedisBackupArtifactsFinalizer)
+	if err := r.Update(ctx, redisBackup); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// jobForArtifactCleanup 创建删除各分片备份文件的 Job，没有已上传的文件时返回 nil
+func jobForArtifactCleanup(redisBackup *redisv1.RedisBackup, image string) *batchv1.Job {
+	var locations []string
+	for _, shard := range redisBackup.Status.Shards {
+		if shard.Location != "" {
+			locations = append(locations, shard.Location)
+		}
+	}
+	if len(locations) == 0 {
+		return nil
+	}
+
+	template := corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{backupLabel: redisBackup.Name},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+		},
+	}
+
+	destination := redisBackup.Spec.Destination
+	switch {
+	case destination.PVC != nil:
+		command := []string{"rm", "-f"}
+		for _, location := range locations {
+			// pvc://<claim>/<path>
+			_, file, _ := strings.Cut(strings.TrimPrefix(location, "pvc://"), "/")
+			command = append(command, path.Join(backupTargetDir, file))
+		}
+		template.Spec.Volumes = []corev1.Volume{{
+			Name: "backup",
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: destination.PVC.ClaimName},
+			},
+		}}
+		template.Spec.Containers = []corev1.Container{{
+			Name:         "cleanup",
+			Image:        image,
+			Command:      command,
+			VolumeMounts: []corev1.VolumeMount{{Name: "backup", MountPath: backupTargetDir}},
+		}}
+	case destination.S3 != nil:
+		var targets []string
+		for _, location := range locations {
+			targets = append(targets, "backup/"+strings.TrimPrefix(location, "s3://"))
+		}
+		template.Spec.Containers = []corev1.Container{mcContainer("cleanup", &destination.S3.ObjectStoreSpec, "rm", targets...)}
+	default:
+		return nil
+	}
+
+	backoffLimit := int32(2)
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      redisBackup.Name + "-cleanup",
+			Namespace: redisBackup.Namespace,
+			Labels:    map[string]string{backupLabel: redisBackup.Name},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template:     template,
+		},
+	}
+}
+
+// mcContainer 返回执行 MinIO 客户端子命令的容器
+// 凭据只通过环境变量传给 mc alias set，不拼接进 URL，任意字符的凭据都无需转义
+func mcContainer(name string, s3 *redisv1.ObjectStoreSpec, subcommand string, args ...string) corev1.Container {
+	image := s3.Image
+	if image == "" {
+		image = defaultMCImage
+	}
+
+	command := append([]string{"sh", "-c", mcAliasScript(s3) + ` && exec "$@"`, "sh"}, mcCommand(s3, subcommand, args...)...)
+	return corev1.Container{
+		Name:    name,
+		Image:   image,
+		Command: command,
+		Env: []corev1.EnvVar{
+			{Name: "S3_ENDPOINT", Value: strings.TrimSuffix(s3.Endpoint, "/")},
+			{
+				Name: "AWS_ACCESS_KEY_ID",
+				ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: s3.CredentialsSecret,
+					Key:                  "accessKeyId",
+				}},
+			},
+			{
+				Name: "AWS_SECRET_ACCESS_KEY",
+				ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: s3.CredentialsSecret,
+					Key:                  "secretAccessKey",
+				}},
+			},
+			// mc 默认把别名写入 $HOME/.mc，以非 root 用户运行时 HOME 可能不可写
+			{Name: "MC_CONFIG_DIR", Value: "/tmp/.mc"},
+		},
+	}
+}
+
+// mcAliasScript 返回用 mcContainer 环境变量中的地址和凭据注册 backup 别名的 shell 命令
+func mcAliasScript(s3 *redisv1.ObjectStoreSpec) string {
+	script := `mc alias set backup "$S3_ENDPOINT" "$AWS_ACCESS_KEY_ID" "$AWS_SECRET_ACCESS_KEY"`
+	if s3.Insecure {
+		script += " --insecure"
+	}
+	return script + " >/dev/null"
+}
+
+// mcCommand 返回访问 backup 别名的 mc 子命令
+func mcCommand(s3 *redisv1.ObjectStoreSpec, subcommand string, args ...string) []string {
+	command := []string{"mc", subcommand}
+	if s3.Insecure {
+		command = append(command, "--insecure")
+	}
+	return append(command, args...)
+}
+
+// backupFileName 返回分片的 RDB 文件名
+func backupFileName(redisBackup *redisv1.RedisBackup, sharded bool, index int32) string {
+	if sharded {
+		return fmt.Sprintf("%s-shard-%d.rdb", redisBackup.Name, index)
+	}
+	return redisBackup.Name + ".rdb"
+}
+
+// dataVolumeClaim 返回 Redis 数据目录所在的 PVC 以及数据目录在卷中的相对路径
+func dataVolumeClaim(pod *corev1.Pod, dir string) (string, string, bool) {
+	for _, container := range pod.Spec.Containers {
+		for _, mount := range container.VolumeMounts {
+			if dir != mount.MountPath && !strings.HasPrefix(dir, strings.TrimSuffix(mount.MountPath, "/")+"/") {
+				continue
+			}
+			for _, volume := range pod.Spec.Volumes {
+				if volume.Name == mount.Name && volume.PersistentVolumeClaim != nil {
+					return volume.PersistentVolumeClaim.ClaimName, path.Join(mount.SubPath, strings.TrimPrefix(dir, mount.MountPath)), true
+				}
+			}
+		}
+	}
+	return "", "", false
+}
+
+// selectBackupNodes 为每个分片选择执行 BGSAVE 的节点，优先选择复制链路正常的副本
+func selectBackupNodes(ctx context.Context, workload *redisWorkload) ([]redisv1.BackupShardStatus, error) {
+	if workload.Kind == "RedisCluster" {
+		return selectClusterBackupNodes(ctx, workload)
+	}
+
+	var master, replica string
+	for i := range workload.Pods {
+		pod := &workload.Pods[i]
+		if !isPodReady(pod) || pod.Status.PodIP == "" {
+			continue
+		}
+		var fields map[string]string
+		err := withWorkloadNode(ctx, workload, pod, func(nodeCtx context.Context, node *redis.Client) error {
+			info, err := node.Info(nodeCtx, "replication").Result()
+			fields = utils.ParseInfo(info)
+			return err
+		})
+		if err != nil {
+			continue
+		}
+		switch {
+		case fields["role"] == "slave" && fields["master_link_status"] == "up" && replica == "":
+			replica = pod.Name
+		case fields["role"] == "master" && master == "":
+			master = pod.Name
+		}
+	}
+
+	selected := replica
+	if selected == "" {
+		selected = master
+	}
+	if selected == "" {
+		return nil, fmt.Errorf("no ready Redis node found")
+	}
+	return []redisv1.BackupShardStatus{{Index: 0, Pod: selected}}, nil
+}
+
+// selectClusterBackupNodes 根据 CLUSTER NODES 为每个持有槽位的 master 选择一个节点
+func selectClusterBackupNodes(ctx context.Context, workload *redisWorkload) ([]redisv1.BackupShardStatus, error) {
+	podByIP := map[string]string{}
+	var first *corev1.Pod
+	for i := range workload.Pods {
+		pod := &workload.Pods[i]
+		if !isPodReady(pod) || pod.Status.PodIP == "" {
+			continue
+		}
+		podByIP[pod.Status.PodIP] = pod.Name
+		if first == nil {
+			first = pod
+		}
+	}
+	if first == nil {
+		return nil, fmt.Errorf("no ready cluster node found")
+	}
+
+	var nodes []utils.ClusterNode
+	err := withWorkloadNode(ctx, workload, first, func(nodeCtx context.Context, node *redis.Client) error {
+		output, err := node.ClusterNodes(nodeCtx).Result()
+		nodes = utils.ParseClusterNodes(output)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("CLUSTER NODES: %w", err)
+	}
+
+	var shards []redisv1.BackupShardStatus
+	for _, master := range nodes {
+		if !master.IsMaster() || len(master.Slots) == 0 {
+			continue
+		}
+		selected := ""
+		for _, replica := range nodes {
+			if replica.MasterID == master.ID && !replica.Failed() && podByIP[replica.IP()] != "" {
+				selected = podByIP[replica.IP()]
+				break
+			}
+		}
+		if selected == "" {
+			selected = podByIP[master.IP()]
+		}
+		if selected == "" {
+			return nil, fmt.Errorf("no ready node found for shard %s", strings.Join(master.Slots, " "))
+		}
+		shards = append(shards, redisv1.BackupShardStatus{
+			Index: int32(len(shards)),
+			Pod:   selected,
+			Slots: strings.Join(master.Slots, " "),
+		})
+	}
+	if len(shards) == 0 {
+		return nil, fmt.Errorf("cluster has no slots assigned")
+	}
+	return shards, nil
+}
+
+// startBGSave 发起 BGSAVE，AOF 重写进行中时由 Redis 推迟执行
+func startBGSave(ctx context.Context, node *redis.Client) error {
+	err := node.Do(ctx, "BGSAVE", "SCHEDULE").Err()
+	if err != nil && strings.Contains(err.Error(), "already in progress") {
+		return nil
+	}
+	return err
+}
+
+// workloadPod 按名称查找工作负载的 Pod
+func workloadPod(workload *redisWorkload, name string) *corev1.Pod {
+	for i := range workload.Pods {
+		if workload.Pods[i].Name == name {
+			return &workload.Pods[i]
+		}
+	}
+	return nil
+}
+
+// withWorkloadNode 连接工作负载的节点执行操作
+func withWorkloadNode(ctx context.Context, workload *redisWorkload, pod *corev1.Pod, fn func(context.Context, *redis.Client) error) error {
+	if pod == nil {
+		return fmt.Errorf("pod not found")
+	}
+	node := workload.client(pod)
+	defer node.Close()
+	nodeCtx, cancel := context.WithTimeout(ctx, backupCommandTimeout)
+	defer cancel()
+	return fn(nodeCtx, node)
+}
+
+// setBackupPhase 更新备份阶段和状态消息
+func setBackupPhase(status *redisv1.RedisBackupStatus, phase redisv1.RedisBackupPhase, message string) {
+	status.Status = string(phase)
+	status.LastConditionMessage = message
+	status.Ready = "False"
+
+	condition := metav1.Condition{
+		Type:    redisBackupCompletedCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  string(phase),
+		Message: message,
+	}
+	if phase == redisv1.RedisBackupPhaseCompleted {
+		status.Ready = "True"
+		condition.Status = metav1.ConditionTrue
+	}
+	meta.SetStatusCondition(&status.Conditions, condition)
+}
+
+// doUpdateRedisBackupStatus 更新 RedisBackup 的状态
+func (r *RedisBackupReconciler) doUpdateRedisBackupStatus(ctx context.Context, redisBackup *redisv1.RedisBackup, status *redisv1.RedisBackupStatus) error {
+	latestBackup := &redisv1.RedisBackup{}
+	if err := r.Get(ctx, types.NamespacedName{Name: redisBackup.Name, Namespace: redisBackup.Namespace}, latestBackup); err != nil {
+		return fmt.Errorf("failed to get latest RedisBackup: %w", err)
+	}
+	latestBackup.Status = *status
+	return r.Status().Update(ctx, latestBackup)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RedisBackupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&redisv1.RedisBackup{}).
+		Owns(&batchv1.Job{}).
+		Named("redisbackup").
+		Complete(instrumentReconciler("RedisBackup", r.Client, func() client.Object { return &redisv1.RedisBackup{} }, r))
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2025 James.Liu.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	redisv1 "github.com/ybooks240/redis-operator/api/v1"
+)
+
+var _ = Describe("RedisBackup Controller", func() {
+	Context("When reconciling a resource", func() {
+		const resourceName = "test-redisbackup"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		BeforeEach(func() {
+			By("creating the custom resource for the Kind RedisBackup")
+			err := k8sClient.Get(ctx, typeNamespacedName, &redisv1.RedisBackup{})
+			if err != nil && errors.IsNotFound(err) {
+				resource := &redisv1.RedisBackup{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      resourceName,
+						Namespace: "default",
+					},
+					Spec: redisv1.RedisBackupSpec{
+						TargetRef: redisv1.RedisWorkloadRef{Kind: "RedisInstance", Name: "missing-instance"},
+						Destination: redisv1.BackupDestination{
+							S3: &redisv1.S3BackupDestination{
+								ObjectStoreSpec: redisv1.ObjectStoreSpec{
+									Endpoint:          "http://minio.minio:9000",
+									CredentialsSecret: corev1.LocalObjectReference{Name: "missing-secret"},
+								},
+								Bucket: "backups",
+							},
+						},
+					},
+				}
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			resource := &redisv1.RedisBackup{}
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Cleanup the specific resource instance RedisBackup")
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+		})
+
+		It("should report a pending status when the target does not exist", func() {
+			controllerReconciler := &RedisBackupReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			redisBackup := &redisv1.RedisBackup{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, redisBackup)).To(Succeed())
+			Expect(redisBackup.Status.Status).To(Equal(string(redisv1.RedisBackupPhasePending)))
+			Expect(redisBackup.Status.StartTime).NotTo(BeNil())
+		})
+	})
+})
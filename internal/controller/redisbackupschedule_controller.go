@@ -0,0 +1,410 @@
+/*
+Copyright 2025 James.Liu.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	redisv1 "github.com/ybooks240/redis-operator/api/v1"
+	"github.com/ybooks240/redis-operator/internal/metrics"
+	"github.com/ybooks240/redis-operator/internal/utils"
+)
+
+const (
+	// redisBackupScheduleLastBackupCondition 最近一次备份是否成功
+	redisBackupScheduleLastBackupCondition = "LastBackupSucceeded"
+
+	// scheduleCatchUpLimit 计算错过的触发时间时最多向前推进的次数
+	scheduleCatchUpLimit = 10000
+
+	// defaultBackupKeepLast 未设置 keepLast 时保留的最近备份数量，与 CRD 默认值一致
+	defaultBackupKeepLast = 7
+)
+
+// RedisBackupScheduleReconciler reconciles a RedisBackupSchedule object
+type RedisBackupScheduleReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=redis.github.com,resources=redisbackupschedules,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=redis.github.com,resources=redisbackupschedules/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=redis.github.com,resources=redisbackupschedules/finalizers,verbs=update
+// +kubebuilder:rbac:groups=redis.github.com,resources=redisbackups,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile 按 cron 表达式创建 RedisBackup，并按保留策略删除旧备份
+// 创建的 RedisBackup 不设置 owner，删除 RedisBackupSchedule 时已有备份保持不变
+func (r *RedisBackupScheduleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logs := logf.FromContext(ctx)
+
+	schedule := &redisv1.RedisBackupSchedule{}
+	if err := r.Get(ctx, req.NamespacedName, schedule); err != nil {
+		if errors.IsNotFound(err) {
+			logs.Info("RedisBackupSchedule not found, ignoring", "name", req.Name)
+			metrics.DeleteBackupScheduleMetrics(req.Namespace, req.Name)
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	status := schedule.Status.DeepCopy()
+	cron, err := utils.ParseCron(schedule.Spec.Schedule)
+	if err != nil {
+		setBackupSchedulePhase(status, redisv1.RedisBackupSchedulePhaseInvalid, fmt.Sprintf("Invalid schedule: %v", err))
+		status.NextScheduleTime = nil
+		return ctrl.Result{}, r.updateStatus(ctx, schedule, status, logs)
+	}
+
+	backups := &redisv1.RedisBackupList{}
+	if err := r.List(ctx, backups, client.InNamespace(schedule.Namespace),
+		client.MatchingLabels{redisv1.RedisBackupScheduleLabel: schedule.Name}); err != nil {
+		return ctrl.Result{}, err
+	}
+	// 按创建时间从新到旧排序
+	sort.Slice(backups.Items, func(i, j int) bool {
+		return backups.Items[j].CreationTimestamp.Before(&backups.Items[i].CreationTimestamp)
+	})
+
+	recordBackupResults(schedule, status, backups.Items)
+
+	now := time.Now().UTC()
+	lastSchedule := schedule.CreationTimestamp.Time
+	if status.LastScheduleTime != nil {
+		lastSchedule = status.LastScheduleTime.Time
+	}
+	message := ""
+	if due := latestDueTime(cron, lastSchedule, now); !due.IsZero() && !schedule.Spec.Suspend {
+		scheduled := metav1.NewTime(due)
+		status.LastScheduleTime = &scheduled
+		lastSchedule = due
+		if status.ActiveBackup != "" {
+			// 上一次备份未结束时跳过本次触发
+			message = fmt.Sprintf("Skipped backup at %s, backup %s is still running", due.Format(time.RFC3339), status.ActiveBackup)
+			logs.Info("Skipping scheduled backup", "active", status.ActiveBackup)
+		} else {
+			redisBackup, err := r.createBackup(ctx, schedule, due)
+			if err != nil {
+				logs.Error(err, "Failed to create scheduled backup")
+				return ctrl.Result{}, err
+			}
+			status.ActiveBackup = redisBackup.Name
+			logs.Info("Scheduled backup created", "backup", redisBackup.Name)
+		}
+	}
+
+	next := cron.Next(lastSchedule)
+	if next.Before(now) {
+		next = cron.Next(now)
+	}
+	if next.IsZero() {
+		status.NextScheduleTime = nil
+	} else {
+		nextTime := metav1.NewTime(next)
+		status.NextScheduleTime = &nextTime
+	}
+
+	if err := r.pruneBackups(ctx, schedule, backups.Items, logs); err != nil {
+		logs.Error(err, "Failed to prune old backups")
+	}
+
+	phase := redisv1.RedisBackupSchedulePhaseActive
+	if schedule.Spec.Suspend {
+		phase = redisv1.RedisBackupSchedulePhaseSuspended
+	}
+	if message == "" {
+		message = scheduleMessage(status)
+	}
+	setBackupSchedulePhase(status, phase, message)
+
+	if err := r.updateStatus(ctx, schedule, status, logs); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	requeueAfter := 30 * time.Second
+	if status.NextScheduleTime != nil && !schedule.Spec.Suspend {
+		if untilNext := time.Until(status.NextScheduleTime.Time); untilNext < requeueAfter {
+			requeueAfter = untilNext + time.Second
+		}
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// latestDueTime 返回 (last, now] 区间内最近的触发时间，没有则返回零值，错过的多次触发只执行一次
+func latestDueTime(cron *utils.CronSchedule, last, now time.Time) time.Time {
+	var due time.Time
+	for i := 0; i < scheduleCatchUpLimit; i++ {
+		next := cron.Next(last)
+		if next.IsZero() || next.After(now) {
+			return due
+		}
+		due, last = next, next
+	}
+	// 错过的触发过多时返回已推进到的时间，下次协调继续推进
+	return due
+}
+
+// recordBackupResults 根据备份列表更新最近一次成功/失败的备份和正在运行的备份，并记录指标
+func recordBackupResults(schedule *redisv1.RedisBackupSchedule, status *redisv1.RedisBackupScheduleStatus, backups []redisv1.RedisBackup) {
+	status.ActiveBackup = ""
+	var lastSuccess, lastFailure *redisv1.RedisBackup
+	for i := range backups {
+		backup := &backups[i]
+		switch redisv1.RedisBackupPhase(backup.Status.Status) {
+		case redisv1.RedisBackupPhaseCompleted:
+			if lastSuccess == nil {
+				lastSuccess = backup
+			}
+		case redisv1.RedisBackupPhaseFailed:
+			if lastFailure == nil {
+				lastFailure = backup
+			}
+		default:
+			if backup.DeletionTimestamp.IsZero() && status.ActiveBackup == "" {
+				status.ActiveBackup = backup.Name
+			}
+		}
+	}
+
+	if lastSuccess != nil {
+		if lastSuccess.Name != status.LastSuccessfulBackup {
+			status.LastSuccessfulBackup = lastSuccess.Name
+			status.LastSuccessTime = lastSuccess.Status.CompletionTime
+			metrics.IncBackupsTotal(schedule.Namespace, schedule.Name, "success")
+		}
+		var completion, duration float64
+		if lastSuccess.Status.CompletionTime != nil {
+			completion = float64(lastSuccess.Status.CompletionTime.Unix())
+			if lastSuccess.Status.StartTime != nil {
+				duration = lastSuccess.Status.CompletionTime.Sub(lastSuccess.Status.StartTime.Time).Seconds()
+			}
+		}
+		metrics.SetBackupLastSuccess(schedule.Namespace, schedule.Name, completion, duration, float64(lastSuccess.Status.Size))
+	}
+	if lastFailure != nil {
+		if lastFailure.Name != status.LastFailedBackup {
+			status.LastFailedBackup = lastFailure.Name
+			failureTime := lastFailure.CreationTimestamp
+			status.LastFailureTime = &failureTime
+			metrics.IncBackupsTotal(schedule.Namespace, schedule.Name, "failure")
+		}
+		metrics.SetBackupLastFailure(schedule.Namespace, schedule.Name, float64(lastFailure.CreationTimestamp.Unix()))
+	}
+}
+
+// createBackup 为触发时间创建 RedisBackup，名称由触发时间决定，重复协调不会重复创建
+func (r *RedisBackupScheduleReconciler) createBackup(ctx context.Context, schedule *redisv1.RedisBackupSchedule, due time.Time) (*redisv1.RedisBackup, error) {
+	redisBackup := &redisv1.RedisBackup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       fmt.Sprintf("%s-%d", schedule.Name, due.Unix()),
+			Namespace:  schedule.Namespace,
+			Labels:     map[string]string{redisv1.RedisBackupScheduleLabel: schedule.Name},
+			Finalizers: []string{redisv1.RedisBackupArtifactsFinalizer},
+		},
+		Spec: redisv1.RedisBackupSpec{
+			TargetRef:   schedule.Spec.TargetRef,
+			Destination: *schedule.Spec.Destination.DeepCopy(),
+		},
+	}
+	if err := r.Create(ctx, redisBackup); err != nil && !errors.IsAlreadyExists(err) {
+		return nil, err
+	}
+	return redisBackup, nil
+}
+
+// pruneBackups 删除保留策略未选中的已完成备份，失败的备份只保留最近一个
+// 备份带有 finalizer，删除时由 RedisBackup 控制器清理备份文件
+func (r *RedisBackupScheduleReconciler) pruneBackups(ctx context.Context, schedule *redisv1.RedisBackupSchedule, backups []redisv1.RedisBackup, logs logr.Logger) error {
+	var completed []redisv1.RedisBackup
+	keepFailed := ""
+	var prune []redisv1.RedisBackup
+	for _, backup := range backups {
+		if !backup.DeletionTimestamp.IsZero() {
+			continue
+		}
+		switch redisv1.RedisBackupPhase(backup.Status.Status) {
+		case redisv1.RedisBackupPhaseCompleted:
+			completed = append(completed, backup)
+		case redisv1.RedisBackupPhaseFailed:
+			if keepFailed == "" {
+				keepFailed = backup.Name
+			} else {
+				prune = append(prune, backup)
+			}
+		}
+	}
+
+	keep := retainedBackups(completed, schedule.Spec.Retention)
+	for _, backup := range completed {
+		if !keep[backup.Name] {
+			prune = append(prune, backup)
+		}
+	}
+
+	for i := range prune {
+		if err := r.Delete(ctx, &prune[i]); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete backup %s: %w", prune[i].Name, err)
+		}
+		logs.Info("Pruned backup", "backup", prune[i].Name)
+	}
+	return nil
+}
+
+// retainedBackups 返回保留策略选中的备份，backups 需按创建时间从新到旧排序，最新的备份始终保留
+func retainedBackups(backups []redisv1.RedisBackup, retention redisv1.BackupRetention) map[string]bool {
+	keep := map[string]bool{}
+	if len(backups) == 0 {
+		return keep
+	}
+	keep[backups[0].Name] = true
+
+	// 未经过 API server 默认值填充的对象（例如旧版本创建的对象）也按默认值保留
+	keepLast := int32(defaultBackupKeepLast)
+	if retention.KeepLast != nil {
+		keepLast = *retention.KeepLast
+	}
+	for i := 0; i < len(backups) && i < int(keepLast); i++ {
+		keep[backups[i].Name] = true
+	}
+
+	keepPerPeriod := func(limit int32, period func(time.Time) string) {
+		seen := map[string]bool{}
+		for _, backup := range backups {
+			if len(seen) >= int(limit) {
+				return
+			}
+			key := period(backup.CreationTimestamp.UTC())
+			if !seen[key] {
+				seen[key] = true
+				keep[backup.Name] = true
+			}
+		}
+	}
+	keepPerPeriod(retention.KeepDaily, func(t time.Time) string {
+		return t.Format("2006-01-02")
+	})
+	keepPerPeriod(retention.KeepWeekly, func(t time.Time) string {
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-%02d", year, week)
+	})
+	return keep
+}
+
+// scheduleMessage 返回描述最近一次备份结果的状态消息
+func scheduleMessage(status *redisv1.RedisBackupScheduleStatus) string {
+	switch {
+	case status.ActiveBackup != "":
+		return fmt.Sprintf("Backup %s is running", status.ActiveBackup)
+	case lastBackupFailed(status):
+		return fmt.Sprintf("Last backup %s failed", status.LastFailedBackup)
+	case status.LastSuccessfulBackup != "":
+		return fmt.Sprintf("Last backup %s completed", status.LastSuccessfulBackup)
+	default:
+		return "Waiting for the first scheduled backup"
+	}
+}
+
+// lastBackupFailed 判断最近结束的备份是否失败
+func lastBackupFailed(status *redisv1.RedisBackupScheduleStatus) bool {
+	if status.LastFailureTime == nil {
+		return false
+	}
+	return status.LastSuccessTime == nil || status.LastSuccessTime.Before(status.LastFailureTime)
+}
+
+// setBackupSchedulePhase 更新调度阶段和最近一次备份条件
+func setBackupSchedulePhase(status *redisv1.RedisBackupScheduleStatus, phase redisv1.RedisBackupSchedulePhase, message string) {
+	status.Status = string(phase)
+	status.LastConditionMessage = message
+
+	condition := metav1.Condition{
+		Type:    redisBackupScheduleLastBackupCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  "BackupSucceeded",
+		Message: message,
+	}
+	switch {
+	case phase == redisv1.RedisBackupSchedulePhaseInvalid:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = string(phase)
+	case lastBackupFailed(status):
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "BackupFailed"
+	case status.LastSuccessfulBackup == "":
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = "NoBackup"
+	}
+	status.Ready = "False"
+	if condition.Status == metav1.ConditionTrue {
+		status.Ready = "True"
+	}
+	meta.SetStatusCondition(&status.Conditions, condition)
+}
+
+// updateStatus 使用冲突重试更新 RedisBackupSchedule 的状态
+func (r *RedisBackupScheduleReconciler) updateStatus(ctx context.Context, schedule *redisv1.RedisBackupSchedule, status *redisv1.RedisBackupScheduleStatus, logs logr.Logger) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		return r.doUpdateRedisBackupScheduleStatus(ctx, schedule, status)
+	})
+	if err != nil {
+		logs.Error(err, "Failed to update RedisBackupSchedule status")
+	}
+	return err
+}
+
+// doUpdateRedisBackupScheduleStatus 更新 RedisBackupSchedule 的状态
+func (r *RedisBackupScheduleReconciler) doUpdateRedisBackupScheduleStatus(ctx context.Context, schedule *redisv1.RedisBackupSchedule, status *redisv1.RedisBackupScheduleStatus) error {
+	latestSchedule := &redisv1.RedisBackupSchedule{}
+	if err := r.Get(ctx, types.NamespacedName{Name: schedule.Name, Namespace: schedule.Namespace}, latestSchedule); err != nil {
+		return fmt.Errorf("failed to get latest RedisBackupSchedule: %w", err)
+	}
+	latestSchedule.Status = *status
+	return r.Status().Update(ctx, latestSchedule)
+}
+
+// schedulesForBackup 将 RedisBackup 的变化映射到创建它的 RedisBackupSchedule
+func (r *RedisBackupScheduleReconciler) schedulesForBackup(ctx context.Context, obj client.Object) []reconcile.Request {
+	name, ok := obj.GetLabels()[redisv1.RedisBackupScheduleLabel]
+	if !ok {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: name, Namespace: obj.GetNamespace()}}}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RedisBackupScheduleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&redisv1.RedisBackupSchedule{}).
+		Watches(&redisv1.RedisBackup{}, handler.EnqueueRequestsFromMapFunc(r.schedulesForBackup)).
+		Named("redisbackupschedule").
+		Complete(instrumentReconciler("RedisBackupSchedule", r.Client, func() client.Object { return &redisv1.RedisBackupSchedule{} }, r))
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2025 James.Liu.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	redisv1 "github.com/ybooks240/redis-operator/api/v1"
+	"github.com/ybooks240/redis-operator/internal/utils"
+)
+
+var _ = Describe("RedisBackupSchedule Controller", func() {
+	Context("When reconciling a resource", func() {
+		const resourceName = "test-redisbackupschedule"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		BeforeEach(func() {
+			By("creating the custom resource for the Kind RedisBackupSchedule")
+			err := k8sClient.Get(ctx, typeNamespacedName, &redisv1.RedisBackupSchedule{})
+			if err != nil && errors.IsNotFound(err) {
+				resource := &redisv1.RedisBackupSchedule{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      resourceName,
+						Namespace: "default",
+					},
+					Spec: redisv1.RedisBackupScheduleSpec{
+						Schedule:  "0 2 * * *",
+						TargetRef: redisv1.RedisWorkloadRef{Kind: "RedisInstance", Name: "missing-instance"},
+						Destination: redisv1.BackupDestination{
+							PVC: &redisv1.PVCBackupDestination{ClaimName: "backups"},
+						},
+					},
+				}
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			resource := &redisv1.RedisBackupSchedule{}
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Cleanup the specific resource instance RedisBackupSchedule")
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+		})
+
+		It("should compute the next schedule time without creating a backup", func() {
+			controllerReconciler := &RedisBackupScheduleReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			schedule := &redisv1.RedisBackupSchedule{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, schedule)).To(Succeed())
+			Expect(schedule.Status.Status).To(Equal(string(redisv1.RedisBackupSchedulePhaseActive)))
+			Expect(schedule.Status.NextScheduleTime).NotTo(BeNil())
+			Expect(schedule.Status.NextScheduleTime.UTC().Hour()).To(Equal(2))
+			// 未设置 retention 时由 CRD 默认值保留最近 7 个备份
+			Expect(schedule.Spec.Retention.KeepLast).To(Equal(int32Ptr(7)))
+
+			backups := &redisv1.RedisBackupList{}
+			Expect(k8sClient.List(ctx, backups, client.InNamespace("default"),
+				client.MatchingLabels{redisv1.RedisBackupScheduleLabel: resourceName})).To(Succeed())
+			Expect(backups.Items).To(BeEmpty())
+		})
+	})
+
+	Context("When parsing cron expressions", func() {
+		base := time.Date(2025, time.March, 14, 10, 30, 0, 0, time.UTC)
+
+		It("should find the next matching minute", func() {
+			cron, err := utils.ParseCron("*/15 * * * *")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cron.Next(base)).To(Equal(time.Date(2025, time.March, 14, 10, 45, 0, 0, time.UTC)))
+		})
+
+		It("should support names, ranges and macros", func() {
+			cron, err := utils.ParseCron("0 3 * * mon-fri")
+			Expect(err).NotTo(HaveOccurred())
+			// 2025-03-14 是星期五，下一个工作日是星期一
+			Expect(cron.Next(base)).To(Equal(time.Date(2025, time.March, 17, 3, 0, 0, 0, time.UTC)))
+
+			cron, err = utils.ParseCron("@monthly")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cron.Next(base)).To(Equal(time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC)))
+		})
+
+		It("should reject invalid expressions", func() {
+			_, err := utils.ParseCron("61 * * * *")
+			Expect(err).To(HaveOccurred())
+			_, err = utils.ParseCron("* * *")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("When applying the retention policy", func() {
+		backupAt := func(name string, t time.Time) redisv1.RedisBackup {
+			return redisv1.RedisBackup{ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.NewTime(t)}}
+		}
+		day := func(d int) time.Time {
+			return time.Date(2025, time.March, d, 2, 0, 0, 0, time.UTC)
+		}
+		// 从新到旧
+		backups := []redisv1.RedisBackup{
+			backupAt("d20", day(20)), backupAt("d19", day(19)), backupAt("d18", day(18)),
+			backupAt("d12", day(12)), backupAt("d5", day(5)),
+		}
+
+		It("should keep the newest backups", func() {
+			keep := retainedBackups(backups, redisv1.BackupRetention{KeepLast: int32Ptr(2)})
+			Expect(keep).To(Equal(map[string]bool{"d20": true, "d19": true}))
+		})
+
+		It("should keep one backup per week", func() {
+			keep := retainedBackups(backups, redisv1.BackupRetention{KeepLast: int32Ptr(0), KeepWeekly: 3})
+			Expect(keep).To(Equal(map[string]bool{"d20": true, "d12": true, "d5": true}))
+		})
+
+		It("should always keep the newest backup", func() {
+			Expect(retainedBackups(backups, redisv1.BackupRetention{KeepLast: int32Ptr(0)})).To(Equal(map[string]bool{"d20": true}))
+		})
+
+		It("should keep 7 backups when retention is not set", func() {
+			var daily []redisv1.RedisBackup
+			for d := 20; d > 10; d-- {
+				daily = append(daily, backupAt(fmt.Sprintf("d%d", d), day(d)))
+			}
+			schedule := &redisv1.RedisBackupSchedule{}
+			keep := retainedBackups(daily, schedule.Spec.Retention)
+			Expect(keep).To(HaveLen(7))
+			Expect(keep).To(HaveKey("d20"))
+			Expect(keep).To(HaveKey("d14"))
+			Expect(keep).NotTo(HaveKey("d13"))
+		})
+	})
+})
@@ -24,11 +24,12 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -40,12 +41,14 @@ import (
 	"github.com/go-logr/logr"
 	redisv1 "github.com/ybooks240/redis-operator/api/v1"
 	"github.com/ybooks240/redis-operator/internal/metrics"
+	"github.com/ybooks240/redis-operator/internal/utils"
 )
 
 // RedisClusterReconciler reconciles a RedisCluster object
 type RedisClusterReconciler struct {
 	client.Client
 	Scheme         *runtime.Scheme
+	Recorder       record.EventRecorder
 	MetricsManager *metrics.MetricsCollectionManager
 }
 
@@ -55,7 +58,7 @@ type RedisClusterReconciler struct {
 // +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
-// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -79,8 +82,15 @@ func (r *RedisClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		logs.Info("RedisCluster is being deleted, cleaning up resources", "name", redisCluster.Name)
 		if err = r.cleanupResources(ctx, req, redisCluster, logs); err != nil {
 			logs.Error(err, "Failed to cleanup resources")
+			r.Recorder.Eventf(redisCluster, corev1.EventTypeWarning, eventReasonCleanupFailed, "Failed to clean up resources: %v", err)
 			return ctrl.Result{}, err
 		}
+		r.Recorder.Event(redisCluster, corev1.EventTypeNormal, eventReasonCleanup, "Deleted dependent resources")
+		// 关闭指标收集器并删除资源的指标序列
+		if r.MetricsManager != nil {
+			r.MetricsManager.RemoveCollectors(redisCluster.UID)
+		}
+		metrics.DeleteResourceMetrics("RedisCluster", redisCluster.Namespace, redisCluster.Name)
 		// 移除 finalizer，使用重试机制避免资源版本冲突
 		err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
 			// 重新获取最新的资源版本
@@ -110,12 +120,24 @@ func (r *RedisClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	}
 
 	// 确保所有资源存在并正确配置
-	err = r.ensureResources(ctx, req, redisCluster, logs)
-	if err != nil {
+	// 配置指令无效时保持现有资源不变，错误通过 ConfigValid 状态条件报告
+	if configErrors := workloadConfigErrors(ctx, r.Client, redisCluster); len(configErrors) > 0 {
+		logs.Info("Invalid Redis configuration, skipping resource update", "errors", configErrors)
+		r.Recorder.Eventf(redisCluster, corev1.EventTypeWarning, eventReasonInvalidConfig, "Skipping resource update: %s", configErrors[0])
+	} else if err = r.ensureResources(ctx, req, redisCluster, logs); err != nil {
 		logs.Error(err, "Failed to ensure resources")
+		r.Recorder.Eventf(redisCluster, corev1.EventTypeWarning, eventReasonReconcileFailed, "Failed to ensure resources: %v", err)
+		if isSpecError(err) {
+			if statusErr := reportInvalidSpec(ctx, r.Client, redisCluster, &redisCluster.Status.Conditions, err); statusErr != nil {
+				logs.Error(statusErr, "Failed to report invalid spec")
+			}
+		}
 		return ctrl.Result{}, err
 	}
 
+	// 从备份恢复时按备份的槽位布局组建集群
+	restoring := reconcileClusterRestore(ctx, r.Client, redisCluster, logs)
+
 	// 更新状态
 	err = r.updateRedisClusterStatus(ctx, redisCluster)
 	if err != nil {
@@ -123,31 +145,52 @@ func (r *RedisClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return ctrl.Result{}, err
 	}
 
+	// 密码 Secret 变化时执行零停机密码轮换
+	if err = reconcilePasswordRotation(ctx, r.Client, r.Scheme, "RedisCluster", redisCluster, redisCluster.Spec.Security, redisCluster.Status.PasswordRotation, logs); err != nil {
+		logs.Error(err, "Failed to rotate password")
+	}
+
 	// 注册指标收集器
 	if r.MetricsManager != nil {
-		// 为 Redis Cluster 添加指标收集器
-		clusterAddrs := []string{fmt.Sprintf("%s-service.%s.svc.cluster.local:6379", redisCluster.Name, redisCluster.Namespace)}
-		clusterCollector := metrics.NewClusterCollector(
-			clusterAddrs,
-			"", // 无密码
+		// 为 Redis Cluster 注册指标收集器，连接参数未变化时复用已有收集器
+		password, err := utils.ReadPassword(ctx, r.Client, redisCluster.Namespace, redisCluster.Spec.Security, redisCluster.Name)
+		if err != nil {
+			logs.Error(err, "Failed to read Redis password for metrics collector")
+		}
+		tlsConfig, err := utils.ClientTLSConfig(ctx, r.Client, redisCluster.Namespace, redisCluster.Spec.Security.TLS)
+		if err != nil {
+			logs.Error(err, "Failed to load TLS certificate for metrics collector")
+		}
+		serviceName := redisCluster.Name + "-service"
+		r.MetricsManager.SyncClusterCollector(
+			metrics.CollectorKey{UID: redisCluster.UID, Pod: serviceName},
+			metrics.Endpoint{
+				Addrs:     []string{fmt.Sprintf("%s.%s.svc.cluster.local:6379", serviceName, redisCluster.Namespace)},
+				Password:  password,
+				TLSConfig: tlsConfig,
+			},
 			redisCluster.Namespace,
 			redisCluster.Name,
+			redisCluster.Spec.ReplicasPerMaster,
 		)
-		r.MetricsManager.AddClusterCollector(clusterCollector)
-
-		// 记录协调操作指标
-		metrics.RecordReconcile("RedisCluster", redisCluster.Namespace, redisCluster.Name, "success", 0.0)
+		// 为每个集群节点注册指标收集器，分片序号由收集器从 CLUSTER NODES 读取
+		if err := syncRedisPodCollectors(ctx, r.Client, r.MetricsManager, "RedisCluster", redisCluster, redisCluster.Spec.Security, serviceName); err != nil {
+			logs.Error(err, "Failed to register metrics collectors")
+		}
 
-		// 更新集群状态指标
+		// 更新资源状态指标，集群拓扑指标由 Cluster 收集器上报
 		if redisCluster.Status.Status != "" {
 			var statusValue float64 = 0
 			if redisCluster.Status.Status == string(redisv1.RedisPhaseRunning) {
 				statusValue = 1
 			}
-			metrics.SetRedisClusterState(redisCluster.Namespace, redisCluster.Name, statusValue)
+			metrics.SetResourceStatus("RedisCluster", redisCluster.Namespace, redisCluster.Name, "ready", statusValue)
 		}
 	}
 
+	if restoring {
+		return ctrl.Result{RequeueAfter: time.Second * 5}, nil
+	}
 	return ctrl.Result{RequeueAfter: time.Second * 30}, nil
 }
 
@@ -197,13 +240,23 @@ func (r *RedisClusterReconciler) cleanupResources(ctx context.Context, req ctrl.
 
 // ensureResources 确保所有必要的资源存在
 func (r *RedisClusterReconciler) ensureResources(ctx context.Context, req ctrl.Request, redisCluster *redisv1.RedisCluster, logs logr.Logger) error {
+	// 启用认证且未引用密码 Secret 时生成随机密码
+	if err := utils.EnsurePasswordSecret(ctx, r.Client, r.Scheme, redisCluster, redisCluster.Spec.Security); err != nil {
+		return err
+	}
+
 	// 确保 ConfigMap
-	if err := r.ensureConfigMap(ctx, redisCluster, logs); err != nil {
+	profile, err := resolveConfigProfile(ctx, r.Client, redisCluster.Namespace, redisCluster.Spec.ConfigRef)
+	if err != nil {
+		return err
+	}
+	redisCluster.Status.ConfigRevision = renderedConfigRevision(redisCluster.Spec.ConfigRef, profile)
+	if err := r.ensureConfigMap(ctx, redisCluster, profile, logs); err != nil {
 		return err
 	}
 
 	// 确保 StatefulSet
-	if err := r.ensureStatefulSet(ctx, redisCluster, logs); err != nil {
+	if err := r.ensureStatefulSet(ctx, redisCluster, profile, logs); err != nil {
 		return err
 	}
 
@@ -212,29 +265,38 @@ func (r *RedisClusterReconciler) ensureResources(ctx context.Context, req ctrl.R
 		return err
 	}
 
+	// 确保 exporter metrics Service 和 ServiceMonitor
+	if err := ensureMonitoring(ctx, r.Client, r.Scheme, r.Recorder, "RedisCluster", redisCluster, redisCluster.Spec.Security, redisCluster.Spec.Monitoring, newAlertTopology("RedisCluster", redisCluster.Name)); err != nil {
+		return err
+	}
+
 	return nil
 }
 
 // ensureConfigMap 确保 ConfigMap 存在
-func (r *RedisClusterReconciler) ensureConfigMap(ctx context.Context, redisCluster *redisv1.RedisCluster, logs logr.Logger) error {
+func (r *RedisClusterReconciler) ensureConfigMap(ctx context.Context, redisCluster *redisv1.RedisCluster, profile *redisv1.ConfigSpec, logs logr.Logger) error {
 	configMap := &corev1.ConfigMap{}
 	configMapName := redisCluster.Name + "-config"
 	err := r.Get(ctx, types.NamespacedName{Name: configMapName, Namespace: redisCluster.Namespace}, configMap)
 
 	if errors.IsNotFound(err) {
 		// 创建新的 ConfigMap
-		configMap = r.configMapForCluster(redisCluster)
+		configMap = r.configMapForCluster(redisCluster, profile)
 		if err = controllerutil.SetControllerReference(redisCluster, configMap, r.Scheme); err != nil {
 			return err
 		}
 		controllerutil.AddFinalizer(configMap, redisv1.RedisClusterFinalizer)
 		logs.Info("Creating cluster ConfigMap", "name", configMap.Name)
-		return r.Create(ctx, configMap)
+		if err := r.Create(ctx, configMap); err != nil {
+			return err
+		}
+		recordCreated(r.Recorder, redisCluster, "ConfigMap", configMap.Name)
+		return nil
 	} else if err != nil {
 		return err
 	} else {
 		// 检查 ConfigMap 是否需要更新
-		desiredConfigMap := r.configMapForCluster(redisCluster)
+		desiredConfigMap := r.configMapForCluster(redisCluster, profile)
 		needsUpdate := false
 
 		// 比较配置数据
@@ -254,7 +316,11 @@ func (r *RedisClusterReconciler) ensureConfigMap(ctx context.Context, redisClust
 			// 更新 ConfigMap
 			configMap.Data = desiredConfigMap.Data
 			logs.Info("Updating cluster ConfigMap", "name", configMap.Name)
-			return r.Update(ctx, configMap)
+			if err := r.Update(ctx, configMap); err != nil {
+				return err
+			}
+			r.Recorder.Eventf(redisCluster, corev1.EventTypeNormal, eventReasonConfigChanged, "Updated ConfigMap %s", configMap.Name)
+			return nil
 		}
 	}
 
@@ -275,56 +341,58 @@ func (r *RedisClusterReconciler) setUpdatingStatus(ctx context.Context, redisClu
 		latestCluster.Status.Ready = "False"
 		latestCluster.Status.LastConditionMessage = message
 
-		// 更新 Conditions
-		condition := metav1.Condition{
-			Type:               "Ready",
-			Status:             metav1.ConditionFalse,
-			Reason:             "Updating",
-			Message:            message,
-			LastTransitionTime: metav1.Now(),
-		}
-
-		if latestCluster.Status.Conditions == nil {
-			latestCluster.Status.Conditions = []metav1.Condition{}
-		}
-
-		// 查找现有条件并更新，或添加新条件
-		found := false
-		for i, existingCondition := range latestCluster.Status.Conditions {
-			if existingCondition.Type == condition.Type {
-				latestCluster.Status.Conditions[i] = condition
-				found = true
-				break
-			}
-		}
-		if !found {
-			latestCluster.Status.Conditions = append(latestCluster.Status.Conditions, condition)
-		}
+		setUpdatingConditions(&latestCluster.Status.Conditions, latestCluster.Generation, message)
 
 		return r.Status().Update(ctx, latestCluster)
 	})
 }
 
 // ensureStatefulSet 确保 StatefulSet 存在
-func (r *RedisClusterReconciler) ensureStatefulSet(ctx context.Context, redisCluster *redisv1.RedisCluster, logs logr.Logger) error {
+func (r *RedisClusterReconciler) ensureStatefulSet(ctx context.Context, redisCluster *redisv1.RedisCluster, profile *redisv1.ConfigSpec, logs logr.Logger) error {
 	statefulSet := &appsv1.StatefulSet{}
 	statefulSetName := redisCluster.Name
 	err := r.Get(ctx, types.NamespacedName{Name: statefulSetName, Namespace: redisCluster.Namespace}, statefulSet)
 
 	if errors.IsNotFound(err) {
 		// 创建新的 StatefulSet
-		statefulSet = r.statefulSetForCluster(redisCluster)
+		statefulSet, err = r.statefulSetForCluster(redisCluster)
+		if err != nil {
+			return err
+		}
 		if err = controllerutil.SetControllerReference(redisCluster, statefulSet, r.Scheme); err != nil {
 			return err
 		}
+		if err = utils.AnnotateTLSCertHash(ctx, r.Client, redisCluster.Namespace, redisCluster.Spec.Security.TLS, &statefulSet.Spec.Template); err != nil {
+			return err
+		}
 		controllerutil.AddFinalizer(statefulSet, redisv1.RedisClusterFinalizer)
+		if err = r.applyClusterRestore(ctx, redisCluster, profile, statefulSet); err != nil {
+			logs.Info("Restore source not ready, postponing cluster StatefulSet creation", "reason", err.Error())
+			r.Recorder.Eventf(redisCluster, corev1.EventTypeNormal, eventReasonRestorePending, "Postponing StatefulSet creation: %v", err)
+			return nil
+		}
 		logs.Info("Creating cluster StatefulSet", "name", statefulSet.Name)
-		return r.Create(ctx, statefulSet)
+		if err := r.Create(ctx, statefulSet); err != nil {
+			return err
+		}
+		recordCreated(r.Recorder, redisCluster, "StatefulSet", statefulSet.Name)
+		return nil
 	} else if err != nil {
 		return err
 	} else {
 		// 检查 StatefulSet 是否需要更新
-		desiredStatefulSet := r.statefulSetForCluster(redisCluster)
+		desiredStatefulSet, err := r.statefulSetForCluster(redisCluster)
+		if err != nil {
+			return err
+		}
+		if err := utils.AnnotateTLSCertHash(ctx, r.Client, redisCluster.Namespace, redisCluster.Spec.Security.TLS, &desiredStatefulSet.Spec.Template); err != nil {
+			return err
+		}
+		// 恢复完成前更新模板需要保留恢复初始化容器
+		if err := r.applyClusterRestore(ctx, redisCluster, profile, desiredStatefulSet); err != nil {
+			logs.Info("Restore source not ready, postponing cluster StatefulSet update", "reason", err.Error())
+			return nil
+		}
 		needsUpdate := false
 		updateReason := ""
 
@@ -346,6 +414,24 @@ func (r *RedisClusterReconciler) ensureStatefulSet(ctx context.Context, redisClu
 			}
 		}
 
+		// 检查认证配置是否变化
+		if !needsUpdate && utils.AuthSecretChanged(&statefulSet.Spec.Template, utils.PasswordSecretRef(redisCluster.Spec.Security, redisCluster.Name)) {
+			needsUpdate = true
+			updateReason = "Authentication configuration change detected"
+		}
+
+		// 检查 TLS 证书是否变化，证书轮换后滚动更新
+		if !needsUpdate && utils.TLSCertChanged(&statefulSet.Spec.Template, &desiredStatefulSet.Spec.Template) {
+			needsUpdate = true
+			updateReason = "TLS certificate change detected"
+		}
+
+		// 检查 exporter sidecar 是否变化
+		if !needsUpdate && utils.ExporterChanged(&statefulSet.Spec.Template, &desiredStatefulSet.Spec.Template) {
+			needsUpdate = true
+			updateReason = "Exporter sidecar configuration change detected"
+		}
+
 		// 检查资源配置是否变化
 		if !needsUpdate && len(statefulSet.Spec.Template.Spec.Containers) > 0 && len(desiredStatefulSet.Spec.Template.Spec.Containers) > 0 {
 			existingResources := statefulSet.Spec.Template.Spec.Containers[0].Resources
@@ -365,10 +451,15 @@ func (r *RedisClusterReconciler) ensureStatefulSet(ctx context.Context, redisClu
 				logs.Error(err, "Failed to set updating status")
 			}
 
-			// 更新 StatefulSet
+			// 更新 StatefulSet，serviceName 创建后不可修改，保留已有的值
+			desiredStatefulSet.Spec.ServiceName = statefulSet.Spec.ServiceName
 			statefulSet.Spec = desiredStatefulSet.Spec
 			logs.Info("Updating cluster StatefulSet", "name", statefulSet.Name, "reason", updateReason)
-			return r.Update(ctx, statefulSet)
+			if err := r.Update(ctx, statefulSet); err != nil {
+				return err
+			}
+			r.Recorder.Eventf(redisCluster, corev1.EventTypeNormal, eventReasonRollingUpdate, "Rolling update of StatefulSet %s: %s", statefulSet.Name, updateReason)
+			return nil
 		}
 	}
 
@@ -389,7 +480,11 @@ func (r *RedisClusterReconciler) ensureService(ctx context.Context, redisCluster
 		}
 		controllerutil.AddFinalizer(service, redisv1.RedisClusterFinalizer)
 		logs.Info("Creating cluster Service", "name", service.Name)
-		return r.Create(ctx, service)
+		if err := r.Create(ctx, service); err != nil {
+			return err
+		}
+		recordCreated(r.Recorder, redisCluster, "Service", service.Name)
+		return nil
 	} else if err != nil {
 		return err
 	}
@@ -406,6 +501,12 @@ func (r *RedisClusterReconciler) updateRedisClusterStatus(ctx context.Context, r
 		if err := r.Get(ctx, types.NamespacedName{Name: redisCluster.Name, Namespace: redisCluster.Namespace}, latestCluster); err != nil {
 			return err
 		}
+		// 恢复进度由 reconcileClusterRestore 写入内存中的对象
+		if restored := meta.FindStatusCondition(redisCluster.Status.Conditions, redisRestoredCondition); restored != nil {
+			meta.SetStatusCondition(&latestCluster.Status.Conditions, *restored)
+		}
+		// 已应用的配置模板版本由 ensureResources 写入内存中的对象，配置模板据此展示滚动进度
+		latestCluster.Status.ConfigRevision = redisCluster.Status.ConfigRevision
 
 		return r.doUpdateRedisClusterStatus(ctx, latestCluster)
 	})
@@ -415,29 +516,54 @@ func (r *RedisClusterReconciler) updateRedisClusterStatus(ctx context.Context, r
 
 // doUpdateRedisClusterStatus 执行实际的状态更新
 func (r *RedisClusterReconciler) doUpdateRedisClusterStatus(ctx context.Context, latestCluster *redisv1.RedisCluster) error {
+	previousReady := latestCluster.Status.Ready
 
 	// 获取 StatefulSet 状态
 	statefulSet := &appsv1.StatefulSet{}
 	statefulSetName := latestCluster.Name
 	statefulSetErr := r.Get(ctx, types.NamespacedName{Name: statefulSetName, Namespace: latestCluster.Namespace}, statefulSet)
 
+	// 从备份恢复期间集群尚未组建，StatefulSet 可能在等待恢复来源
+	restored := meta.FindStatusCondition(latestCluster.Status.Conditions, redisRestoredCondition)
+	restoring := latestCluster.Spec.RestoreFrom != nil && restored != nil && restored.Status != metav1.ConditionTrue
+
 	// 更新状态
-	if statefulSetErr != nil {
+	var message string
+	observed := workloadObservation{
+		MinAvailable: latestCluster.Spec.Masters,
+		ConfigErrors: setConfigValidCondition(ctx, r.Client, latestCluster, &latestCluster.Status.Conditions),
+	}
+	if latestCluster.Spec.RestoreFrom != nil {
+		observed.Failure = restoreFailureMessage(latestCluster.Status.Conditions)
+	}
+	if statefulSetErr != nil && !restoring {
 		latestCluster.Status.Status = string(redisv1.RedisClusterPhaseFailed)
-		latestCluster.Status.Ready = "False"
-		latestCluster.Status.LastConditionMessage = "Failed to get StatefulSet"
+		message = "Failed to get StatefulSet"
+		observed.Missing = fmt.Sprintf("%s: %v", message, statefulSetErr)
 	} else {
 		totalNodes := latestCluster.Spec.Masters * (1 + latestCluster.Spec.ReplicasPerMaster)
 		clusterReady := statefulSet.Status.ReadyReplicas == totalNodes
+		if statefulSetErr == nil {
+			observeStatefulSets(&observed, statefulSet)
+		} else {
+			observed.Desired = totalNodes
+		}
 
-		if clusterReady {
+		if restoring {
+			clusterReady = false
+			latestCluster.Status.Status = string(redisv1.RedisClusterPhasePending)
+			if observed.Failure != "" {
+				latestCluster.Status.Status = string(redisv1.RedisClusterPhaseFailed)
+			} else {
+				observed.Waiting = restored.Message
+			}
+			message = restored.Message
+		} else if clusterReady {
 			latestCluster.Status.Status = string(redisv1.RedisClusterPhaseRunning)
-			latestCluster.Status.Ready = "True"
-			latestCluster.Status.LastConditionMessage = "All cluster nodes are ready"
+			message = "All cluster nodes are ready"
 		} else {
 			latestCluster.Status.Status = string(redisv1.RedisClusterPhasePending)
-			latestCluster.Status.Ready = "False"
-			latestCluster.Status.LastConditionMessage = "Waiting for cluster nodes to be ready"
+			message = "Waiting for cluster nodes to be ready"
 		}
 
 		// 更新集群状态
@@ -453,48 +579,29 @@ func (r *RedisClusterReconciler) doUpdateRedisClusterStatus(ctx context.Context,
 		}
 	}
 
-	// 更新 Conditions
-	condition := metav1.Condition{
-		Type:               "Ready",
-		Status:             metav1.ConditionFalse,
-		Reason:             "NotReady",
-		Message:            latestCluster.Status.LastConditionMessage,
-		LastTransitionTime: metav1.Now(),
-	}
+	// 更新统一的状态条件
+	ready := setWorkloadConditions(&latestCluster.Status.Conditions, latestCluster.Generation, observed)
+	setStorageReadyCondition(ctx, r.Client, "RedisCluster", latestCluster, &latestCluster.Status.Conditions)
+	latestCluster.Status.Ready = string(conditionStatus(ready))
 
-	if latestCluster.Status.Ready == "true" {
-		condition.Status = metav1.ConditionTrue
-		condition.Reason = "Ready"
-	}
+	// 更新 TLS 证书有效期状态
+	setTLSCertificateCondition(ctx, r.Client, "RedisCluster", latestCluster, latestCluster.Spec.Security, &latestCluster.Status.Conditions)
 
-	// 添加或更新条件
-	if latestCluster.Status.Conditions == nil {
-		latestCluster.Status.Conditions = []metav1.Condition{}
-	}
-
-	// 查找现有条件并更新，或添加新条件
-	found := false
-	for i, existingCondition := range latestCluster.Status.Conditions {
-		if existingCondition.Type == condition.Type {
-			latestCluster.Status.Conditions[i] = condition
-			found = true
-			break
-		}
-	}
-	if !found {
-		latestCluster.Status.Conditions = append(latestCluster.Status.Conditions, condition)
+	// 配置指令无效时在状态消息中给出原因
+	latestCluster.Status.LastConditionMessage = message
+	if len(observed.ConfigErrors) > 0 {
+		latestCluster.Status.LastConditionMessage = "Invalid configuration: " + observed.ConfigErrors[0]
 	}
 
-	// 限制条件数量
-	if len(latestCluster.Status.Conditions) > 10 {
-		latestCluster.Status.Conditions = latestCluster.Status.Conditions[len(latestCluster.Status.Conditions)-10:]
+	if err := r.Status().Update(ctx, latestCluster); err != nil {
+		return err
 	}
-
-	return r.Status().Update(ctx, latestCluster)
+	recordReadyTransition(r.Recorder, latestCluster, previousReady, ready, latestCluster.Status.LastConditionMessage)
+	return nil
 }
 
 // configMapForCluster 创建 Cluster ConfigMap
-func (r *RedisClusterReconciler) configMapForCluster(redisCluster *redisv1.RedisCluster) *corev1.ConfigMap {
+func (r *RedisClusterReconciler) configMapForCluster(redisCluster *redisv1.RedisCluster, profile *redisv1.ConfigSpec) *corev1.ConfigMap {
 	clusterConfig := map[string]string{
 		"redis.conf": fmt.Sprintf(`# Redis Cluster Configuration
 port 6379
@@ -515,12 +622,7 @@ save 60 10000
 			redisCluster.Spec.Config.ClusterMigrationBarrier),
 	}
 
-	// 合并用户自定义配置
-	if redisCluster.Spec.Config.AdditionalConfig != nil {
-		for key, value := range redisCluster.Spec.Config.AdditionalConfig {
-			clusterConfig[key] = value
-		}
-	}
+	clusterConfig["redis.conf"] = utils.AppendRedisConfig(clusterConfig["redis.conf"], clusterRedisConfig(redisCluster, profile))
 
 	return &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
@@ -536,8 +638,26 @@ save 60 10000
 	}
 }
 
+// clusterRedisConfig 合并配置模板和用户自定义配置，结果追加到集群 redis.conf
+func clusterRedisConfig(redisCluster *redisv1.RedisCluster, profile *redisv1.ConfigSpec) map[string]string {
+	return mergeProfileConfig(redisCluster.Spec.Config.AdditionalConfig, profile.Redis, profile.Cluster)
+}
+
+// applyClusterRestore 数据尚未恢复时为集群 StatefulSet 添加恢复初始化容器，恢复来源未就绪或与集群不匹配时返回错误
+func (r *RedisClusterReconciler) applyClusterRestore(ctx context.Context, redisCluster *redisv1.RedisCluster, profile *redisv1.ConfigSpec, statefulSet *appsv1.StatefulSet) error {
+	if redisCluster.Spec.RestoreFrom == nil || restoreCompleted(redisCluster.Status.Conditions) {
+		return nil
+	}
+	shards, err := resolveClusterRestoreSource(ctx, r.Client, redisCluster)
+	if err != nil {
+		return err
+	}
+	applyClusterRestore(&statefulSet.Spec.Template, shards, redisCluster.Spec.Image, clusterRedisConfig(redisCluster, profile))
+	return nil
+}
+
 // statefulSetForCluster 创建 Cluster StatefulSet
-func (r *RedisClusterReconciler) statefulSetForCluster(redisCluster *redisv1.RedisCluster) *appsv1.StatefulSet {
+func (r *RedisClusterReconciler) statefulSetForCluster(redisCluster *redisv1.RedisCluster) (*appsv1.StatefulSet, error) {
 	replicas := redisCluster.Spec.Masters * (1 + redisCluster.Spec.ReplicasPerMaster)
 	if replicas == 0 {
 		replicas = 6 // 默认值：3 masters + 3 replicas
@@ -571,6 +691,10 @@ func (r *RedisClusterReconciler) statefulSetForCluster(redisCluster *redisv1.Red
 
 	var volumeClaimTemplates []corev1.PersistentVolumeClaim
 	if redisCluster.Spec.Storage.Size != "" {
+		storageSize, err := parseStorageSize("spec.storage.size", redisCluster.Spec.Storage.Size)
+		if err != nil {
+			return nil, err
+		}
 		volumeClaimTemplates = []corev1.PersistentVolumeClaim{
 			{
 				ObjectMeta: metav1.ObjectMeta{
@@ -580,7 +704,7 @@ func (r *RedisClusterReconciler) statefulSetForCluster(redisCluster *redisv1.Red
 					AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
 					Resources: corev1.VolumeResourceRequirements{
 						Requests: corev1.ResourceList{
-							corev1.ResourceStorage: resource.MustParse(redisCluster.Spec.Storage.Size),
+							corev1.ResourceStorage: storageSize,
 						},
 					},
 					StorageClassName: &redisCluster.Spec.Storage.StorageClassName,
@@ -596,7 +720,7 @@ func (r *RedisClusterReconciler) statefulSetForCluster(redisCluster *redisv1.Red
 		})
 	}
 
-	return &appsv1.StatefulSet{
+	statefulSet := &appsv1.StatefulSet{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      redisCluster.Name,
 			Namespace: redisCluster.Namespace,
@@ -608,6 +732,8 @@ func (r *RedisClusterReconciler) statefulSetForCluster(redisCluster *redisv1.Red
 		},
 		Spec: appsv1.StatefulSetSpec{
 			Replicas: &replicas,
+			// 关联 headless Service，为每个 Pod 提供稳定的 DNS 名称
+			ServiceName: redisCluster.Name + "-service",
 			Selector: &metav1.LabelSelector{
 				MatchLabels: map[string]string{
 					"app":       "redis-cluster",
@@ -670,6 +796,12 @@ func (r *RedisClusterReconciler) statefulSetForCluster(redisCluster *redisv1.Red
 			VolumeClaimTemplates: volumeClaimTemplates,
 		},
 	}
+
+	utils.ApplyExporter(&statefulSet.Spec.Template, redisCluster.Spec.Monitoring, utils.ExporterAuthSecret(redisCluster.Spec.Security, redisCluster.Name), redisCluster.Spec.Security.TLS)
+	utils.ApplyTLS(&statefulSet.Spec.Template, "redis", redisCluster.Spec.Security.TLS, 6379, true)
+	utils.ApplyAuth(&statefulSet.Spec.Template, "redis", utils.PasswordSecretRef(redisCluster.Spec.Security, redisCluster.Name))
+
+	return statefulSet, nil
 }
 
 // serviceForCluster 创建 Cluster Service
@@ -710,6 +842,42 @@ func (r *RedisClusterReconciler) serviceForCluster(redisCluster *redisv1.RedisCl
 	}
 }
 
+// clustersForSecret 返回引用了该 Secret（密码或 TLS 证书）的 RedisCluster，证书轮换后触发滚动更新
+func (r *RedisClusterReconciler) clustersForSecret(ctx context.Context, obj client.Object) []reconcile.Request {
+	clusters := &redisv1.RedisClusterList{}
+	if err := r.List(ctx, clusters, client.InNamespace(obj.GetNamespace())); err != nil {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, cluster := range clusters.Items {
+		if utils.ReferencesSecret(cluster.Spec.Security, cluster.Name, obj.GetName()) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace},
+			})
+		}
+	}
+	return requests
+}
+
+// clustersForConfig 返回引用了该配置模板的 RedisCluster，配置模板变化后重新生成配置
+func (r *RedisClusterReconciler) clustersForConfig(ctx context.Context, obj client.Object) []reconcile.Request {
+	clusters := &redisv1.RedisClusterList{}
+	if err := r.List(ctx, clusters, client.InNamespace(obj.GetNamespace())); err != nil {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, cluster := range clusters.Items {
+		if cluster.Spec.ConfigRef != nil && cluster.Spec.ConfigRef.Name == obj.GetName() {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace},
+			})
+		}
+	}
+	return requests
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *RedisClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
@@ -717,6 +885,14 @@ func (r *RedisClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&corev1.ConfigMap{}).
 		Owns(&corev1.Service{}).
 		Owns(&appsv1.StatefulSet{}).
+		Watches(
+			&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.clustersForSecret),
+		).
+		Watches(
+			&redisv1.Config{},
+			handler.EnqueueRequestsFromMapFunc(r.clustersForConfig),
+		).
 		Watches(
 			&corev1.ConfigMap{},
 			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
@@ -751,5 +927,5 @@ func (r *RedisClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
 				return nil
 			}),
 		).
-		Complete(r)
+		Complete(instrumentReconciler("RedisCluster", r.Client, func() client.Object { return &redisv1.RedisCluster{} }, r))
 }
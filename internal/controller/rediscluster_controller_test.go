@@ -21,8 +21,11 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -81,8 +84,9 @@ var _ = Describe("RedisCluster Controller", func() {
 		It("should successfully reconcile the resource", func() {
 			By("Reconciling the created resource")
 			controllerReconciler := &RedisClusterReconciler{
-				Client: k8sClient,
-				Scheme: k8sClient.Scheme(),
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: &record.FakeRecorder{},
 			}
 
 			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
@@ -93,4 +97,135 @@ var _ = Describe("RedisCluster Controller", func() {
 			// Example: If you expect a certain status condition after reconciliation, verify it here.
 		})
 	})
+
+	Context("When restoring from a backup", func() {
+		const resourceName = "test-restore-cluster"
+		const backupName = "test-cluster-backup"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		BeforeEach(func() {
+			backup := &redisv1.RedisBackup{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      backupName,
+					Namespace: "default",
+				},
+				Spec: redisv1.RedisBackupSpec{
+					TargetRef: redisv1.RedisWorkloadRef{Kind: "RedisCluster", Name: "old-cluster"},
+					Destination: redisv1.BackupDestination{
+						PVC: &redisv1.PVCBackupDestination{ClaimName: "backups"},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, backup)).To(Succeed())
+			backup.Status = redisv1.RedisBackupStatus{
+				Status: string(redisv1.RedisBackupPhaseCompleted),
+				Shards: []redisv1.BackupShardStatus{
+					{Index: 0, Slots: "0-8191", Location: "pvc://backups/" + backupName + "-shard-0.rdb"},
+					{Index: 1, Slots: "8192-16383", Location: "pvc://backups/" + backupName + "-shard-1.rdb"},
+				},
+			}
+			Expect(k8sClient.Status().Update(ctx, backup)).To(Succeed())
+
+			resource := &redisv1.RedisCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: redisv1.RedisClusterSpec{
+					Image:             "redis:7.0",
+					Masters:           3,
+					ReplicasPerMaster: 1,
+					RestoreFrom:       &redisv1.ClusterRestoreSource{Backup: backupName},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			resource := &redisv1.RedisCluster{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+
+			backup := &redisv1.RedisBackup{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: backupName, Namespace: "default"}, backup)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, backup)).To(Succeed())
+		})
+
+		It("should refuse a backup with a different number of shards", func() {
+			controllerReconciler := &RedisClusterReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: &record.FakeRecorder{},
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = k8sClient.Get(ctx, typeNamespacedName, &appsv1.StatefulSet{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+
+			redisCluster := &redisv1.RedisCluster{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, redisCluster)).To(Succeed())
+			condition := meta.FindStatusCondition(redisCluster.Status.Conditions, redisRestoredCondition)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal("RestoreRefused"))
+			Expect(redisCluster.Status.Status).To(Equal(string(redisv1.RedisClusterPhaseFailed)))
+		})
+
+		It("should validate the slot map of the backup manifest", func() {
+			slots, err := parseSlotRanges([]string{"0-2", "5"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(slots).To(Equal([]int{0, 1, 2, 5}))
+
+			_, err = parseSlotRanges([]string{"16380-16384"})
+			Expect(err).To(HaveOccurred())
+
+			first, _ := parseSlotRanges([]string{"0-8191"})
+			second, _ := parseSlotRanges([]string{"8000-16383"})
+			Expect(validateSlotMap([]clusterRestoreShard{{Slots: first}, {Slots: second}})).To(MatchError(ContainSubstring("slot 8000")))
+
+			second, _ = parseSlotRanges([]string{"8192-16382"})
+			Expect(validateSlotMap([]clusterRestoreShard{{Slots: first}, {Slots: second}})).To(MatchError(ContainSubstring("slot 16383")))
+		})
+
+		It("should spread slots evenly when resharding", func() {
+			owners := evenSlotOwners(3)
+			Expect(owners[0]).To(Equal(0))
+			Expect(owners[5460]).To(Equal(0))
+			Expect(owners[5461]).To(Equal(1))
+			Expect(owners[16383]).To(Equal(2))
+		})
+
+		It("should load each shard on the pod with the same ordinal", func() {
+			first, _ := parseSlotRanges([]string{"0-8191"})
+			second, _ := parseSlotRanges([]string{"8192-16383"})
+			shards := []clusterRestoreShard{
+				{Location: &restoreLocation{ClaimName: "backups", Path: "nightly-shard-0.rdb"}, Slots: first},
+				{Location: &restoreLocation{ClaimName: "backups", Path: "nightly-shard-1.rdb"}, Slots: second},
+			}
+			statefulSet := &appsv1.StatefulSet{}
+			applyClusterRestore(&statefulSet.Spec.Template, shards, "redis:7.0", nil)
+
+			initContainers := statefulSet.Spec.Template.Spec.InitContainers
+			Expect(initContainers).To(HaveLen(2))
+			Expect(initContainers[0].Command[2]).To(ContainSubstring("1) cp '/restore-source/nightly-shard-1.rdb'"))
+			Expect(initContainers[1].Command[2]).To(ContainSubstring("mv /data/restore.rdb /data/dump.rdb"))
+			Expect(initContainers[1].Command[2]).To(ContainSubstring("config set appendonly yes"))
+
+			By("following dbfilename and appendonly from the rendered config")
+			statefulSet = &appsv1.StatefulSet{}
+			applyClusterRestore(&statefulSet.Spec.Template, shards, "redis:7.0", map[string]string{"dbfilename": "shard.rdb", "appendonly": "no"})
+			restore := statefulSet.Spec.Template.Spec.InitContainers[1].Command[2]
+			Expect(restore).To(ContainSubstring("/data/shard.rdb"))
+			Expect(restore).NotTo(ContainSubstring("config set appendonly yes"))
+		})
+	})
 })
@@ -21,17 +21,16 @@ import (
 	"crypto/sha256"
 	"fmt"
 	"reflect"
-	"sort"
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
-	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -50,12 +49,14 @@ import (
 type RedisInstanceReconciler struct {
 	client.Client
 	Scheme         *runtime.Scheme
+	Recorder       record.EventRecorder
 	MetricsManager *metrics.MetricsCollectionManager
 }
 
 // +kubebuilder:rbac:groups=redis.github.com,resources=redisinstances,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=redis.github.com,resources=redisinstances/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=redis.github.com,resources=redisinstances/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -96,8 +97,15 @@ func (r *RedisInstanceReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		logs.Info("RedisInstance is being deleted, cleaning up resources", "name", redisInstance.Name)
 		if err = r.cleanupResources(ctx, req, redisInstance, logs); err != nil {
 			logs.Error(err, "Failed to cleanup resources")
+			r.Recorder.Eventf(redisInstance, corev1.EventTypeWarning, eventReasonCleanupFailed, "Failed to clean up resources: %v", err)
 			return ctrl.Result{}, err
 		}
+		r.Recorder.Event(redisInstance, corev1.EventTypeNormal, eventReasonCleanup, "Deleted Service, StatefulSet and ConfigMap")
+		// 关闭指标收集器并删除资源的指标序列
+		if r.MetricsManager != nil {
+			r.MetricsManager.RemoveCollectors(redisInstance.UID)
+		}
+		metrics.DeleteResourceMetrics("RedisInstance", redisInstance.Namespace, redisInstance.Name)
 		// 移除 finalizer，使用重试机制避免资源版本冲突
 		err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
 			// 重新获取最新的资源版本
@@ -130,9 +138,18 @@ func (r *RedisInstanceReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	}
 
 	// 检查并创建或更新所有资源
-	err = r.ensureResources(ctx, req, redisInstance, statefulSet, configMap, service, logs)
-	if err != nil {
+	// 配置指令无效时保持现有资源不变，错误通过 ConfigValid 状态条件报告
+	if configErrors := workloadConfigErrors(ctx, r.Client, redisInstance); len(configErrors) > 0 {
+		logs.Info("Invalid Redis configuration, skipping resource update", "errors", configErrors)
+		r.Recorder.Eventf(redisInstance, corev1.EventTypeWarning, eventReasonInvalidConfig, "Skipping resource update: %s", configErrors[0])
+	} else if err = r.ensureResources(ctx, req, redisInstance, statefulSet, configMap, service, logs); err != nil {
 		logs.Error(err, "Failed to ensure resources")
+		r.Recorder.Eventf(redisInstance, corev1.EventTypeWarning, eventReasonReconcileFailed, "Failed to ensure resources: %v", err)
+		if isSpecError(err) {
+			if statusErr := reportInvalidSpec(ctx, r.Client, redisInstance, &redisInstance.Status.Conditions, err); statusErr != nil {
+				logs.Error(statusErr, "Failed to report invalid spec")
+			}
+		}
 		return ctrl.Result{}, err
 	}
 
@@ -144,28 +161,25 @@ func (r *RedisInstanceReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return ctrl.Result{}, err
 	}
 
+	// 密码 Secret 变化时执行零停机密码轮换
+	if err = reconcilePasswordRotation(ctx, r.Client, r.Scheme, "RedisInstance", redisInstance, redisInstance.Spec.Security, redisInstance.Status.PasswordRotation, logs); err != nil {
+		logs.Error(err, "Failed to rotate password")
+	}
+
 	// 注册指标收集器
 	if r.MetricsManager != nil {
-		// 为 Redis 实例添加指标收集器
-		redisCollector := metrics.NewRedisCollector(
-			fmt.Sprintf("%s.%s.svc.cluster.local:6379", redisInstance.Name, redisInstance.Namespace),
-			"", // 无密码
-			redisInstance.Namespace,
-			redisInstance.Name,
-			"master", // Redis 角色
-		)
-		r.MetricsManager.AddRedisCollector(redisCollector)
-
-		// 记录协调操作指标
-		metrics.RecordReconcile("RedisInstance", redisInstance.Namespace, redisInstance.Name, "success", 0.0)
-
-		// 更新实例状态指标
+		// 为 Redis 实例的每个 Pod 注册指标收集器，连接参数未变化时复用已有收集器
+		if err := syncRedisPodCollectors(ctx, r.Client, r.MetricsManager, "RedisInstance", redisInstance, redisInstance.Spec.Security); err != nil {
+			logs.Error(err, "Failed to register metrics collectors")
+		}
+
+		// 更新资源状态指标，节点级别的 redis_instance_status 由每个 Pod 的收集器上报
 		if redisInstance.Status.Status != "" {
 			var statusValue float64 = 0
 			if redisInstance.Status.Status == string(redisv1.RedisPhaseRunning) {
 				statusValue = 1
 			}
-			metrics.SetRedisInstanceStatus(redisInstance.Namespace, redisInstance.Name, "master", statusValue)
+			metrics.SetResourceStatus("RedisInstance", redisInstance.Namespace, redisInstance.Name, "ready", statusValue)
 		}
 	}
 
@@ -242,7 +256,7 @@ func (r *RedisInstanceReconciler) cleanupResources(ctx context.Context, req ctrl
 // 		// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
 // 		// Return and don't requeue
 // 		logs.Info("Reconcile RedisInstance.configMap not found", "configMap", configMap)
-// 		configMap, err = r.configMapForRedisInstance(redisInstance, logs)
+// 		configMap, err = r.configMapForRedisInstance(redisInstance, config, logs)
 // 		if err != nil {
 // 			return err
 // 		}
@@ -295,6 +309,10 @@ func (r *RedisInstanceReconciler) cleanupResources(ctx context.Context, req ctrl
 // }
 
 func (r *RedisInstanceReconciler) statefulSetForRedisInstance(redisInstance *redisv1.RedisInstance, logs logr.Logger) (*appsv1.StatefulSet, error) {
+	storageSize, err := parseStorageSize("spec.storage.size", redisInstance.Spec.Storage.Size)
+	if err != nil {
+		return nil, err
+	}
 	label := utils.LabelsForRedis(redisInstance.Name)
 	owner := []metav1.OwnerReference{
 		{
@@ -386,7 +404,7 @@ func (r *RedisInstanceReconciler) statefulSetForRedisInstance(redisInstance *red
 						StorageClassName: &redisInstance.Spec.Storage.StorageClassName,
 						Resources: corev1.VolumeResourceRequirements{
 							Requests: corev1.ResourceList{
-								"storage": resource.MustParse(redisInstance.Spec.Storage.Size),
+								"storage": storageSize,
 							},
 						},
 					},
@@ -396,6 +414,10 @@ func (r *RedisInstanceReconciler) statefulSetForRedisInstance(redisInstance *red
 		},
 	}
 
+	utils.ApplyExporter(&sts.Spec.Template, redisInstance.Spec.Monitoring, utils.ExporterAuthSecret(redisInstance.Spec.Security, redisInstance.Name), redisInstance.Spec.Security.TLS)
+	utils.ApplyTLS(&sts.Spec.Template, "redis", redisInstance.Spec.Security.TLS, 6379, false)
+	utils.ApplyAuth(&sts.Spec.Template, "redis", utils.PasswordSecretRef(redisInstance.Spec.Security, redisInstance.Name))
+
 	if err := ctrl.SetControllerReference(redisInstance, sts, r.Scheme); err != nil {
 		logs.Error(err, "statefulSetForRedisInstance SetControllerReference Error")
 		return nil, err
@@ -403,9 +425,29 @@ func (r *RedisInstanceReconciler) statefulSetForRedisInstance(redisInstance *red
 	return sts, nil
 }
 
-func (r *RedisInstanceReconciler) configMapForRedisInstance(redisInstance *redisv1.RedisInstance, logs logr.Logger) (*corev1.ConfigMap, error) {
+// applyRedisInstanceRestore 数据尚未恢复时为 StatefulSet 添加恢复初始化容器，恢复来源未就绪时返回错误
+func (r *RedisInstanceReconciler) applyRedisInstanceRestore(ctx context.Context, redisInstance *redisv1.RedisInstance, config map[string]string, statefulSet *appsv1.StatefulSet) error {
+	if redisInstance.Spec.RestoreFrom == nil || restoreCompleted(redisInstance.Status.Conditions) {
+		return nil
+	}
+	location, err := resolveRestoreSource(ctx, r.Client, redisInstance.Namespace, redisInstance.Spec.RestoreFrom)
+	if err != nil {
+		return err
+	}
+
+	// 与 GenerateRedisConfig 的默认值保持一致
+	dbfilename := "dump.rdb"
+	if value, ok := config["dbfilename"]; ok {
+		dbfilename = value
+	}
+	appendonly := config["appendonly"] != "no"
+	applyRestore(&statefulSet.Spec.Template, location, redisInstance.Spec.Image, "redis-data", dbfilename, appendonly)
+	return nil
+}
+
+func (r *RedisInstanceReconciler) configMapForRedisInstance(redisInstance *redisv1.RedisInstance, redisConfig map[string]string, logs logr.Logger) (*corev1.ConfigMap, error) {
 	label := utils.LabelsForRedis(redisInstance.Name)
-	config := utils.GenerateRedisConfig(redisInstance.Spec.Config)
+	config := utils.GenerateRedisConfig(redisConfig)
 	owner := []metav1.OwnerReference{
 		{
 			APIVersion: "redis.github.com/v1",
@@ -482,12 +524,12 @@ func (r *RedisInstanceReconciler) calculateConfigHash(config string) string {
 // needsStatefulSetRestart 检查是否需要重启 StatefulSet
 // needsStatefulSetRestart 检查是否需要重建StatefulSet
 // 只有配置文件变化和存储变化需要重建，其他变化可以通过滚动更新处理
-func (r *RedisInstanceReconciler) needsStatefulSetRestart(ctx context.Context, redisInstance *redisv1.RedisInstance, statefulSet *appsv1.StatefulSet, logs logr.Logger) (bool, error) {
+func (r *RedisInstanceReconciler) needsStatefulSetRestart(ctx context.Context, redisInstance *redisv1.RedisInstance, config map[string]string, statefulSet *appsv1.StatefulSet, logs logr.Logger) (bool, error) {
 	needsRestart := false
 	reasonMsg := ""
 
 	// 1. 检查配置文件变化 - 需要重建
-	expectedConfig := utils.GenerateRedisConfig(redisInstance.Spec.Config)
+	expectedConfig := utils.GenerateRedisConfig(config)
 	expectedHash := r.calculateConfigHash(expectedConfig)
 
 	var stsConfigHash string
@@ -501,11 +543,37 @@ func (r *RedisInstanceReconciler) needsStatefulSetRestart(ctx context.Context, r
 		reasonMsg = "Redis configuration has changed, StatefulSet will be recreated"
 	}
 
-	// 2. 检查存储配置变化 - 需要重建（PVC不能直接修改）
+	// 2. 检查认证配置变化 - 需要重建
+	if utils.AuthSecretChanged(&statefulSet.Spec.Template, utils.PasswordSecretRef(redisInstance.Spec.Security, redisInstance.Name)) {
+		logs.Info("Authentication change detected, StatefulSet restart required")
+		needsRestart = true
+		reasonMsg = "Authentication configuration has changed, StatefulSet will be recreated"
+	}
+
+	// 3. 检查 TLS 证书或选项变化 - 需要重建
+	desiredTemplate := &corev1.PodTemplateSpec{}
+	if err := utils.AnnotateTLSCertHash(ctx, r.Client, redisInstance.Namespace, redisInstance.Spec.Security.TLS, desiredTemplate); err != nil {
+		return false, err
+	}
+	if utils.TLSCertChanged(&statefulSet.Spec.Template, desiredTemplate) {
+		logs.Info("TLS certificate change detected, StatefulSet restart required")
+		needsRestart = true
+		reasonMsg = "TLS certificate has changed, StatefulSet will be recreated"
+	}
+
+	// 4. 检查存储配置变化 - 需要重建（PVC不能直接修改）
 	if len(statefulSet.Spec.VolumeClaimTemplates) > 0 {
 		currentStorageSize := statefulSet.Spec.VolumeClaimTemplates[0].Spec.Resources.Requests["storage"]
-		expectedStorageSize := resource.MustParse(redisInstance.Spec.Storage.Size)
-		if !currentStorageSize.Equal(expectedStorageSize) {
+		expectedStorageSize, err := parseStorageSize("spec.storage.size", redisInstance.Spec.Storage.Size)
+		if err != nil {
+			return false, err
+		}
+		if expectedStorageSize.Cmp(currentStorageSize) < 0 {
+			// 缩容会在重建时丢失数据，保持现有存储
+			logs.Info("Storage shrink rejected", "expected", expectedStorageSize.String(), "current", currentStorageSize.String())
+			r.Recorder.Eventf(redisInstance, corev1.EventTypeWarning, eventReasonStorageShrinkRejected,
+				"Storage cannot shrink from %s to %s, keeping the current size", currentStorageSize.String(), expectedStorageSize.String())
+		} else if !currentStorageSize.Equal(expectedStorageSize) {
 			logs.Info("Storage size change detected, StatefulSet restart required",
 				"expected", expectedStorageSize.String(), "current", currentStorageSize.String())
 			needsRestart = true
@@ -527,6 +595,7 @@ func (r *RedisInstanceReconciler) needsStatefulSetRestart(ctx context.Context, r
 	// 如果需要重建，设置RedisInstance状态为Updating
 	if needsRestart {
 		r.setUpdatingStatus(ctx, redisInstance, "StatefulSetRestart", reasonMsg)
+		r.Recorder.Event(redisInstance, corev1.EventTypeNormal, eventReasonStatefulSetRecreated, reasonMsg)
 	} else {
 		logs.Info("No restart-requiring changes detected")
 	}
@@ -569,6 +638,16 @@ func (r *RedisInstanceReconciler) needsStatefulSetUpdate(ctx context.Context, re
 		}
 	}
 
+	// 3. 检查 exporter sidecar 变化 - 可以滚动更新
+	exporterAuth := utils.ExporterAuthSecret(redisInstance.Spec.Security, redisInstance.Name)
+	desiredTemplate := &corev1.PodTemplateSpec{}
+	utils.ApplyExporter(desiredTemplate, redisInstance.Spec.Monitoring, exporterAuth, redisInstance.Spec.Security.TLS)
+	if utils.ExporterChanged(&statefulSet.Spec.Template, desiredTemplate) {
+		logs.Info("Exporter sidecar change detected, will update")
+		utils.ReplaceExporter(&statefulSet.Spec.Template, redisInstance.Spec.Monitoring, exporterAuth, redisInstance.Spec.Security.TLS)
+		updated = true
+	}
+
 	// 如果有更新，设置RedisInstance状态为Updating
 	if updated {
 		r.setUpdatingStatus(ctx, redisInstance, "StatefulSetUpdate", "StatefulSet is being updated with new configuration")
@@ -578,13 +657,27 @@ func (r *RedisInstanceReconciler) needsStatefulSetUpdate(ctx context.Context, re
 }
 
 func (r *RedisInstanceReconciler) ensureResources(ctx context.Context, req ctrl.Request, redisInstance *redisv1.RedisInstance, statefulSet *appsv1.StatefulSet, configMap *corev1.ConfigMap, service *corev1.Service, logs logr.Logger) error {
+	// 启用认证且未引用密码 Secret 时生成随机密码
+	if err := utils.EnsurePasswordSecret(ctx, r.Client, r.Scheme, redisInstance, redisInstance.Spec.Security); err != nil {
+		logs.Error(err, "Failed to ensure password Secret")
+		return err
+	}
+
+	// 合并引用的配置模板，内联配置优先，变化时与内联配置一样重建 StatefulSet
+	profile, err := resolveConfigProfile(ctx, r.Client, redisInstance.Namespace, redisInstance.Spec.ConfigRef)
+	if err != nil {
+		return err
+	}
+	redisInstance.Status.ConfigRevision = renderedConfigRevision(redisInstance.Spec.ConfigRef, profile)
+	config := mergeProfileConfig(redisInstance.Spec.Config, profile.Redis)
+
 	// 检查 ConfigMap 是否存在
 	configMapErr := r.Get(ctx, types.NamespacedName{Name: redisInstance.Name, Namespace: redisInstance.Namespace}, configMap)
 	configMapRecreated := false
 
 	if errors.IsNotFound(configMapErr) {
 		logs.Info("ConfigMap not found, creating new one", "name", redisInstance.Name)
-		newConfigMap, err := r.configMapForRedisInstance(redisInstance, logs)
+		newConfigMap, err := r.configMapForRedisInstance(redisInstance, config, logs)
 		if err != nil {
 			return err
 		}
@@ -594,13 +687,14 @@ func (r *RedisInstanceReconciler) ensureResources(ctx context.Context, req ctrl.
 			logs.Error(err, "Failed to create ConfigMap")
 			return err
 		}
+		recordCreated(r.Recorder, redisInstance, "ConfigMap", newConfigMap.Name)
 		// 标记 ConfigMap 被重新创建
 		configMapRecreated = true
 	} else if configMapErr != nil {
 		return configMapErr
 	} else {
 		// ConfigMap 存在，检查配置是否需要更新
-		expectedConfig := utils.GenerateRedisConfig(redisInstance.Spec.Config)
+		expectedConfig := utils.GenerateRedisConfig(config)
 		currentConfig := configMap.Data["redis.conf"]
 
 		if expectedConfig != currentConfig {
@@ -612,6 +706,7 @@ func (r *RedisInstanceReconciler) ensureResources(ctx context.Context, req ctrl.
 				logs.Error(err, "Failed to update ConfigMap")
 				return err
 			}
+			r.Recorder.Eventf(redisInstance, corev1.EventTypeNormal, eventReasonConfigChanged, "Updated redis.conf in ConfigMap %s", configMap.Name)
 		}
 
 		// 检查是否需要移除 finalizer
@@ -630,7 +725,7 @@ func (r *RedisInstanceReconciler) ensureResources(ctx context.Context, req ctrl.
 
 	// 如果 StatefulSet 存在，检查是否需要重启
 	if statefulSetErr == nil {
-		restart, err := r.needsStatefulSetRestart(ctx, redisInstance, statefulSet, logs)
+		restart, err := r.needsStatefulSetRestart(ctx, redisInstance, config, statefulSet, logs)
 		if err != nil {
 			logs.Error(err, "Failed to check if StatefulSet needs restart")
 			return err
@@ -647,18 +742,28 @@ func (r *RedisInstanceReconciler) ensureResources(ctx context.Context, req ctrl.
 		}
 
 		// 添加配置哈希值到 StatefulSet 的 annotation 中
-		expectedConfig := utils.GenerateRedisConfig(redisInstance.Spec.Config)
+		expectedConfig := utils.GenerateRedisConfig(config)
 		configHash := r.calculateConfigHash(expectedConfig)
 		if newStatefulSet.Spec.Template.Annotations == nil {
 			newStatefulSet.Spec.Template.Annotations = make(map[string]string)
 		}
 		newStatefulSet.Spec.Template.Annotations["redis.github.com/config-hash"] = configHash
+		if err := utils.AnnotateTLSCertHash(ctx, r.Client, redisInstance.Namespace, redisInstance.Spec.Security.TLS, &newStatefulSet.Spec.Template); err != nil {
+			logs.Error(err, "Failed to load TLS certificate")
+			return err
+		}
 
 		// 移除 finalizer
 		newStatefulSet.ObjectMeta.Finalizers = []string{}
-		if err := r.Create(ctx, newStatefulSet); err != nil {
+		// 恢复来源未就绪时暂不创建 StatefulSet，原因由 Restored 条件展示
+		if err := r.applyRedisInstanceRestore(ctx, redisInstance, config, newStatefulSet); err != nil {
+			logs.Info("Restore source not ready, postponing StatefulSet creation", "reason", err.Error())
+			r.Recorder.Eventf(redisInstance, corev1.EventTypeNormal, eventReasonRestorePending, "Postponing StatefulSet creation: %v", err)
+		} else if err := r.Create(ctx, newStatefulSet); err != nil {
 			logs.Error(err, "Failed to create StatefulSet")
 			return err
+		} else {
+			recordCreated(r.Recorder, redisInstance, "StatefulSet", newStatefulSet.Name)
 		}
 	} else if statefulSetErr != nil {
 		return statefulSetErr
@@ -690,15 +795,24 @@ func (r *RedisInstanceReconciler) ensureResources(ctx context.Context, req ctrl.
 			}
 
 			// 添加配置哈希值到 StatefulSet 的 annotation 中
-			expectedConfig := utils.GenerateRedisConfig(redisInstance.Spec.Config)
+			expectedConfig := utils.GenerateRedisConfig(config)
 			configHash := r.calculateConfigHash(expectedConfig)
 			if newStatefulSet.Spec.Template.Annotations == nil {
 				newStatefulSet.Spec.Template.Annotations = make(map[string]string)
 			}
 			newStatefulSet.Spec.Template.Annotations["redis.github.com/config-hash"] = configHash
+			if err := utils.AnnotateTLSCertHash(ctx, r.Client, redisInstance.Namespace, redisInstance.Spec.Security.TLS, &newStatefulSet.Spec.Template); err != nil {
+				logs.Error(err, "Failed to load TLS certificate")
+				return err
+			}
 
 			// 移除 finalizer
 			newStatefulSet.ObjectMeta.Finalizers = []string{}
+			if err := r.applyRedisInstanceRestore(ctx, redisInstance, config, newStatefulSet); err != nil {
+				logs.Info("Restore source not ready, postponing StatefulSet creation", "reason", err.Error())
+				r.Recorder.Eventf(redisInstance, corev1.EventTypeNormal, eventReasonRestorePending, "Postponing StatefulSet creation: %v", err)
+				return nil
+			}
 			if err := r.Create(ctx, newStatefulSet); err != nil {
 				logs.Error(err, "Failed to create StatefulSet")
 				return err
@@ -719,6 +833,7 @@ func (r *RedisInstanceReconciler) ensureResources(ctx context.Context, req ctrl.
 				logs.Info("StatefulSet spec needs update, performing rolling update")
 				// 设置 Updating 状态
 				r.setUpdatingStatus(ctx, redisInstance, "StatefulSetUpdate", "StatefulSet spec needs update")
+				r.Recorder.Eventf(redisInstance, corev1.EventTypeNormal, eventReasonRollingUpdate, "Rolling update of StatefulSet %s", statefulSet.Name)
 				updated = true
 			}
 
@@ -746,6 +861,7 @@ func (r *RedisInstanceReconciler) ensureResources(ctx context.Context, req ctrl.
 			logs.Error(err, "Failed to create Service")
 			return err
 		}
+		recordCreated(r.Recorder, redisInstance, "Service", newService.Name)
 	} else if serviceErr != nil {
 		return serviceErr
 	} else {
@@ -759,20 +875,19 @@ func (r *RedisInstanceReconciler) ensureResources(ctx context.Context, req ctrl.
 		}
 	}
 
+	// 确保 exporter metrics Service 和 ServiceMonitor
+	if err := ensureMonitoring(ctx, r.Client, r.Scheme, r.Recorder, "RedisInstance", redisInstance, redisInstance.Spec.Security, redisInstance.Spec.Monitoring, newAlertTopology("RedisInstance", redisInstance.Name)); err != nil {
+		logs.Error(err, "Failed to ensure monitoring resources")
+		return err
+	}
+
 	return nil
 }
 
 // setUpdatingStatus 设置RedisInstance状态为Updating
 func (r *RedisInstanceReconciler) setUpdatingStatus(ctx context.Context, redisInstance *redisv1.RedisInstance, reason, message string) {
 	// 设置状态为Updating
-	meta.SetStatusCondition(&redisInstance.Status.Conditions, metav1.Condition{
-		Type:               string(redisv1.RedisPhaseUpdating),
-		Status:             metav1.ConditionFalse, // Ready为false
-		LastTransitionTime: metav1.Now(),
-		Reason:             reason,
-		Message:            message,
-		ObservedGeneration: redisInstance.Generation,
-	})
+	setUpdatingConditions(&redisInstance.Status.Conditions, redisInstance.Generation, message)
 
 	// 更新状态字段
 	redisInstance.Status.Status = string(redisv1.RedisPhaseUpdating)
@@ -800,6 +915,7 @@ func (r *RedisInstanceReconciler) doUpdateRedisInstanceStatus(ctx context.Contex
 	if err := r.Get(ctx, types.NamespacedName{Name: redisInstance.Name, Namespace: redisInstance.Namespace}, latestInstance); err != nil {
 		return err
 	}
+	previousReady := latestInstance.Status.Ready
 
 	// 获取 ConfigMap 状态
 	configMap := &corev1.ConfigMap{}
@@ -813,9 +929,8 @@ func (r *RedisInstanceReconciler) doUpdateRedisInstanceStatus(ctx context.Contex
 	sts := &appsv1.StatefulSet{}
 	statefulSetErr := r.Get(ctx, types.NamespacedName{Name: latestInstance.Name, Namespace: latestInstance.Namespace}, sts)
 
-	// 设置状态条件
-	var conditionStatus metav1.ConditionStatus
-	var conditionType string
+	// 计算阶段
+	var phase string
 	var reason string
 	var message string
 
@@ -837,72 +952,135 @@ func (r *RedisInstanceReconciler) doUpdateRedisInstanceStatus(ctx context.Contex
 	// 更新 RedisInstance 状态
 	if !latestInstance.DeletionTimestamp.IsZero() {
 		// 如果正在删除，设置为 Terminated 状态
-		conditionStatus = metav1.ConditionUnknown
-		conditionType = string(redisv1.RedisPhaseTerminated)
+		phase = string(redisv1.RedisPhaseTerminated)
 		reason = "Deleteing"
 		message = fmt.Sprintf("If you want to delete RedisInstance %s,you need remove finalizer.", latestInstance.Name)
+	} else if errors.IsNotFound(statefulSetErr) && latestInstance.Spec.RestoreFrom != nil && !restoreCompleted(latestInstance.Status.Conditions) {
+		// 等待恢复来源就绪后再创建 StatefulSet
+		phase = string(redisv1.RedisPhasePending)
+		reason = "WaitingForRestoreSource"
+		message = "Waiting for the restore source, see the Restored condition"
 	} else if configMapErr != nil || serviceErr != nil || statefulSetErr != nil {
 		// 如果任何资源不存在，设置为 Failed 状态
-		conditionStatus = metav1.ConditionFalse
-		conditionType = string(redisv1.RedisPhaseFailed)
+		phase = string(redisv1.RedisPhaseFailed)
 		reason = "ResourceMissing"
 		message = fmt.Sprintf("One or more required resources are missing. ConfigMap: %v, Service: %v, StatefulSet: %v",
 			configMapErr, serviceErr, statefulSetErr)
 	} else if isUpdating {
 		// 如果StatefulSet正在更新，保持Updating状态
-		conditionStatus = metav1.ConditionFalse // Ready为false
-		conditionType = string(redisv1.RedisPhaseUpdating)
+		phase = string(redisv1.RedisPhaseUpdating)
 		reason = "StatefulSetUpdating"
 		message = fmt.Sprintf("StatefulSet is being updated. Updated: %d/%d, Ready: %d/%d",
 			sts.Status.UpdatedReplicas, sts.Status.Replicas, sts.Status.ReadyReplicas, sts.Status.Replicas)
 	} else if sts.Status.ReadyReplicas == 0 {
 		// 如果 StatefulSet 没有就绪的副本，设置为 Pending 状态
-		conditionStatus = metav1.ConditionFalse
-		conditionType = string(redisv1.RedisPhasePending)
+		phase = string(redisv1.RedisPhasePending)
 		reason = "RedisInstancePending"
 		message = fmt.Sprintf("statefulSet for RedisInstance %s is pending.", latestInstance.Name)
 	} else if sts.Status.ReadyReplicas == sts.Status.Replicas {
 		// 如果所有副本都就绪，设置为 Running 状态
-		conditionStatus = metav1.ConditionTrue
-		conditionType = string(redisv1.RedisPhaseRunning)
+		phase = string(redisv1.RedisPhaseRunning)
 		reason = "RedisInstanceReady"
 		message = fmt.Sprintf("RedisInstance is running. StatefulSet: %v, Replicas: %v",
 			sts.Status.ReadyReplicas, sts.Status.Replicas)
 	} else {
 		// 如果部分副本就绪，设置为 Running 状态但条件为 False
-		conditionStatus = metav1.ConditionFalse
-		conditionType = string(redisv1.RedisPhaseRunning)
+		phase = string(redisv1.RedisPhaseRunning)
 		reason = "RedisInstanceRunning"
 		message = fmt.Sprintf("RedisInstance is running with %d/%d ready replicas.",
 			sts.Status.ReadyReplicas, sts.Status.Replicas)
 	}
 
-	// 限制 Conditions 数量最多为 10 个
-	if len(latestInstance.Status.Conditions) >= 10 {
-		// 按时间排序，保留最新的 9 个
-		sort.Slice(latestInstance.Status.Conditions, func(i, j int) bool {
-			return latestInstance.Status.Conditions[i].LastTransitionTime.Before(&latestInstance.Status.Conditions[j].LastTransitionTime)
-		})
-		// 删除最旧的条件，直到只剩下 9 个
-		latestInstance.Status.Conditions = latestInstance.Status.Conditions[len(latestInstance.Status.Conditions)-9:]
-	}
-
-	// 设置状态条件，并添加 ObservedGeneration 到 Condition 中
-	meta.SetStatusCondition(&latestInstance.Status.Conditions, metav1.Condition{
-		Type:               conditionType,
-		Status:             conditionStatus,
-		LastTransitionTime: metav1.Now(),
-		Reason:             reason,
-		Message:            message,
-		ObservedGeneration: latestInstance.Generation,
-	})
+	// 更新 TLS 证书有效期状态
+	setTLSCertificateCondition(ctx, r.Client, "RedisInstance", latestInstance, latestInstance.Spec.Security, &latestInstance.Status.Conditions)
+
+	// 记录已应用的配置模板版本，配置模板据此展示滚动进度
+	latestInstance.Status.ConfigRevision = redisInstance.Status.ConfigRevision
+
+	// 更新创建时恢复数据的进度
+	if latestInstance.Spec.RestoreFrom != nil {
+		pods, err := listWorkloadRedisPods(ctx, r.Client, "RedisInstance", latestInstance.Namespace, latestInstance.Name)
+		if err != nil {
+			return err
+		}
+		setRestoreCondition(ctx, r.Client, latestInstance.Namespace, latestInstance.Spec.RestoreFrom, pods, &latestInstance.Status.Conditions)
+	}
+
+	// 旧版本以阶段名作为条件类型，统一条件后移除
+	for _, phase := range []redisv1.RedisPhase{redisv1.RedisPhaseUnknown, redisv1.RedisPhaseCreating, redisv1.RedisPhasePending,
+		redisv1.RedisPhaseRunning, redisv1.RedisPhaseFailed, redisv1.RedisPhaseTerminated, redisv1.RedisPhaseUpdating} {
+		meta.RemoveStatusCondition(&latestInstance.Status.Conditions, string(phase))
+	}
+
+	// 更新统一的状态条件
+	observed := workloadObservation{
+		MinAvailable: 1,
+		ConfigErrors: setConfigValidCondition(ctx, r.Client, latestInstance, &latestInstance.Status.Conditions),
+	}
+	switch reason {
+	case "Deleteing", "WaitingForRestoreSource":
+		observed.Waiting = message
+	case "ResourceMissing":
+		observed.Missing = message
+	}
+	if latestInstance.Spec.RestoreFrom != nil {
+		observed.Failure = restoreFailureMessage(latestInstance.Status.Conditions)
+	}
+	if statefulSetErr == nil {
+		observeStatefulSets(&observed, sts)
+	}
+	ready := setWorkloadConditions(&latestInstance.Status.Conditions, latestInstance.Generation, observed)
+	setStorageReadyCondition(ctx, r.Client, "RedisInstance", latestInstance, &latestInstance.Status.Conditions)
 
 	// 直接使用当前计算出的状态，而不是从conditions数组中获取
 	latestInstance.Status.LastConditionMessage = message
-	latestInstance.Status.Status = conditionType
-	latestInstance.Status.Ready = string(conditionStatus)
+	if len(observed.ConfigErrors) > 0 {
+		latestInstance.Status.LastConditionMessage = "Invalid configuration: " + observed.ConfigErrors[0]
+	}
+	latestInstance.Status.Status = phase
+	latestInstance.Status.Ready = string(conditionStatus(ready))
 
-	return r.Status().Update(ctx, latestInstance)
+	if err := r.Status().Update(ctx, latestInstance); err != nil {
+		return err
+	}
+	recordReadyTransition(r.Recorder, latestInstance, previousReady, ready, latestInstance.Status.LastConditionMessage)
+	return nil
+}
+
+// instancesForSecret 返回引用了该 Secret（密码或 TLS 证书）的 RedisInstance，证书轮换后触发滚动更新
+func (r *RedisInstanceReconciler) instancesForSecret(ctx context.Context, obj client.Object) []reconcile.Request {
+	instances := &redisv1.RedisInstanceList{}
+	if err := r.List(ctx, instances, client.InNamespace(obj.GetNamespace())); err != nil {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, instance := range instances.Items {
+		if utils.ReferencesSecret(instance.Spec.Security, instance.Name, obj.GetName()) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: instance.Name, Namespace: instance.Namespace},
+			})
+		}
+	}
+	return requests
+}
+
+// instancesForConfig 返回引用了该配置模板的 RedisInstance，配置模板变化后重新生成配置
+func (r *RedisInstanceReconciler) instancesForConfig(ctx context.Context, obj client.Object) []reconcile.Request {
+	instances := &redisv1.RedisInstanceList{}
+	if err := r.List(ctx, instances, client.InNamespace(obj.GetNamespace())); err != nil {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, instance := range instances.Items {
+		if instance.Spec.ConfigRef != nil && instance.Spec.ConfigRef.Name == obj.GetName() {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: instance.Name, Namespace: instance.Namespace},
+			})
+		}
+	}
+	return requests
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -912,6 +1090,14 @@ func (r *RedisInstanceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&corev1.ConfigMap{}).
 		Owns(&corev1.Service{}).
 		Owns(&appsv1.StatefulSet{}).
+		Watches(
+			&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.instancesForSecret),
+		).
+		Watches(
+			&redisv1.Config{},
+			handler.EnqueueRequestsFromMapFunc(r.instancesForConfig),
+		).
 		Watches(
 			&corev1.ConfigMap{},
 			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
@@ -963,5 +1149,5 @@ func (r *RedisInstanceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 				return nil
 			}),
 		).
-		Complete(r)
+		Complete(instrumentReconciler("RedisInstance", r.Client, func() client.Object { return &redisv1.RedisInstance{} }, r))
 }
@@ -18,16 +18,38 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"net"
+	"syscall"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	redisv1 "github.com/ybooks240/redis-operator/api/v1"
+	"github.com/ybooks240/redis-operator/internal/metrics"
+	"github.com/ybooks240/redis-operator/internal/tracing"
+	"github.com/ybooks240/redis-operator/internal/utils"
 )
 
 var _ = Describe("RedisInstance Controller", func() {
@@ -69,8 +91,9 @@ var _ = Describe("RedisInstance Controller", func() {
 		It("should successfully reconcile the resource", func() {
 			By("Reconciling the created resource")
 			controllerReconciler := &RedisInstanceReconciler{
-				Client: k8sClient,
-				Scheme: k8sClient.Scheme(),
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: &record.FakeRecorder{},
 			}
 
 			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
@@ -81,4 +104,555 @@ var _ = Describe("RedisInstance Controller", func() {
 			// Example: If you expect a certain status condition after reconciliation, verify it here.
 		})
 	})
+
+	Context("When authentication is enabled", func() {
+		const resourceName = "test-auth-instance"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		BeforeEach(func() {
+			resource := &redisv1.RedisInstance{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: redisv1.RedisInstanceSpec{
+					Security: redisv1.SecuritySpec{AuthEnabled: true},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			resource := &redisv1.RedisInstance{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+		})
+
+		It("should record the initial password for later rotations", func() {
+			controllerReconciler := &RedisInstanceReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: &record.FakeRecorder{},
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			passwordSecret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{
+				Name:      utils.PasswordSecretName(resourceName),
+				Namespace: "default",
+			}, passwordSecret)).To(Succeed())
+
+			activeSecret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{
+				Name:      utils.ActivePasswordSecretName(resourceName),
+				Namespace: "default",
+			}, activeSecret)).To(Succeed())
+			Expect(activeSecret.Data[utils.PasswordSecretKey]).To(Equal(passwordSecret.Data[utils.PasswordSecretKey]))
+
+			redisInstance := &redisv1.RedisInstance{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, redisInstance)).To(Succeed())
+			Expect(redisInstance.Status.PasswordRotation).NotTo(BeNil())
+			Expect(redisInstance.Status.PasswordRotation.Phase).To(Equal(redisv1.PasswordRotationCompleted))
+			Expect(redisInstance.Status.PasswordRotation.SecretResourceVersion).To(Equal(passwordSecret.ResourceVersion))
+		})
+	})
+
+	Context("When restoring from a backup", func() {
+		const resourceName = "test-restore-instance"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		BeforeEach(func() {
+			resource := &redisv1.RedisInstance{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: redisv1.RedisInstanceSpec{
+					Image:       "redis:7.0",
+					Storage:     redisv1.StorageSpec{Size: "1Gi", StorageClassName: "standard"},
+					RestoreFrom: &redisv1.RestoreSource{Backup: "missing-backup"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			resource := &redisv1.RedisInstance{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+		})
+
+		It("should wait for the backup before creating the StatefulSet", func() {
+			recorder := record.NewFakeRecorder(20)
+			controllerReconciler := &RedisInstanceReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: recorder,
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = k8sClient.Get(ctx, typeNamespacedName, &appsv1.StatefulSet{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+
+			redisInstance := &redisv1.RedisInstance{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, redisInstance)).To(Succeed())
+			condition := meta.FindStatusCondition(redisInstance.Status.Conditions, redisRestoredCondition)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("WaitingForSource"))
+
+			Expect(redisInstance.Status.Ready).To(Equal("False"))
+			progressing := meta.FindStatusCondition(redisInstance.Status.Conditions, redisv1.ConditionProgressing)
+			Expect(progressing).NotTo(BeNil())
+			Expect(progressing.Status).To(Equal(metav1.ConditionTrue))
+			Expect(progressing.Reason).To(Equal("Waiting"))
+			Expect(meta.FindStatusCondition(redisInstance.Status.Conditions, string(redisv1.RedisPhasePending))).To(BeNil())
+
+			var events []string
+			for len(recorder.Events) > 0 {
+				events = append(events, <-recorder.Events)
+			}
+			Expect(events).To(ContainElement(HavePrefix("Normal RestorePending ")))
+			Expect(events).To(ContainElement(HavePrefix("Normal Created Created ConfigMap ")))
+		})
+
+		It("should rebuild the AOF from the restored RDB", func() {
+			statefulSet := &appsv1.StatefulSet{}
+			location := &restoreLocation{ClaimName: "backups", Path: "nightly.rdb"}
+			applyRestore(&statefulSet.Spec.Template, location, "redis:7.0", "redis-data", "dump.rdb", true)
+
+			initContainers := statefulSet.Spec.Template.Spec.InitContainers
+			Expect(initContainers).To(HaveLen(2))
+			Expect(initContainers[0].Command).To(ContainElement("/restore-source/nightly.rdb"))
+			Expect(initContainers[1].Command[2]).To(ContainSubstring("config set appendonly yes"))
+		})
+	})
+
+	Context("When monitoring is enabled", func() {
+		const resourceName = "test-monitoring-instance"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		BeforeEach(func() {
+			resource := &redisv1.RedisInstance{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: redisv1.RedisInstanceSpec{
+					Image:      "redis:7.0",
+					Storage:    redisv1.StorageSpec{Size: "1Gi", StorageClassName: "standard"},
+					Security:   redisv1.SecuritySpec{AuthEnabled: true},
+					Monitoring: &redisv1.MonitoringSpec{Enabled: true},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			resource := &redisv1.RedisInstance{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+		})
+
+		It("should inject the exporter sidecar and create the metrics Service", func() {
+			controllerReconciler := &RedisInstanceReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: record.NewFakeRecorder(20),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			statefulSet := &appsv1.StatefulSet{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, statefulSet)).To(Succeed())
+			containers := statefulSet.Spec.Template.Spec.Containers
+			Expect(containers).To(HaveLen(2))
+			Expect(containers[1].Name).To(Equal(utils.ExporterContainerName))
+			Expect(containers[1].Image).To(Equal(utils.DefaultExporterImage))
+			Expect(containers[1].Env).To(ContainElement(HaveField("Name", "REDIS_PASSWORD_FILE")))
+			Expect(containers[1].Env).NotTo(ContainElement(HaveField("Name", "REDIS_PASSWORD")))
+			Expect(statefulSet.Spec.Template.Annotations).To(HaveKey(utils.ExporterHashAnnotation))
+
+			// exporter 从挂载的密码文件读取密码，密码轮换后无需重启
+			password, err := utils.ReadPassword(ctx, k8sClient, "default", redisv1.SecuritySpec{AuthEnabled: true}, resourceName)
+			Expect(err).NotTo(HaveOccurred())
+			exporterAuthKey := types.NamespacedName{Name: utils.ExporterAuthSecretName(resourceName), Namespace: "default"}
+			exporterAuth := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, exporterAuthKey, exporterAuth)).To(Succeed())
+			Expect(exporterAuth.Data[utils.ExporterPasswordFileKey]).To(MatchJSON(`{"redis://localhost:6379": "` + password + `"}`))
+
+			// 测试环境未安装 ServiceMonitor CRD，只创建 metrics Service
+			service := &corev1.Service{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{
+				Name:      metricsServiceName(resourceName),
+				Namespace: "default",
+			}, service)).To(Succeed())
+			Expect(service.Spec.Ports).To(HaveLen(1))
+			Expect(service.Spec.Ports[0].Port).To(Equal(utils.DefaultExporterPort))
+			Expect(service.Spec.Selector).To(HaveKeyWithValue("redis.github.com/instance", resourceName))
+
+			By("disabling monitoring")
+			redisInstance := &redisv1.RedisInstance{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, redisInstance)).To(Succeed())
+			redisInstance.Spec.Monitoring.Enabled = false
+			Expect(k8sClient.Update(ctx, redisInstance)).To(Succeed())
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, statefulSet)).To(Succeed())
+			Expect(statefulSet.Spec.Template.Spec.Containers).To(HaveLen(1))
+			Expect(statefulSet.Spec.Template.Spec.Volumes).NotTo(ContainElement(HaveField("Name", "redis-exporter-auth")))
+			err = k8sClient.Get(ctx, types.NamespacedName{
+				Name:      metricsServiceName(resourceName),
+				Namespace: "default",
+			}, &corev1.Service{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, exporterAuthKey, &corev1.Secret{}))).To(BeTrue())
+		})
+	})
+
+	Context("When harvesting the slowlog", func() {
+		const resourceName = "test-slowlog-instance"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		BeforeEach(func() {
+			resource := &redisv1.RedisInstance{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: redisv1.RedisInstanceSpec{
+					Image:   "redis:7.0",
+					Storage: redisv1.StorageSpec{Size: "1Gi", StorageClassName: "standard"},
+					Monitoring: &redisv1.MonitoringSpec{
+						Slowlog: &redisv1.SlowlogSpec{Enabled: true, TopN: 2},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			resource := &redisv1.RedisInstance{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+		})
+
+		It("should keep the harvest in the slowlog ConfigMap and remove it when disabled", func() {
+			controllerReconciler := &RedisInstanceReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: record.NewFakeRecorder(20),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			// 测试环境没有运行中的 Redis Pod，ConfigMap 中只有空的结果
+			configMapKey := types.NamespacedName{Name: slowlogConfigMapName(resourceName), Namespace: "default"}
+			configMap := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(ctx, configMapKey, configMap)).To(Succeed())
+			Expect(configMap.Annotations).To(HaveKey(slowlogHarvestedAtAnnotation))
+			Expect(configMap.Data).To(HaveKeyWithValue(slowlogEntriesKey, "[]"))
+			Expect(configMap.Data).To(HaveKeyWithValue(latencyEventsKey, "[]"))
+
+			By("disabling slowlog harvesting")
+			redisInstance := &redisv1.RedisInstance{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, redisInstance)).To(Succeed())
+			redisInstance.Spec.Monitoring.Slowlog.Enabled = false
+			Expect(k8sClient.Update(ctx, redisInstance)).To(Succeed())
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			err = k8sClient.Get(ctx, configMapKey, &corev1.ConfigMap{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should parse latency events and keep the most recent slow entries", func() {
+			events := parseLatencyLatest([]interface{}{
+				[]interface{}{"command", int64(1700000000), int64(120), int64(350)},
+				[]interface{}{"fork"},
+			})
+			Expect(events).To(HaveLen(1))
+			Expect(events[0].Event).To(Equal("command"))
+			Expect(events[0].Time).To(Equal(time.Unix(1700000000, 0).UTC()))
+			Expect(events[0].LatestMillis).To(Equal(int64(120)))
+			Expect(events[0].MaxMillis).To(Equal(int64(350)))
+
+			now := time.Now()
+			entries := recentSlowlogEntries([]slowlogEntry{
+				{Pod: "a", ID: 1, Time: now.Add(-time.Hour)},
+				{Pod: "b", ID: 7, Time: now},
+				{Pod: "a", ID: 2, Time: now.Add(-time.Minute)},
+			}, 2)
+			Expect(entries).To(HaveLen(2))
+			Expect(entries[0].ID).To(Equal(int64(7)))
+			Expect(entries[1].ID).To(Equal(int64(2)))
+		})
+	})
+
+	Context("When recording reconcile metrics", func() {
+		It("should classify reconcile errors", func() {
+			resource := schema.GroupResource{Group: "apps", Resource: "statefulsets"}
+			Expect(classifyReconcileError(errors.NewConflict(resource, "test", fmt.Errorf("stale object")))).To(Equal(reconcileErrorAPIConflict))
+			Expect(classifyReconcileError(errors.NewBadRequest("bad spec"))).To(Equal(reconcileErrorValidation))
+			Expect(classifyReconcileError(errors.NewNotFound(resource, "test"))).To(Equal(reconcileErrorAPI))
+			Expect(classifyReconcileError(fmt.Errorf("failed to ping: %w", &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}))).To(Equal(reconcileErrorRedisUnreachable))
+			Expect(classifyReconcileError(fmt.Errorf("sync: %w", storageError(fmt.Errorf("no PVCs found"))))).To(Equal(reconcileErrorStorage))
+			Expect(classifyReconcileError(fmt.Errorf("unexpected"))).To(Equal(reconcileErrorOther))
+		})
+
+		It("should record phase transitions and the time to become ready", func() {
+			const kind = "RedisInstancePhaseTest"
+			reconciler := instrumentReconciler(kind, k8sClient, nil, nil).(*instrumentedReconciler)
+			key := types.NamespacedName{Name: "phase-test", Namespace: "default"}
+			notReadySince := metav1.NewTime(time.Now().Add(-time.Minute))
+			redisInstance := &redisv1.RedisInstance{
+				ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace, CreationTimestamp: notReadySince},
+				Status: redisv1.RedisInstanceStatus{
+					Status: string(redisv1.RedisPhasePending),
+					Conditions: []metav1.Condition{{
+						Type:               redisv1.ConditionReady,
+						Status:             metav1.ConditionFalse,
+						LastTransitionTime: notReadySince,
+					}},
+				},
+			}
+
+			By("only remembering the state on the first observation")
+			reconciler.observe(key, redisInstance)
+			Expect(testutil.ToFloat64(metrics.PhaseTransitions.WithLabelValues(kind, "None", "Pending"))).To(BeZero())
+
+			By("recording the transition to Running")
+			running := redisInstance.DeepCopy()
+			running.Status.Status = string(redisv1.RedisPhaseRunning)
+			running.Status.Conditions[0].Status = metav1.ConditionTrue
+			running.Status.Conditions[0].LastTransitionTime = metav1.Now()
+			reconciler.observe(key, running)
+			Expect(testutil.ToFloat64(metrics.PhaseTransitions.WithLabelValues(kind, "Pending", "Running"))).To(Equal(1.0))
+
+			histogram := &dto.Metric{}
+			Expect(metrics.TimeToReady.WithLabelValues(kind, "Pending").(prometheus.Histogram).Write(histogram)).To(Succeed())
+			Expect(histogram.GetHistogram().GetSampleCount()).To(Equal(uint64(1)))
+			Expect(histogram.GetHistogram().GetSampleSum()).To(BeNumerically(">=", time.Minute.Seconds()))
+
+			By("ignoring reconciles without changes")
+			reconciler.observe(key, running)
+			Expect(testutil.ToFloat64(metrics.PhaseTransitions.WithLabelValues(kind, "Pending", "Running"))).To(Equal(1.0))
+		})
+	})
+
+	Context("When collecting Redis node metrics", func() {
+		It("should keep the role label of a node that is down from its first scrape", func() {
+			manager := metrics.NewMetricsCollectionManager(20 * time.Millisecond)
+			registry := prometheus.NewRegistry()
+			Expect(registry.Register(manager)).To(Succeed())
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go manager.Start(ctx)
+			defer manager.Stop()
+
+			// instanceStatus 返回 Pod 的 redis_instance_status 样本的 role 标签和值
+			instanceStatus := func(pod string) (string, float64) {
+				families, err := registry.Gather()
+				Expect(err).NotTo(HaveOccurred())
+				for _, family := range families {
+					if family.GetName() != "redis_instance_status" {
+						continue
+					}
+					for _, metric := range family.GetMetric() {
+						labels := map[string]string{}
+						for _, label := range metric.GetLabel() {
+							labels[label.GetName()] = label.GetValue()
+						}
+						if labels["pod"] == pod {
+							return labels["role"], metric.GetGauge().GetValue()
+						}
+					}
+				}
+				return "", -1
+			}
+			roleOf := func(pod string) string {
+				role, _ := instanceStatus(pod)
+				return role
+			}
+			key := metrics.CollectorKey{UID: "down-uid", Pod: "down-master-0"}
+
+			By("using the role from the resource status before the first successful INFO")
+			// 端口 1 上没有 Redis，收集器从第一次采集起就不可用
+			manager.SyncRedisCollector(key, metrics.Endpoint{Addrs: []string{"127.0.0.1:1"}}, "default", "down", "master")
+			Eventually(func() float64 {
+				_, value := instanceStatus(key.Pod)
+				return value
+			}).Should(Equal(0.0))
+			Expect(roleOf(key.Pod)).To(Equal("master"))
+
+			By("keeping the last known role when the collector is recreated after a Pod IP change")
+			manager.SyncRedisCollector(key, metrics.Endpoint{Addrs: []string{"127.0.0.2:1"}}, "default", "down", "")
+			Eventually(func() string { return roleOf(key.Pod) }).Should(Equal("master"))
+			Consistently(func() string { return roleOf(key.Pod) }, 100*time.Millisecond).Should(Equal("master"))
+
+			By("resolving the role of Pods from the workload status")
+			Expect(redisPodRole(&redisv1.RedisInstance{}, "instance-0")).To(Equal("master"))
+			masterReplica := &redisv1.RedisMasterReplica{}
+			Expect(redisPodRole(masterReplica, "mr-master-0")).To(BeEmpty())
+			masterReplica.Status.Master.PodName = "mr-replica-0"
+			Expect(redisPodRole(masterReplica, "mr-replica-0")).To(Equal("master"))
+			Expect(redisPodRole(masterReplica, "mr-master-0")).To(Equal("replica"))
+		})
+	})
+
+	Context("When tracing reconciles", func() {
+		It("should record Kubernetes API calls and Redis commands as child spans", func() {
+			recorder := tracetest.NewSpanRecorder()
+			previous := otel.GetTracerProvider()
+			otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+			DeferCleanup(func() { otel.SetTracerProvider(previous) })
+
+			watchClient, err := client.NewWithWatch(cfg, client.Options{Scheme: k8sClient.Scheme()})
+			Expect(err).NotTo(HaveOccurred())
+			tracedClient := tracing.WrapClient(watchClient)
+			inner := reconcile.Func(func(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+				redisClient := newRedisClient(&redis.Options{Addr: "127.0.0.1:1", Password: "secret", MaxRetries: -1})
+				defer redisClient.Close()
+				Expect(redisClient.ConfigSet(ctx, "masterauth", "secret").Err()).To(HaveOccurred())
+				return reconcile.Result{}, nil
+			})
+			reconciler := instrumentReconciler("RedisInstance", tracedClient, func() client.Object { return &redisv1.RedisInstance{} }, inner)
+			_, err = reconciler.Reconcile(context.Background(), reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: "traced-instance", Namespace: "default"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			spans := map[string]sdktrace.ReadOnlySpan{}
+			for _, span := range recorder.Ended() {
+				spans[span.Name()] = span
+			}
+			Expect(spans).To(HaveKey("Reconcile RedisInstance"))
+			Expect(spans).To(HaveKey("k8s get RedisInstance"))
+			Expect(spans).To(HaveKey("redis CONFIG SET"))
+			root := spans["Reconcile RedisInstance"].SpanContext().SpanID()
+
+			get := spans["k8s get RedisInstance"]
+			Expect(get.Parent().SpanID()).To(Equal(root))
+			Expect(get.Status().Code).To(Equal(codes.Unset))
+			Expect(get.Attributes()).To(ContainElement(attribute.Bool("k8s.not_found", true)))
+
+			configSet := spans["redis CONFIG SET"]
+			Expect(configSet.Parent().SpanID()).To(Equal(root))
+			Expect(configSet.Status().Code).To(Equal(codes.Error))
+			for _, attr := range configSet.Attributes() {
+				Expect(attr.Value.Emit()).NotTo(ContainSubstring("secret"))
+			}
+		})
+	})
+
+	Context("When computing status conditions", func() {
+		const generation = 3
+
+		It("should report a completed rollout as Ready and Available", func() {
+			var conditions []metav1.Condition
+			ready := setWorkloadConditions(&conditions, generation, workloadObservation{Desired: 3, Ready: 3, Updated: 3, MinAvailable: 1})
+			Expect(ready).To(BeTrue())
+
+			for conditionType, status := range map[string]metav1.ConditionStatus{
+				redisv1.ConditionReady:         metav1.ConditionTrue,
+				redisv1.ConditionAvailable:     metav1.ConditionTrue,
+				redisv1.ConditionProgressing:   metav1.ConditionFalse,
+				redisv1.ConditionDegraded:      metav1.ConditionFalse,
+				redisv1.ConditionConfigApplied: metav1.ConditionTrue,
+			} {
+				condition := meta.FindStatusCondition(conditions, conditionType)
+				Expect(condition).NotTo(BeNil(), conditionType)
+				Expect(condition.Status).To(Equal(status), conditionType)
+				Expect(condition.ObservedGeneration).To(BeEquivalentTo(generation))
+			}
+		})
+
+		It("should keep the transition time while the status is unchanged", func() {
+			var conditions []metav1.Condition
+			setWorkloadConditions(&conditions, generation, workloadObservation{Desired: 3, Ready: 3, Updated: 3, MinAvailable: 1})
+			ready := meta.FindStatusCondition(conditions, redisv1.ConditionReady)
+			transitioned := metav1.NewTime(ready.LastTransitionTime.Add(-time.Hour))
+			ready.LastTransitionTime = transitioned
+
+			setWorkloadConditions(&conditions, generation+1, workloadObservation{Desired: 3, Ready: 3, Updated: 3, MinAvailable: 1})
+			ready = meta.FindStatusCondition(conditions, redisv1.ConditionReady)
+			Expect(ready.LastTransitionTime).To(Equal(transitioned))
+			Expect(ready.ObservedGeneration).To(BeEquivalentTo(generation + 1))
+
+			setWorkloadConditions(&conditions, generation+1, workloadObservation{Desired: 3, Ready: 2, Updated: 3, MinAvailable: 1})
+			ready = meta.FindStatusCondition(conditions, redisv1.ConditionReady)
+			Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+			Expect(ready.LastTransitionTime).NotTo(Equal(transitioned))
+		})
+
+		It("should treat a rolling update as progressing rather than degraded", func() {
+			var conditions []metav1.Condition
+			ready := setWorkloadConditions(&conditions, generation, workloadObservation{Desired: 3, Ready: 2, Updated: 1, MinAvailable: 1})
+			Expect(ready).To(BeFalse())
+			Expect(meta.IsStatusConditionTrue(conditions, redisv1.ConditionAvailable)).To(BeTrue())
+			Expect(meta.FindStatusCondition(conditions, redisv1.ConditionProgressing).Reason).To(Equal("RollingUpdate"))
+			Expect(meta.IsStatusConditionFalse(conditions, redisv1.ConditionDegraded)).To(BeTrue())
+			Expect(meta.FindStatusCondition(conditions, redisv1.ConditionConfigApplied).Reason).To(Equal("RollingOut"))
+
+			setWorkloadConditions(&conditions, generation, workloadObservation{Desired: 3, Ready: 2, Updated: 3, MinAvailable: 1})
+			Expect(meta.FindStatusCondition(conditions, redisv1.ConditionDegraded).Reason).To(Equal("PodsUnavailable"))
+		})
+
+		It("should report missing resources and invalid configuration", func() {
+			var conditions []metav1.Condition
+			setWorkloadConditions(&conditions, generation, workloadObservation{
+				MinAvailable: 1,
+				Missing:      "StatefulSet not found",
+				ConfigErrors: []string{"spec.config[maxmemory-polcy]: unknown directive"},
+			})
+			Expect(meta.IsStatusConditionFalse(conditions, redisv1.ConditionAvailable)).To(BeTrue())
+			Expect(meta.FindStatusCondition(conditions, redisv1.ConditionProgressing).Reason).To(Equal("Blocked"))
+			Expect(meta.FindStatusCondition(conditions, redisv1.ConditionDegraded).Reason).To(Equal("ResourceMissing"))
+			Expect(meta.FindStatusCondition(conditions, redisv1.ConditionConfigApplied).Reason).To(Equal("InvalidConfig"))
+		})
+	})
 })
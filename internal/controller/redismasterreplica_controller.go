@@ -20,16 +20,17 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -41,12 +42,14 @@ import (
 	"github.com/go-logr/logr"
 	redisv1 "github.com/ybooks240/redis-operator/api/v1"
 	"github.com/ybooks240/redis-operator/internal/metrics"
+	"github.com/ybooks240/redis-operator/internal/utils"
 )
 
 // RedisMasterReplicaReconciler reconciles a RedisMasterReplica object
 type RedisMasterReplicaReconciler struct {
 	client.Client
 	Scheme         *runtime.Scheme
+	Recorder       record.EventRecorder
 	MetricsManager *metrics.MetricsCollectionManager
 }
 
@@ -56,7 +59,7 @@ type RedisMasterReplicaReconciler struct {
 // +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
-// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -80,8 +83,15 @@ func (r *RedisMasterReplicaReconciler) Reconcile(ctx context.Context, req ctrl.R
 		logs.Info("RedisMasterReplica is being deleted, cleaning up resources", "name", redisMasterReplica.Name)
 		if err = r.cleanupResources(ctx, req, redisMasterReplica, logs); err != nil {
 			logs.Error(err, "Failed to cleanup resources")
+			r.Recorder.Eventf(redisMasterReplica, corev1.EventTypeWarning, eventReasonCleanupFailed, "Failed to clean up resources: %v", err)
 			return ctrl.Result{}, err
 		}
+		r.Recorder.Event(redisMasterReplica, corev1.EventTypeNormal, eventReasonCleanup, "Deleted dependent resources")
+		// 关闭指标收集器并删除资源的指标序列
+		if r.MetricsManager != nil {
+			r.MetricsManager.RemoveCollectors(redisMasterReplica.UID)
+		}
+		metrics.DeleteResourceMetrics("RedisMasterReplica", redisMasterReplica.Namespace, redisMasterReplica.Name)
 		// 移除 finalizer，使用重试机制避免资源版本冲突
 		err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
 			// 重新获取最新的资源版本
@@ -111,9 +121,18 @@ func (r *RedisMasterReplicaReconciler) Reconcile(ctx context.Context, req ctrl.R
 	}
 
 	// 确保所有资源存在并正确配置
-	err = r.ensureResources(ctx, req, redisMasterReplica, logs)
-	if err != nil {
+	// 配置指令无效时保持现有资源不变，错误通过 ConfigValid 状态条件报告
+	if configErrors := workloadConfigErrors(ctx, r.Client, redisMasterReplica); len(configErrors) > 0 {
+		logs.Info("Invalid Redis configuration, skipping resource update", "errors", configErrors)
+		r.Recorder.Eventf(redisMasterReplica, corev1.EventTypeWarning, eventReasonInvalidConfig, "Skipping resource update: %s", configErrors[0])
+	} else if err = r.ensureResources(ctx, req, redisMasterReplica, logs); err != nil {
 		logs.Error(err, "Failed to ensure resources")
+		r.Recorder.Eventf(redisMasterReplica, corev1.EventTypeWarning, eventReasonReconcileFailed, "Failed to ensure resources: %v", err)
+		if isSpecError(err) {
+			if statusErr := reportInvalidSpec(ctx, r.Client, redisMasterReplica, &redisMasterReplica.Status.Conditions, err); statusErr != nil {
+				logs.Error(statusErr, "Failed to report invalid spec")
+			}
+		}
 		return ctrl.Result{}, err
 	}
 
@@ -124,38 +143,24 @@ func (r *RedisMasterReplicaReconciler) Reconcile(ctx context.Context, req ctrl.R
 		return ctrl.Result{}, err
 	}
 
+	// 密码 Secret 变化时执行零停机密码轮换
+	if err = reconcilePasswordRotation(ctx, r.Client, r.Scheme, "RedisMasterReplica", redisMasterReplica, redisMasterReplica.Spec.Security, redisMasterReplica.Status.PasswordRotation, logs); err != nil {
+		logs.Error(err, "Failed to rotate password")
+	}
+
 	// 添加指标收集器
 	if r.MetricsManager != nil {
-		// 为主节点添加指标收集器
-		masterAddr := fmt.Sprintf("%s-master-service.%s.svc.cluster.local:6379", redisMasterReplica.Name, redisMasterReplica.Namespace)
-		masterCollector := metrics.NewRedisCollector(
-			masterAddr,
-			"", // password
-			redisMasterReplica.Namespace,
-			redisMasterReplica.Name+"-master",
-			"master",
-		)
-		r.MetricsManager.AddRedisCollector(masterCollector)
-
-		// 为从节点添加指标收集器
-		replicaAddr := fmt.Sprintf("%s-replica-service.%s.svc.cluster.local:6379", redisMasterReplica.Name, redisMasterReplica.Namespace)
-		replicaCollector := metrics.NewRedisCollector(
-			replicaAddr,
-			"", // password
-			redisMasterReplica.Namespace,
-			redisMasterReplica.Name+"-replica",
-			"replica",
-		)
-		r.MetricsManager.AddRedisCollector(replicaCollector)
-
-		// 记录协调操作指标
-		start := time.Now()
-		statusValue := 1.0
-		if redisMasterReplica.Status.Status != "Ready" {
-			statusValue = 0.0
+		// 为主节点和从节点的每个 Pod 注册指标收集器，角色由收集器从 INFO replication 读取
+		if err := syncRedisPodCollectors(ctx, r.Client, r.MetricsManager, "RedisMasterReplica", redisMasterReplica, redisMasterReplica.Spec.Security); err != nil {
+			logs.Error(err, "Failed to register metrics collectors")
 		}
-		metrics.RecordReconcile(redisMasterReplica.Namespace, redisMasterReplica.Name, "success", "master-replica", float64(time.Since(start).Milliseconds()))
-		metrics.SetRedisInstanceStatus(redisMasterReplica.Namespace, redisMasterReplica.Name, "master-replica", statusValue)
+
+		// 更新资源状态指标，协调耗时和结果由 instrumentReconciler 记录
+		statusValue := 0.0
+		if redisMasterReplica.Status.Status == string(redisv1.RedisMasterReplicaPhaseRunning) {
+			statusValue = 1.0
+		}
+		metrics.SetResourceStatus("RedisMasterReplica", redisMasterReplica.Namespace, redisMasterReplica.Name, "ready", statusValue)
 	}
 
 	return ctrl.Result{RequeueAfter: time.Second * 30}, nil
@@ -204,6 +209,7 @@ func (r *RedisMasterReplicaReconciler) cleanupResources(ctx context.Context, req
 	// 删除 Services 和 ConfigMaps
 	resourcesToDelete := []client.Object{
 		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: redisMasterReplica.Name + "-master-service", Namespace: redisMasterReplica.Namespace}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: redisMasterReplica.Name + "-master-headless", Namespace: redisMasterReplica.Namespace}},
 		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: redisMasterReplica.Name + "-replica-service", Namespace: redisMasterReplica.Namespace}},
 		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: redisMasterReplica.Name + "-master-config", Namespace: redisMasterReplica.Namespace}},
 		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: redisMasterReplica.Name + "-replica-config", Namespace: redisMasterReplica.Namespace}},
@@ -228,18 +234,30 @@ func (r *RedisMasterReplicaReconciler) cleanupResources(ctx context.Context, req
 
 // ensureResources 确保所有必要的资源存在
 func (r *RedisMasterReplicaReconciler) ensureResources(ctx context.Context, req ctrl.Request, redisMasterReplica *redisv1.RedisMasterReplica, logs logr.Logger) error {
+	// 启用认证且未引用密码 Secret 时生成随机密码
+	if err := utils.EnsurePasswordSecret(ctx, r.Client, r.Scheme, redisMasterReplica, redisMasterReplica.Spec.Security); err != nil {
+		return err
+	}
+
+	// 读取引用的配置模板
+	profile, err := resolveConfigProfile(ctx, r.Client, redisMasterReplica.Namespace, redisMasterReplica.Spec.ConfigRef)
+	if err != nil {
+		return err
+	}
+	redisMasterReplica.Status.ConfigRevision = renderedConfigRevision(redisMasterReplica.Spec.ConfigRef, profile)
+
 	// 确保主节点 ConfigMap
-	if err := r.ensureMasterConfigMap(ctx, redisMasterReplica, logs); err != nil {
+	if err := r.ensureMasterConfigMap(ctx, redisMasterReplica, profile, logs); err != nil {
 		return err
 	}
 
 	// 确保从节点 ConfigMap
-	if err := r.ensureReplicaConfigMap(ctx, redisMasterReplica, logs); err != nil {
+	if err := r.ensureReplicaConfigMap(ctx, redisMasterReplica, profile, logs); err != nil {
 		return err
 	}
 
 	// 确保主节点 StatefulSet
-	if err := r.ensureMasterStatefulSet(ctx, redisMasterReplica, logs); err != nil {
+	if err := r.ensureMasterStatefulSet(ctx, redisMasterReplica, profile, logs); err != nil {
 		return err
 	}
 
@@ -253,35 +271,49 @@ func (r *RedisMasterReplicaReconciler) ensureResources(ctx context.Context, req
 		return err
 	}
 
+	// 确保主节点 Headless Service
+	if err := r.ensureMasterHeadlessService(ctx, redisMasterReplica, logs); err != nil {
+		return err
+	}
+
 	// 确保从节点 Service
 	if err := r.ensureReplicaService(ctx, redisMasterReplica, logs); err != nil {
 		return err
 	}
 
+	// 确保 exporter metrics Service 和 ServiceMonitor
+	if err := ensureMonitoring(ctx, r.Client, r.Scheme, r.Recorder, "RedisMasterReplica", redisMasterReplica, redisMasterReplica.Spec.Security, redisMasterReplica.Spec.Monitoring, newAlertTopology("RedisMasterReplica", redisMasterReplica.Name)); err != nil {
+		return err
+	}
+
 	return nil
 }
 
 // ensureMasterConfigMap 确保主节点 ConfigMap 存在
-func (r *RedisMasterReplicaReconciler) ensureMasterConfigMap(ctx context.Context, redisMasterReplica *redisv1.RedisMasterReplica, logs logr.Logger) error {
+func (r *RedisMasterReplicaReconciler) ensureMasterConfigMap(ctx context.Context, redisMasterReplica *redisv1.RedisMasterReplica, profile *redisv1.ConfigSpec, logs logr.Logger) error {
 	configMap := &corev1.ConfigMap{}
 	configMapName := redisMasterReplica.Name + "-master-config"
 	err := r.Get(ctx, types.NamespacedName{Name: configMapName, Namespace: redisMasterReplica.Namespace}, configMap)
 
 	if errors.IsNotFound(err) {
 		// 创建新的 ConfigMap
-		configMap = r.configMapForMaster(redisMasterReplica)
+		configMap = r.configMapForMaster(redisMasterReplica, profile)
 		if err = controllerutil.SetControllerReference(redisMasterReplica, configMap, r.Scheme); err != nil {
 			return err
 		}
 		controllerutil.AddFinalizer(configMap, redisv1.RedisMasterReplicaFinalizer)
 		logs.Info("Creating master ConfigMap", "name", configMap.Name)
-		return r.Create(ctx, configMap)
+		if err := r.Create(ctx, configMap); err != nil {
+			return err
+		}
+		recordCreated(r.Recorder, redisMasterReplica, "ConfigMap", configMap.Name)
+		return nil
 	} else if err != nil {
 		return err
 	}
 
 	// 检查 ConfigMap 是否需要更新
-	desiredConfigMap := r.configMapForMaster(redisMasterReplica)
+	desiredConfigMap := r.configMapForMaster(redisMasterReplica, profile)
 	if !reflect.DeepEqual(configMap.Data, desiredConfigMap.Data) {
 		// 设置状态为 Updating
 		if err := r.setUpdatingStatus(ctx, redisMasterReplica, "Updating master ConfigMap"); err != nil {
@@ -290,33 +322,41 @@ func (r *RedisMasterReplicaReconciler) ensureMasterConfigMap(ctx context.Context
 
 		configMap.Data = desiredConfigMap.Data
 		logs.Info("Updating master ConfigMap", "name", configMap.Name)
-		return r.Update(ctx, configMap)
+		if err := r.Update(ctx, configMap); err != nil {
+			return err
+		}
+		r.Recorder.Eventf(redisMasterReplica, corev1.EventTypeNormal, eventReasonConfigChanged, "Updated ConfigMap %s", configMap.Name)
+		return nil
 	}
 
 	return nil
 }
 
 // ensureReplicaConfigMap 确保从节点 ConfigMap 存在
-func (r *RedisMasterReplicaReconciler) ensureReplicaConfigMap(ctx context.Context, redisMasterReplica *redisv1.RedisMasterReplica, logs logr.Logger) error {
+func (r *RedisMasterReplicaReconciler) ensureReplicaConfigMap(ctx context.Context, redisMasterReplica *redisv1.RedisMasterReplica, profile *redisv1.ConfigSpec, logs logr.Logger) error {
 	configMap := &corev1.ConfigMap{}
 	configMapName := redisMasterReplica.Name + "-replica-config"
 	err := r.Get(ctx, types.NamespacedName{Name: configMapName, Namespace: redisMasterReplica.Namespace}, configMap)
 
 	if errors.IsNotFound(err) {
 		// 创建新的 ConfigMap
-		configMap = r.configMapForReplica(redisMasterReplica)
+		configMap = r.configMapForReplica(redisMasterReplica, profile)
 		if err = controllerutil.SetControllerReference(redisMasterReplica, configMap, r.Scheme); err != nil {
 			return err
 		}
 		controllerutil.AddFinalizer(configMap, redisv1.RedisMasterReplicaFinalizer)
 		logs.Info("Creating replica ConfigMap", "name", configMap.Name)
-		return r.Create(ctx, configMap)
+		if err := r.Create(ctx, configMap); err != nil {
+			return err
+		}
+		recordCreated(r.Recorder, redisMasterReplica, "ConfigMap", configMap.Name)
+		return nil
 	} else if err != nil {
 		return err
 	}
 
 	// 检查 ConfigMap 是否需要更新
-	desiredConfigMap := r.configMapForReplica(redisMasterReplica)
+	desiredConfigMap := r.configMapForReplica(redisMasterReplica, profile)
 	if !reflect.DeepEqual(configMap.Data, desiredConfigMap.Data) {
 		// 设置状态为 Updating
 		if err := r.setUpdatingStatus(ctx, redisMasterReplica, "Updating replica ConfigMap"); err != nil {
@@ -325,33 +365,64 @@ func (r *RedisMasterReplicaReconciler) ensureReplicaConfigMap(ctx context.Contex
 
 		configMap.Data = desiredConfigMap.Data
 		logs.Info("Updating replica ConfigMap", "name", configMap.Name)
-		return r.Update(ctx, configMap)
+		if err := r.Update(ctx, configMap); err != nil {
+			return err
+		}
+		r.Recorder.Eventf(redisMasterReplica, corev1.EventTypeNormal, eventReasonConfigChanged, "Updated ConfigMap %s", configMap.Name)
+		return nil
 	}
 
 	return nil
 }
 
 // ensureMasterStatefulSet 确保主节点 StatefulSet 存在
-func (r *RedisMasterReplicaReconciler) ensureMasterStatefulSet(ctx context.Context, redisMasterReplica *redisv1.RedisMasterReplica, logs logr.Logger) error {
+func (r *RedisMasterReplicaReconciler) ensureMasterStatefulSet(ctx context.Context, redisMasterReplica *redisv1.RedisMasterReplica, profile *redisv1.ConfigSpec, logs logr.Logger) error {
 	statefulSet := &appsv1.StatefulSet{}
 	statefulSetName := redisMasterReplica.Name + "-master"
 	err := r.Get(ctx, types.NamespacedName{Name: statefulSetName, Namespace: redisMasterReplica.Namespace}, statefulSet)
 
 	if errors.IsNotFound(err) {
 		// 创建新的 StatefulSet
-		statefulSet = r.statefulSetForMaster(redisMasterReplica)
+		statefulSet, err = r.statefulSetForMaster(redisMasterReplica)
+		if err != nil {
+			return err
+		}
 		if err = controllerutil.SetControllerReference(redisMasterReplica, statefulSet, r.Scheme); err != nil {
 			return err
 		}
+		if err = utils.AnnotateTLSCertHash(ctx, r.Client, redisMasterReplica.Namespace, redisMasterReplica.Spec.Security.TLS, &statefulSet.Spec.Template); err != nil {
+			return err
+		}
 		controllerutil.AddFinalizer(statefulSet, redisv1.RedisMasterReplicaFinalizer)
+		// 恢复来源未就绪时暂不创建 StatefulSet，原因由 Restored 条件展示
+		if err = r.applyMasterRestore(ctx, redisMasterReplica, profile, statefulSet); err != nil {
+			logs.Info("Restore source not ready, postponing master StatefulSet creation", "reason", err.Error())
+			r.Recorder.Eventf(redisMasterReplica, corev1.EventTypeNormal, eventReasonRestorePending, "Postponing master StatefulSet creation: %v", err)
+			return nil
+		}
 		logs.Info("Creating master StatefulSet", "name", statefulSet.Name)
-		return r.Create(ctx, statefulSet)
+		if err := r.Create(ctx, statefulSet); err != nil {
+			return err
+		}
+		recordCreated(r.Recorder, redisMasterReplica, "StatefulSet", statefulSet.Name)
+		return nil
 	} else if err != nil {
 		return err
 	}
 
 	// 检查 StatefulSet 是否需要更新
-	desiredStatefulSet := r.statefulSetForMaster(redisMasterReplica)
+	desiredStatefulSet, err := r.statefulSetForMaster(redisMasterReplica)
+	if err != nil {
+		return err
+	}
+	if err := utils.AnnotateTLSCertHash(ctx, r.Client, redisMasterReplica.Namespace, redisMasterReplica.Spec.Security.TLS, &desiredStatefulSet.Spec.Template); err != nil {
+		return err
+	}
+	// 恢复完成前更新模板需要保留恢复初始化容器
+	if err := r.applyMasterRestore(ctx, redisMasterReplica, profile, desiredStatefulSet); err != nil {
+		logs.Info("Restore source not ready, postponing master StatefulSet update", "reason", err.Error())
+		return nil
+	}
 	needsUpdate := false
 
 	// 检查副本数
@@ -373,6 +444,21 @@ func (r *RedisMasterReplicaReconciler) ensureMasterStatefulSet(ctx context.Conte
 		}
 	}
 
+	// 检查认证配置
+	if utils.AuthSecretChanged(&statefulSet.Spec.Template, utils.PasswordSecretRef(redisMasterReplica.Spec.Security, redisMasterReplica.Name)) {
+		needsUpdate = true
+	}
+
+	// 检查 TLS 证书，证书轮换后滚动更新
+	if utils.TLSCertChanged(&statefulSet.Spec.Template, &desiredStatefulSet.Spec.Template) {
+		needsUpdate = true
+	}
+
+	// 检查 exporter sidecar
+	if utils.ExporterChanged(&statefulSet.Spec.Template, &desiredStatefulSet.Spec.Template) {
+		needsUpdate = true
+	}
+
 	if needsUpdate {
 		// 设置状态为 Updating
 		if err := r.setUpdatingStatus(ctx, redisMasterReplica, "Updating master StatefulSet"); err != nil {
@@ -382,7 +468,11 @@ func (r *RedisMasterReplicaReconciler) ensureMasterStatefulSet(ctx context.Conte
 		statefulSet.Spec.Replicas = desiredStatefulSet.Spec.Replicas
 		statefulSet.Spec.Template = desiredStatefulSet.Spec.Template
 		logs.Info("Updating master StatefulSet", "name", statefulSet.Name)
-		return r.Update(ctx, statefulSet)
+		if err := r.Update(ctx, statefulSet); err != nil {
+			return err
+		}
+		r.Recorder.Eventf(redisMasterReplica, corev1.EventTypeNormal, eventReasonRollingUpdate, "Rolling update of StatefulSet %s", statefulSet.Name)
+		return nil
 	}
 
 	return nil
@@ -395,20 +485,42 @@ func (r *RedisMasterReplicaReconciler) ensureReplicaStatefulSet(ctx context.Cont
 	err := r.Get(ctx, types.NamespacedName{Name: statefulSetName, Namespace: redisMasterReplica.Namespace}, statefulSet)
 
 	if errors.IsNotFound(err) {
+		// 主节点加载恢复的数据后再创建从节点，避免从节点同步空数据
+		if redisMasterReplica.Spec.RestoreFrom != nil && !restoreCompleted(redisMasterReplica.Status.Conditions) {
+			logs.Info("Waiting for the master to restore data before creating replicas")
+			return nil
+		}
+
 		// 创建新的 StatefulSet
-		statefulSet = r.statefulSetForReplica(redisMasterReplica)
+		statefulSet, err = r.statefulSetForReplica(redisMasterReplica)
+		if err != nil {
+			return err
+		}
 		if err = controllerutil.SetControllerReference(redisMasterReplica, statefulSet, r.Scheme); err != nil {
 			return err
 		}
+		if err = utils.AnnotateTLSCertHash(ctx, r.Client, redisMasterReplica.Namespace, redisMasterReplica.Spec.Security.TLS, &statefulSet.Spec.Template); err != nil {
+			return err
+		}
 		controllerutil.AddFinalizer(statefulSet, redisv1.RedisMasterReplicaFinalizer)
 		logs.Info("Creating replica StatefulSet", "name", statefulSet.Name)
-		return r.Create(ctx, statefulSet)
+		if err := r.Create(ctx, statefulSet); err != nil {
+			return err
+		}
+		recordCreated(r.Recorder, redisMasterReplica, "StatefulSet", statefulSet.Name)
+		return nil
 	} else if err != nil {
 		return err
 	}
 
 	// 检查 StatefulSet 是否需要更新
-	desiredStatefulSet := r.statefulSetForReplica(redisMasterReplica)
+	desiredStatefulSet, err := r.statefulSetForReplica(redisMasterReplica)
+	if err != nil {
+		return err
+	}
+	if err := utils.AnnotateTLSCertHash(ctx, r.Client, redisMasterReplica.Namespace, redisMasterReplica.Spec.Security.TLS, &desiredStatefulSet.Spec.Template); err != nil {
+		return err
+	}
 	needsUpdate := false
 
 	// 检查副本数
@@ -430,6 +542,21 @@ func (r *RedisMasterReplicaReconciler) ensureReplicaStatefulSet(ctx context.Cont
 		}
 	}
 
+	// 检查认证配置
+	if utils.AuthSecretChanged(&statefulSet.Spec.Template, utils.PasswordSecretRef(redisMasterReplica.Spec.Security, redisMasterReplica.Name)) {
+		needsUpdate = true
+	}
+
+	// 检查 TLS 证书，证书轮换后滚动更新
+	if utils.TLSCertChanged(&statefulSet.Spec.Template, &desiredStatefulSet.Spec.Template) {
+		needsUpdate = true
+	}
+
+	// 检查 exporter sidecar
+	if utils.ExporterChanged(&statefulSet.Spec.Template, &desiredStatefulSet.Spec.Template) {
+		needsUpdate = true
+	}
+
 	if needsUpdate {
 		// 设置状态为 Updating
 		if err := r.setUpdatingStatus(ctx, redisMasterReplica, "Updating replica StatefulSet"); err != nil {
@@ -439,7 +566,11 @@ func (r *RedisMasterReplicaReconciler) ensureReplicaStatefulSet(ctx context.Cont
 		statefulSet.Spec.Replicas = desiredStatefulSet.Spec.Replicas
 		statefulSet.Spec.Template = desiredStatefulSet.Spec.Template
 		logs.Info("Updating replica StatefulSet", "name", statefulSet.Name)
-		return r.Update(ctx, statefulSet)
+		if err := r.Update(ctx, statefulSet); err != nil {
+			return err
+		}
+		r.Recorder.Eventf(redisMasterReplica, corev1.EventTypeNormal, eventReasonRollingUpdate, "Rolling update of StatefulSet %s", statefulSet.Name)
+		return nil
 	}
 
 	return nil
@@ -459,7 +590,37 @@ func (r *RedisMasterReplicaReconciler) ensureMasterService(ctx context.Context,
 		}
 		controllerutil.AddFinalizer(service, redisv1.RedisMasterReplicaFinalizer)
 		logs.Info("Creating master Service", "name", service.Name)
-		return r.Create(ctx, service)
+		if err := r.Create(ctx, service); err != nil {
+			return err
+		}
+		recordCreated(r.Recorder, redisMasterReplica, "Service", service.Name)
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ensureMasterHeadlessService 确保主节点 Headless Service 存在，为 master Pod 提供稳定的 DNS 名称
+func (r *RedisMasterReplicaReconciler) ensureMasterHeadlessService(ctx context.Context, redisMasterReplica *redisv1.RedisMasterReplica, logs logr.Logger) error {
+	service := &corev1.Service{}
+	serviceName := redisMasterReplica.Name + "-master-headless"
+	err := r.Get(ctx, types.NamespacedName{Name: serviceName, Namespace: redisMasterReplica.Namespace}, service)
+
+	if errors.IsNotFound(err) {
+		// 创建新的 Service
+		service = r.headlessServiceForMaster(redisMasterReplica)
+		if err = controllerutil.SetControllerReference(redisMasterReplica, service, r.Scheme); err != nil {
+			return err
+		}
+		controllerutil.AddFinalizer(service, redisv1.RedisMasterReplicaFinalizer)
+		logs.Info("Creating master headless Service", "name", service.Name)
+		if err := r.Create(ctx, service); err != nil {
+			return err
+		}
+		recordCreated(r.Recorder, redisMasterReplica, "Service", service.Name)
+		return nil
 	} else if err != nil {
 		return err
 	}
@@ -481,7 +642,11 @@ func (r *RedisMasterReplicaReconciler) ensureReplicaService(ctx context.Context,
 		}
 		controllerutil.AddFinalizer(service, redisv1.RedisMasterReplicaFinalizer)
 		logs.Info("Creating replica Service", "name", service.Name)
-		return r.Create(ctx, service)
+		if err := r.Create(ctx, service); err != nil {
+			return err
+		}
+		recordCreated(r.Recorder, redisMasterReplica, "Service", service.Name)
+		return nil
 	} else if err != nil {
 		return err
 	}
@@ -502,6 +667,7 @@ func (r *RedisMasterReplicaReconciler) doUpdateRedisMasterReplicaStatus(ctx cont
 	if err := r.Get(ctx, types.NamespacedName{Name: redisMasterReplica.Name, Namespace: redisMasterReplica.Namespace}, latestMasterReplica); err != nil {
 		return err
 	}
+	previousReady := latestMasterReplica.Status.Ready
 
 	// 获取主节点 StatefulSet 状态
 	masterSts := &appsv1.StatefulSet{}
@@ -513,23 +679,50 @@ func (r *RedisMasterReplicaReconciler) doUpdateRedisMasterReplicaStatus(ctx cont
 	replicaStsName := latestMasterReplica.Name + "-replica"
 	replicaErr := r.Get(ctx, types.NamespacedName{Name: replicaStsName, Namespace: latestMasterReplica.Namespace}, replicaSts)
 
+	// 更新创建时恢复数据的进度，只有主节点执行恢复
+	if latestMasterReplica.Spec.RestoreFrom != nil {
+		pods, err := listWorkloadRedisPods(ctx, r.Client, "RedisMasterReplica", latestMasterReplica.Namespace, latestMasterReplica.Name)
+		if err != nil {
+			return err
+		}
+		var masterPods []corev1.Pod
+		for _, pod := range pods {
+			if strings.HasPrefix(pod.Name, masterStsName+"-") {
+				masterPods = append(masterPods, pod)
+			}
+		}
+		setRestoreCondition(ctx, r.Client, latestMasterReplica.Namespace, latestMasterReplica.Spec.RestoreFrom, masterPods, &latestMasterReplica.Status.Conditions)
+	}
+	restoring := latestMasterReplica.Spec.RestoreFrom != nil && !restoreCompleted(latestMasterReplica.Status.Conditions)
+
 	// 更新状态
-	if masterErr != nil || replicaErr != nil {
+	var message string
+	observed := workloadObservation{
+		MinAvailable: 1,
+		ConfigErrors: setConfigValidCondition(ctx, r.Client, latestMasterReplica, &latestMasterReplica.Status.Conditions),
+	}
+	if latestMasterReplica.Spec.RestoreFrom != nil {
+		observed.Failure = restoreFailureMessage(latestMasterReplica.Status.Conditions)
+	}
+	if restoring && errors.IsNotFound(replicaErr) {
+		latestMasterReplica.Status.Status = string(redisv1.RedisMasterReplicaPhasePending)
+		message = "Waiting for the master to restore data, see the Restored condition"
+		observed.Waiting = message
+	} else if masterErr != nil || replicaErr != nil {
 		latestMasterReplica.Status.Status = string(redisv1.RedisMasterReplicaPhaseFailed)
-		latestMasterReplica.Status.Ready = "False"
-		latestMasterReplica.Status.LastConditionMessage = "Failed to get StatefulSets"
+		message = "Failed to get StatefulSets"
+		observed.Missing = fmt.Sprintf("%s: master: %v, replica: %v", message, masterErr, replicaErr)
 	} else {
+		observeStatefulSets(&observed, masterSts, replicaSts)
 		masterReady := masterSts.Status.ReadyReplicas == *masterSts.Spec.Replicas
 		replicaReady := replicaSts.Status.ReadyReplicas == *replicaSts.Spec.Replicas
 
 		if masterReady && replicaReady {
 			latestMasterReplica.Status.Status = string(redisv1.RedisMasterReplicaPhaseRunning)
-			latestMasterReplica.Status.Ready = "True"
-			latestMasterReplica.Status.LastConditionMessage = "All master and replica nodes are ready"
+			message = "All master and replica nodes are ready"
 		} else {
 			latestMasterReplica.Status.Status = string(redisv1.RedisMasterReplicaPhasePending)
-			latestMasterReplica.Status.Ready = "False"
-			latestMasterReplica.Status.LastConditionMessage = "Waiting for replicas to be ready"
+			message = "Waiting for replicas to be ready"
 		}
 
 		// 更新主节点状态
@@ -544,55 +737,40 @@ func (r *RedisMasterReplicaReconciler) doUpdateRedisMasterReplicaStatus(ctx cont
 		latestMasterReplica.Status.Replica.ServiceName = latestMasterReplica.Name + "-replica-service"
 	}
 
-	// 更新 Conditions
-	condition := metav1.Condition{
-		Type:               "Ready",
-		Status:             metav1.ConditionFalse,
-		Reason:             "NotReady",
-		Message:            latestMasterReplica.Status.LastConditionMessage,
-		LastTransitionTime: metav1.Now(),
-	}
+	// 更新统一的状态条件
+	ready := setWorkloadConditions(&latestMasterReplica.Status.Conditions, latestMasterReplica.Generation, observed)
+	setStorageReadyCondition(ctx, r.Client, "RedisMasterReplica", latestMasterReplica, &latestMasterReplica.Status.Conditions)
+	latestMasterReplica.Status.Ready = string(conditionStatus(ready))
 
-	if latestMasterReplica.Status.Ready == "true" {
-		condition.Status = metav1.ConditionTrue
-		condition.Reason = "Ready"
-	}
+	// 更新 TLS 证书有效期状态
+	setTLSCertificateCondition(ctx, r.Client, "RedisMasterReplica", latestMasterReplica, latestMasterReplica.Spec.Security, &latestMasterReplica.Status.Conditions)
 
-	// 添加或更新条件
-	if latestMasterReplica.Status.Conditions == nil {
-		latestMasterReplica.Status.Conditions = []metav1.Condition{}
+	// 配置指令无效时在状态消息中给出原因
+	latestMasterReplica.Status.LastConditionMessage = message
+	if len(observed.ConfigErrors) > 0 {
+		latestMasterReplica.Status.LastConditionMessage = "Invalid configuration: " + observed.ConfigErrors[0]
 	}
 
-	// 查找现有条件并更新，或添加新条件
-	found := false
-	for i, existingCondition := range latestMasterReplica.Status.Conditions {
-		if existingCondition.Type == condition.Type {
-			latestMasterReplica.Status.Conditions[i] = condition
-			found = true
-			break
-		}
-	}
-	if !found {
-		latestMasterReplica.Status.Conditions = append(latestMasterReplica.Status.Conditions, condition)
-	}
+	// 记录已应用的配置模板版本，配置模板据此展示滚动进度
+	latestMasterReplica.Status.ConfigRevision = redisMasterReplica.Status.ConfigRevision
 
-	// 限制条件数量
-	if len(latestMasterReplica.Status.Conditions) > 10 {
-		latestMasterReplica.Status.Conditions = latestMasterReplica.Status.Conditions[len(latestMasterReplica.Status.Conditions)-10:]
+	if err := r.Status().Update(ctx, latestMasterReplica); err != nil {
+		return err
 	}
-
-	return r.Status().Update(ctx, latestMasterReplica)
+	recordReadyTransition(r.Recorder, latestMasterReplica, previousReady, ready, latestMasterReplica.Status.LastConditionMessage)
+	return nil
 }
 
 // setUpdatingStatus 设置更新状态
 func (r *RedisMasterReplicaReconciler) setUpdatingStatus(ctx context.Context, redisMasterReplica *redisv1.RedisMasterReplica, message string) error {
 	redisMasterReplica.Status.Status = string(redisv1.RedisMasterReplicaPhaseUpdating)
 	redisMasterReplica.Status.LastConditionMessage = message
+	setUpdatingConditions(&redisMasterReplica.Status.Conditions, redisMasterReplica.Generation, message)
 	return r.Status().Update(ctx, redisMasterReplica)
 }
 
 // configMapForMaster 创建主节点 ConfigMap
-func (r *RedisMasterReplicaReconciler) configMapForMaster(redisMasterReplica *redisv1.RedisMasterReplica) *corev1.ConfigMap {
+func (r *RedisMasterReplicaReconciler) configMapForMaster(redisMasterReplica *redisv1.RedisMasterReplica, profile *redisv1.ConfigSpec) *corev1.ConfigMap {
 	redisConfig := map[string]string{
 		"redis.conf": `# Redis Master Configuration
 port 6379
@@ -608,13 +786,7 @@ maxmemory-policy allkeys-lru
 `,
 	}
 
-	// 合并用户自定义配置
-	for key, value := range redisMasterReplica.Spec.Config {
-		redisConfig[key] = value
-	}
-	for key, value := range redisMasterReplica.Spec.Master.Config {
-		redisConfig[key] = value
-	}
+	redisConfig["redis.conf"] = utils.AppendRedisConfig(redisConfig["redis.conf"], masterRedisConfig(redisMasterReplica, profile))
 
 	return &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
@@ -630,8 +802,13 @@ maxmemory-policy allkeys-lru
 	}
 }
 
+// masterRedisConfig 合并配置模板和用户自定义配置，角色配置优先级最高，结果追加到主节点 redis.conf
+func masterRedisConfig(redisMasterReplica *redisv1.RedisMasterReplica, profile *redisv1.ConfigSpec) map[string]string {
+	return mergeProfileConfig(redisMasterReplica.Spec.Master.Config, profile.Redis, redisMasterReplica.Spec.Config)
+}
+
 // configMapForReplica 创建从节点 ConfigMap
-func (r *RedisMasterReplicaReconciler) configMapForReplica(redisMasterReplica *redisv1.RedisMasterReplica) *corev1.ConfigMap {
+func (r *RedisMasterReplicaReconciler) configMapForReplica(redisMasterReplica *redisv1.RedisMasterReplica, profile *redisv1.ConfigSpec) *corev1.ConfigMap {
 	masterServiceName := redisMasterReplica.Name + "-master-service"
 	redisConfig := map[string]string{
 		"redis.conf": fmt.Sprintf(`# Redis Replica Configuration
@@ -645,13 +822,9 @@ maxmemory-policy allkeys-lru
 `, masterServiceName),
 	}
 
-	// 合并用户自定义配置
-	for key, value := range redisMasterReplica.Spec.Config {
-		redisConfig[key] = value
-	}
-	for key, value := range redisMasterReplica.Spec.Replica.Config {
-		redisConfig[key] = value
-	}
+	// 合并配置模板和用户自定义配置，角色配置优先级最高
+	redisConfig["redis.conf"] = utils.AppendRedisConfig(redisConfig["redis.conf"],
+		mergeProfileConfig(redisMasterReplica.Spec.Replica.Config, profile.Redis, redisMasterReplica.Spec.Config))
 
 	return &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
@@ -667,8 +840,28 @@ maxmemory-policy allkeys-lru
 	}
 }
 
+// applyMasterRestore 数据尚未恢复时为主节点 StatefulSet 添加恢复初始化容器，恢复来源未就绪时返回错误
+func (r *RedisMasterReplicaReconciler) applyMasterRestore(ctx context.Context, redisMasterReplica *redisv1.RedisMasterReplica, profile *redisv1.ConfigSpec, statefulSet *appsv1.StatefulSet) error {
+	if redisMasterReplica.Spec.RestoreFrom == nil || restoreCompleted(redisMasterReplica.Status.Conditions) {
+		return nil
+	}
+	location, err := resolveRestoreSource(ctx, r.Client, redisMasterReplica.Namespace, redisMasterReplica.Spec.RestoreFrom)
+	if err != nil {
+		return err
+	}
+	// 与 configMapForMaster 渲染的 redis.conf 保持一致，默认 dbfilename dump.rdb 且未开启 appendonly
+	config := masterRedisConfig(redisMasterReplica, profile)
+	dbfilename := "dump.rdb"
+	if value, ok := config["dbfilename"]; ok {
+		dbfilename = value
+	}
+	appendonly := config["appendonly"] == "yes"
+	applyRestore(&statefulSet.Spec.Template, location, redisMasterReplica.Spec.Image, "data", dbfilename, appendonly)
+	return nil
+}
+
 // statefulSetForMaster 创建主节点 StatefulSet
-func (r *RedisMasterReplicaReconciler) statefulSetForMaster(redisMasterReplica *redisv1.RedisMasterReplica) *appsv1.StatefulSet {
+func (r *RedisMasterReplicaReconciler) statefulSetForMaster(redisMasterReplica *redisv1.RedisMasterReplica) (*appsv1.StatefulSet, error) {
 	replicas := int32(1)
 	resources := redisMasterReplica.Spec.Resources
 	if len(redisMasterReplica.Spec.Master.Resources.Limits) > 0 || len(redisMasterReplica.Spec.Master.Resources.Requests) > 0 {
@@ -701,6 +894,10 @@ func (r *RedisMasterReplicaReconciler) statefulSetForMaster(redisMasterReplica *
 
 	var volumeClaimTemplates []corev1.PersistentVolumeClaim
 	if redisMasterReplica.Spec.Storage.Size != "" {
+		storageSize, err := parseStorageSize("spec.storage.size", redisMasterReplica.Spec.Storage.Size)
+		if err != nil {
+			return nil, err
+		}
 		volumeClaimTemplates = []corev1.PersistentVolumeClaim{
 			{
 				ObjectMeta: metav1.ObjectMeta{
@@ -710,7 +907,7 @@ func (r *RedisMasterReplicaReconciler) statefulSetForMaster(redisMasterReplica *
 					AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
 					Resources: corev1.VolumeResourceRequirements{
 						Requests: corev1.ResourceList{
-							corev1.ResourceStorage: resource.MustParse(redisMasterReplica.Spec.Storage.Size),
+							corev1.ResourceStorage: storageSize,
 						},
 					},
 					StorageClassName: &redisMasterReplica.Spec.Storage.StorageClassName,
@@ -726,7 +923,7 @@ func (r *RedisMasterReplicaReconciler) statefulSetForMaster(redisMasterReplica *
 		})
 	}
 
-	return &appsv1.StatefulSet{
+	statefulSet := &appsv1.StatefulSet{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      redisMasterReplica.Name + "-master",
 			Namespace: redisMasterReplica.Namespace,
@@ -738,6 +935,8 @@ func (r *RedisMasterReplicaReconciler) statefulSetForMaster(redisMasterReplica *
 		},
 		Spec: appsv1.StatefulSetSpec{
 			Replicas: &replicas,
+			// ServiceName 创建后不可修改，已有的 StatefulSet 保持原值
+			ServiceName: redisMasterReplica.Name + "-master-headless",
 			Selector: &metav1.LabelSelector{
 				MatchLabels: map[string]string{
 					"app":       "redis",
@@ -793,10 +992,16 @@ func (r *RedisMasterReplicaReconciler) statefulSetForMaster(redisMasterReplica *
 			VolumeClaimTemplates: volumeClaimTemplates,
 		},
 	}
+
+	utils.ApplyExporter(&statefulSet.Spec.Template, redisMasterReplica.Spec.Monitoring, utils.ExporterAuthSecret(redisMasterReplica.Spec.Security, redisMasterReplica.Name), redisMasterReplica.Spec.Security.TLS)
+	utils.ApplyTLS(&statefulSet.Spec.Template, "redis", redisMasterReplica.Spec.Security.TLS, 6379, false)
+	utils.ApplyAuth(&statefulSet.Spec.Template, "redis", utils.PasswordSecretRef(redisMasterReplica.Spec.Security, redisMasterReplica.Name))
+
+	return statefulSet, nil
 }
 
 // statefulSetForReplica 创建从节点 StatefulSet
-func (r *RedisMasterReplicaReconciler) statefulSetForReplica(redisMasterReplica *redisv1.RedisMasterReplica) *appsv1.StatefulSet {
+func (r *RedisMasterReplicaReconciler) statefulSetForReplica(redisMasterReplica *redisv1.RedisMasterReplica) (*appsv1.StatefulSet, error) {
 	replicas := redisMasterReplica.Spec.Replica.Replicas
 	if replicas == 0 {
 		replicas = 2 // 默认值
@@ -833,6 +1038,10 @@ func (r *RedisMasterReplicaReconciler) statefulSetForReplica(redisMasterReplica
 
 	var volumeClaimTemplates []corev1.PersistentVolumeClaim
 	if redisMasterReplica.Spec.Storage.Size != "" {
+		storageSize, err := parseStorageSize("spec.storage.size", redisMasterReplica.Spec.Storage.Size)
+		if err != nil {
+			return nil, err
+		}
 		volumeClaimTemplates = []corev1.PersistentVolumeClaim{
 			{
 				ObjectMeta: metav1.ObjectMeta{
@@ -842,7 +1051,7 @@ func (r *RedisMasterReplicaReconciler) statefulSetForReplica(redisMasterReplica
 					AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
 					Resources: corev1.VolumeResourceRequirements{
 						Requests: corev1.ResourceList{
-							corev1.ResourceStorage: resource.MustParse(redisMasterReplica.Spec.Storage.Size),
+							corev1.ResourceStorage: storageSize,
 						},
 					},
 					StorageClassName: &redisMasterReplica.Spec.Storage.StorageClassName,
@@ -858,7 +1067,7 @@ func (r *RedisMasterReplicaReconciler) statefulSetForReplica(redisMasterReplica
 		})
 	}
 
-	return &appsv1.StatefulSet{
+	statefulSet := &appsv1.StatefulSet{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      redisMasterReplica.Name + "-replica",
 			Namespace: redisMasterReplica.Namespace,
@@ -925,6 +1134,12 @@ func (r *RedisMasterReplicaReconciler) statefulSetForReplica(redisMasterReplica
 			VolumeClaimTemplates: volumeClaimTemplates,
 		},
 	}
+
+	utils.ApplyExporter(&statefulSet.Spec.Template, redisMasterReplica.Spec.Monitoring, utils.ExporterAuthSecret(redisMasterReplica.Spec.Security, redisMasterReplica.Name), redisMasterReplica.Spec.Security.TLS)
+	utils.ApplyTLS(&statefulSet.Spec.Template, "redis", redisMasterReplica.Spec.Security.TLS, 6379, false)
+	utils.ApplyAuth(&statefulSet.Spec.Template, "redis", utils.PasswordSecretRef(redisMasterReplica.Spec.Security, redisMasterReplica.Name))
+
+	return statefulSet, nil
 }
 
 // serviceForMaster 创建主节点 Service
@@ -958,6 +1173,38 @@ func (r *RedisMasterReplicaReconciler) serviceForMaster(redisMasterReplica *redi
 	}
 }
 
+// headlessServiceForMaster 创建主节点 Headless Service
+func (r *RedisMasterReplicaReconciler) headlessServiceForMaster(redisMasterReplica *redisv1.RedisMasterReplica) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      redisMasterReplica.Name + "-master-headless",
+			Namespace: redisMasterReplica.Namespace,
+			Labels: map[string]string{
+				"app":       "redis",
+				"component": "master",
+				"instance":  redisMasterReplica.Name,
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			ClusterIP:                corev1.ClusterIPNone,
+			PublishNotReadyAddresses: true,
+			Selector: map[string]string{
+				"app":       "redis",
+				"component": "master",
+				"instance":  redisMasterReplica.Name,
+			},
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "redis",
+					Port:       6379,
+					TargetPort: intstr.FromInt(6379),
+					Protocol:   corev1.ProtocolTCP,
+				},
+			},
+		},
+	}
+}
+
 // serviceForReplica 创建从节点 Service
 func (r *RedisMasterReplicaReconciler) serviceForReplica(redisMasterReplica *redisv1.RedisMasterReplica) *corev1.Service {
 	return &corev1.Service{
@@ -989,6 +1236,42 @@ func (r *RedisMasterReplicaReconciler) serviceForReplica(redisMasterReplica *red
 	}
 }
 
+// masterReplicasForSecret 返回引用了该 Secret（密码或 TLS 证书）的 RedisMasterReplica，证书轮换后触发滚动更新
+func (r *RedisMasterReplicaReconciler) masterReplicasForSecret(ctx context.Context, obj client.Object) []reconcile.Request {
+	masterReplicas := &redisv1.RedisMasterReplicaList{}
+	if err := r.List(ctx, masterReplicas, client.InNamespace(obj.GetNamespace())); err != nil {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, masterReplica := range masterReplicas.Items {
+		if utils.ReferencesSecret(masterReplica.Spec.Security, masterReplica.Name, obj.GetName()) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: masterReplica.Name, Namespace: masterReplica.Namespace},
+			})
+		}
+	}
+	return requests
+}
+
+// masterReplicasForConfig 返回引用了该配置模板的 RedisMasterReplica，配置模板变化后重新生成配置
+func (r *RedisMasterReplicaReconciler) masterReplicasForConfig(ctx context.Context, obj client.Object) []reconcile.Request {
+	masterReplicas := &redisv1.RedisMasterReplicaList{}
+	if err := r.List(ctx, masterReplicas, client.InNamespace(obj.GetNamespace())); err != nil {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, masterReplica := range masterReplicas.Items {
+		if masterReplica.Spec.ConfigRef != nil && masterReplica.Spec.ConfigRef.Name == obj.GetName() {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: masterReplica.Name, Namespace: masterReplica.Namespace},
+			})
+		}
+	}
+	return requests
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *RedisMasterReplicaReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
@@ -996,6 +1279,14 @@ func (r *RedisMasterReplicaReconciler) SetupWithManager(mgr ctrl.Manager) error
 		Owns(&corev1.ConfigMap{}).
 		Owns(&corev1.Service{}).
 		Owns(&appsv1.StatefulSet{}).
+		Watches(
+			&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.masterReplicasForSecret),
+		).
+		Watches(
+			&redisv1.Config{},
+			handler.EnqueueRequestsFromMapFunc(r.masterReplicasForConfig),
+		).
 		Watches(
 			&corev1.ConfigMap{},
 			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
@@ -1030,5 +1321,5 @@ func (r *RedisMasterReplicaReconciler) SetupWithManager(mgr ctrl.Manager) error
 				return nil
 			}),
 		).
-		Complete(r)
+		Complete(instrumentReconciler("RedisMasterReplica", r.Client, func() client.Object { return &redisv1.RedisMasterReplica{} }, r))
 }
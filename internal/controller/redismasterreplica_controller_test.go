@@ -21,8 +21,11 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -69,8 +72,9 @@ var _ = Describe("RedisMasterReplica Controller", func() {
 		It("should successfully reconcile the resource", func() {
 			By("Reconciling the created resource")
 			controllerReconciler := &RedisMasterReplicaReconciler{
-				Client: k8sClient,
-				Scheme: k8sClient.Scheme(),
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: &record.FakeRecorder{},
 			}
 
 			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
@@ -81,4 +85,77 @@ var _ = Describe("RedisMasterReplica Controller", func() {
 			// Example: If you expect a certain status condition after reconciliation, verify it here.
 		})
 	})
+
+	Context("When restoring the master with a config profile", func() {
+		const resourceName = "test-restore-masterreplica"
+		const profileName = "test-restore-aof-profile"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{Name: resourceName, Namespace: "default"}
+		profileKey := types.NamespacedName{Name: profileName, Namespace: "default"}
+
+		BeforeEach(func() {
+			profile := &redisv1.Config{
+				ObjectMeta: metav1.ObjectMeta{Name: profileName, Namespace: "default"},
+				Spec: redisv1.ConfigSpec{
+					Redis: map[string]string{"appendonly": "yes"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, profile)).To(Succeed())
+
+			resource := &redisv1.RedisMasterReplica{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+				Spec: redisv1.RedisMasterReplicaSpec{
+					Image:     "redis:7.0",
+					ConfigRef: &corev1.LocalObjectReference{Name: profileName},
+					Config:    map[string]string{"dbfilename": "orders.rdb"},
+					RestoreFrom: &redisv1.RestoreSource{
+						URL: "s3://backups/orders/dump.rdb",
+						S3: &redisv1.ObjectStoreSpec{
+							Endpoint:          "http://minio.minio:9000",
+							CredentialsSecret: corev1.LocalObjectReference{Name: "minio-credentials"},
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			resource := &redisv1.RedisMasterReplica{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+
+			profile := &redisv1.Config{}
+			Expect(k8sClient.Get(ctx, profileKey, profile)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, profile)).To(Succeed())
+		})
+
+		It("should restore into the dbfilename and appendonly mode of the rendered config", func() {
+			controllerReconciler := &RedisMasterReplicaReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: &record.FakeRecorder{},
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			configMap := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName + "-master-config", Namespace: "default"}, configMap)).To(Succeed())
+			Expect(configMap.Data["redis.conf"]).To(ContainSubstring("appendonly yes"))
+
+			statefulSet := &appsv1.StatefulSet{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName + "-master", Namespace: "default"}, statefulSet)).To(Succeed())
+			var restore *corev1.Container
+			for i := range statefulSet.Spec.Template.Spec.InitContainers {
+				if statefulSet.Spec.Template.Spec.InitContainers[i].Name == "restore" {
+					restore = &statefulSet.Spec.Template.Spec.InitContainers[i]
+				}
+			}
+			Expect(restore).NotTo(BeNil())
+			Expect(restore.Command[2]).To(ContainSubstring("/data/orders.rdb"))
+			Expect(restore.Command[2]).To(ContainSubstring("config set appendonly yes"))
+		})
+	})
 })
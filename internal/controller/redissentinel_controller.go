@@ -18,18 +18,20 @@ package controller
 
 import (
 	"context"
+	"crypto/sha256"
 	"fmt"
 	"reflect"
+	"strings"
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -48,6 +50,7 @@ import (
 type RedisSentinelReconciler struct {
 	client.Client
 	Scheme         *runtime.Scheme
+	Recorder       record.EventRecorder
 	storageManager *utils.StorageManager
 	MetricsManager *metrics.MetricsCollectionManager
 }
@@ -58,7 +61,10 @@ type RedisSentinelReconciler struct {
 // +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
-// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=redis.github.com,resources=redismasterreplicas,verbs=get;list;watch
+// +kubebuilder:rbac:groups=authorization.k8s.io,resources=selfsubjectaccessreviews,verbs=create
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -87,8 +93,15 @@ func (r *RedisSentinelReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		logs.Info("RedisSentinel is being deleted, cleaning up resources", "name", redisSentinel.Name)
 		if err = r.cleanupResources(ctx, req, redisSentinel, logs); err != nil {
 			logs.Error(err, "Failed to cleanup resources")
+			r.Recorder.Eventf(redisSentinel, corev1.EventTypeWarning, eventReasonCleanupFailed, "Failed to clean up resources: %v", err)
 			return ctrl.Result{}, err
 		}
+		r.Recorder.Event(redisSentinel, corev1.EventTypeNormal, eventReasonCleanup, "Deleted dependent resources")
+		// 关闭指标收集器并删除资源的指标序列
+		if r.MetricsManager != nil {
+			r.MetricsManager.RemoveCollectors(redisSentinel.UID)
+		}
+		metrics.DeleteResourceMetrics("RedisSentinel", redisSentinel.Namespace, redisSentinel.Name)
 		// 移除 finalizer，使用重试机制避免资源版本冲突
 		err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
 			// 重新获取最新的资源版本
@@ -118,9 +131,18 @@ func (r *RedisSentinelReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	}
 
 	// 确保所有资源存在并正确配置
-	err = r.ensureResources(ctx, req, redisSentinel, logs)
-	if err != nil {
+	// 配置指令无效时保持现有资源不变，错误通过 ConfigValid 状态条件报告
+	if configErrors := workloadConfigErrors(ctx, r.Client, redisSentinel); len(configErrors) > 0 {
+		logs.Info("Invalid Redis configuration, skipping resource update", "errors", configErrors)
+		r.Recorder.Eventf(redisSentinel, corev1.EventTypeWarning, eventReasonInvalidConfig, "Skipping resource update: %s", configErrors[0])
+	} else if err = r.ensureResources(ctx, req, redisSentinel, logs); err != nil {
 		logs.Error(err, "Failed to ensure resources")
+		r.Recorder.Eventf(redisSentinel, corev1.EventTypeWarning, eventReasonReconcileFailed, "Failed to ensure resources: %v", err)
+		if isSpecError(err) {
+			if statusErr := reportInvalidSpec(ctx, r.Client, redisSentinel, &redisSentinel.Status.Conditions, err); statusErr != nil {
+				logs.Error(statusErr, "Failed to report invalid spec")
+			}
+		}
 		return ctrl.Result{}, err
 	}
 
@@ -131,25 +153,40 @@ func (r *RedisSentinelReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return ctrl.Result{}, err
 	}
 
+	// 密码 Secret 变化时执行零停机密码轮换
+	if err = reconcilePasswordRotation(ctx, r.Client, r.Scheme, "RedisSentinel", redisSentinel, redisSentinel.Spec.Security, redisSentinel.Status.PasswordRotation, logs); err != nil {
+		logs.Error(err, "Failed to rotate password")
+	}
+
 	// 注册指标收集器
 	if r.MetricsManager != nil {
-		// 为 Sentinel 添加指标收集器
-		sentinelAddrs := []string{
-			fmt.Sprintf("%s-sentinel-service.%s.svc.cluster.local:26379", redisSentinel.Name, redisSentinel.Namespace),
+		// 为 Sentinel 注册指标收集器，连接参数未变化时复用已有收集器
+		tlsConfig, err := utils.ClientTLSConfig(ctx, r.Client, redisSentinel.Namespace, redisSentinel.Spec.Security.TLS)
+		if err != nil {
+			logs.Error(err, "Failed to load TLS certificate for metrics collector")
+		}
+		serviceName := redisSentinel.Name + "-sentinel-service"
+		r.MetricsManager.SyncSentinelCollector(
+			metrics.CollectorKey{UID: redisSentinel.UID, Pod: serviceName},
+			metrics.Endpoint{
+				Addrs:     []string{fmt.Sprintf("%s.%s.svc.cluster.local:26379", serviceName, redisSentinel.Namespace)},
+				TLSConfig: tlsConfig,
+			},
+			redisSentinel.Namespace,
+			redisSentinel.Name,
+		)
+		// 为嵌入式 Redis 的每个 Pod 注册指标收集器
+		if err := syncRedisPodCollectors(ctx, r.Client, r.MetricsManager, "RedisSentinel", redisSentinel, redisSentinel.Spec.Security, serviceName); err != nil {
+			logs.Error(err, "Failed to register metrics collectors")
 		}
-		sentinelCollector := metrics.NewSentinelCollector(sentinelAddrs, redisSentinel.Namespace, redisSentinel.Name)
-		r.MetricsManager.AddSentinelCollector(sentinelCollector)
-
-		// 记录协调操作指标
-		metrics.RecordReconcile("RedisSentinel", redisSentinel.Namespace, redisSentinel.Name, "success", 0.0)
 
-		// 更新 Sentinel 指标
-		if redisSentinel.Status.Status != "" {
+		// 更新每个被监控 master 的指标
+		for _, master := range redisSentinel.Status.MonitoredMasters {
 			var statusValue float64 = 0
-			if redisSentinel.Status.Status == string(redisv1.RedisPhaseRunning) {
+			if master.Status == "up" {
 				statusValue = 1
 			}
-			metrics.SetRedisSentinelMasterStatus(redisSentinel.Namespace, redisSentinel.Name, "mymaster", statusValue)
+			metrics.SetRedisSentinelMasterStatus(redisSentinel.Namespace, redisSentinel.Name, master.Name, statusValue)
 		}
 	}
 
@@ -160,7 +197,7 @@ func (r *RedisSentinelReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 func (r *RedisSentinelReconciler) cleanupResources(ctx context.Context, req ctrl.Request, redisSentinel *redisv1.RedisSentinel, logs logr.Logger) error {
 	// 如果配置了嵌入式 Redis，先删除 Redis 相关资源
 	if r.hasEmbeddedRedis(redisSentinel) {
-		// 删除 Redis Master StatefulSet
+		// 删除旧版本的 Redis Master StatefulSet（迁移未完成时仍可能存在）
 		redisMasterStatefulSet := &appsv1.StatefulSet{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      redisSentinel.Name + "-redis-master",
@@ -180,7 +217,7 @@ func (r *RedisSentinelReconciler) cleanupResources(ctx context.Context, req ctrl
 			logs.Info("Deleted Redis Master StatefulSet", "name", redisMasterStatefulSet.Name)
 		}
 
-		// 删除 Redis Replica StatefulSet
+		// 删除旧版本的 Redis Replica StatefulSet
 		redisReplicaStatefulSet := &appsv1.StatefulSet{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      redisSentinel.Name + "-redis-replica",
@@ -200,7 +237,7 @@ func (r *RedisSentinelReconciler) cleanupResources(ctx context.Context, req ctrl
 			logs.Info("Deleted Redis Replica StatefulSet", "name", redisReplicaStatefulSet.Name)
 		}
 
-		// 删除 Redis 统一 StatefulSet（如果存在）
+		// 删除 Redis StatefulSet
 		redisStatefulSet := &appsv1.StatefulSet{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      redisSentinel.Name + "-redis",
@@ -327,62 +364,122 @@ func (r *RedisSentinelReconciler) cleanupResources(ctx context.Context, req ctrl
 
 // ensureResources 确保所有必要的资源存在
 func (r *RedisSentinelReconciler) ensureResources(ctx context.Context, req ctrl.Request, redisSentinel *redisv1.RedisSentinel, logs logr.Logger) error {
+	// 读取引用的配置模板，redis 部分用于嵌入式 Redis，sentinel 部分用于 Sentinel
+	profile, err := resolveConfigProfile(ctx, r.Client, redisSentinel.Namespace, redisSentinel.Spec.ConfigRef)
+	if err != nil {
+		return err
+	}
+	redisSentinel.Status.ConfigRevision = renderedConfigRevision(redisSentinel.Spec.ConfigRef, profile)
+
 	// 如果配置了嵌入式 Redis，则创建 Redis StatefulSet 和 Headless Service
 	if r.hasEmbeddedRedis(redisSentinel) {
+		// 启用认证且未引用密码 Secret 时生成随机密码
+		if err := utils.EnsurePasswordSecret(ctx, r.Client, r.Scheme, redisSentinel, redisSentinel.Spec.Security); err != nil {
+			return err
+		}
+
+		legacy, err := r.hasLegacyEmbeddedRedis(ctx, redisSentinel)
+		if err != nil {
+			return err
+		}
+
 		// 确保 Redis Headless Service 存在
 		if err := r.ensureRedisHeadlessService(ctx, redisSentinel, logs); err != nil {
 			return err
 		}
 
 		// 确保 Redis StatefulSet 存在
-		if err := r.ensureRedisStatefulSet(ctx, redisSentinel, logs); err != nil {
+		if err := r.ensureRedisStatefulSet(ctx, redisSentinel, embeddedBootstrapMaster(redisSentinel, legacy), profile, logs); err != nil {
 			return err
 		}
+
+		// 将旧版本的 master/replica StatefulSet 迁移到单个 StatefulSet
+		if legacy {
+			if err := r.migrateLegacyEmbeddedRedis(ctx, redisSentinel, logs); err != nil {
+				logs.Error(err, "Failed to migrate legacy embedded Redis")
+			}
+		} else if migration := redisSentinel.Status.Migration; migration != nil && migration.Phase == redisv1.EmbeddedMigrationResettingSentinels {
+			if err := r.resetSentinelsAfterMigration(ctx, redisSentinel, logs); err != nil {
+				logs.Error(err, "Failed to reset sentinels after migrating embedded Redis")
+			}
+		}
+	}
+
+	// 解析所有被监控的 master，解析失败的 master 不写入 Sentinel 配置
+	masters := r.resolveMonitoredMasters(ctx, redisSentinel)
+	for _, master := range masters {
+		if master.Err != nil {
+			logs.Error(master.Err, "Failed to resolve monitored master", "master", master.Name, "source", master.Source)
+		}
 	}
 
 	// 确保 Sentinel ConfigMap
-	if err := r.ensureSentinelConfigMap(ctx, redisSentinel, logs); err != nil {
+	if err := r.ensureSentinelConfigMap(ctx, redisSentinel, masters, profile, logs); err != nil {
 		return err
 	}
 
 	// 确保 Sentinel StatefulSet
-	if err := r.ensureSentinelStatefulSet(ctx, redisSentinel, logs); err != nil {
+	if err := r.ensureSentinelStatefulSet(ctx, redisSentinel, masters, profile, logs); err != nil {
 		return err
 	}
 
+	// 修复运行中 Sentinel 监控的失效 master 地址
+	if err := r.repairSentinelMonitors(ctx, redisSentinel, masters, logs); err != nil {
+		logs.Error(err, "Failed to repair sentinel monitors")
+	}
+
 	// 确保 Sentinel Service
 	if err := r.ensureSentinelService(ctx, redisSentinel, logs); err != nil {
 		return err
 	}
 
+	// exporter sidecar、慢日志和 Redis 节点告警只适用于嵌入式 Redis，Sentinel 告警按各 master 的 quorum 生成
+	monitoring := redisSentinel.Spec.Monitoring
+	topology := newAlertTopology("RedisSentinel", redisSentinel.Name)
+	if !r.hasEmbeddedRedis(redisSentinel) {
+		if monitoring != nil {
+			monitoring = monitoring.DeepCopy()
+			monitoring.Enabled = false
+			monitoring.Slowlog = nil
+		}
+		topology = alertTopology{}
+	}
+	topology.quorums = make(map[string]int32, len(masters))
+	for _, master := range masters {
+		topology.quorums[master.Name] = master.Quorum
+	}
+	if err := ensureMonitoring(ctx, r.Client, r.Scheme, r.Recorder, "RedisSentinel", redisSentinel, redisSentinel.Spec.Security, monitoring, topology); err != nil {
+		return err
+	}
+
 	return nil
 }
 
 // ensureSentinelConfigMap 确保 Sentinel ConfigMap 存在
-func (r *RedisSentinelReconciler) ensureSentinelConfigMap(ctx context.Context, redisSentinel *redisv1.RedisSentinel, logs logr.Logger) error {
+func (r *RedisSentinelReconciler) ensureSentinelConfigMap(ctx context.Context, redisSentinel *redisv1.RedisSentinel, masters []resolvedMaster, profile *redisv1.ConfigSpec, logs logr.Logger) error {
 	configMap := &corev1.ConfigMap{}
 	configMapName := redisSentinel.Name + "-sentinel-config"
 	err := r.Get(ctx, types.NamespacedName{Name: configMapName, Namespace: redisSentinel.Namespace}, configMap)
 
-	// 使用 DNS 名称直接指向 Redis master Pod
-	masterDNS := fmt.Sprintf("%s-redis-0.%s-redis-headless.%s.svc.cluster.local",
-		redisSentinel.Name, redisSentinel.Name, redisSentinel.Namespace)
-
 	if errors.IsNotFound(err) {
 		// 创建新的 ConfigMap
-		configMap = r.configMapForSentinel(redisSentinel, masterDNS)
+		configMap = r.configMapForSentinel(redisSentinel, masters, profile)
 		if err = controllerutil.SetControllerReference(redisSentinel, configMap, r.Scheme); err != nil {
 			return err
 		}
 		controllerutil.AddFinalizer(configMap, redisv1.RedisSentinelFinalizer)
 		logs.Info("Creating sentinel ConfigMap", "name", configMap.Name)
-		return r.Create(ctx, configMap)
+		if err := r.Create(ctx, configMap); err != nil {
+			return err
+		}
+		recordCreated(r.Recorder, redisSentinel, "ConfigMap", configMap.Name)
+		return nil
 	} else if err != nil {
 		return err
 	}
 
 	// 检查是否需要更新ConfigMap
-	newConfigMap := r.configMapForSentinel(redisSentinel, masterDNS)
+	newConfigMap := r.configMapForSentinel(redisSentinel, masters, profile)
 	if configMap.Data["sentinel.conf"] != newConfigMap.Data["sentinel.conf"] {
 		// 设置状态为 Updating
 		if err := r.setUpdatingStatus(ctx, redisSentinel, "Updating sentinel ConfigMap"); err != nil {
@@ -390,34 +487,54 @@ func (r *RedisSentinelReconciler) ensureSentinelConfigMap(ctx context.Context, r
 		}
 
 		configMap.Data = newConfigMap.Data
-		logs.Info("Updating sentinel ConfigMap with new master DNS", "name", configMap.Name, "masterDNS", masterDNS)
-		return r.Update(ctx, configMap)
+		logs.Info("Updating sentinel ConfigMap with new monitored masters", "name", configMap.Name, "masters", len(masters))
+		if err := r.Update(ctx, configMap); err != nil {
+			return err
+		}
+		r.Recorder.Eventf(redisSentinel, corev1.EventTypeNormal, eventReasonConfigChanged, "Updated ConfigMap %s", configMap.Name)
+		return nil
 	}
 
 	return nil
 }
 
 // ensureSentinelStatefulSet 确保 Sentinel StatefulSet 存在
-func (r *RedisSentinelReconciler) ensureSentinelStatefulSet(ctx context.Context, redisSentinel *redisv1.RedisSentinel, logs logr.Logger) error {
+func (r *RedisSentinelReconciler) ensureSentinelStatefulSet(ctx context.Context, redisSentinel *redisv1.RedisSentinel, masters []resolvedMaster, profile *redisv1.ConfigSpec, logs logr.Logger) error {
 	statefulSet := &appsv1.StatefulSet{}
 	statefulSetName := redisSentinel.Name + "-sentinel"
 	err := r.Get(ctx, types.NamespacedName{Name: statefulSetName, Namespace: redisSentinel.Namespace}, statefulSet)
 
 	if errors.IsNotFound(err) {
 		// 创建新的 StatefulSet
-		statefulSet = r.statefulSetForSentinelWithDynamicConfig(redisSentinel)
+		statefulSet, err = r.statefulSetForSentinelWithDynamicConfig(redisSentinel, masters, profile)
+		if err != nil {
+			return err
+		}
 		if err = controllerutil.SetControllerReference(redisSentinel, statefulSet, r.Scheme); err != nil {
 			return err
 		}
+		if err = utils.AnnotateTLSCertHash(ctx, r.Client, redisSentinel.Namespace, redisSentinel.Spec.Security.TLS, &statefulSet.Spec.Template); err != nil {
+			return err
+		}
 		controllerutil.AddFinalizer(statefulSet, redisv1.RedisSentinelFinalizer)
 		logs.Info("Creating sentinel StatefulSet with dynamic config", "name", statefulSet.Name)
-		return r.Create(ctx, statefulSet)
+		if err := r.Create(ctx, statefulSet); err != nil {
+			return err
+		}
+		recordCreated(r.Recorder, redisSentinel, "StatefulSet", statefulSet.Name)
+		return nil
 	} else if err != nil {
 		return err
 	}
 
 	// 检查 StatefulSet 是否需要更新
-	desiredStatefulSet := r.statefulSetForSentinelWithDynamicConfig(redisSentinel)
+	desiredStatefulSet, err := r.statefulSetForSentinelWithDynamicConfig(redisSentinel, masters, profile)
+	if err != nil {
+		return err
+	}
+	if err := utils.AnnotateTLSCertHash(ctx, r.Client, redisSentinel.Namespace, redisSentinel.Spec.Security.TLS, &desiredStatefulSet.Spec.Template); err != nil {
+		return err
+	}
 	needsUpdate := false
 
 	// 检查副本数
@@ -439,6 +556,25 @@ func (r *RedisSentinelReconciler) ensureSentinelStatefulSet(ctx context.Context,
 		}
 	}
 
+	// 检查配置哈希，被监控 master 变化时需要滚动重启 Sentinel
+	if statefulSet.Spec.Template.Annotations["redis.github.com/config-hash"] != desiredStatefulSet.Spec.Template.Annotations["redis.github.com/config-hash"] {
+		needsUpdate = true
+	}
+
+	// 检查 TLS 证书，证书轮换后滚动更新
+	if utils.TLSCertChanged(&statefulSet.Spec.Template, &desiredStatefulSet.Spec.Template) {
+		needsUpdate = true
+	}
+
+	// 检查配置初始化容器（auth-pass 注入）
+	if len(statefulSet.Spec.Template.Spec.InitContainers) > 0 && len(desiredStatefulSet.Spec.Template.Spec.InitContainers) > 0 {
+		currentInit := statefulSet.Spec.Template.Spec.InitContainers[0]
+		desiredInit := desiredStatefulSet.Spec.Template.Spec.InitContainers[0]
+		if !reflect.DeepEqual(currentInit.Command, desiredInit.Command) || !reflect.DeepEqual(currentInit.Env, desiredInit.Env) {
+			needsUpdate = true
+		}
+	}
+
 	if needsUpdate {
 		// 设置状态为 Updating
 		if err := r.setUpdatingStatus(ctx, redisSentinel, "Updating sentinel StatefulSet"); err != nil {
@@ -448,7 +584,11 @@ func (r *RedisSentinelReconciler) ensureSentinelStatefulSet(ctx context.Context,
 		statefulSet.Spec.Replicas = desiredStatefulSet.Spec.Replicas
 		statefulSet.Spec.Template = desiredStatefulSet.Spec.Template
 		logs.Info("Updating sentinel StatefulSet", "name", statefulSet.Name)
-		return r.Update(ctx, statefulSet)
+		if err := r.Update(ctx, statefulSet); err != nil {
+			return err
+		}
+		r.Recorder.Eventf(redisSentinel, corev1.EventTypeNormal, eventReasonRollingUpdate, "Rolling update of StatefulSet %s", statefulSet.Name)
+		return nil
 	}
 
 	return nil
@@ -492,7 +632,11 @@ func (r *RedisSentinelReconciler) ensureRedisHeadlessService(ctx context.Context
 		}
 		controllerutil.AddFinalizer(service, redisv1.RedisSentinelFinalizer)
 		logs.Info("Creating Redis Headless Service", "name", service.Name)
-		return r.Create(ctx, service)
+		if err := r.Create(ctx, service); err != nil {
+			return err
+		}
+		recordCreated(r.Recorder, redisSentinel, "Service", service.Name)
+		return nil
 	} else if err != nil {
 		return err
 	}
@@ -501,7 +645,8 @@ func (r *RedisSentinelReconciler) ensureRedisHeadlessService(ctx context.Context
 }
 
 // ensureRedisStatefulSet 确保 Redis StatefulSet 存在
-func (r *RedisSentinelReconciler) ensureRedisStatefulSet(ctx context.Context, redisSentinel *redisv1.RedisSentinel, logs logr.Logger) error {
+// bootstrapMaster 为 Sentinel 不可用时 Pod 启动所复制的 master 地址
+func (r *RedisSentinelReconciler) ensureRedisStatefulSet(ctx context.Context, redisSentinel *redisv1.RedisSentinel, bootstrapMaster string, profile *redisv1.ConfigSpec, logs logr.Logger) error {
 	statefulSet := &appsv1.StatefulSet{}
 	statefulSetName := redisSentinel.Name + "-redis"
 	err := r.Get(ctx, types.NamespacedName{Name: statefulSetName, Namespace: redisSentinel.Namespace}, statefulSet)
@@ -511,163 +656,27 @@ func (r *RedisSentinelReconciler) ensureRedisStatefulSet(ctx context.Context, re
 	// 计算总副本数：1个master + N个replica
 	desiredReplicas := int32(1 + redisConfig.Replica.Replicas) // 1个master + replica数量
 
+	desiredStatefulSet, specErr := r.statefulSetForEmbeddedRedis(redisSentinel, bootstrapMaster, profile)
+	if specErr != nil {
+		return specErr
+	}
+	if err := utils.AnnotateTLSCertHash(ctx, r.Client, redisSentinel.Namespace, redisSentinel.Spec.Security.TLS, &desiredStatefulSet.Spec.Template); err != nil {
+		return err
+	}
+
 	if errors.IsNotFound(err) {
 		// 创建新的 StatefulSet
-		statefulSet = &appsv1.StatefulSet{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      statefulSetName,
-				Namespace: redisSentinel.Namespace,
-				Labels: map[string]string{
-					"app":      "redis",
-					"instance": redisSentinel.Name,
-				},
-			},
-			Spec: appsv1.StatefulSetSpec{
-				Replicas:    &desiredReplicas,
-				ServiceName: redisSentinel.Name + "-redis-headless",
-				Selector: &metav1.LabelSelector{
-					MatchLabels: map[string]string{
-						"app":      "redis",
-						"instance": redisSentinel.Name,
-					},
-				},
-				Template: corev1.PodTemplateSpec{
-					ObjectMeta: metav1.ObjectMeta{
-						Labels: map[string]string{
-							"app":      "redis",
-							"instance": redisSentinel.Name,
-						},
-					},
-					Spec: corev1.PodSpec{
-						InitContainers: []corev1.Container{
-							{
-								Name:  "redis-config",
-								Image: "busybox:1.35",
-								Command: []string{
-									"/bin/sh",
-									"-c",
-									`
-										# 获取当前 Pod 的序号
-										ORDINAL=${HOSTNAME##*-}
-										echo "Pod ordinal: $ORDINAL"
-										
-										# 创建 Redis 配置文件
-										if [ "$ORDINAL" = "0" ]; then
-											# 第一个 Pod 作为 Master
-											echo "port 6379" > /etc/redis/redis.conf
-											echo "bind 0.0.0.0" >> /etc/redis/redis.conf
-											echo "protected-mode no" >> /etc/redis/redis.conf
-											echo "save 900 1" >> /etc/redis/redis.conf
-											echo "save 300 10" >> /etc/redis/redis.conf
-											echo "save 60 10000" >> /etc/redis/redis.conf
-										else
-											# 其他 Pod 作为 Replica
-											echo "port 6379" > /etc/redis/redis.conf
-											echo "bind 0.0.0.0" >> /etc/redis/redis.conf
-											echo "protected-mode no" >> /etc/redis/redis.conf
-											echo "replicaof ` + redisSentinel.Name + `-redis-0.` + redisSentinel.Name + `-redis-headless.` + redisSentinel.Namespace + `.svc.cluster.local 6379" >> /etc/redis/redis.conf
-										fi
-										`,
-								},
-								VolumeMounts: []corev1.VolumeMount{
-									{
-										Name:      "redis-config",
-										MountPath: "/etc/redis",
-									},
-								},
-							},
-						},
-						Containers: []corev1.Container{
-							{
-								Name:  "redis",
-								Image: "redis:7.0",
-								Command: []string{
-									"redis-server",
-									"/etc/redis/redis.conf",
-								},
-								Ports: []corev1.ContainerPort{
-									{
-										ContainerPort: 6379,
-										Name:          "redis",
-									},
-								},
-								VolumeMounts: []corev1.VolumeMount{
-									{
-										Name:      "redis-config",
-										MountPath: "/etc/redis",
-									},
-									{
-										Name:      "redis-data",
-										MountPath: "/data",
-									},
-								},
-								ReadinessProbe: &corev1.Probe{
-									ProbeHandler: corev1.ProbeHandler{
-										Exec: &corev1.ExecAction{
-											Command: []string{"redis-cli", "ping"},
-										},
-									},
-									InitialDelaySeconds: 5,
-									PeriodSeconds:       3,
-								},
-								LivenessProbe: &corev1.Probe{
-									ProbeHandler: corev1.ProbeHandler{
-										Exec: &corev1.ExecAction{
-											Command: []string{"redis-cli", "ping"},
-										},
-									},
-									InitialDelaySeconds: 30,
-									PeriodSeconds:       3,
-								},
-							},
-						},
-						Volumes: []corev1.Volume{
-							{
-								Name: "redis-config",
-								VolumeSource: corev1.VolumeSource{
-									EmptyDir: &corev1.EmptyDirVolumeSource{},
-								},
-							},
-						},
-					},
-				},
-				VolumeClaimTemplates: []corev1.PersistentVolumeClaim{
-					{
-						ObjectMeta: metav1.ObjectMeta{
-							Name: "redis-data",
-						},
-						Spec: corev1.PersistentVolumeClaimSpec{
-							AccessModes: []corev1.PersistentVolumeAccessMode{
-								corev1.ReadWriteOnce,
-							},
-							Resources: corev1.VolumeResourceRequirements{
-								Requests: corev1.ResourceList{
-									corev1.ResourceStorage: resource.MustParse(func() string {
-										if redisConfig.Master.Storage.Size != "" {
-											return redisConfig.Master.Storage.Size
-										}
-										return "1Gi" // 默认存储大小
-									}()),
-								},
-							},
-							StorageClassName: func() *string {
-								if redisConfig.Master.Storage.StorageClassName != "" {
-									return &redisConfig.Master.Storage.StorageClassName
-								}
-								return nil
-							}(),
-						},
-					},
-				},
-			},
-		}
-
+		statefulSet = desiredStatefulSet
 		if err = controllerutil.SetControllerReference(redisSentinel, statefulSet, r.Scheme); err != nil {
 			return err
 		}
 		controllerutil.AddFinalizer(statefulSet, redisv1.RedisSentinelFinalizer)
 		logs.Info("Creating Redis StatefulSet", "name", statefulSet.Name)
-		return r.Create(ctx, statefulSet)
+		if err := r.Create(ctx, statefulSet); err != nil {
+			return err
+		}
+		recordCreated(r.Recorder, redisSentinel, "StatefulSet", statefulSet.Name)
+		return nil
 	} else if err != nil {
 		return err
 	}
@@ -686,10 +695,7 @@ func (r *RedisSentinelReconciler) ensureRedisStatefulSet(ctx context.Context, re
 
 	// 检查镜像变更
 	currentImage := statefulSet.Spec.Template.Spec.Containers[0].Image
-	desiredImage := redisSentinel.Spec.Image
-	if desiredImage == "" {
-		desiredImage = "redis:7.0" // 默认镜像
-	}
+	desiredImage := desiredStatefulSet.Spec.Template.Spec.Containers[0].Image
 	if currentImage != desiredImage {
 		needsUpdate = true
 		if updateType == "" {
@@ -726,7 +732,10 @@ func (r *RedisSentinelReconciler) ensureRedisStatefulSet(ctx context.Context, re
 		if redisConfig.Master.Storage.Size != "" {
 			desiredStorageSize = redisConfig.Master.Storage.Size
 		}
-		desiredStorage := resource.MustParse(desiredStorageSize)
+		desiredStorage, err := parseStorageSize("spec.redis.master.storage.size", desiredStorageSize)
+		if err != nil {
+			return err
+		}
 
 		// 只有在存储大小真正不同时才触发更新
 		if !currentStorage.Equal(desiredStorage) {
@@ -739,7 +748,8 @@ func (r *RedisSentinelReconciler) ensureRedisStatefulSet(ctx context.Context, re
 
 			// 处理存储变更结果
 			if storageResult.ErrorMessage != "" {
-				return fmt.Errorf("%s", storageResult.ErrorMessage)
+				r.Recorder.Event(redisSentinel, corev1.EventTypeWarning, eventReasonStorageShrinkRejected, storageResult.ErrorMessage)
+				return storageError(fmt.Errorf("%s", storageResult.ErrorMessage))
 			}
 
 			if storageResult.ChangeType == utils.StorageExpansion {
@@ -748,8 +758,37 @@ func (r *RedisSentinelReconciler) ensureRedisStatefulSet(ctx context.Context, re
 		}
 	}
 
-	// 注意：当前 Redis StatefulSet 使用 InitContainer 生成配置文件，不使用环境变量
-	// 如果将来需要支持环境变量配置，可以在这里添加环境变量比较逻辑
+	// 检查配置初始化容器变更（旧版本的初始化脚本或迁移结束后的 bootstrap master 变化）
+	currentInit := statefulSet.Spec.Template.Spec.InitContainers
+	desiredInit := desiredStatefulSet.Spec.Template.Spec.InitContainers
+	if len(currentInit) != len(desiredInit) || currentInit[0].Image != desiredInit[0].Image || !reflect.DeepEqual(currentInit[0].Command, desiredInit[0].Command) {
+		needsUpdate = true
+		if updateType == "" {
+			updateType = "rolling update"
+		}
+		logs.Info("Redis init container change detected")
+	}
+
+	// 检查认证和 TLS 配置变更
+	securityChanged := utils.AuthSecretChanged(&statefulSet.Spec.Template, utils.PasswordSecretRef(redisSentinel.Spec.Security, redisSentinel.Name)) ||
+		utils.TLSCertChanged(&statefulSet.Spec.Template, &desiredStatefulSet.Spec.Template)
+	if securityChanged {
+		needsUpdate = true
+		if updateType == "" {
+			updateType = "rolling update"
+		}
+		logs.Info("Redis authentication or TLS change detected")
+	}
+
+	// 检查 exporter sidecar 变更
+	exporterChanged := utils.ExporterChanged(&statefulSet.Spec.Template, &desiredStatefulSet.Spec.Template)
+	if exporterChanged {
+		needsUpdate = true
+		if updateType == "" {
+			updateType = "rolling update"
+		}
+		logs.Info("Redis exporter sidecar change detected")
+	}
 
 	// 如果存储需要扩容，通过 PVC 动态扩展实现
 	if storageNeedsExpansion {
@@ -766,10 +805,12 @@ func (r *RedisSentinelReconciler) ensureRedisStatefulSet(ctx context.Context, re
 			desiredStorageSize = "1Gi"
 		}
 		if err := r.storageManager.ExpandStatefulSetPVCs(ctx, statefulSet, desiredStorageSize, "Redis"); err != nil {
-			return fmt.Errorf("failed to expand PVCs: %w", err)
+			return storageError(fmt.Errorf("failed to expand PVCs: %w", err))
 		}
 
 		logs.Info("PVC expansion completed", "name", statefulSet.Name)
+		r.Recorder.Eventf(redisSentinel, corev1.EventTypeNormal, eventReasonStorageExpanded,
+			"Expanded PersistentVolumeClaims of StatefulSet %s to %s", statefulSet.Name, desiredStorageSize)
 		return nil
 	}
 
@@ -782,6 +823,11 @@ func (r *RedisSentinelReconciler) ensureRedisStatefulSet(ctx context.Context, re
 		// 更新 StatefulSet
 		statefulSet.Spec.Replicas = &desiredReplicas
 		statefulSet.Spec.Template.Spec.Containers[0].Image = desiredImage
+		statefulSet.Spec.Template.Spec.InitContainers = desiredStatefulSet.Spec.Template.Spec.InitContainers
+		if securityChanged || exporterChanged {
+			// 认证、TLS 和 exporter 配置涉及挂载卷、环境变量、启动命令和 sidecar 容器，直接使用期望的 Pod 模板
+			statefulSet.Spec.Template = desiredStatefulSet.Spec.Template
+		}
 
 		// 更新资源配置（如果指定了的话）
 		if !isEmptyResourceRequirements(redisConfig.Master.Resources) {
@@ -789,12 +835,204 @@ func (r *RedisSentinelReconciler) ensureRedisStatefulSet(ctx context.Context, re
 		}
 
 		logs.Info("Updating Redis StatefulSet", "name", statefulSet.Name, "type", updateType)
-		return r.Update(ctx, statefulSet)
+		if err := r.Update(ctx, statefulSet); err != nil {
+			return err
+		}
+		r.Recorder.Eventf(redisSentinel, corev1.EventTypeNormal, eventReasonRollingUpdate, "Rolling update of StatefulSet %s: %s", statefulSet.Name, updateType)
+		return nil
 	}
 
 	return nil
 }
 
+// statefulSetForEmbeddedRedis 创建嵌入式 Redis StatefulSet
+// 所有 Redis 节点位于同一个 StatefulSet 中，启动时优先向 Sentinel 查询当前 master，
+// Sentinel 不可用时（首次部署）复制 bootstrapMaster，序号 0 的 Pod 即为初始 master
+// 配置模板的 redis 部分和 spec.redis.config 合并后写入 redis.conf，内联配置优先
+func (r *RedisSentinelReconciler) statefulSetForEmbeddedRedis(redisSentinel *redisv1.RedisSentinel, bootstrapMaster string, profile *redisv1.ConfigSpec) (*appsv1.StatefulSet, error) {
+	redisConfig := redisSentinel.Spec.Redis
+	replicas := int32(1 + redisConfig.Replica.Replicas) // 1个master + replica数量
+
+	image := redisSentinel.Spec.Image
+	if image == "" {
+		image = "redis:7.0" // 默认镜像
+	}
+
+	masterName := redisConfig.MasterName
+	if masterName == "" {
+		masterName = defaultSentinelMasterName
+	}
+
+	storageSize := "1Gi" // 默认存储大小
+	if redisConfig.Master.Storage.Size != "" {
+		storageSize = redisConfig.Master.Storage.Size
+	}
+	storageQuantity, err := parseStorageSize("spec.redis.master.storage.size", storageSize)
+	if err != nil {
+		return nil, err
+	}
+	var storageClassName *string
+	if redisConfig.Master.Storage.StorageClassName != "" {
+		storageClassName = &redisConfig.Master.Storage.StorageClassName
+	}
+
+	labels := map[string]string{
+		"app":      "redis",
+		"instance": redisSentinel.Name,
+	}
+
+	// 自定义配置通过不展开变量的 heredoc 写入，位于运维必需的复制配置之前
+	customConfig := ""
+	if config := utils.AppendRedisConfig("", mergeProfileConfig(redisConfig.Config, profile.Redis)); config != "" {
+		customConfig = "cat >> /etc/redis/redis.conf <<'REDIS_CONFIG_EOF'\n" + config + "REDIS_CONFIG_EOF\n"
+	}
+
+	// Sentinel 启用 TLS 时 redis-cli 需要使用证书连接
+	cliTLSArgs := ""
+	if utils.TLSEnabled(redisSentinel.Spec.Security) {
+		cliTLSArgs = strings.Join(utils.TLSCLIArgs(), " ") + " "
+	}
+
+	initScript := fmt.Sprintf(`
+SELF="${HOSTNAME}.%[1]s-redis-headless.%[2]s.svc.cluster.local"
+MASTER=""
+
+# 优先从 Sentinel 获取当前 master，Pod 重建、故障转移或迁移后按实际拓扑加入
+if ADDR=$(timeout 5 redis-cli %[5]s-h %[1]s-sentinel-service -p 26379 --raw sentinel get-master-addr-by-name %[3]s 2>/dev/null); then
+	MASTER=$(echo "$ADDR" | head -n 1)
+fi
+if [ -z "$MASTER" ]; then
+	MASTER="%[4]s"
+fi
+
+echo "port 6379" > /etc/redis/redis.conf
+echo "bind 0.0.0.0" >> /etc/redis/redis.conf
+echo "protected-mode no" >> /etc/redis/redis.conf
+echo "save 900 1" >> /etc/redis/redis.conf
+echo "save 300 10" >> /etc/redis/redis.conf
+echo "save 60 10000" >> /etc/redis/redis.conf
+%[6]s
+# 使用稳定的 Pod DNS 名称对外公告，Sentinel 发现的节点地址不随 Pod IP 变化
+echo "replica-announce-ip ${SELF}" >> /etc/redis/redis.conf
+
+if [ "$MASTER" != "$SELF" ] && [ "$MASTER" != "$(hostname -i)" ]; then
+	echo "replicaof ${MASTER} 6379" >> /etc/redis/redis.conf
+fi
+`, redisSentinel.Name, redisSentinel.Namespace, masterName, bootstrapMaster, cliTLSArgs, customConfig)
+
+	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      redisSentinel.Name + "-redis",
+			Namespace: redisSentinel.Namespace,
+			Labels:    labels,
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas:    &replicas,
+			ServiceName: redisSentinel.Name + "-redis-headless",
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					InitContainers: []corev1.Container{
+						{
+							Name:    "redis-config",
+							Image:   image,
+							Command: []string{"/bin/sh", "-c", initScript},
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      "redis-config",
+									MountPath: "/etc/redis",
+								},
+							},
+						},
+					},
+					Containers: []corev1.Container{
+						{
+							Name:  "redis",
+							Image: image,
+							Command: []string{
+								"redis-server",
+								"/etc/redis/redis.conf",
+							},
+							Ports: []corev1.ContainerPort{
+								{
+									ContainerPort: 6379,
+									Name:          "redis",
+								},
+							},
+							Resources: redisConfig.Master.Resources,
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      "redis-config",
+									MountPath: "/etc/redis",
+								},
+								{
+									Name:      "redis-data",
+									MountPath: "/data",
+								},
+							},
+							ReadinessProbe: &corev1.Probe{
+								ProbeHandler: corev1.ProbeHandler{
+									Exec: &corev1.ExecAction{
+										Command: []string{"redis-cli", "ping"},
+									},
+								},
+								InitialDelaySeconds: 5,
+								PeriodSeconds:       3,
+							},
+							LivenessProbe: &corev1.Probe{
+								ProbeHandler: corev1.ProbeHandler{
+									Exec: &corev1.ExecAction{
+										Command: []string{"redis-cli", "ping"},
+									},
+								},
+								InitialDelaySeconds: 30,
+								PeriodSeconds:       3,
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "redis-config",
+							VolumeSource: corev1.VolumeSource{
+								EmptyDir: &corev1.EmptyDirVolumeSource{},
+							},
+						},
+					},
+				},
+			},
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "redis-data",
+					},
+					Spec: corev1.PersistentVolumeClaimSpec{
+						AccessModes: []corev1.PersistentVolumeAccessMode{
+							corev1.ReadWriteOnce,
+						},
+						Resources: corev1.VolumeResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceStorage: storageQuantity,
+							},
+						},
+						StorageClassName: storageClassName,
+					},
+				},
+			},
+		},
+	}
+
+	utils.ApplyExporter(&statefulSet.Spec.Template, redisSentinel.Spec.Monitoring, utils.ExporterAuthSecret(redisSentinel.Spec.Security, redisSentinel.Name), redisSentinel.Spec.Security.TLS)
+	utils.ApplyTLS(&statefulSet.Spec.Template, "redis", redisSentinel.Spec.Security.TLS, 6379, false)
+	utils.ApplyAuth(&statefulSet.Spec.Template, "redis", utils.PasswordSecretRef(redisSentinel.Spec.Security, redisSentinel.Name))
+
+	return statefulSet, nil
+}
+
 // ensureSentinelService 确保 Sentinel Service 存在
 func (r *RedisSentinelReconciler) ensureSentinelService(ctx context.Context, redisSentinel *redisv1.RedisSentinel, logs logr.Logger) error {
 	service := &corev1.Service{}
@@ -809,7 +1047,11 @@ func (r *RedisSentinelReconciler) ensureSentinelService(ctx context.Context, red
 		}
 		controllerutil.AddFinalizer(service, redisv1.RedisSentinelFinalizer)
 		logs.Info("Creating sentinel Service", "name", service.Name)
-		return r.Create(ctx, service)
+		if err := r.Create(ctx, service); err != nil {
+			return err
+		}
+		recordCreated(r.Recorder, redisSentinel, "Service", service.Name)
+		return nil
 	} else if err != nil {
 		return err
 	}
@@ -819,39 +1061,45 @@ func (r *RedisSentinelReconciler) ensureSentinelService(ctx context.Context, red
 
 // updateRedisSentinelStatus 更新 RedisSentinel 状态，带有重试机制避免冲突
 func (r *RedisSentinelReconciler) updateRedisSentinelStatus(ctx context.Context, redisSentinel *redisv1.RedisSentinel) error {
+	// 在重试之外查询被监控 master 的状态，避免冲突重试时重复访问 Sentinel
+	masterStatuses := r.monitoredMasterStatuses(ctx, redisSentinel, r.resolveMonitoredMasters(ctx, redisSentinel))
 	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		return r.doUpdateRedisSentinelStatus(ctx, redisSentinel)
+		return r.doUpdateRedisSentinelStatus(ctx, redisSentinel, masterStatuses)
 	})
 }
 
 // doUpdateRedisSentinelStatus 执行实际的状态更新逻辑
-func (r *RedisSentinelReconciler) doUpdateRedisSentinelStatus(ctx context.Context, redisSentinel *redisv1.RedisSentinel) error {
+func (r *RedisSentinelReconciler) doUpdateRedisSentinelStatus(ctx context.Context, redisSentinel *redisv1.RedisSentinel, masterStatuses []redisv1.MonitoredMasterStatus) error {
 	// 重新获取最新的资源版本以避免冲突
 	latestSentinel := &redisv1.RedisSentinel{}
 	if err := r.Get(ctx, types.NamespacedName{Name: redisSentinel.Name, Namespace: redisSentinel.Namespace}, latestSentinel); err != nil {
 		return fmt.Errorf("failed to get latest RedisSentinel: %w", err)
 	}
+	previousReady, previousMasters := latestSentinel.Status.Ready, latestSentinel.Status.MonitoredMasters
 
 	// 获取 Sentinel StatefulSet 状态
 	sentinelSts := &appsv1.StatefulSet{}
 	sentinelStsName := redisSentinel.Name + "-sentinel"
 	sentinelErr := r.Get(ctx, types.NamespacedName{Name: sentinelStsName, Namespace: redisSentinel.Namespace}, sentinelSts)
 
-	// 更新状态
+	// 更新状态，多数 Sentinel 就绪时才能完成故障转移
+	observed := workloadObservation{
+		MinAvailable: latestSentinel.Spec.Replicas/2 + 1,
+		ConfigErrors: setConfigValidCondition(ctx, r.Client, latestSentinel, &latestSentinel.Status.Conditions),
+	}
 	if sentinelErr != nil {
 		latestSentinel.Status.Status = string(redisv1.RedisSentinelPhaseFailed)
-		latestSentinel.Status.Ready = "False"
 		latestSentinel.Status.LastConditionMessage = fmt.Sprintf("Failed to get Sentinel StatefulSet: %v", sentinelErr)
+		observed.Missing = latestSentinel.Status.LastConditionMessage
 	} else {
+		observeStatefulSets(&observed, sentinelSts)
 		sentinelReady := sentinelSts.Status.ReadyReplicas == *sentinelSts.Spec.Replicas
 
 		if sentinelReady {
 			latestSentinel.Status.Status = string(redisv1.RedisSentinelPhaseRunning)
-			latestSentinel.Status.Ready = "True"
 			latestSentinel.Status.LastConditionMessage = fmt.Sprintf("All %d sentinels are ready", sentinelSts.Status.ReadyReplicas)
 		} else {
 			latestSentinel.Status.Status = string(redisv1.RedisSentinelPhasePending)
-			latestSentinel.Status.Ready = "False"
 			latestSentinel.Status.LastConditionMessage = fmt.Sprintf("Waiting for sentinels to be ready: %d/%d", sentinelSts.Status.ReadyReplicas, *sentinelSts.Spec.Replicas)
 		}
 
@@ -859,138 +1107,100 @@ func (r *RedisSentinelReconciler) doUpdateRedisSentinelStatus(ctx context.Contex
 		latestSentinel.Status.ReadyReplicas = sentinelSts.Status.ReadyReplicas
 		latestSentinel.Status.Replicas = *sentinelSts.Spec.Replicas
 		latestSentinel.Status.ServiceName = latestSentinel.Name + "-sentinel-service"
-		// 初始化 MonitoredMaster 状态
-		monitoredMaster := latestSentinel.Status.MonitoredMaster
-		// 只有在 MasterReplicaRef 不为 nil 时才访问其字段
-		if latestSentinel.Spec.MasterReplicaRef != nil && latestSentinel.Spec.MasterReplicaRef.Name != "" {
+		// 更新每个被监控 master 的状态，MonitoredMaster 保留第一个 master 以兼容旧版本
+		latestSentinel.Status.MonitoredMasters = masterStatuses
+		monitoredMaster := redisv1.MonitoredMasterStatus{}
+		if len(masterStatuses) > 0 {
+			monitoredMaster = masterStatuses[0]
+		}
+		// 旧的 MasterReplicaRef 字段沿用引用资源的名称
+		if len(latestSentinel.Spec.Masters) == 0 && latestSentinel.Spec.MasterReplicaRef != nil && latestSentinel.Spec.MasterReplicaRef.Name != "" {
 			monitoredMaster.Name = latestSentinel.Spec.MasterReplicaRef.Name
-		} else {
-			// 使用嵌入式 Redis 时的默认 master 名称
-			monitoredMaster.Name = "mymaster"
 		}
 		latestSentinel.Status.MonitoredMaster = monitoredMaster
-	}
 
-	// 更新 Conditions
-	condition := metav1.Condition{
-		Type:               "Ready",
-		Status:             metav1.ConditionFalse,
-		Reason:             "NotReady",
-		Message:            latestSentinel.Status.LastConditionMessage,
-		LastTransitionTime: metav1.Now(),
+		// 存在无法监控的 master 时在状态消息中说明
+		for _, master := range masterStatuses {
+			if master.Status == "error" {
+				latestSentinel.Status.LastConditionMessage = fmt.Sprintf("%s; master %s: %s",
+					latestSentinel.Status.LastConditionMessage, master.Name, master.Message)
+			}
+		}
 	}
 
-	if latestSentinel.Status.Ready == "true" {
-		condition.Status = metav1.ConditionTrue
-		condition.Reason = "Ready"
-	}
+	// 更新统一的状态条件
+	ready := setWorkloadConditions(&latestSentinel.Status.Conditions, latestSentinel.Generation, observed)
+	setStorageReadyCondition(ctx, r.Client, "RedisSentinel", latestSentinel, &latestSentinel.Status.Conditions)
+	latestSentinel.Status.Ready = string(conditionStatus(ready))
 
-	// 添加或更新条件
-	if latestSentinel.Status.Conditions == nil {
-		latestSentinel.Status.Conditions = []metav1.Condition{}
-	}
+	// 更新 TLS 证书有效期状态
+	setTLSCertificateCondition(ctx, r.Client, "RedisSentinel", latestSentinel, latestSentinel.Spec.Security, &latestSentinel.Status.Conditions)
 
-	// 查找现有条件并更新，或添加新条件
-	found := false
-	for j, existingCondition := range latestSentinel.Status.Conditions {
-		if existingCondition.Type == condition.Type {
-			// 只有在状态真正改变时才更新
-			if existingCondition.Status != condition.Status || existingCondition.Reason != condition.Reason {
-				latestSentinel.Status.Conditions[j] = condition
-			}
-			found = true
-			break
-		}
-	}
-	if !found {
-		latestSentinel.Status.Conditions = append(latestSentinel.Status.Conditions, condition)
+	// 配置指令无效时在状态消息中给出原因
+	if len(observed.ConfigErrors) > 0 {
+		latestSentinel.Status.LastConditionMessage = "Invalid configuration: " + observed.ConfigErrors[0]
 	}
 
-	// 限制条件数量
-	if len(latestSentinel.Status.Conditions) > 10 {
-		latestSentinel.Status.Conditions = latestSentinel.Status.Conditions[len(latestSentinel.Status.Conditions)-10:]
-	}
+	// 记录已应用的配置模板版本，配置模板据此展示滚动进度
+	latestSentinel.Status.ConfigRevision = redisSentinel.Status.ConfigRevision
 
-	// 更新状态
-	return r.Status().Update(ctx, latestSentinel)
+	if err := r.Status().Update(ctx, latestSentinel); err != nil {
+		return err
+	}
+	recordReadyTransition(r.Recorder, latestSentinel, previousReady, ready, latestSentinel.Status.LastConditionMessage)
+	recordMasterTransitions(r.Recorder, latestSentinel, previousMasters, latestSentinel.Status.MonitoredMasters)
+	return nil
 }
 
 // hasEmbeddedRedis 检查是否配置了嵌入式 Redis
 func (r *RedisSentinelReconciler) hasEmbeddedRedis(redisSentinel *redisv1.RedisSentinel) bool {
-	// 如果没有配置外部 MasterReplicaRef，则使用嵌入式 Redis
-	return redisSentinel.Spec.MasterReplicaRef == nil || redisSentinel.Spec.MasterReplicaRef.Name == ""
+	// 如果没有配置 Masters 或外部 MasterReplicaRef，则使用嵌入式 Redis
+	return len(monitoredMasters(redisSentinel)) == 0
 }
 
-// getMasterServiceIP 获取主节点Service的ClusterIP
-func (r *RedisSentinelReconciler) getMasterServiceIP(ctx context.Context, redisSentinel *redisv1.RedisSentinel) (string, error) {
-	var masterServiceName string
-
-	if r.hasEmbeddedRedis(redisSentinel) {
-		// 使用嵌入式 Redis 的 Service 名称
-		masterServiceName = redisSentinel.Name + "-redis-master-service"
-	} else {
-		if redisSentinel.Spec.MasterReplicaRef.Name == "" {
-			return "redis-master", nil // 默认主机名
-		}
-		// 使用外部 RedisMasterReplica 的 Service 名称
-		masterServiceName = redisSentinel.Spec.MasterReplicaRef.Name + "-master-service"
+// sentinelTimings 返回 down-after-milliseconds、failover-timeout 和 parallel-syncs，未配置时使用默认值
+func sentinelTimings(redisSentinel *redisv1.RedisSentinel) (downAfterMilliseconds, failoverTimeout, parallelSyncs int32) {
+	downAfterMilliseconds = redisSentinel.Spec.Config.DownAfterMilliseconds
+	if downAfterMilliseconds <= 0 {
+		downAfterMilliseconds = 30000
 	}
-
-	// 获取主节点Service
-	masterService := &corev1.Service{}
-	err := r.Get(ctx, types.NamespacedName{Name: masterServiceName, Namespace: redisSentinel.Namespace}, masterService)
-	if err != nil {
-		if errors.IsNotFound(err) {
-			// Service不存在，使用FQDN作为fallback
-			return fmt.Sprintf("%s.%s.svc.cluster.local", masterServiceName, redisSentinel.Namespace), nil
-		}
-		return "", err
+	failoverTimeout = redisSentinel.Spec.Config.FailoverTimeout
+	if failoverTimeout <= 0 {
+		failoverTimeout = 180000
 	}
-
-	// 返回ClusterIP
-	if masterService.Spec.ClusterIP != "" && masterService.Spec.ClusterIP != "None" {
-		return masterService.Spec.ClusterIP, nil
+	parallelSyncs = redisSentinel.Spec.Config.ParallelSyncs
+	if parallelSyncs <= 0 {
+		parallelSyncs = 1
 	}
-
-	// 如果没有ClusterIP，使用FQDN
-	return fmt.Sprintf("%s.%s.svc.cluster.local", masterServiceName, redisSentinel.Namespace), nil
+	return downAfterMilliseconds, failoverTimeout, parallelSyncs
 }
 
-// configMapForSentinel 创建 Sentinel ConfigMap
-func (r *RedisSentinelReconciler) configMapForSentinel(redisSentinel *redisv1.RedisSentinel, masterHost string) *corev1.ConfigMap {
-	masterName := "mymaster"
-	masterPort := "6379"
-	quorum := "2"
-
-	// 只有在 MasterReplicaRef 不为 nil 时才访问其字段
-	if redisSentinel.Spec.MasterReplicaRef != nil && redisSentinel.Spec.MasterReplicaRef.Name != "" {
-		if redisSentinel.Spec.MasterReplicaRef.MasterName != "" {
-			masterName = redisSentinel.Spec.MasterReplicaRef.MasterName
-		}
-	}
-
-	if redisSentinel.Spec.Config.Quorum > 0 {
-		quorum = fmt.Sprintf("%d", redisSentinel.Spec.Config.Quorum)
-	}
+// configMapForSentinel 创建 Sentinel ConfigMap，为每个已解析的 master 生成一组 monitor 配置
+func (r *RedisSentinelReconciler) configMapForSentinel(redisSentinel *redisv1.RedisSentinel, masters []resolvedMaster, profile *redisv1.ConfigSpec) *corev1.ConfigMap {
+	downAfterMilliseconds, failoverTimeout, parallelSyncs := sentinelTimings(redisSentinel)
 
-	sentinelConfig := map[string]string{
-		"sentinel.conf": fmt.Sprintf(`# Redis Sentinel Configuration
+	var config strings.Builder
+	config.WriteString(`# Redis Sentinel Configuration
 port 26379
 bind 0.0.0.0
 sentinel resolve-hostnames yes
-sentinel monitor %s %s %s %s
-sentinel down-after-milliseconds %s 30000
-sentinel parallel-syncs %s 1
-sentinel failover-timeout %s 180000
-sentinel deny-scripts-reconfig yes
-`, masterName, masterHost, masterPort, quorum, masterName, masterName, masterName),
+sentinel announce-hostnames yes
+`)
+	for _, master := range masters {
+		if master.Err != nil {
+			continue
+		}
+		fmt.Fprintf(&config, "sentinel monitor %s %s %d %d\n", master.Name, master.Host, master.Port, master.Quorum)
+		fmt.Fprintf(&config, "sentinel down-after-milliseconds %s %d\n", master.Name, downAfterMilliseconds)
+		fmt.Fprintf(&config, "sentinel parallel-syncs %s %d\n", master.Name, parallelSyncs)
+		fmt.Fprintf(&config, "sentinel failover-timeout %s %d\n", master.Name, failoverTimeout)
 	}
+	config.WriteString("sentinel deny-scripts-reconfig yes\n")
 
-	// 合并用户自定义配置
-	if redisSentinel.Spec.Config.AdditionalConfig != nil {
-		for key, value := range redisSentinel.Spec.Config.AdditionalConfig {
-			sentinelConfig[key] = value
-		}
+	// 合并配置模板和用户自定义配置
+	sentinelConfig := map[string]string{
+		"sentinel.conf": utils.AppendRedisConfig(config.String(),
+			mergeProfileConfig(redisSentinel.Spec.Config.AdditionalConfig, profile.Sentinel)),
 	}
 
 	return &corev1.ConfigMap{
@@ -1008,7 +1218,7 @@ sentinel deny-scripts-reconfig yes
 }
 
 // statefulSetForSentinelWithDynamicConfig 创建带有动态配置的 Sentinel StatefulSet
-func (r *RedisSentinelReconciler) statefulSetForSentinelWithDynamicConfig(redisSentinel *redisv1.RedisSentinel) *appsv1.StatefulSet {
+func (r *RedisSentinelReconciler) statefulSetForSentinelWithDynamicConfig(redisSentinel *redisv1.RedisSentinel, masters []resolvedMaster, profile *redisv1.ConfigSpec) (*appsv1.StatefulSet, error) {
 	replicas := redisSentinel.Spec.Replicas
 	if replicas == 0 {
 		replicas = 3 // 默认值
@@ -1060,6 +1270,10 @@ func (r *RedisSentinelReconciler) statefulSetForSentinelWithDynamicConfig(redisS
 
 	var volumeClaimTemplates []corev1.PersistentVolumeClaim
 	if redisSentinel.Spec.Storage.Size != "" {
+		storageQuantity, err := parseStorageSize("spec.storage.size", redisSentinel.Spec.Storage.Size)
+		if err != nil {
+			return nil, err
+		}
 		volumeClaimTemplates = []corev1.PersistentVolumeClaim{
 			{
 				ObjectMeta: metav1.ObjectMeta{
@@ -1069,7 +1283,7 @@ func (r *RedisSentinelReconciler) statefulSetForSentinelWithDynamicConfig(redisS
 					AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
 					Resources: corev1.VolumeResourceRequirements{
 						Requests: corev1.ResourceList{
-							corev1.ResourceStorage: resource.MustParse(redisSentinel.Spec.Storage.Size),
+							corev1.ResourceStorage: storageQuantity,
 						},
 					},
 					StorageClassName: &redisSentinel.Spec.Storage.StorageClassName,
@@ -1085,7 +1299,27 @@ func (r *RedisSentinelReconciler) statefulSetForSentinelWithDynamicConfig(redisS
 		})
 	}
 
-	return &appsv1.StatefulSet{
+	// 为配置了 AuthSecret 的 master 注入 auth-pass，密码只通过环境变量传递，不写入 ConfigMap
+	initScript := "cp /config-source/* /config-dest/"
+	var initEnv []corev1.EnvVar
+	for i, master := range masters {
+		if master.Err != nil || master.AuthSecret == nil {
+			continue
+		}
+		envName := fmt.Sprintf("SENTINEL_AUTH_PASS_%d", i)
+		initEnv = append(initEnv, corev1.EnvVar{
+			Name: envName,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: master.AuthSecret,
+			},
+		})
+		initScript += fmt.Sprintf(" && printf 'sentinel auth-pass %s %%s\\n' \"$%s\" >> /config-dest/sentinel.conf", master.Name, envName)
+	}
+
+	// 配置哈希变化时滚动重启 Sentinel，使新的 monitor 配置生效
+	configHash := sha256.Sum256([]byte(r.configMapForSentinel(redisSentinel, masters, profile).Data["sentinel.conf"]))
+
+	statefulSet := &appsv1.StatefulSet{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      redisSentinel.Name + "-sentinel",
 			Namespace: redisSentinel.Namespace,
@@ -1111,13 +1345,17 @@ func (r *RedisSentinelReconciler) statefulSetForSentinelWithDynamicConfig(redisS
 						"component": "sentinel",
 						"instance":  redisSentinel.Name,
 					},
+					Annotations: map[string]string{
+						"redis.github.com/config-hash": fmt.Sprintf("%x", configHash),
+					},
 				},
 				Spec: corev1.PodSpec{
 					InitContainers: []corev1.Container{
 						{
 							Name:         "config-init",
 							Image:        "busybox:1.35",
-							Command:      []string{"sh", "-c", "cp /config-source/* /config-dest/"},
+							Command:      []string{"sh", "-c", initScript},
+							Env:          initEnv,
 							VolumeMounts: initContainerVolumeMounts,
 						},
 					},
@@ -1160,6 +1398,10 @@ func (r *RedisSentinelReconciler) statefulSetForSentinelWithDynamicConfig(redisS
 			VolumeClaimTemplates: volumeClaimTemplates,
 		},
 	}
+
+	utils.ApplyTLS(&statefulSet.Spec.Template, "sentinel", redisSentinel.Spec.Security.TLS, 26379, false)
+
+	return statefulSet, nil
 }
 
 // serviceForSentinel 创建 Sentinel Service
@@ -1193,402 +1435,6 @@ func (r *RedisSentinelReconciler) serviceForSentinel(redisSentinel *redisv1.Redi
 	}
 }
 
-// ensureRedisMasterStatefulSet 确保 Redis Master StatefulSet 存在
-func (r *RedisSentinelReconciler) ensureRedisMasterStatefulSet(ctx context.Context, redisSentinel *redisv1.RedisSentinel, logs logr.Logger) error {
-	statefulSet := &appsv1.StatefulSet{}
-	statefulSetName := redisSentinel.Name + "-redis-master"
-	err := r.Get(ctx, types.NamespacedName{Name: statefulSetName, Namespace: redisSentinel.Namespace}, statefulSet)
-
-	if errors.IsNotFound(err) {
-		// 创建新的 StatefulSet
-		statefulSet = r.statefulSetForRedisMaster(redisSentinel)
-		if err = controllerutil.SetControllerReference(redisSentinel, statefulSet, r.Scheme); err != nil {
-			return err
-		}
-		controllerutil.AddFinalizer(statefulSet, redisv1.RedisSentinelFinalizer)
-		logs.Info("Creating Redis master StatefulSet", "name", statefulSet.Name)
-		return r.Create(ctx, statefulSet)
-	} else if err != nil {
-		return err
-	}
-
-	return nil
-}
-
-// ensureRedisMasterService 确保 Redis Master Service 存在
-func (r *RedisSentinelReconciler) ensureRedisMasterService(ctx context.Context, redisSentinel *redisv1.RedisSentinel, logs logr.Logger) error {
-	service := &corev1.Service{}
-	serviceName := redisSentinel.Name + "-redis-master-service"
-	err := r.Get(ctx, types.NamespacedName{Name: serviceName, Namespace: redisSentinel.Namespace}, service)
-
-	if errors.IsNotFound(err) {
-		// 创建新的 Service
-		service = r.serviceForRedisMaster(redisSentinel)
-		if err = controllerutil.SetControllerReference(redisSentinel, service, r.Scheme); err != nil {
-			return err
-		}
-		controllerutil.AddFinalizer(service, redisv1.RedisSentinelFinalizer)
-		logs.Info("Creating Redis master Service", "name", service.Name)
-		return r.Create(ctx, service)
-	} else if err != nil {
-		return err
-	}
-
-	return nil
-}
-
-// ensureRedisReplicaStatefulSet 确保 Redis Replica StatefulSet 存在
-func (r *RedisSentinelReconciler) ensureRedisReplicaStatefulSet(ctx context.Context, redisSentinel *redisv1.RedisSentinel, logs logr.Logger) error {
-	statefulSet := &appsv1.StatefulSet{}
-	statefulSetName := redisSentinel.Name + "-redis-replica"
-	err := r.Get(ctx, types.NamespacedName{Name: statefulSetName, Namespace: redisSentinel.Namespace}, statefulSet)
-
-	if errors.IsNotFound(err) {
-		// 创建新的 StatefulSet
-		statefulSet = r.statefulSetForRedisReplica(redisSentinel)
-		if err = controllerutil.SetControllerReference(redisSentinel, statefulSet, r.Scheme); err != nil {
-			return err
-		}
-		controllerutil.AddFinalizer(statefulSet, redisv1.RedisSentinelFinalizer)
-		logs.Info("Creating Redis replica StatefulSet", "name", statefulSet.Name)
-		return r.Create(ctx, statefulSet)
-	} else if err != nil {
-		return err
-	}
-
-	return nil
-}
-
-// ensureRedisReplicaService 确保 Redis Replica Service 存在
-func (r *RedisSentinelReconciler) ensureRedisReplicaService(ctx context.Context, redisSentinel *redisv1.RedisSentinel, logs logr.Logger) error {
-	service := &corev1.Service{}
-	serviceName := redisSentinel.Name + "-redis-replica-service"
-	err := r.Get(ctx, types.NamespacedName{Name: serviceName, Namespace: redisSentinel.Namespace}, service)
-
-	if errors.IsNotFound(err) {
-		// 创建新的 Service
-		service = r.serviceForRedisReplica(redisSentinel)
-		if err = controllerutil.SetControllerReference(redisSentinel, service, r.Scheme); err != nil {
-			return err
-		}
-		controllerutil.AddFinalizer(service, redisv1.RedisSentinelFinalizer)
-		logs.Info("Creating Redis replica Service", "name", service.Name)
-		return r.Create(ctx, service)
-	} else if err != nil {
-		return err
-	}
-
-	return nil
-}
-
-// statefulSetForRedisMaster 创建 Redis Master StatefulSet
-func (r *RedisSentinelReconciler) statefulSetForRedisMaster(redisSentinel *redisv1.RedisSentinel) *appsv1.StatefulSet {
-	replicas := int32(1)
-	resources := redisSentinel.Spec.Resources
-	if len(redisSentinel.Spec.Redis.Master.Resources.Limits) > 0 || len(redisSentinel.Spec.Redis.Master.Resources.Requests) > 0 {
-		resources = redisSentinel.Spec.Redis.Master.Resources
-	}
-
-	volumeMounts := []corev1.VolumeMount{
-		{
-			Name:      "data",
-			MountPath: "/data",
-		},
-	}
-
-	volumes := []corev1.Volume{}
-	var volumeClaimTemplates []corev1.PersistentVolumeClaim
-
-	storageSize := redisSentinel.Spec.Storage.Size
-	storageClassName := redisSentinel.Spec.Storage.StorageClassName
-	if redisSentinel.Spec.Redis.Master.Storage.Size != "" {
-		storageSize = redisSentinel.Spec.Redis.Master.Storage.Size
-		storageClassName = redisSentinel.Spec.Redis.Master.Storage.StorageClassName
-	}
-
-	if storageSize != "" {
-		volumeClaimTemplates = []corev1.PersistentVolumeClaim{
-			{
-				ObjectMeta: metav1.ObjectMeta{
-					Name: "data",
-				},
-				Spec: corev1.PersistentVolumeClaimSpec{
-					AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
-					Resources: corev1.VolumeResourceRequirements{
-						Requests: corev1.ResourceList{
-							corev1.ResourceStorage: resource.MustParse(storageSize),
-						},
-					},
-					StorageClassName: &storageClassName,
-				},
-			},
-		}
-	} else {
-		volumes = append(volumes, corev1.Volume{
-			Name: "data",
-			VolumeSource: corev1.VolumeSource{
-				EmptyDir: &corev1.EmptyDirVolumeSource{},
-			},
-		})
-	}
-
-	return &appsv1.StatefulSet{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      redisSentinel.Name + "-redis-master",
-			Namespace: redisSentinel.Namespace,
-			Labels: map[string]string{
-				"app":       "redis",
-				"component": "master",
-				"instance":  redisSentinel.Name,
-			},
-		},
-		Spec: appsv1.StatefulSetSpec{
-			Replicas: &replicas,
-			Selector: &metav1.LabelSelector{
-				MatchLabels: map[string]string{
-					"app":       "redis",
-					"component": "master",
-					"instance":  redisSentinel.Name,
-				},
-			},
-			Template: corev1.PodTemplateSpec{
-				ObjectMeta: metav1.ObjectMeta{
-					Labels: map[string]string{
-						"app":       "redis",
-						"component": "master",
-						"instance":  redisSentinel.Name,
-					},
-				},
-				Spec: corev1.PodSpec{
-					Containers: []corev1.Container{
-						{
-							Name:  "redis",
-							Image: redisSentinel.Spec.Image,
-							Ports: []corev1.ContainerPort{
-								{
-									ContainerPort: 6379,
-									Name:          "redis",
-								},
-							},
-							Command:      []string{"redis-server", "--port", "6379"},
-							VolumeMounts: volumeMounts,
-							Resources:    resources,
-							ReadinessProbe: &corev1.Probe{
-								ProbeHandler: corev1.ProbeHandler{
-									TCPSocket: &corev1.TCPSocketAction{
-										Port: intstr.FromInt(6379),
-									},
-								},
-								InitialDelaySeconds: 5,
-								PeriodSeconds:       3,
-							},
-							LivenessProbe: &corev1.Probe{
-								ProbeHandler: corev1.ProbeHandler{
-									TCPSocket: &corev1.TCPSocketAction{
-										Port: intstr.FromInt(6379),
-									},
-								},
-								InitialDelaySeconds: 30,
-								PeriodSeconds:       3,
-							},
-						},
-					},
-					Volumes: volumes,
-				},
-			},
-			VolumeClaimTemplates: volumeClaimTemplates,
-		},
-	}
-}
-
-// serviceForRedisMaster 创建 Redis Master Service
-func (r *RedisSentinelReconciler) serviceForRedisMaster(redisSentinel *redisv1.RedisSentinel) *corev1.Service {
-	return &corev1.Service{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      redisSentinel.Name + "-redis-master-service",
-			Namespace: redisSentinel.Namespace,
-			Labels: map[string]string{
-				"app":       "redis",
-				"component": "master",
-				"instance":  redisSentinel.Name,
-			},
-		},
-		Spec: corev1.ServiceSpec{
-			Selector: map[string]string{
-				"app":       "redis",
-				"component": "master",
-				"instance":  redisSentinel.Name,
-			},
-			Ports: []corev1.ServicePort{
-				{
-					Name:       "redis",
-					Port:       6379,
-					TargetPort: intstr.FromInt(6379),
-					Protocol:   corev1.ProtocolTCP,
-				},
-			},
-			Type: corev1.ServiceTypeClusterIP,
-		},
-	}
-}
-
-// statefulSetForRedisReplica 创建 Redis Replica StatefulSet
-func (r *RedisSentinelReconciler) statefulSetForRedisReplica(redisSentinel *redisv1.RedisSentinel) *appsv1.StatefulSet {
-	replicas := int32(2)
-	if redisSentinel.Spec.Redis.Replica.Replicas > 0 {
-		replicas = redisSentinel.Spec.Redis.Replica.Replicas
-	}
-
-	resources := redisSentinel.Spec.Resources
-	if len(redisSentinel.Spec.Redis.Replica.Resources.Limits) > 0 || len(redisSentinel.Spec.Redis.Replica.Resources.Requests) > 0 {
-		resources = redisSentinel.Spec.Redis.Replica.Resources
-	}
-
-	masterServiceName := redisSentinel.Name + "-redis-master-service"
-
-	volumeMounts := []corev1.VolumeMount{
-		{
-			Name:      "data",
-			MountPath: "/data",
-		},
-	}
-
-	volumes := []corev1.Volume{}
-	var volumeClaimTemplates []corev1.PersistentVolumeClaim
-
-	storageSize := redisSentinel.Spec.Storage.Size
-	storageClassName := redisSentinel.Spec.Storage.StorageClassName
-	if redisSentinel.Spec.Redis.Replica.Storage.Size != "" {
-		storageSize = redisSentinel.Spec.Redis.Replica.Storage.Size
-		storageClassName = redisSentinel.Spec.Redis.Replica.Storage.StorageClassName
-	}
-
-	if storageSize != "" {
-		volumeClaimTemplates = []corev1.PersistentVolumeClaim{
-			{
-				ObjectMeta: metav1.ObjectMeta{
-					Name: "data",
-				},
-				Spec: corev1.PersistentVolumeClaimSpec{
-					AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
-					Resources: corev1.VolumeResourceRequirements{
-						Requests: corev1.ResourceList{
-							corev1.ResourceStorage: resource.MustParse(storageSize),
-						},
-					},
-					StorageClassName: &storageClassName,
-				},
-			},
-		}
-	} else {
-		volumes = append(volumes, corev1.Volume{
-			Name: "data",
-			VolumeSource: corev1.VolumeSource{
-				EmptyDir: &corev1.EmptyDirVolumeSource{},
-			},
-		})
-	}
-
-	return &appsv1.StatefulSet{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      redisSentinel.Name + "-redis-replica",
-			Namespace: redisSentinel.Namespace,
-			Labels: map[string]string{
-				"app":       "redis",
-				"component": "replica",
-				"instance":  redisSentinel.Name,
-			},
-		},
-		Spec: appsv1.StatefulSetSpec{
-			Replicas: &replicas,
-			Selector: &metav1.LabelSelector{
-				MatchLabels: map[string]string{
-					"app":       "redis",
-					"component": "replica",
-					"instance":  redisSentinel.Name,
-				},
-			},
-			Template: corev1.PodTemplateSpec{
-				ObjectMeta: metav1.ObjectMeta{
-					Labels: map[string]string{
-						"app":       "redis",
-						"component": "replica",
-						"instance":  redisSentinel.Name,
-					},
-				},
-				Spec: corev1.PodSpec{
-					Containers: []corev1.Container{
-						{
-							Name:  "redis",
-							Image: redisSentinel.Spec.Image,
-							Ports: []corev1.ContainerPort{
-								{
-									ContainerPort: 6379,
-									Name:          "redis",
-								},
-							},
-							Command:      []string{"redis-server", "--port", "6379", "--replicaof", masterServiceName, "6379"},
-							VolumeMounts: volumeMounts,
-							Resources:    resources,
-							ReadinessProbe: &corev1.Probe{
-								ProbeHandler: corev1.ProbeHandler{
-									TCPSocket: &corev1.TCPSocketAction{
-										Port: intstr.FromInt(6379),
-									},
-								},
-								InitialDelaySeconds: 5,
-								PeriodSeconds:       3,
-							},
-							LivenessProbe: &corev1.Probe{
-								ProbeHandler: corev1.ProbeHandler{
-									TCPSocket: &corev1.TCPSocketAction{
-										Port: intstr.FromInt(6379),
-									},
-								},
-								InitialDelaySeconds: 30,
-								PeriodSeconds:       3,
-							},
-						},
-					},
-					Volumes: volumes,
-				},
-			},
-			VolumeClaimTemplates: volumeClaimTemplates,
-		},
-	}
-}
-
-// serviceForRedisReplica 创建 Redis Replica Service
-func (r *RedisSentinelReconciler) serviceForRedisReplica(redisSentinel *redisv1.RedisSentinel) *corev1.Service {
-	return &corev1.Service{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      redisSentinel.Name + "-redis-replica-service",
-			Namespace: redisSentinel.Namespace,
-			Labels: map[string]string{
-				"app":       "redis",
-				"component": "replica",
-				"instance":  redisSentinel.Name,
-			},
-		},
-		Spec: corev1.ServiceSpec{
-			Selector: map[string]string{
-				"app":       "redis",
-				"component": "replica",
-				"instance":  redisSentinel.Name,
-			},
-			Ports: []corev1.ServicePort{
-				{
-					Name:       "redis",
-					Port:       6379,
-					TargetPort: intstr.FromInt(6379),
-					Protocol:   corev1.ProtocolTCP,
-				},
-			},
-			Type: corev1.ServiceTypeClusterIP,
-		},
-	}
-}
-
 // setUpdatingStatus 设置更新状态
 func (r *RedisSentinelReconciler) setUpdatingStatus(ctx context.Context, redisSentinel *redisv1.RedisSentinel, message string) error {
 	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
@@ -1602,98 +1448,33 @@ func (r *RedisSentinelReconciler) setUpdatingStatus(ctx context.Context, redisSe
 		latestSentinel.Status.Ready = "False"
 		latestSentinel.Status.LastConditionMessage = message
 
-		// 更新 Conditions
-		condition := metav1.Condition{
-			Type:               "Ready",
-			Status:             metav1.ConditionFalse,
-			Reason:             "Updating",
-			Message:            message,
-			LastTransitionTime: metav1.Now(),
-		}
-
-		if latestSentinel.Status.Conditions == nil {
-			latestSentinel.Status.Conditions = []metav1.Condition{}
-		}
-
-		// 更新或添加条件
-		found := false
-		for i, existingCondition := range latestSentinel.Status.Conditions {
-			if existingCondition.Type == condition.Type {
-				latestSentinel.Status.Conditions[i] = condition
-				found = true
-				break
-			}
-		}
-		if !found {
-			latestSentinel.Status.Conditions = append(latestSentinel.Status.Conditions, condition)
-		}
+		setUpdatingConditions(&latestSentinel.Status.Conditions, latestSentinel.Generation, message)
 
 		return r.Status().Update(ctx, latestSentinel)
 	})
 }
 
-// buildRedisEnvVars 构建 Redis 容器的环境变量
-func (r *RedisSentinelReconciler) buildRedisEnvVars(redisConfig redisv1.RedisInstanceConfig) []corev1.EnvVar {
-	envVars := []corev1.EnvVar{}
-
-	// 添加基础配置
-	if redisConfig.MasterName != "" {
-		envVars = append(envVars, corev1.EnvVar{
-			Name:  "REDIS_MASTER_NAME",
-			Value: redisConfig.MasterName,
-		})
-	}
-
-	// 添加全局配置
-	for key, value := range redisConfig.Config {
-		envVars = append(envVars, corev1.EnvVar{
-			Name:  fmt.Sprintf("REDIS_CONFIG_%s", key),
-			Value: value,
-		})
-	}
-
-	// 添加主节点配置
-	for key, value := range redisConfig.Master.Config {
-		envVars = append(envVars, corev1.EnvVar{
-			Name:  fmt.Sprintf("REDIS_MASTER_CONFIG_%s", key),
-			Value: value,
-		})
-	}
-
-	// 添加从节点配置
-	for key, value := range redisConfig.Replica.Config {
-		envVars = append(envVars, corev1.EnvVar{
-			Name:  fmt.Sprintf("REDIS_REPLICA_CONFIG_%s", key),
-			Value: value,
-		})
-	}
-
-	return envVars
+// isEmptyResourceRequirements 检查资源配置是否为空
+func isEmptyResourceRequirements(resources corev1.ResourceRequirements) bool {
+	return len(resources.Limits) == 0 && len(resources.Requests) == 0
 }
 
-// compareEnvVars 比较两个环境变量列表是否相等
-func (r *RedisSentinelReconciler) compareEnvVars(current, desired []corev1.EnvVar) bool {
-	if len(current) != len(desired) {
-		return false
-	}
-
-	// 创建映射以便比较
-	currentMap := make(map[string]string)
-	for _, env := range current {
-		currentMap[env.Name] = env.Value
+// sentinelsForConfig 返回引用了该配置模板的 RedisSentinel，配置模板变化后重新生成配置
+func (r *RedisSentinelReconciler) sentinelsForConfig(ctx context.Context, obj client.Object) []reconcile.Request {
+	sentinels := &redisv1.RedisSentinelList{}
+	if err := r.List(ctx, sentinels, client.InNamespace(obj.GetNamespace())); err != nil {
+		return nil
 	}
 
-	desiredMap := make(map[string]string)
-	for _, env := range desired {
-		desiredMap[env.Name] = env.Value
+	var requests []reconcile.Request
+	for _, sentinel := range sentinels.Items {
+		if sentinel.Spec.ConfigRef != nil && sentinel.Spec.ConfigRef.Name == obj.GetName() {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: sentinel.Name, Namespace: sentinel.Namespace},
+			})
+		}
 	}
-
-	return reflect.DeepEqual(currentMap, desiredMap)
-}
-
-// isEmptyResourceRequirements 检查资源配置是否为空
-func isEmptyResourceRequirements(resources corev1.ResourceRequirements) bool {
-	return len(resources.Limits) == 0 && len(resources.Requests) == 0
+	return requests
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -1703,6 +1484,14 @@ func (r *RedisSentinelReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&corev1.ConfigMap{}).
 		Owns(&corev1.Service{}).
 		Owns(&appsv1.StatefulSet{}).
+		Watches(
+			&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.sentinelsForSecret),
+		).
+		Watches(
+			&redisv1.Config{},
+			handler.EnqueueRequestsFromMapFunc(r.sentinelsForConfig),
+		).
 		Watches(
 			&corev1.ConfigMap{},
 			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
@@ -1720,6 +1509,10 @@ func (r *RedisSentinelReconciler) SetupWithManager(mgr ctrl.Manager) error {
 				return nil
 			}),
 		).
+		Watches(
+			&redisv1.RedisMasterReplica{},
+			handler.EnqueueRequestsFromMapFunc(r.sentinelsForMasterReplica),
+		).
 		Watches(
 			&appsv1.StatefulSet{},
 			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
@@ -1737,5 +1530,5 @@ func (r *RedisSentinelReconciler) SetupWithManager(mgr ctrl.Manager) error {
 				return nil
 			}),
 		).
-		Complete(r)
+		Complete(instrumentReconciler("RedisSentinel", r.Client, func() client.Object { return &redisv1.RedisSentinel{} }, r))
 }
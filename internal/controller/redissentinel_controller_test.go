@@ -29,6 +29,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
@@ -52,8 +53,9 @@ var _ = Describe("RedisSentinel Controller", func() {
 
 		k8sClient = fake.NewClientBuilder().WithScheme(scheme).Build()
 		reconciler = &RedisSentinelReconciler{
-			Client: k8sClient,
-			Scheme: scheme,
+			Client:   k8sClient,
+			Scheme:   scheme,
+			Recorder: &record.FakeRecorder{},
 		}
 	})
 
@@ -187,27 +189,25 @@ var _ = Describe("RedisSentinel Controller", func() {
 
 			Expect(sentinelCm.Data).To(HaveKey("sentinel.conf"))
 
-			By("Checking that Redis Master StatefulSet is created")
-			masterSts := &appsv1.StatefulSet{}
+			By("Checking that a single Redis StatefulSet holds the master and replicas")
+			redisSts := &appsv1.StatefulSet{}
 			Eventually(func() error {
 				return k8sClient.Get(ctx, types.NamespacedName{
-					Name:      resourceName + "-redis-master",
+					Name:      resourceName + "-redis",
 					Namespace: "default",
-				}, masterSts)
+				}, redisSts)
 			}, time.Second*10, time.Millisecond*250).Should(Succeed())
 
-			Expect(masterSts.Spec.Replicas).To(Equal(int32Ptr(1)))
-
-			By("Checking that Redis Replica StatefulSet is created")
-			replicaSts := &appsv1.StatefulSet{}
-			Eventually(func() error {
-				return k8sClient.Get(ctx, types.NamespacedName{
-					Name:      resourceName + "-redis-replica",
-					Namespace: "default",
-				}, replicaSts)
-			}, time.Second*10, time.Millisecond*250).Should(Succeed())
+			Expect(redisSts.Spec.Replicas).To(Equal(int32Ptr(3)))
+			Expect(redisSts.Spec.ServiceName).To(Equal(resourceName + "-redis-headless"))
 
-			Expect(replicaSts.Spec.Replicas).To(Equal(int32Ptr(2)))
+			By("Checking that legacy master/replica StatefulSets are not created")
+			masterSts := &appsv1.StatefulSet{}
+			err = k8sClient.Get(ctx, types.NamespacedName{
+				Name:      resourceName + "-redis-master",
+				Namespace: "default",
+			}, masterSts)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
 		})
 
 		It("should update status correctly", func() {
@@ -433,6 +433,327 @@ var _ = Describe("RedisSentinel Controller", func() {
 			}, time.Second*10, time.Millisecond*250).Should(BeTrue())
 		})
 	})
+
+	Context("When monitoring multiple masters", func() {
+		const resourceName = "test-sentinel-multi"
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		var (
+			redisSentinel *redisv1.RedisSentinel
+			masterReplica *redisv1.RedisMasterReplica
+		)
+
+		BeforeEach(func() {
+			masterReplica = &redisv1.RedisMasterReplica{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "orders-redis",
+					Namespace: "default",
+				},
+				Spec: redisv1.RedisMasterReplicaSpec{
+					Image: "redis:7.0",
+				},
+			}
+			Expect(k8sClient.Create(ctx, masterReplica)).To(Succeed())
+
+			redisSentinel = &redisv1.RedisSentinel{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: redisv1.RedisSentinelSpec{
+					Image:    "redis:7.0",
+					Replicas: 3,
+					Config: redisv1.SentinelConfig{
+						Quorum: 2,
+					},
+					Masters: []redisv1.MonitoredMasterSpec{
+						{
+							Name: "orders",
+							MasterReplicaRef: &redisv1.MasterReplicaRef{
+								Name: "orders-redis",
+							},
+							AuthSecret: &corev1.SecretKeySelector{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "orders-auth"},
+								Key:                  "password",
+							},
+						},
+						{
+							Name:   "legacy",
+							Quorum: 3,
+							External: &redisv1.ExternalMasterSpec{
+								Host: "redis.legacy.example.com",
+								Port: 6380,
+							},
+						},
+						{
+							Name: "forbidden",
+							MasterReplicaRef: &redisv1.MasterReplicaRef{
+								Name:      "other-redis",
+								Namespace: "other",
+							},
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, redisSentinel)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			By("Cleanup the RedisSentinel resource")
+			Expect(k8sClient.Delete(ctx, redisSentinel)).To(Succeed())
+			By("Cleanup the RedisMasterReplica resource")
+			Expect(k8sClient.Delete(ctx, masterReplica)).To(Succeed())
+		})
+
+		It("should write a monitor block per resolvable master", func() {
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking the sentinel configuration")
+			sentinelCm := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{
+				Name:      resourceName + "-sentinel-config",
+				Namespace: "default",
+			}, sentinelCm)).To(Succeed())
+			config := sentinelCm.Data["sentinel.conf"]
+			Expect(config).To(ContainSubstring("sentinel monitor orders orders-redis-master-service.default.svc.cluster.local 6379 2"))
+			Expect(config).To(ContainSubstring("sentinel monitor legacy redis.legacy.example.com 6380 3"))
+			Expect(config).NotTo(ContainSubstring("sentinel monitor forbidden"))
+
+			By("Checking that no embedded Redis is created")
+			redisSts := &appsv1.StatefulSet{}
+			err = k8sClient.Get(ctx, types.NamespacedName{
+				Name:      resourceName + "-redis",
+				Namespace: "default",
+			}, redisSts)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+
+			By("Checking that the auth password is injected from the secret")
+			sentinelSts := &appsv1.StatefulSet{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{
+				Name:      resourceName + "-sentinel",
+				Namespace: "default",
+			}, sentinelSts)).To(Succeed())
+			initContainer := sentinelSts.Spec.Template.Spec.InitContainers[0]
+			Expect(initContainer.Env).To(HaveLen(1))
+			Expect(initContainer.Env[0].ValueFrom.SecretKeyRef.Name).To(Equal("orders-auth"))
+			Expect(initContainer.Command[2]).To(ContainSubstring("sentinel auth-pass orders"))
+
+			By("Checking the per-master status")
+			updatedSentinel := &redisv1.RedisSentinel{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, updatedSentinel)).To(Succeed())
+			Expect(updatedSentinel.Status.MonitoredMasters).To(HaveLen(3))
+			Expect(updatedSentinel.Status.MonitoredMasters[0].Source).To(Equal("RedisMasterReplica/default/orders-redis"))
+			Expect(updatedSentinel.Status.MonitoredMasters[1].Source).To(Equal("External"))
+			Expect(updatedSentinel.Status.MonitoredMasters[2].Status).To(Equal("error"))
+		})
+	})
+
+	Context("When authentication is enabled for embedded Redis", func() {
+		const resourceName = "test-sentinel-auth"
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		var redisSentinel *redisv1.RedisSentinel
+
+		BeforeEach(func() {
+			redisSentinel = &redisv1.RedisSentinel{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: redisv1.RedisSentinelSpec{
+					Image:    "redis:7.0",
+					Replicas: 3,
+					Security: redisv1.SecuritySpec{
+						AuthEnabled: true,
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, redisSentinel)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			By("Cleanup the RedisSentinel resource")
+			Expect(k8sClient.Delete(ctx, redisSentinel)).To(Succeed())
+		})
+
+		It("should generate a password secret and wire it into Redis and Sentinel", func() {
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking that a random password secret is generated")
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{
+				Name:      resourceName + "-redis-auth",
+				Namespace: "default",
+			}, secret)).To(Succeed())
+			password := string(secret.Data["password"])
+			Expect(password).To(HaveLen(32))
+
+			By("Checking that Redis loads the password from the mounted secret")
+			redisSts := &appsv1.StatefulSet{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{
+				Name:      resourceName + "-redis",
+				Namespace: "default",
+			}, redisSts)).To(Succeed())
+			redisContainer := redisSts.Spec.Template.Spec.Containers[0]
+			Expect(redisContainer.Command[2]).To(ContainSubstring("requirepass"))
+			Expect(redisContainer.Command[2]).To(ContainSubstring("--include /tmp/redis-auth.conf"))
+			Expect(redisContainer.Env).To(ContainElement(HaveField("Name", "REDISCLI_AUTH")))
+			Expect(redisSts.Spec.Template.Annotations).To(HaveKeyWithValue("redis.github.com/auth-secret", resourceName+"-redis-auth/password"))
+
+			By("Checking that sentinels authenticate to the embedded master")
+			sentinelSts := &appsv1.StatefulSet{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{
+				Name:      resourceName + "-sentinel",
+				Namespace: "default",
+			}, sentinelSts)).To(Succeed())
+			initContainer := sentinelSts.Spec.Template.Spec.InitContainers[0]
+			Expect(initContainer.Env).To(HaveLen(1))
+			Expect(initContainer.Env[0].ValueFrom.SecretKeyRef.Name).To(Equal(resourceName + "-redis-auth"))
+			Expect(initContainer.Command[2]).To(ContainSubstring("sentinel auth-pass mymaster"))
+
+			By("Checking that the password is never written into a ConfigMap")
+			sentinelCm := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{
+				Name:      resourceName + "-sentinel-config",
+				Namespace: "default",
+			}, sentinelCm)).To(Succeed())
+			Expect(sentinelCm.Data["sentinel.conf"]).NotTo(ContainSubstring(password))
+		})
+	})
+
+	Context("When checking monitored master addresses", func() {
+		It("should accept addresses of the workload pods and reject stale ones", func() {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "orders-redis-master-0",
+					Namespace: "default",
+					Labels: map[string]string{
+						"app":      "redis",
+						"instance": "orders-redis",
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+			pod.Status.PodIP = "10.0.0.12"
+			Expect(k8sClient.Status().Update(ctx, pod)).To(Succeed())
+
+			master := resolvedMaster{
+				Name:      "orders",
+				Host:      "orders-redis-master-0.orders-redis-master-headless.default.svc.cluster.local",
+				Namespace: "default",
+				PodLabels: map[string]string{
+					"app":      "redis",
+					"instance": "orders-redis",
+				},
+			}
+			isKnownAddress, err := reconciler.masterAddressMatcher(ctx, master)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(isKnownAddress(master.Host)).To(BeTrue())
+			Expect(isKnownAddress("10.0.0.12")).To(BeTrue())
+			Expect(isKnownAddress("orders-redis-master-0.other.default.svc.cluster.local")).To(BeTrue())
+			Expect(isKnownAddress("10.96.0.25")).To(BeFalse())
+
+			By("Not checking addresses of external masters")
+			isKnownAddress, err = reconciler.masterAddressMatcher(ctx, resolvedMaster{Name: "legacy", Host: "redis.example.com"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(isKnownAddress).To(BeNil())
+		})
+	})
+
+	Context("When checking cross-namespace permissions", func() {
+		It("should honour the allowed-sentinel-namespaces annotation", func() {
+			masterReplica := &redisv1.RedisMasterReplica{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						redisv1.AllowedSentinelNamespacesAnnotation: "monitoring, ops",
+					},
+				},
+			}
+			Expect(allowsSentinelNamespace(masterReplica, "ops")).To(BeTrue())
+			Expect(allowsSentinelNamespace(masterReplica, "default")).To(BeFalse())
+
+			masterReplica.Annotations[redisv1.AllowedSentinelNamespacesAnnotation] = "*"
+			Expect(allowsSentinelNamespace(masterReplica, "default")).To(BeTrue())
+
+			delete(masterReplica.Annotations, redisv1.AllowedSentinelNamespacesAnnotation)
+			Expect(allowsSentinelNamespace(masterReplica, "default")).To(BeFalse())
+		})
+	})
+
+	Context("When sentinels report a different master", func() {
+		It("should record failover and master down events", func() {
+			recorder := record.NewFakeRecorder(10)
+			redisSentinel := &redisv1.RedisSentinel{ObjectMeta: metav1.ObjectMeta{Name: "test-sentinel", Namespace: "default"}}
+			previous := []redisv1.MonitoredMasterStatus{
+				{Name: "orders", IP: "10.0.0.12", Port: 6379, Status: "up"},
+				{Name: "billing", IP: "billing.example.com", Port: 6379, Status: "unknown"},
+			}
+			current := []redisv1.MonitoredMasterStatus{
+				{Name: "orders", IP: "10.0.0.13", Port: 6379, Status: "down"},
+				{Name: "billing", IP: "10.0.0.20", Port: 6379, Status: "up"},
+			}
+
+			recordMasterTransitions(recorder, redisSentinel, previous, current)
+			Expect(recorder.Events).To(HaveLen(2))
+			Expect(<-recorder.Events).To(Equal("Warning Failover Master orders failed over from 10.0.0.12:6379 to 10.0.0.13:6379"))
+			Expect(<-recorder.Events).To(Equal("Warning MasterDown Sentinels report master orders at 10.0.0.13:6379 as down"))
+
+			By("Not recording events while nothing changes")
+			recordMasterTransitions(recorder, redisSentinel, current, current)
+			Expect(recorder.Events).To(BeEmpty())
+		})
+	})
+
+	Context("When generating alert rules", func() {
+		It("should alert on the quorum of every monitored master", func() {
+			topology := alertTopology{quorums: map[string]int32{"orders": 2, "billing": 3}}
+			rules := buildAlertRules("default", "test-sentinel", &redisv1.AlertsSpec{Enabled: true}, topology)
+
+			alerts := map[string]alertRule{}
+			for _, rule := range rules {
+				alerts[rule.alert] = rule
+			}
+			// 未配置嵌入式 Redis 时不生成 Redis 节点和存储告警
+			Expect(alerts).To(HaveLen(2))
+			Expect(alerts).To(HaveKey("RedisSentinelMasterDown"))
+			Expect(alerts["RedisSentinelQuorumLost"].expr).To(Equal(
+				`redis_sentinel_sentinels_total{namespace="default",name="test-sentinel",master_name="billing"} + 1 < 3 or ` +
+					`redis_sentinel_sentinels_total{namespace="default",name="test-sentinel",master_name="orders"} + 1 < 2`))
+		})
+
+		It("should apply threshold overrides to the embedded Redis alerts", func() {
+			topology := newAlertTopology("RedisSentinel", "test.sentinel")
+			rules := buildAlertRules("default", "test.sentinel", &redisv1.AlertsSpec{
+				Enabled:               true,
+				For:                   "10m",
+				ReplicationLagSeconds: 60,
+				StorageUsagePercent:   70,
+			}, topology)
+
+			alerts := map[string]alertRule{}
+			for _, rule := range rules {
+				alerts[rule.alert] = rule
+			}
+			Expect(alerts["RedisMasterDown"].duration).To(Equal(criticalAlertFor))
+			Expect(alerts["RedisReplicationLag"].duration).To(Equal("10m"))
+			Expect(alerts["RedisReplicationLag"].expr).To(HaveSuffix("> 60"))
+			Expect(alerts["RedisMemoryNearMaxmemory"].expr).To(HaveSuffix("> 90"))
+			Expect(alerts["RedisPVCNearlyFull"].expr).To(ContainSubstring(`persistentvolumeclaim=~"redis-data-test\\.sentinel-redis-[0-9]+"`))
+			Expect(alerts["RedisPVCNearlyFull"].expr).To(HaveSuffix("> 70"))
+		})
+	})
 })
 
 // 辅助函数
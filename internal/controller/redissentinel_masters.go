@@ -0,0 +1,373 @@
+/*
+Copyright 2025 James.Liu.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	appsv1 "k8s.io/api/apps/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	redisv1 "github.com/ybooks240/redis-operator/api/v1"
+	"github.com/ybooks240/redis-operator/internal/utils"
+)
+
+const (
+	defaultSentinelMasterName = "mymaster"
+	defaultRedisPort          = 6379
+
+	// sentinelQueryTimeout 查询 Sentinel 运行状态的超时时间
+	sentinelQueryTimeout = 2 * time.Second
+)
+
+// resolvedMaster 已解析出地址的被监控 master
+type resolvedMaster struct {
+	Name   string
+	Source string
+	Host   string
+	Port   int32
+	Quorum int32
+	// AuthSecret master 密码所在的 Secret，必须位于 Sentinel 所在命名空间
+	AuthSecret *corev1.SecretKeySelector
+	// Namespace 和 PodLabels 用于定位 master 所属工作负载的 Pod，外部 master 为空
+	Namespace string
+	PodLabels map[string]string
+	Err       error
+}
+
+// monitoredMasters 返回规范化后的被监控 master 列表
+// 未配置 Masters 时兼容旧的 MasterReplicaRef 字段，两者都未配置时返回空列表（使用嵌入式 Redis）
+func monitoredMasters(redisSentinel *redisv1.RedisSentinel) []redisv1.MonitoredMasterSpec {
+	if len(redisSentinel.Spec.Masters) > 0 {
+		return redisSentinel.Spec.Masters
+	}
+
+	ref := redisSentinel.Spec.MasterReplicaRef
+	if ref != nil && ref.Name != "" {
+		masterName := ref.MasterName
+		if masterName == "" {
+			masterName = defaultSentinelMasterName
+		}
+		return []redisv1.MonitoredMasterSpec{{
+			Name:             masterName,
+			MasterReplicaRef: ref,
+		}}
+	}
+
+	return nil
+}
+
+// resolveMonitoredMasters 解析所有被监控 master 的地址，单个 master 解析失败不影响其他 master
+func (r *RedisSentinelReconciler) resolveMonitoredMasters(ctx context.Context, redisSentinel *redisv1.RedisSentinel) []resolvedMaster {
+	defaultQuorum := redisSentinel.Spec.Config.Quorum
+	if defaultQuorum <= 0 {
+		// 未经过准入 webhook 默认值填充时，使用 sentinel 的多数派
+		defaultQuorum = redisSentinel.Spec.Replicas/2 + 1
+	}
+
+	if r.hasEmbeddedRedis(redisSentinel) {
+		masterName := redisSentinel.Spec.Redis.MasterName
+		if masterName == "" {
+			masterName = defaultSentinelMasterName
+		}
+		// 使用 DNS 名称直接指向嵌入式 Redis master Pod，迁移期间指向旧的 master Service
+		legacy, err := r.hasLegacyEmbeddedRedis(ctx, redisSentinel)
+		return []resolvedMaster{{
+			Name:       masterName,
+			Source:     "Embedded",
+			Host:       embeddedBootstrapMaster(redisSentinel, legacy),
+			Port:       defaultRedisPort,
+			Quorum:     defaultQuorum,
+			AuthSecret: utils.PasswordSecretRef(redisSentinel.Spec.Security, redisSentinel.Name),
+			Namespace:  redisSentinel.Namespace,
+			PodLabels: map[string]string{
+				"app":      "redis",
+				"instance": redisSentinel.Name,
+			},
+			Err: err,
+		}}
+	}
+
+	specs := monitoredMasters(redisSentinel)
+	masters := make([]resolvedMaster, 0, len(specs))
+	for _, spec := range specs {
+		master := resolvedMaster{
+			Name:       spec.Name,
+			Quorum:     spec.Quorum,
+			AuthSecret: spec.AuthSecret,
+		}
+		if master.Quorum <= 0 {
+			master.Quorum = defaultQuorum
+		}
+
+		switch {
+		case spec.External != nil:
+			master.Source = "External"
+			master.Host = spec.External.Host
+			master.Port = spec.External.Port
+			if master.Port == 0 {
+				master.Port = defaultRedisPort
+			}
+		case spec.MasterReplicaRef != nil:
+			namespace := masterReplicaRefNamespace(redisSentinel, spec.MasterReplicaRef)
+			master.Source = fmt.Sprintf("RedisMasterReplica/%s/%s", namespace, spec.MasterReplicaRef.Name)
+			master.Port = defaultRedisPort
+			master.Namespace = namespace
+			master.PodLabels = map[string]string{
+				"app":      "redis",
+				"instance": spec.MasterReplicaRef.Name,
+			}
+			master.Host, master.Err = r.resolveMasterReplicaHost(ctx, redisSentinel, spec.MasterReplicaRef)
+			// 同命名空间的 RedisMasterReplica 未显式指定 authSecret 时使用其密码 Secret
+			if master.Err == nil && master.AuthSecret == nil && namespace == redisSentinel.Namespace {
+				master.AuthSecret, master.Err = r.masterReplicaAuthSecret(ctx, namespace, spec.MasterReplicaRef.Name)
+			}
+		default:
+			master.Err = fmt.Errorf("neither masterReplicaRef nor external is set")
+		}
+
+		masters = append(masters, master)
+	}
+
+	return masters
+}
+
+// masterReplicaRefNamespace 返回引用的 RedisMasterReplica 所在命名空间，默认为 Sentinel 所在命名空间
+func masterReplicaRefNamespace(redisSentinel *redisv1.RedisSentinel, ref *redisv1.MasterReplicaRef) string {
+	if ref.Namespace != "" {
+		return ref.Namespace
+	}
+	return redisSentinel.Namespace
+}
+
+// resolveMasterReplicaHost 解析 RedisMasterReplica 的 master 地址，跨命名空间引用需要通过访问检查
+func (r *RedisSentinelReconciler) resolveMasterReplicaHost(ctx context.Context, redisSentinel *redisv1.RedisSentinel, ref *redisv1.MasterReplicaRef) (string, error) {
+	namespace := masterReplicaRefNamespace(redisSentinel, ref)
+
+	if namespace != redisSentinel.Namespace {
+		if err := r.checkMasterReplicaAccess(ctx, namespace); err != nil {
+			return "", err
+		}
+	}
+
+	masterReplica := &redisv1.RedisMasterReplica{}
+	if err := r.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, masterReplica); err != nil {
+		if errors.IsNotFound(err) {
+			return "", fmt.Errorf("RedisMasterReplica %s/%s not found", namespace, ref.Name)
+		}
+		if errors.IsForbidden(err) {
+			return "", fmt.Errorf("operator is not allowed to read RedisMasterReplica %s/%s: %w", namespace, ref.Name, err)
+		}
+		return "", err
+	}
+
+	if namespace != redisSentinel.Namespace && !allowsSentinelNamespace(masterReplica, redisSentinel.Namespace) {
+		return "", fmt.Errorf("RedisMasterReplica %s/%s does not allow sentinels from namespace %s (annotation %s)",
+			namespace, ref.Name, redisSentinel.Namespace, redisv1.AllowedSentinelNamespacesAnnotation)
+	}
+
+	return r.masterReplicaHost(ctx, namespace, ref.Name)
+}
+
+// masterReplicaAuthSecret 返回 RedisMasterReplica 使用的密码 Secret，未启用认证时返回 nil
+func (r *RedisSentinelReconciler) masterReplicaAuthSecret(ctx context.Context, namespace, name string) (*corev1.SecretKeySelector, error) {
+	masterReplica := &redisv1.RedisMasterReplica{}
+	if err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, masterReplica); err != nil {
+		return nil, err
+	}
+	return utils.PasswordSecretRef(masterReplica.Spec.Security, masterReplica.Name), nil
+}
+
+// masterReplicaHost 返回 RedisMasterReplica master 的稳定 DNS 名称
+// master StatefulSet 绑定了 Headless Service 时使用 Pod DNS，否则使用 master Service 的 DNS，
+// 不再使用 ClusterIP，避免 Service 重建后 Sentinel 继续监控失效的地址
+func (r *RedisSentinelReconciler) masterReplicaHost(ctx context.Context, namespace, name string) (string, error) {
+	masterSts := &appsv1.StatefulSet{}
+	err := r.Get(ctx, types.NamespacedName{Name: name + "-master", Namespace: namespace}, masterSts)
+	if err != nil && !errors.IsNotFound(err) {
+		return "", err
+	}
+	if err == nil && masterSts.Spec.ServiceName != "" {
+		return fmt.Sprintf("%s-0.%s.%s.svc.cluster.local", masterSts.Name, masterSts.Spec.ServiceName, namespace), nil
+	}
+
+	return fmt.Sprintf("%s-master-service.%s.svc.cluster.local", name, namespace), nil
+}
+
+// checkMasterReplicaAccess 检查 operator 是否有权限读取目标命名空间中的 RedisMasterReplica
+func (r *RedisSentinelReconciler) checkMasterReplicaAccess(ctx context.Context, namespace string) error {
+	review := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      "get",
+				Group:     redisv1.GroupVersion.Group,
+				Resource:  "redismasterreplicas",
+			},
+		},
+	}
+	if err := r.Create(ctx, review); err != nil {
+		return fmt.Errorf("failed to review access to namespace %s: %w", namespace, err)
+	}
+	if !review.Status.Allowed {
+		return fmt.Errorf("operator is not allowed to get redismasterreplicas in namespace %s: %s", namespace, review.Status.Reason)
+	}
+	return nil
+}
+
+// allowsSentinelNamespace 检查 RedisMasterReplica 是否允许指定命名空间的 Sentinel 监控它
+func allowsSentinelNamespace(masterReplica *redisv1.RedisMasterReplica, namespace string) bool {
+	allowed, ok := masterReplica.Annotations[redisv1.AllowedSentinelNamespacesAnnotation]
+	if !ok {
+		return false
+	}
+	for _, ns := range strings.Split(allowed, ",") {
+		ns = strings.TrimSpace(ns)
+		if ns == "*" || ns == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// monitoredMasterStatuses 根据解析结果和 Sentinel 的运行状态生成每个 master 的状态
+func (r *RedisSentinelReconciler) monitoredMasterStatuses(ctx context.Context, redisSentinel *redisv1.RedisSentinel, masters []resolvedMaster) []redisv1.MonitoredMasterStatus {
+	statuses := make([]redisv1.MonitoredMasterStatus, 0, len(masters))
+
+	var sentinelClient *redis.SentinelClient
+	if len(masters) > 0 {
+		tlsConfig, err := utils.ClientTLSConfig(ctx, r.Client, redisSentinel.Namespace, redisSentinel.Spec.Security.TLS)
+		if err != nil {
+			logf.FromContext(ctx).Error(err, "Failed to load TLS certificate for sentinel client")
+		}
+		sentinelClient = newSentinelClient(&redis.Options{
+			Addr:      fmt.Sprintf("%s-sentinel-service.%s.svc.cluster.local:26379", redisSentinel.Name, redisSentinel.Namespace),
+			TLSConfig: tlsConfig,
+		})
+		defer sentinelClient.Close()
+	}
+
+	for _, master := range masters {
+		status := redisv1.MonitoredMasterStatus{
+			Name:   master.Name,
+			Source: master.Source,
+			IP:     master.Host,
+			Port:   master.Port,
+			Status: "unknown",
+		}
+
+		if master.Err != nil {
+			status.Status = "error"
+			status.Message = master.Err.Error()
+			statuses = append(statuses, status)
+			continue
+		}
+
+		queryCtx, cancel := context.WithTimeout(ctx, sentinelQueryTimeout)
+		info, err := sentinelClient.Master(queryCtx, master.Name).Result()
+		cancel()
+		if err != nil {
+			status.Message = fmt.Sprintf("Failed to query sentinels: %v", err)
+			statuses = append(statuses, status)
+			continue
+		}
+
+		if ip := info["ip"]; ip != "" {
+			status.IP = ip
+		}
+		if port, err := strconv.ParseInt(info["port"], 10, 32); err == nil {
+			status.Port = int32(port)
+		}
+		if replicas, err := strconv.ParseInt(info["num-slaves"], 10, 32); err == nil {
+			status.KnownReplicas = int32(replicas)
+		}
+		if others, err := strconv.ParseInt(info["num-other-sentinels"], 10, 32); err == nil {
+			status.KnownSentinels = int32(others) + 1
+		}
+		if flags := info["flags"]; strings.Contains(flags, "down") {
+			status.Status = "down"
+		} else {
+			status.Status = "up"
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses
+}
+
+// sentinelsForMasterReplica 查找引用了指定 RedisMasterReplica 的所有 RedisSentinel
+func (r *RedisSentinelReconciler) sentinelsForMasterReplica(ctx context.Context, obj client.Object) []reconcile.Request {
+	sentinelList := &redisv1.RedisSentinelList{}
+	if err := r.List(ctx, sentinelList); err != nil {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range sentinelList.Items {
+		redisSentinel := &sentinelList.Items[i]
+		for _, spec := range monitoredMasters(redisSentinel) {
+			ref := spec.MasterReplicaRef
+			if ref == nil || ref.Name != obj.GetName() || masterReplicaRefNamespace(redisSentinel, ref) != obj.GetNamespace() {
+				continue
+			}
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      redisSentinel.Name,
+					Namespace: redisSentinel.Namespace,
+				},
+			})
+			break
+		}
+	}
+
+	return requests
+}
+
+// sentinelsForSecret 返回引用了该 Secret（密码、TLS 证书或 master authSecret）的 RedisSentinel
+func (r *RedisSentinelReconciler) sentinelsForSecret(ctx context.Context, obj client.Object) []reconcile.Request {
+	sentinels := &redisv1.RedisSentinelList{}
+	if err := r.List(ctx, sentinels, client.InNamespace(obj.GetNamespace())); err != nil {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, sentinel := range sentinels.Items {
+		referenced := utils.ReferencesSecret(sentinel.Spec.Security, sentinel.Name, obj.GetName())
+		for _, master := range sentinel.Spec.Masters {
+			if master.AuthSecret != nil && master.AuthSecret.Name == obj.GetName() {
+				referenced = true
+			}
+		}
+		if referenced {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: sentinel.Name, Namespace: sentinel.Namespace},
+			})
+		}
+	}
+	return requests
+}
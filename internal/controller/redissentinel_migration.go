@@ -0,0 +1,407 @@
+/*
+Copyright 2025 James.Liu.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/redis/go-redis/v9"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	redisv1 "github.com/ybooks240/redis-operator/api/v1"
+	"github.com/ybooks240/redis-operator/internal/tracing"
+	"github.com/ybooks240/redis-operator/internal/utils"
+)
+
+// 旧版本嵌入式 Redis 使用独立的 master/replica StatefulSet，当前版本只支持单个 -redis StatefulSet。
+// 迁移流程：
+//  1. 新 StatefulSet 的 Pod 通过 Sentinel 找到旧 master 并作为 replica 完成全量同步
+//  2. 将其他 replica 的 replica-priority 设为 0，通过 SENTINEL FAILOVER 提升 -redis-0
+//  3. Sentinel 确认 -redis-0 为 master 后删除旧的 StatefulSet 和 Service（PVC 保留）
+//  4. 逐个对 Sentinel 执行 SENTINEL RESET，清除已删除的旧 replica，上一个 Sentinel 重新发现其他 Sentinel 后再重置下一个
+
+// hasLegacyEmbeddedRedis 检查是否存在旧版本的 master/replica StatefulSet
+func (r *RedisSentinelReconciler) hasLegacyEmbeddedRedis(ctx context.Context, redisSentinel *redisv1.RedisSentinel) (bool, error) {
+	legacyMaster := &appsv1.StatefulSet{}
+	err := r.Get(ctx, types.NamespacedName{Name: redisSentinel.Name + "-redis-master", Namespace: redisSentinel.Namespace}, legacyMaster)
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// embeddedBootstrapMaster 返回 Sentinel 不可用时嵌入式 Redis 复制的 master 地址
+// 迁移期间指向旧的 master Service，否则指向 -redis-0 Pod
+func embeddedBootstrapMaster(redisSentinel *redisv1.RedisSentinel, legacy bool) string {
+	if legacy {
+		return fmt.Sprintf("%s-redis-master-service.%s.svc.cluster.local", redisSentinel.Name, redisSentinel.Namespace)
+	}
+	return fmt.Sprintf("%s-redis-0.%s-redis-headless.%s.svc.cluster.local",
+		redisSentinel.Name, redisSentinel.Name, redisSentinel.Namespace)
+}
+
+// migrateLegacyEmbeddedRedis 将旧版本的 master/replica StatefulSet 迁移到单个 StatefulSet，每次协调推进一步
+func (r *RedisSentinelReconciler) migrateLegacyEmbeddedRedis(ctx context.Context, redisSentinel *redisv1.RedisSentinel, logs logr.Logger) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "migrate embedded redis")
+	defer func() { tracing.EndSpan(span, err) }()
+
+	masterName := redisSentinel.Spec.Redis.MasterName
+	if masterName == "" {
+		masterName = defaultSentinelMasterName
+	}
+
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(redisSentinel.Namespace), client.MatchingLabels{
+		"app":      "redis",
+		"instance": redisSentinel.Name,
+	}); err != nil {
+		return fmt.Errorf("failed to list redis pods: %w", err)
+	}
+
+	var unifiedPods, legacyPods []corev1.Pod
+	for _, pod := range pods.Items {
+		if isEmbeddedRedisPod(redisSentinel, pod.Name) {
+			unifiedPods = append(unifiedPods, pod)
+		} else {
+			legacyPods = append(legacyPods, pod)
+		}
+	}
+
+	password, err := utils.ReadPassword(ctx, r.Client, redisSentinel.Namespace, redisSentinel.Spec.Security, redisSentinel.Name)
+	if err != nil {
+		return fmt.Errorf("failed to read redis password: %w", err)
+	}
+	tlsConfig, err := utils.ClientTLSConfig(ctx, r.Client, redisSentinel.Namespace, redisSentinel.Spec.Security.TLS)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+	redisOptions := func(pod corev1.Pod) *redis.Options {
+		return &redis.Options{Addr: fmt.Sprintf("%s:6379", pod.Status.PodIP), Password: password, TLSConfig: tlsConfig}
+	}
+
+	// 步骤 1：等待新 StatefulSet 的所有 Pod 就绪并完成同步
+	desiredReplicas := int(1 + redisSentinel.Spec.Redis.Replica.Replicas)
+	readyPods := 0
+	for i := range unifiedPods {
+		if isPodReady(&unifiedPods[i]) {
+			readyPods++
+		}
+	}
+	if readyPods < desiredReplicas {
+		return r.setMigrationStatus(ctx, redisSentinel, redisv1.EmbeddedMigrationSyncing,
+			fmt.Sprintf("Waiting for new Redis pods to be ready: %d/%d", readyPods, desiredReplicas))
+	}
+	for _, pod := range unifiedPods {
+		linked, err := replicaLinkUp(ctx, redisOptions(pod))
+		if err != nil {
+			return r.setMigrationStatus(ctx, redisSentinel, redisv1.EmbeddedMigrationSyncing,
+				fmt.Sprintf("Failed to query replication of pod %s: %v", pod.Name, err))
+		}
+		if !linked {
+			return r.setMigrationStatus(ctx, redisSentinel, redisv1.EmbeddedMigrationSyncing,
+				fmt.Sprintf("Waiting for pod %s to finish replication", pod.Name))
+		}
+	}
+
+	// 步骤 2：通过 Sentinel 将 -redis-0 提升为 master
+	sentinelClient := newSentinelClient(&redis.Options{
+		Addr:      fmt.Sprintf("%s-sentinel-service.%s.svc.cluster.local:26379", redisSentinel.Name, redisSentinel.Namespace),
+		TLSConfig: tlsConfig,
+	})
+	defer sentinelClient.Close()
+
+	queryCtx, cancel := context.WithTimeout(ctx, sentinelQueryTimeout)
+	defer cancel()
+
+	masterAddr, err := sentinelClient.GetMasterAddrByName(queryCtx, masterName).Result()
+	if err != nil || len(masterAddr) == 0 {
+		return r.setMigrationStatus(ctx, redisSentinel, redisv1.EmbeddedMigrationSyncing,
+			fmt.Sprintf("Failed to query current master from sentinels: %v", err))
+	}
+
+	firstPodName := redisSentinel.Name + "-redis-0"
+	firstPodIP := ""
+	for _, pod := range unifiedPods {
+		if pod.Name == firstPodName {
+			firstPodIP = pod.Status.PodIP
+		}
+	}
+
+	if masterAddr[0] != firstPodIP && !strings.HasPrefix(masterAddr[0], firstPodName+".") {
+		info, err := sentinelClient.Master(queryCtx, masterName).Result()
+		if err == nil && strings.Contains(info["flags"], "failover_in_progress") {
+			return r.setMigrationStatus(ctx, redisSentinel, redisv1.EmbeddedMigrationFailingOver,
+				"Waiting for sentinels to finish failover")
+		}
+
+		// 只允许 -redis-0 被选为新 master
+		for _, pod := range append(unifiedPods, legacyPods...) {
+			priority := "0"
+			if pod.Name == firstPodName {
+				priority = "1"
+			}
+			if err := setReplicaPriority(ctx, redisOptions(pod), priority); err != nil {
+				logs.Error(err, "Failed to set replica priority", "pod", pod.Name)
+			}
+		}
+
+		logs.Info("Promoting first pod of the unified Redis StatefulSet", "master", masterName, "pod", firstPodName)
+		if err := sentinelClient.Failover(queryCtx, masterName).Err(); err != nil {
+			return r.setMigrationStatus(ctx, redisSentinel, redisv1.EmbeddedMigrationFailingOver,
+				fmt.Sprintf("SENTINEL FAILOVER failed: %v", err))
+		}
+		r.Recorder.Eventf(redisSentinel, corev1.EventTypeNormal, eventReasonFailover,
+			"Requested failover of master %s to %s to migrate the embedded Redis", masterName, firstPodName)
+		return r.setMigrationStatus(ctx, redisSentinel, redisv1.EmbeddedMigrationFailingOver,
+			fmt.Sprintf("Failover to %s requested", firstPodName))
+	}
+
+	// 步骤 3：恢复优先级并删除旧资源
+	for _, pod := range unifiedPods {
+		if err := setReplicaPriority(ctx, redisOptions(pod), "100"); err != nil {
+			logs.Error(err, "Failed to restore replica priority", "pod", pod.Name)
+		}
+	}
+
+	legacyResources := []client.Object{
+		&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: redisSentinel.Name + "-redis-master", Namespace: redisSentinel.Namespace}},
+		&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: redisSentinel.Name + "-redis-replica", Namespace: redisSentinel.Namespace}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: redisSentinel.Name + "-redis-master-service", Namespace: redisSentinel.Namespace}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: redisSentinel.Name + "-redis-replica-service", Namespace: redisSentinel.Namespace}},
+	}
+	for _, resource := range legacyResources {
+		if err := r.Get(ctx, types.NamespacedName{Name: resource.GetName(), Namespace: resource.GetNamespace()}, resource); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		controllerutil.RemoveFinalizer(resource, redisv1.RedisSentinelFinalizer)
+		if err := r.Update(ctx, resource); err != nil {
+			return err
+		}
+		if err := r.Delete(ctx, resource); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		logs.Info("Deleted legacy embedded Redis resource", "name", resource.GetName(), "type", fmt.Sprintf("%T", resource))
+	}
+
+	// 步骤 4：旧资源删除后 hasLegacyEmbeddedRedis 不再成立，后续协调根据迁移阶段继续重置 Sentinel
+	if err := r.setMigrationStatus(ctx, redisSentinel, redisv1.EmbeddedMigrationResettingSentinels,
+		"Legacy resources removed, resetting sentinels one at a time"); err != nil {
+		return err
+	}
+	return r.resetSentinelsAfterMigration(ctx, redisSentinel, logs)
+}
+
+// resetSentinelsAfterMigration 对仍记录旧 replica 的 Sentinel 执行 SENTINEL RESET，每次协调最多重置一个
+// 重置后的 Sentinel 需要通过 hello 消息重新发现其他 Sentinel，全部 Sentinel 互相可见后才重置下一个，
+// 避免同时重置多个 Sentinel 导致无法达成故障转移所需的多数派
+func (r *RedisSentinelReconciler) resetSentinelsAfterMigration(ctx context.Context, redisSentinel *redisv1.RedisSentinel, logs logr.Logger) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "reset sentinels")
+	defer func() { tracing.EndSpan(span, err) }()
+
+	masterName := redisSentinel.Spec.Redis.MasterName
+	if masterName == "" {
+		masterName = defaultSentinelMasterName
+	}
+
+	sentinelPods := &corev1.PodList{}
+	if err := r.List(ctx, sentinelPods, client.InNamespace(redisSentinel.Namespace), client.MatchingLabels{
+		"app":       "redis-sentinel",
+		"component": "sentinel",
+		"instance":  redisSentinel.Name,
+	}); err != nil {
+		return fmt.Errorf("failed to list sentinel pods: %w", err)
+	}
+	sort.Slice(sentinelPods.Items, func(i, j int) bool { return sentinelPods.Items[i].Name < sentinelPods.Items[j].Name })
+
+	redisPods, err := listWorkloadRedisPods(ctx, r.Client, "RedisSentinel", redisSentinel.Namespace, redisSentinel.Name)
+	if err != nil {
+		return err
+	}
+	// isCurrentReplica 判断 Sentinel 记录的 replica 地址是否属于单个 -redis StatefulSet，节点使用 Pod DNS 名称公告
+	isCurrentReplica := func(addr string) bool {
+		for _, pod := range redisPods {
+			if addr == pod.Status.PodIP || strings.HasPrefix(addr, pod.Name+".") {
+				return true
+			}
+		}
+		return false
+	}
+
+	tlsConfig, err := utils.ClientTLSConfig(ctx, r.Client, redisSentinel.Namespace, redisSentinel.Spec.Security.TLS)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+
+	otherSentinels := strconv.Itoa(len(sentinelPods.Items) - 1)
+	for i := range sentinelPods.Items {
+		pod := &sentinelPods.Items[i]
+		if pod.Status.PodIP == "" || !isPodReady(pod) {
+			return r.setMigrationStatus(ctx, redisSentinel, redisv1.EmbeddedMigrationResettingSentinels,
+				fmt.Sprintf("Waiting for sentinel %s to be ready", pod.Name))
+		}
+
+		reset, err := resetStaleSentinel(ctx, &redis.Options{Addr: fmt.Sprintf("%s:26379", pod.Status.PodIP), TLSConfig: tlsConfig},
+			masterName, otherSentinels, isCurrentReplica)
+		if err != nil {
+			return r.setMigrationStatus(ctx, redisSentinel, redisv1.EmbeddedMigrationResettingSentinels,
+				fmt.Sprintf("Failed to reset sentinel %s: %v", pod.Name, err))
+		}
+		switch reset {
+		case sentinelWaitingForPeers:
+			return r.setMigrationStatus(ctx, redisSentinel, redisv1.EmbeddedMigrationResettingSentinels,
+				fmt.Sprintf("Waiting for sentinel %s to rediscover the other sentinels", pod.Name))
+		case sentinelReset:
+			logs.Info("Reset sentinel to forget legacy replicas", "sentinel", pod.Name, "master", masterName)
+			return r.setMigrationStatus(ctx, redisSentinel, redisv1.EmbeddedMigrationResettingSentinels,
+				fmt.Sprintf("Reset sentinel %s", pod.Name))
+		}
+	}
+
+	return r.setMigrationStatus(ctx, redisSentinel, redisv1.EmbeddedMigrationCompleted,
+		"Migrated to the single Redis StatefulSet, legacy PVCs are retained")
+}
+
+// sentinelResetResult resetStaleSentinel 对单个 Sentinel 的处理结果
+type sentinelResetResult int
+
+const (
+	// sentinelUpToDate Sentinel 只记录当前的 replica，无需重置
+	sentinelUpToDate sentinelResetResult = iota
+	// sentinelWaitingForPeers Sentinel 尚未发现全部其他 Sentinel，需要等待后再继续
+	sentinelWaitingForPeers
+	// sentinelReset 已对 Sentinel 执行 SENTINEL RESET
+	sentinelReset
+)
+
+// resetStaleSentinel 在 Sentinel 记录了不属于当前 StatefulSet 的 replica 时执行 SENTINEL RESET
+func resetStaleSentinel(ctx context.Context, options *redis.Options, masterName, otherSentinels string, isCurrentReplica func(string) bool) (sentinelResetResult, error) {
+	sentinelClient := newSentinelClient(options)
+	defer sentinelClient.Close()
+
+	queryCtx, cancel := context.WithTimeout(ctx, sentinelQueryTimeout)
+	defer cancel()
+
+	master, err := sentinelClient.Master(queryCtx, masterName).Result()
+	if err != nil {
+		return sentinelUpToDate, err
+	}
+	if master["num-other-sentinels"] != otherSentinels {
+		return sentinelWaitingForPeers, nil
+	}
+
+	replicas, err := sentinelClient.Replicas(queryCtx, masterName).Result()
+	if err != nil {
+		return sentinelUpToDate, err
+	}
+	stale := false
+	for _, replica := range replicas {
+		if !isCurrentReplica(replica["ip"]) {
+			stale = true
+			break
+		}
+	}
+	if !stale {
+		return sentinelUpToDate, nil
+	}
+
+	if err := sentinelClient.Reset(queryCtx, masterName).Err(); err != nil {
+		return sentinelUpToDate, fmt.Errorf("SENTINEL RESET %s: %w", masterName, err)
+	}
+	return sentinelReset, nil
+}
+
+// isEmbeddedRedisPod 检查 Pod 是否属于单个 -redis StatefulSet（名称为 <name>-redis-<ordinal>）
+func isEmbeddedRedisPod(redisSentinel *redisv1.RedisSentinel, podName string) bool {
+	ordinal, found := strings.CutPrefix(podName, redisSentinel.Name+"-redis-")
+	if !found {
+		return false
+	}
+	_, err := strconv.Atoi(ordinal)
+	return err == nil
+}
+
+// replicaLinkUp 检查节点是 master 或已与 master 建立同步连接
+func replicaLinkUp(ctx context.Context, options *redis.Options) (bool, error) {
+	redisClient := newRedisClient(options)
+	defer redisClient.Close()
+
+	queryCtx, cancel := context.WithTimeout(ctx, sentinelQueryTimeout)
+	defer cancel()
+
+	info, err := redisClient.Info(queryCtx, "replication").Result()
+	if err != nil {
+		return false, err
+	}
+	if strings.Contains(info, "role:master") {
+		return true, nil
+	}
+	return strings.Contains(info, "master_link_status:up") && !strings.Contains(info, "master_sync_in_progress:1"), nil
+}
+
+// setReplicaPriority 设置节点的 replica-priority，0 表示不参与故障转移选举
+func setReplicaPriority(ctx context.Context, options *redis.Options, priority string) error {
+	redisClient := newRedisClient(options)
+	defer redisClient.Close()
+
+	queryCtx, cancel := context.WithTimeout(ctx, sentinelQueryTimeout)
+	defer cancel()
+
+	return redisClient.ConfigSet(queryCtx, "replica-priority", priority).Err()
+}
+
+// setMigrationStatus 记录迁移进度，阶段变化时更新 LastTransitionTime
+func (r *RedisSentinelReconciler) setMigrationStatus(ctx context.Context, redisSentinel *redisv1.RedisSentinel, phase redisv1.EmbeddedMigrationPhase, message string) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		// 重新获取最新的资源版本以避免冲突
+		latestSentinel := &redisv1.RedisSentinel{}
+		if err := r.Get(ctx, types.NamespacedName{Name: redisSentinel.Name, Namespace: redisSentinel.Namespace}, latestSentinel); err != nil {
+			return err
+		}
+
+		migration := latestSentinel.Status.Migration
+		if migration == nil {
+			migration = &redisv1.EmbeddedMigrationStatus{}
+		}
+		if migration.Phase == phase && migration.Message == message {
+			return nil
+		}
+		if migration.Phase != phase {
+			now := metav1.Now()
+			migration.LastTransitionTime = &now
+		}
+		migration.Phase = phase
+		migration.Message = message
+		latestSentinel.Status.Migration = migration
+
+		return r.Status().Update(ctx, latestSentinel)
+	})
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2025 James.Liu.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/redis/go-redis/v9"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	redisv1 "github.com/ybooks240/redis-operator/api/v1"
+	"github.com/ybooks240/redis-operator/internal/utils"
+)
+
+// repairSentinelMonitors 检查每个 Sentinel 实际监控的 master 地址
+// 未监控或地址已失效时通过 SENTINEL REMOVE + SENTINEL MONITOR 在线修复，无需重启 Sentinel
+func (r *RedisSentinelReconciler) repairSentinelMonitors(ctx context.Context, redisSentinel *redisv1.RedisSentinel, masters []resolvedMaster, logs logr.Logger) error {
+	sentinelPods := &corev1.PodList{}
+	if err := r.List(ctx, sentinelPods, client.InNamespace(redisSentinel.Namespace), client.MatchingLabels{
+		"app":       "redis-sentinel",
+		"component": "sentinel",
+		"instance":  redisSentinel.Name,
+	}); err != nil {
+		return fmt.Errorf("failed to list sentinel pods: %w", err)
+	}
+
+	tlsConfig, err := utils.ClientTLSConfig(ctx, r.Client, redisSentinel.Namespace, redisSentinel.Spec.Security.TLS)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+
+	for _, master := range masters {
+		if master.Err != nil {
+			continue
+		}
+
+		isKnownAddress, err := r.masterAddressMatcher(ctx, master)
+		if err != nil {
+			logs.Error(err, "Failed to list pods of monitored master", "master", master.Name)
+			continue
+		}
+
+		password := ""
+		if master.AuthSecret != nil {
+			password, err = utils.ReadSecretValue(ctx, r.Client, redisSentinel.Namespace, master.AuthSecret)
+			if err != nil {
+				logs.Error(err, "Failed to read master auth secret", "master", master.Name)
+				continue
+			}
+		}
+
+		for i := range sentinelPods.Items {
+			pod := &sentinelPods.Items[i]
+			if pod.Status.PodIP == "" || !isPodReady(pod) {
+				continue
+			}
+			addr := fmt.Sprintf("%s:26379", pod.Status.PodIP)
+			if err := r.repairSentinelMonitor(ctx, redisSentinel, &redis.Options{Addr: addr, TLSConfig: tlsConfig}, master, isKnownAddress, password, logs); err != nil {
+				logs.Error(err, "Failed to repair sentinel monitor", "sentinel", pod.Name, "master", master.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// repairSentinelMonitor 修复单个 Sentinel 对指定 master 的监控
+func (r *RedisSentinelReconciler) repairSentinelMonitor(ctx context.Context, redisSentinel *redisv1.RedisSentinel, clientOptions *redis.Options, master resolvedMaster, isKnownAddress func(string) bool, password string, logs logr.Logger) error {
+	addr := clientOptions.Addr
+	sentinelClient := newSentinelClient(clientOptions)
+	defer sentinelClient.Close()
+
+	queryCtx, cancel := context.WithTimeout(ctx, sentinelQueryTimeout)
+	defer cancel()
+
+	info, err := sentinelClient.Master(queryCtx, master.Name).Result()
+	switch {
+	case err != nil && !strings.Contains(err.Error(), "No such master"):
+		return err
+	case err == nil:
+		// 外部 master 无法判断故障转移后的地址是否合法，只修复缺失的监控
+		if isKnownAddress == nil || isKnownAddress(info["ip"]) {
+			return nil
+		}
+		logs.Info("Sentinel monitors a stale master address, re-registering",
+			"sentinel", addr, "master", master.Name, "current", info["ip"], "desired", master.Host)
+		if err := sentinelClient.Remove(queryCtx, master.Name).Err(); err != nil {
+			return fmt.Errorf("SENTINEL REMOVE %s: %w", master.Name, err)
+		}
+	default:
+		logs.Info("Sentinel does not monitor master, registering", "sentinel", addr, "master", master.Name, "host", master.Host)
+	}
+
+	if err := sentinelClient.Monitor(queryCtx, master.Name, master.Host,
+		fmt.Sprintf("%d", master.Port), fmt.Sprintf("%d", master.Quorum)).Err(); err != nil {
+		return fmt.Errorf("SENTINEL MONITOR %s: %w", master.Name, err)
+	}
+
+	downAfterMilliseconds, failoverTimeout, parallelSyncs := sentinelTimings(redisSentinel)
+	options := [][2]string{
+		{"down-after-milliseconds", fmt.Sprintf("%d", downAfterMilliseconds)},
+		{"failover-timeout", fmt.Sprintf("%d", failoverTimeout)},
+		{"parallel-syncs", fmt.Sprintf("%d", parallelSyncs)},
+	}
+	if password != "" {
+		options = append(options, [2]string{"auth-pass", password})
+	}
+	for _, option := range options {
+		if err := sentinelClient.Set(queryCtx, master.Name, option[0], option[1]).Err(); err != nil {
+			return fmt.Errorf("SENTINEL SET %s %s: %w", master.Name, option[0], err)
+		}
+	}
+
+	return nil
+}
+
+// masterAddressMatcher 返回判断地址是否属于 master 所在工作负载的函数
+// 故障转移后 Sentinel 监控的地址可能是任意一个 Pod 的 IP 或 DNS 名称，这些地址都视为有效
+func (r *RedisSentinelReconciler) masterAddressMatcher(ctx context.Context, master resolvedMaster) (func(string) bool, error) {
+	if len(master.PodLabels) == 0 {
+		return nil, nil
+	}
+
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(master.Namespace), client.MatchingLabels(master.PodLabels)); err != nil {
+		return nil, err
+	}
+
+	return func(address string) bool {
+		if address == master.Host {
+			return true
+		}
+		for _, pod := range pods.Items {
+			if address == pod.Status.PodIP || strings.HasPrefix(address, pod.Name+".") {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+// isPodReady 检查 Pod 是否处于 Ready 状态
+func isPodReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,542 @@
+/*
+Copyright 2025 James.Liu.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/redis/go-redis/v9"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	redisv1 "github.com/ybooks240/redis-operator/api/v1"
+	"github.com/ybooks240/redis-operator/internal/utils"
+)
+
+const (
+	// redisUserSyncedCondition 用户 ACL 在所有节点上与声明一致
+	redisUserSyncedCondition = "Synced"
+
+	// aclCommandTimeout 单个节点执行 ACL 命令的超时时间
+	aclCommandTimeout = 5 * time.Second
+
+	// defaultACLUser Redis 内置用户，由目标实例的 spec.security 管理
+	defaultACLUser = "default"
+)
+
+// RedisUserReconciler reconciles a RedisUser object
+type RedisUserReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=redis.github.com,resources=redisusers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=redis.github.com,resources=redisusers/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=redis.github.com,resources=redisusers/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
+
+// Reconcile 将 RedisUser 声明的 ACL 应用到目标工作负载的每个节点，并记录各节点的偏差
+func (r *RedisUserReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logs := logf.FromContext(ctx)
+
+	redisUser := &redisv1.RedisUser{}
+	if err := r.Get(ctx, req.NamespacedName, redisUser); err != nil {
+		if errors.IsNotFound(err) {
+			logs.Info("RedisUser not found, ignoring", "name", req.Name)
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// 检查是否正在删除
+	if redisUser.DeletionTimestamp != nil {
+		logs.Info("RedisUser is being deleted, removing ACL user", "name", redisUser.Name)
+		r.deleteACLUser(ctx, redisUser, logs)
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			latestRedisUser := &redisv1.RedisUser{}
+			if err := r.Get(ctx, req.NamespacedName, latestRedisUser); err != nil {
+				return err
+			}
+			controllerutil.RemoveFinalizer(latestRedisUser, redisv1.RedisUserFinalizer)
+			return r.Update(ctx, latestRedisUser)
+		})
+		if err != nil {
+			logs.Error(err, "Failed to remove finalizer")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if !controllerutil.ContainsFinalizer(redisUser, redisv1.RedisUserFinalizer) {
+		controllerutil.AddFinalizer(redisUser, redisv1.RedisUserFinalizer)
+		if err := r.Update(ctx, redisUser); err != nil {
+			logs.Error(err, "Failed to update RedisUser finalizer")
+			return ctrl.Result{}, err
+		}
+	}
+
+	nodes, syncErr := r.syncACLUser(ctx, redisUser, logs)
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		return r.doUpdateRedisUserStatus(ctx, redisUser, nodes, syncErr)
+	})
+	if err != nil {
+		logs.Error(err, "Failed to update RedisUser status")
+		return ctrl.Result{}, err
+	}
+
+	// 定期重新同步，覆盖 Pod 重启后丢失的 ACL 以及手工修改产生的偏差
+	return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+}
+
+// syncACLUser 检查每个就绪节点上的 ACL，存在偏差时使用 ACL SETUSER 重新应用
+func (r *RedisUserReconciler) syncACLUser(ctx context.Context, redisUser *redisv1.RedisUser, logs logr.Logger) ([]redisv1.RedisUserNodeStatus, error) {
+	username := aclUsername(redisUser)
+	if username == defaultACLUser {
+		return nil, specError(fmt.Errorf("the %q user is managed by spec.security of the target, set spec.username to manage another user", username))
+	}
+
+	target, err := resolveRedisWorkload(ctx, r.Client, redisUser.Spec.TargetRef.Kind, redisUser.Namespace, redisUser.Spec.TargetRef.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	password, err := utils.ReadSecretValue(ctx, r.Client, redisUser.Namespace, &redisUser.Spec.PasswordSecret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read user password: %w", err)
+	}
+	rules := aclRules(redisUser, password)
+
+	var nodes []redisv1.RedisUserNodeStatus
+	for i := range target.Pods {
+		pod := &target.Pods[i]
+		if !isPodReady(pod) || pod.Status.PodIP == "" {
+			continue
+		}
+
+		node := redisv1.RedisUserNodeStatus{Pod: pod.Name}
+		redisClient := target.client(pod)
+		func() {
+			defer redisClient.Close()
+			nodeCtx, cancel := context.WithTimeout(ctx, aclCommandTimeout)
+			defer cancel()
+
+			live, err := redisClient.Do(nodeCtx, "ACL", "GETUSER", username).Result()
+			if err != nil && err != redis.Nil {
+				node.Message = fmt.Sprintf("failed to read ACL: %v", err)
+				return
+			}
+			node.Drift = aclDrift(redisUser, password, live)
+			if len(node.Drift) == 0 {
+				node.Synced = true
+				return
+			}
+
+			logs.Info("ACL drift detected, applying user", "pod", pod.Name, "user", username, "drift", node.Drift)
+			args := append([]interface{}{"ACL", "SETUSER", username}, rules...)
+			if err := redisClient.Do(nodeCtx, args...).Err(); err != nil {
+				node.Message = fmt.Sprintf("failed to apply ACL: %v", err)
+				return
+			}
+			node.Synced = true
+		}()
+		nodes = append(nodes, node)
+	}
+
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("no ready Redis nodes found for %s %s", redisUser.Spec.TargetRef.Kind, redisUser.Spec.TargetRef.Name)
+	}
+	return nodes, nil
+}
+
+// deleteACLUser 在目标工作负载的每个就绪节点上删除 ACL 用户，目标不存在时直接跳过
+func (r *RedisUserReconciler) deleteACLUser(ctx context.Context, redisUser *redisv1.RedisUser, logs logr.Logger) {
+	username := aclUsername(redisUser)
+	if username == defaultACLUser {
+		logs.Info("Skipping ACL DELUSER, the default user is managed by the target", "user", username)
+		return
+	}
+
+	target, err := resolveRedisWorkload(ctx, r.Client, redisUser.Spec.TargetRef.Kind, redisUser.Namespace, redisUser.Spec.TargetRef.Name)
+	if err != nil {
+		logs.Info("Skipping ACL DELUSER, target not available", "reason", err.Error())
+		return
+	}
+
+	for i := range target.Pods {
+		pod := &target.Pods[i]
+		if !isPodReady(pod) || pod.Status.PodIP == "" {
+			continue
+		}
+		redisClient := target.client(pod)
+		nodeCtx, cancel := context.WithTimeout(ctx, aclCommandTimeout)
+		if err := redisClient.Do(nodeCtx, "ACL", "DELUSER", username).Err(); err != nil {
+			logs.Error(err, "Failed to delete ACL user", "pod", pod.Name, "user", username)
+		}
+		cancel()
+		redisClient.Close()
+	}
+}
+
+// aclUsername 返回应用到 Redis 的用户名，未指定时使用 RedisUser 名称
+// 结果为 defaultACLUser 时不会执行任何 ACL 命令
+func aclUsername(redisUser *redisv1.RedisUser) string {
+	if redisUser.Spec.Username != "" {
+		return redisUser.Spec.Username
+	}
+	return redisUser.Name
+}
+
+// aclEnabled 返回用户是否启用
+func aclEnabled(redisUser *redisv1.RedisUser) bool {
+	return redisUser.Spec.Enabled == nil || *redisUser.Spec.Enabled
+}
+
+// aclRules 返回 ACL SETUSER 的规则参数，reset 保证规则完全由声明决定
+func aclRules(redisUser *redisv1.RedisUser, password string) []interface{} {
+	rules := []interface{}{"reset"}
+	if aclEnabled(redisUser) {
+		rules = append(rules, "on")
+	} else {
+		rules = append(rules, "off")
+	}
+	rules = append(rules, ">"+password)
+	for _, key := range aclKeyPatterns(redisUser.Spec.Keys) {
+		rules = append(rules, key)
+	}
+	for _, channel := range aclChannelPatterns(redisUser.Spec.Channels) {
+		rules = append(rules, channel)
+	}
+	for _, command := range aclCommandRules(redisUser.Spec.Commands) {
+		rules = append(rules, command)
+	}
+	return rules
+}
+
+// aclKeyPatterns 为键模式补充 ~ 前缀，已带 ~ 或 %R~/%W~ 前缀的模式保持不变
+func aclKeyPatterns(keys []string) []string {
+	patterns := make([]string, 0, len(keys))
+	for _, key := range keys {
+		switch {
+		case key == "allkeys":
+			patterns = append(patterns, "~*")
+		case strings.HasPrefix(key, "~") || strings.HasPrefix(key, "%"):
+			patterns = append(patterns, key)
+		default:
+			patterns = append(patterns, "~"+key)
+		}
+	}
+	return patterns
+}
+
+// aclChannelPatterns 为频道模式补充 & 前缀
+func aclChannelPatterns(channels []string) []string {
+	patterns := make([]string, 0, len(channels))
+	for _, channel := range channels {
+		switch {
+		case channel == "allchannels":
+			patterns = append(patterns, "&*")
+		case strings.HasPrefix(channel, "&"):
+			patterns = append(patterns, channel)
+		default:
+			patterns = append(patterns, "&"+channel)
+		}
+	}
+	return patterns
+}
+
+// aclCommandRules 将命令规则的别名转换为 ACL GETUSER 输出使用的形式
+func aclCommandRules(commands []string) []string {
+	rules := make([]string, 0, len(commands))
+	for _, command := range commands {
+		switch command {
+		case "allcommands":
+			rules = append(rules, "+@all")
+		case "nocommands":
+			rules = append(rules, "-@all")
+		default:
+			rules = append(rules, command)
+		}
+	}
+	return rules
+}
+
+// aclDrift 比较 ACL GETUSER 的结果与声明，返回存在偏差的项
+func aclDrift(redisUser *redisv1.RedisUser, password string, live interface{}) []string {
+	fields := aclFields(live)
+	if fields == nil {
+		return []string{"user does not exist"}
+	}
+
+	var drift []string
+	flags := aclTokens(fields["flags"])
+	if aclEnabled(redisUser) != containsString(flags, "on") {
+		drift = append(drift, fmt.Sprintf("enabled: live flags %v", flags))
+	}
+
+	passwordHash := fmt.Sprintf("%x", sha256.Sum256([]byte(password)))
+	passwords := aclTokens(fields["passwords"])
+	if len(passwords) != 1 || passwords[0] != passwordHash {
+		drift = append(drift, "password")
+	}
+
+	// Redis 6 以列表形式返回不带前缀的模式，Redis 7 返回以空格分隔的规则字符串
+	keys := aclPrefixed(aclTokens(fields["keys"]), "~", "%")
+	if !sameStrings(keys, aclKeyPatterns(redisUser.Spec.Keys)) {
+		drift = append(drift, fmt.Sprintf("keys: live %v", keys))
+	}
+	channels := aclPrefixed(aclTokens(fields["channels"]), "&")
+	if !sameStrings(channels, aclChannelPatterns(redisUser.Spec.Channels)) {
+		drift = append(drift, fmt.Sprintf("channels: live %v", channels))
+	}
+
+	// reset 之后的基础规则 -@all 不参与比较
+	var commands []string
+	for _, command := range aclTokens(fields["commands"]) {
+		if command != "-@all" {
+			commands = append(commands, command)
+		}
+	}
+	var declared []string
+	for _, command := range aclCommandRules(redisUser.Spec.Commands) {
+		if command != "-@all" {
+			declared = append(declared, command)
+		}
+	}
+	if !sameStrings(commands, declared) {
+		drift = append(drift, fmt.Sprintf("commands: live %v", commands))
+	}
+
+	return drift
+}
+
+// aclFields 将 RESP2 的键值数组或 RESP3 的 map 转换为字段表，用户不存在时返回 nil
+func aclFields(live interface{}) map[string]interface{} {
+	fields := map[string]interface{}{}
+	switch value := live.(type) {
+	case map[interface{}]interface{}:
+		for k, v := range value {
+			fields[fmt.Sprint(k)] = v
+		}
+	case []interface{}:
+		for i := 0; i+1 < len(value); i += 2 {
+			fields[fmt.Sprint(value[i])] = value[i+1]
+		}
+	default:
+		return nil
+	}
+	return fields
+}
+
+// aclTokens 将字符串或列表形式的字段值拆分为规则列表
+func aclTokens(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return strings.Fields(v)
+	case []interface{}:
+		tokens := make([]string, 0, len(v))
+		for _, item := range v {
+			tokens = append(tokens, fmt.Sprint(item))
+		}
+		return tokens
+	default:
+		return nil
+	}
+}
+
+// aclPrefixed 为缺少前缀的模式补充前缀，兼容 Redis 6 的输出
+func aclPrefixed(tokens []string, prefix string, alternatives ...string) []string {
+	result := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		prefixed := strings.HasPrefix(token, prefix)
+		for _, alternative := range alternatives {
+			prefixed = prefixed || strings.HasPrefix(token, alternative)
+		}
+		if !prefixed {
+			token = prefix + token
+		}
+		result = append(result, token)
+	}
+	return result
+}
+
+// sameStrings 比较两个列表是否包含相同的元素（忽略顺序）
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	sortedA := append([]string(nil), a...)
+	sortedB := append([]string(nil), b...)
+	sort.Strings(sortedA)
+	sort.Strings(sortedB)
+	for i := range sortedA {
+		if sortedA[i] != sortedB[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// containsString 检查列表是否包含指定元素
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// doUpdateRedisUserStatus 更新 RedisUser 的状态
+func (r *RedisUserReconciler) doUpdateRedisUserStatus(ctx context.Context, redisUser *redisv1.RedisUser, nodes []redisv1.RedisUserNodeStatus, syncErr error) error {
+	latestRedisUser := &redisv1.RedisUser{}
+	if err := r.Get(ctx, types.NamespacedName{Name: redisUser.Name, Namespace: redisUser.Namespace}, latestRedisUser); err != nil {
+		return fmt.Errorf("failed to get latest RedisUser: %w", err)
+	}
+
+	var synced, drifted, failed int32
+	var failures []string
+	for _, node := range nodes {
+		if node.Synced {
+			synced++
+		} else {
+			failed++
+			failures = append(failures, fmt.Sprintf("%s: %s", node.Pod, node.Message))
+		}
+		if len(node.Drift) > 0 {
+			drifted++
+		}
+	}
+
+	condition := metav1.Condition{
+		Type:               redisUserSyncedCondition,
+		ObservedGeneration: latestRedisUser.Generation,
+	}
+	switch {
+	case isSpecError(syncErr):
+		latestRedisUser.Status.Status = string(redisv1.RedisUserPhaseFailed)
+		latestRedisUser.Status.Ready = "False"
+		latestRedisUser.Status.LastConditionMessage = syncErr.Error()
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "InvalidSpec"
+	case syncErr != nil:
+		latestRedisUser.Status.Status = string(redisv1.RedisUserPhasePending)
+		latestRedisUser.Status.Ready = "False"
+		latestRedisUser.Status.LastConditionMessage = syncErr.Error()
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "TargetNotReady"
+	case failed > 0:
+		latestRedisUser.Status.Status = string(redisv1.RedisUserPhaseFailed)
+		latestRedisUser.Status.Ready = "False"
+		latestRedisUser.Status.LastConditionMessage = fmt.Sprintf("Failed to sync %d nodes: %s", failed, strings.Join(failures, "; "))
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "SyncFailed"
+	case drifted > 0:
+		latestRedisUser.Status.Status = string(redisv1.RedisUserPhaseDrifted)
+		latestRedisUser.Status.Ready = "True"
+		latestRedisUser.Status.LastConditionMessage = fmt.Sprintf("Corrected ACL drift on %d of %d nodes", drifted, len(nodes))
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "DriftCorrected"
+	default:
+		latestRedisUser.Status.Status = string(redisv1.RedisUserPhaseSynced)
+		latestRedisUser.Status.Ready = "True"
+		latestRedisUser.Status.LastConditionMessage = fmt.Sprintf("ACL user in sync on %d nodes", synced)
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "InSync"
+	}
+	condition.Message = latestRedisUser.Status.LastConditionMessage
+	meta.SetStatusCondition(&latestRedisUser.Status.Conditions, condition)
+
+	latestRedisUser.Status.SyncedNodes = synced
+	latestRedisUser.Status.Nodes = nodes
+
+	return r.Status().Update(ctx, latestRedisUser)
+}
+
+// redisUsersForPod 返回以该 Pod 所属工作负载为目标的 RedisUser，新节点就绪后立即应用 ACL
+func (r *RedisUserReconciler) redisUsersForPod(ctx context.Context, obj client.Object) []reconcile.Request {
+	podLabels := obj.GetLabels()
+	targetName := podLabels["instance"]
+	if targetName == "" {
+		targetName = podLabels["redis.github.com/instance"]
+	}
+	if targetName == "" {
+		return nil
+	}
+	return r.redisUsersMatching(ctx, obj.GetNamespace(), func(redisUser *redisv1.RedisUser) bool {
+		return redisUser.Spec.TargetRef.Name == targetName
+	})
+}
+
+// redisUsersForSecret 返回引用了该 Secret 作为用户密码的 RedisUser
+func (r *RedisUserReconciler) redisUsersForSecret(ctx context.Context, obj client.Object) []reconcile.Request {
+	return r.redisUsersMatching(ctx, obj.GetNamespace(), func(redisUser *redisv1.RedisUser) bool {
+		return redisUser.Spec.PasswordSecret.Name == obj.GetName()
+	})
+}
+
+// redisUsersMatching 返回命名空间中满足条件的 RedisUser
+func (r *RedisUserReconciler) redisUsersMatching(ctx context.Context, namespace string, match func(*redisv1.RedisUser) bool) []reconcile.Request {
+	redisUsers := &redisv1.RedisUserList{}
+	if err := r.List(ctx, redisUsers, client.InNamespace(namespace)); err != nil {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range redisUsers.Items {
+		if match(&redisUsers.Items[i]) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: redisUsers.Items[i].Name, Namespace: namespace},
+			})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RedisUserReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&redisv1.RedisUser{}).
+		Watches(
+			&corev1.Pod{},
+			handler.EnqueueRequestsFromMapFunc(r.redisUsersForPod),
+		).
+		Watches(
+			&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.redisUsersForSecret),
+		).
+		Named("redisuser").
+		Complete(instrumentReconciler("RedisUser", r.Client, func() client.Object { return &redisv1.RedisUser{} }, r))
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2025 James.Liu.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	redisv1 "github.com/ybooks240/redis-operator/api/v1"
+)
+
+var _ = Describe("RedisUser Controller", func() {
+	Context("When reconciling a resource", func() {
+		const resourceName = "test-redisuser"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		BeforeEach(func() {
+			By("creating the custom resource for the Kind RedisUser")
+			err := k8sClient.Get(ctx, typeNamespacedName, &redisv1.RedisUser{})
+			if err != nil && errors.IsNotFound(err) {
+				resource := &redisv1.RedisUser{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      resourceName,
+						Namespace: "default",
+					},
+					Spec: redisv1.RedisUserSpec{
+						TargetRef: redisv1.RedisWorkloadRef{Kind: "RedisInstance", Name: "missing-instance"},
+						PasswordSecret: corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "missing-secret"},
+							Key:                  "password",
+						},
+					},
+				}
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			resource := &redisv1.RedisUser{}
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Cleanup the specific resource instance RedisUser")
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			controllerReconciler := &RedisUserReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should report a pending status when the target does not exist", func() {
+			controllerReconciler := &RedisUserReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			redisUser := &redisv1.RedisUser{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, redisUser)).To(Succeed())
+			Expect(redisUser.Finalizers).To(ContainElement(redisv1.RedisUserFinalizer))
+			Expect(redisUser.Status.Status).To(Equal(string(redisv1.RedisUserPhasePending)))
+			Expect(redisUser.Status.Ready).To(Equal("False"))
+		})
+	})
+
+	Context("When the RedisUser resolves to the default user", func() {
+		ctx := context.Background()
+
+		newDefaultUser := func(name, username string) *redisv1.RedisUser {
+			return &redisv1.RedisUser{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+				Spec: redisv1.RedisUserSpec{
+					Username:  username,
+					TargetRef: redisv1.RedisWorkloadRef{Kind: "RedisInstance", Name: "missing-instance"},
+					PasswordSecret: corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "missing-secret"},
+						Key:                  "password",
+					},
+				},
+			}
+		}
+
+		It("should reject a RedisUser named default without spec.username", func() {
+			err := k8sClient.Create(ctx, newDefaultUser("default", ""))
+			Expect(errors.IsInvalid(err)).To(BeTrue(), "unexpected error: %v", err)
+		})
+
+		It("should fail without issuing ACL commands", func() {
+			controllerReconciler := &RedisUserReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			nodes, syncErr := controllerReconciler.syncACLUser(ctx, newDefaultUser("default", ""), logf.Log)
+			Expect(nodes).To(BeEmpty())
+			Expect(isSpecError(syncErr)).To(BeTrue())
+
+			redisUser := newDefaultUser("default-user-check", "app")
+			Expect(k8sClient.Create(ctx, redisUser)).To(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, redisUser)).To(Succeed())
+			}()
+			Expect(controllerReconciler.doUpdateRedisUserStatus(ctx, redisUser, nodes, syncErr)).To(Succeed())
+
+			updated := &redisv1.RedisUser{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: redisUser.Name, Namespace: "default"}, updated)).To(Succeed())
+			Expect(updated.Status.Status).To(Equal(string(redisv1.RedisUserPhaseFailed)))
+			Expect(updated.Status.Ready).To(Equal("False"))
+		})
+	})
+
+	Context("When comparing the live ACL with the declared user", func() {
+		redisUser := &redisv1.RedisUser{
+			ObjectMeta: metav1.ObjectMeta{Name: "app"},
+			Spec: redisv1.RedisUserSpec{
+				Commands: []string{"+@read", "-flushall"},
+				Keys:     []string{"app:*"},
+				Channels: []string{"events"},
+			},
+		}
+		passwordHash := fmt.Sprintf("%x", sha256.Sum256([]byte("secret")))
+
+		It("should report no drift for a matching Redis 7 user", func() {
+			live := map[interface{}]interface{}{
+				"flags":     []interface{}{"on"},
+				"passwords": []interface{}{passwordHash},
+				"commands":  "-@all +@read -flushall",
+				"keys":      "~app:*",
+				"channels":  "&events",
+			}
+			Expect(aclDrift(redisUser, "secret", live)).To(BeEmpty())
+		})
+
+		It("should report drift for a changed password and keys", func() {
+			live := []interface{}{
+				"flags", []interface{}{"on"},
+				"passwords", []interface{}{"other"},
+				"commands", "-@all +@read -flushall",
+				"keys", []interface{}{"*"},
+				"channels", []interface{}{"events"},
+			}
+			drift := aclDrift(redisUser, "secret", live)
+			Expect(drift).To(HaveLen(2))
+			Expect(drift[0]).To(Equal("password"))
+		})
+
+		It("should report a missing user", func() {
+			Expect(aclDrift(redisUser, "secret", nil)).To(Equal([]string{"user does not exist"}))
+		})
+	})
+})
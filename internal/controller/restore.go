@@ -0,0 +1,224 @@
+/*
+Copyright 2025 James.Liu.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	redisv1 "github.com/ybooks240/redis-operator/api/v1"
+)
+
+const (
+	// redisRestoredCondition 创建时从备份恢复数据的进度
+	redisRestoredCondition = "Restored"
+
+	// restoreMarkerFile 恢复完成后写入数据目录的标记文件，Pod 重启时不再重复恢复
+	restoreMarkerFile = "/data/.redis-restored"
+
+	// restoreStagingFile 下载的 RDB 文件，恢复时再替换数据文件
+	restoreStagingFile = "/data/restore.rdb"
+
+	restoreSourceDir = "/restore-source"
+)
+
+// restoreLocation 恢复使用的 RDB 文件位置，对象存储和 PVC 二选一
+type restoreLocation struct {
+	// ObjectStore 与 Key 描述对象存储中的文件，Key 包含 bucket
+	ObjectStore *redisv1.ObjectStoreSpec
+	Key         string
+
+	// ClaimName 与 Path 描述 PVC 中的文件
+	ClaimName string
+	Path      string
+}
+
+// String 返回用于状态消息的位置描述
+func (l *restoreLocation) String() string {
+	if l.ObjectStore != nil {
+		return "s3://" + l.Key
+	}
+	return fmt.Sprintf("pvc://%s/%s", l.ClaimName, l.Path)
+}
+
+// resolveRestoreSource 解析恢复来源，引用的 RedisBackup 未完成时返回错误
+func resolveRestoreSource(ctx context.Context, c client.Client, namespace string, source *redisv1.RestoreSource) (*restoreLocation, error) {
+	if source.URL != "" {
+		return &restoreLocation{ObjectStore: source.S3, Key: strings.TrimPrefix(source.URL, "s3://")}, nil
+	}
+
+	backup := &redisv1.RedisBackup{}
+	if err := c.Get(ctx, types.NamespacedName{Name: source.Backup, Namespace: namespace}, backup); err != nil {
+		return nil, fmt.Errorf("failed to get RedisBackup %s: %w", source.Backup, err)
+	}
+	if backup.Status.Status != string(redisv1.RedisBackupPhaseCompleted) {
+		return nil, fmt.Errorf("RedisBackup %s is not completed yet", source.Backup)
+	}
+	if len(backup.Status.Shards) != 1 {
+		return nil, fmt.Errorf("RedisBackup %s has %d shards, only single-shard backups can be restored", source.Backup, len(backup.Status.Shards))
+	}
+
+	return backupShardLocation(backup, backup.Status.Shards[0].Location)
+}
+
+// backupShardLocation 解析备份状态中记录的分片文件位置
+func backupShardLocation(backup *redisv1.RedisBackup, location string) (*restoreLocation, error) {
+	switch {
+	case strings.HasPrefix(location, "s3://") && backup.Spec.Destination.S3 != nil:
+		return &restoreLocation{ObjectStore: &backup.Spec.Destination.S3.ObjectStoreSpec, Key: strings.TrimPrefix(location, "s3://")}, nil
+	case strings.HasPrefix(location, "pvc://"):
+		claimName, file, _ := strings.Cut(strings.TrimPrefix(location, "pvc://"), "/")
+		return &restoreLocation{ClaimName: claimName, Path: file}, nil
+	}
+	return nil, fmt.Errorf("RedisBackup %s has an unsupported location %q", backup.Name, location)
+}
+
+// restoreCompleted 判断数据是否已经恢复
+func restoreCompleted(conditions []metav1.Condition) bool {
+	return meta.IsStatusConditionTrue(conditions, redisRestoredCondition)
+}
+
+// applyRestore 在 redis-server 启动前添加恢复数据的初始化容器
+// restore-fetch 下载 RDB 文件，restore 删除旧的 AOF 并替换数据文件；
+// 开启 appendonly 时先用临时实例加载 RDB 并重写 AOF，避免启动时空 AOF 覆盖恢复的数据
+func applyRestore(template *corev1.PodTemplateSpec, location *restoreLocation, image, dataVolume, dbfilename string, appendonly bool) {
+	dataMount := corev1.VolumeMount{Name: dataVolume, MountPath: "/data"}
+	skipIfRestored := fmt.Sprintf(`[ -f %s ] || exec "$@"`, restoreMarkerFile)
+
+	var fetch corev1.Container
+	if location.ObjectStore != nil {
+		fetch = mcContainer("restore-fetch", location.ObjectStore, "cp", "backup/"+location.Key, restoreStagingFile)
+	} else {
+		fetch = corev1.Container{
+			Name:    "restore-fetch",
+			Image:   image,
+			Command: []string{"cp", path.Join(restoreSourceDir, location.Path), restoreStagingFile},
+		}
+		fetch.VolumeMounts = append(fetch.VolumeMounts, corev1.VolumeMount{Name: "restore-source", MountPath: restoreSourceDir, ReadOnly: true})
+		template.Spec.Volumes = append(template.Spec.Volumes, corev1.Volume{
+			Name: "restore-source",
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: location.ClaimName, ReadOnly: true},
+			},
+		})
+	}
+	fetch.Command = append([]string{"sh", "-c", skipIfRestored, "sh"}, fetch.Command...)
+	fetch.VolumeMounts = append(fetch.VolumeMounts, dataMount)
+	fetch.TerminationMessagePolicy = corev1.TerminationMessageFallbackToLogsOnError
+
+	restore := restoreDataContainer(image, dataMount, dbfilename, appendonly)
+	template.Spec.InitContainers = append([]corev1.Container{fetch, restore}, template.Spec.InitContainers...)
+}
+
+// restoreDataContainer 返回删除旧的 AOF 并用下载的 RDB 替换数据文件的初始化容器
+func restoreDataContainer(image string, dataMount corev1.VolumeMount, dbfilename string, appendonly bool) corev1.Container {
+	script := fmt.Sprintf(`set -e
+if [ -f %[1]s ]; then exit 0; fi
+rm -rf /data/appendonlydir /data/appendonly.aof
+mv %[2]s /data/%[3]s
+`, restoreMarkerFile, restoreStagingFile, dbfilename)
+	if appendonly {
+		script += fmt.Sprintf(`redis-server --port 0 --unixsocket /tmp/restore.sock --dir /data --dbfilename %s --appendonly no --save "" --daemonize yes
+until [ "$(redis-cli -s /tmp/restore.sock ping 2>/dev/null)" = PONG ]; do sleep 1; done
+redis-cli -s /tmp/restore.sock config set appendonly yes
+until redis-cli -s /tmp/restore.sock info persistence | grep -q '^aof_rewrite_in_progress:0' && \
+  redis-cli -s /tmp/restore.sock info persistence | grep -q '^aof_rewrite_scheduled:0'; do sleep 1; done
+redis-cli -s /tmp/restore.sock info persistence | grep -q '^aof_last_bgrewrite_status:ok'
+redis-cli -s /tmp/restore.sock shutdown nosave || true
+`, dbfilename)
+	}
+	script += fmt.Sprintf("touch %s\n", restoreMarkerFile)
+
+	return corev1.Container{
+		Name:                     "restore",
+		Image:                    image,
+		Command:                  []string{"sh", "-c", script},
+		VolumeMounts:             []corev1.VolumeMount{dataMount},
+		TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+	}
+}
+
+// setRestoreCondition 根据执行恢复的 Pod 设置 Restored 条件，恢复完成后不再变化
+func setRestoreCondition(ctx context.Context, c client.Client, namespace string, source *redisv1.RestoreSource, pods []corev1.Pod, conditions *[]metav1.Condition) {
+	if source == nil || restoreCompleted(*conditions) {
+		return
+	}
+
+	condition := metav1.Condition{
+		Type:   redisRestoredCondition,
+		Status: metav1.ConditionFalse,
+	}
+	location, err := resolveRestoreSource(ctx, c, namespace, source)
+	if err != nil {
+		condition.Reason = "WaitingForSource"
+		condition.Message = err.Error()
+		meta.SetStatusCondition(conditions, condition)
+		return
+	}
+
+	ready := len(pods) > 0
+	for _, pod := range pods {
+		if message := restoreFailure(&pod); message != "" {
+			condition.Reason = "RestoreFailed"
+			condition.Message = fmt.Sprintf("Restoring %s on pod %s failed: %s", location, pod.Name, message)
+			meta.SetStatusCondition(conditions, condition)
+			return
+		}
+		if !isPodReady(&pod) {
+			ready = false
+		}
+	}
+
+	if ready {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "Restored"
+		condition.Message = fmt.Sprintf("Data restored from %s", location)
+	} else {
+		condition.Reason = "Restoring"
+		condition.Message = fmt.Sprintf("Restoring data from %s", location)
+	}
+	meta.SetStatusCondition(conditions, condition)
+}
+
+// restoreFailure 返回恢复初始化容器最近一次失败的原因
+func restoreFailure(pod *corev1.Pod) string {
+	for _, status := range pod.Status.InitContainerStatuses {
+		if status.Name != "restore-fetch" && status.Name != "restore" {
+			continue
+		}
+		terminated := status.State.Terminated
+		if terminated == nil {
+			terminated = status.LastTerminationState.Terminated
+		}
+		if terminated != nil && terminated.ExitCode != 0 {
+			message := strings.TrimSpace(terminated.Message)
+			if message == "" {
+				message = fmt.Sprintf("exit code %d", terminated.ExitCode)
+			}
+			return fmt.Sprintf("%s: %s", status.Name, message)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2025 James.Liu.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	redisv1 "github.com/ybooks240/redis-operator/api/v1"
+	"github.com/ybooks240/redis-operator/internal/metrics"
+	"github.com/ybooks240/redis-operator/internal/tracing"
+	"github.com/ybooks240/redis-operator/internal/utils"
+)
+
+// setTLSCertificateCondition 检查 TLS 证书有效期，更新状态条件和过期时间指标
+// 未启用 TLS 时移除证书状态条件
+func setTLSCertificateCondition(ctx context.Context, c client.Client, kind string, obj client.Object, security redisv1.SecuritySpec, conditions *[]metav1.Condition) {
+	if !utils.TLSEnabled(security) {
+		meta.RemoveStatusCondition(conditions, utils.TLSCertificateConditionType)
+		return
+	}
+
+	secret, err := utils.LoadTLSSecret(ctx, c, obj.GetNamespace(), security.TLS)
+	if err != nil {
+		meta.SetStatusCondition(conditions, utils.TLSCertificateCondition(nil, err, obj.GetGeneration()))
+		return
+	}
+
+	cert, err := utils.ParseTLSCertificate(secret)
+	if err == nil {
+		metrics.SetTLSCertificateExpiry(kind, obj.GetNamespace(), obj.GetName(), security.TLS.SecretName, float64(cert.NotAfter.Unix()))
+	}
+	meta.SetStatusCondition(conditions, utils.TLSCertificateCondition(cert, err, obj.GetGeneration()))
+}
+
+// workloadRedisPodLabels 返回选择工作负载 Redis 数据节点 Pod 的标签
+// RedisSentinel 的嵌入式 Redis 与 Sentinel Pod 的 app 标签不同，不会选中 Sentinel Pod
+func workloadRedisPodLabels(kind, name string) (map[string]string, error) {
+	switch kind {
+	case "RedisInstance":
+		return map[string]string{"redis.github.com/instance": name, "app": "redisInstance"}, nil
+	case "RedisMasterReplica", "RedisSentinel":
+		return map[string]string{"app": "redis", "instance": name}, nil
+	case "RedisCluster":
+		return map[string]string{"app": "redis-cluster", "component": "cluster", "instance": name}, nil
+	default:
+		return nil, fmt.Errorf("unsupported workload kind %q", kind)
+	}
+}
+
+// listWorkloadRedisPods 返回工作负载的 Redis 数据节点 Pod，按名称排序
+// RedisSentinel 只包含嵌入式 Redis 的 Pod，不包含 Sentinel Pod
+func listWorkloadRedisPods(ctx context.Context, c client.Client, kind, namespace, name string) ([]corev1.Pod, error) {
+	podLabels, err := workloadRedisPodLabels(kind, name)
+	if err != nil {
+		return nil, err
+	}
+	// podFilter 排除标签相同但属于其他工作负载的 Pod
+	podFilter := func(string) bool { return true }
+
+	switch kind {
+	case "RedisMasterReplica":
+		podFilter = func(podName string) bool {
+			return strings.HasPrefix(podName, name+"-master-") || strings.HasPrefix(podName, name+"-replica-")
+		}
+	case "RedisSentinel":
+		podFilter = func(podName string) bool {
+			return isEmbeddedRedisPod(&redisv1.RedisSentinel{ObjectMeta: metav1.ObjectMeta{Name: name}}, podName)
+		}
+	}
+
+	podList := &corev1.PodList{}
+	if err := c.List(ctx, podList, client.InNamespace(namespace), client.MatchingLabels(podLabels)); err != nil {
+		return nil, fmt.Errorf("failed to list %s pods: %w", kind, err)
+	}
+	var pods []corev1.Pod
+	for _, pod := range podList.Items {
+		if podFilter(pod.Name) {
+			pods = append(pods, pod)
+		}
+	}
+	sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+	return pods, nil
+}
+
+// redisWorkload 工作负载的 Redis 节点以及管理连接使用的密码和 TLS 配置
+type redisWorkload struct {
+	Kind      string
+	Namespace string
+	Name      string
+	// Image 工作负载使用的 Redis 镜像
+	Image     string
+	Security  redisv1.SecuritySpec
+	Pods      []corev1.Pod
+	Password  string
+	TLSConfig *tls.Config
+}
+
+// resolveRedisWorkload 查找工作负载的 Redis Pod 以及管理连接使用的密码和 TLS 配置
+func resolveRedisWorkload(ctx context.Context, c client.Client, kind, namespace, name string) (*redisWorkload, error) {
+	key := types.NamespacedName{Name: name, Namespace: namespace}
+	workload := &redisWorkload{Kind: kind, Namespace: namespace, Name: name}
+
+	switch kind {
+	case "RedisInstance":
+		instance := &redisv1.RedisInstance{}
+		if err := c.Get(ctx, key, instance); err != nil {
+			return nil, err
+		}
+		workload.Image = instance.Spec.Image
+		workload.Security = instance.Spec.Security
+	case "RedisMasterReplica":
+		masterReplica := &redisv1.RedisMasterReplica{}
+		if err := c.Get(ctx, key, masterReplica); err != nil {
+			return nil, err
+		}
+		workload.Image = masterReplica.Spec.Image
+		workload.Security = masterReplica.Spec.Security
+	case "RedisSentinel":
+		sentinel := &redisv1.RedisSentinel{}
+		if err := c.Get(ctx, key, sentinel); err != nil {
+			return nil, err
+		}
+		workload.Image = sentinel.Spec.Image
+		workload.Security = sentinel.Spec.Security
+	case "RedisCluster":
+		cluster := &redisv1.RedisCluster{}
+		if err := c.Get(ctx, key, cluster); err != nil {
+			return nil, err
+		}
+		workload.Image = cluster.Spec.Image
+		workload.Security = cluster.Spec.Security
+	default:
+		return nil, fmt.Errorf("unsupported workload kind %q", kind)
+	}
+
+	var err error
+	workload.Pods, err = listWorkloadRedisPods(ctx, c, kind, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	workload.Password, err = utils.ReadPassword(ctx, c, namespace, workload.Security, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s password: %w", kind, err)
+	}
+	workload.TLSConfig, err = utils.ClientTLSConfig(ctx, c, namespace, workload.Security.TLS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s TLS certificate: %w", kind, err)
+	}
+	return workload, nil
+}
+
+// client 返回连接指定节点的管理客户端
+func (w *redisWorkload) client(pod *corev1.Pod) *redis.Client {
+	return newRedisClient(&redis.Options{
+		Addr:      fmt.Sprintf("%s:%d", pod.Status.PodIP, defaultRedisPort),
+		Password:  w.Password,
+		TLSConfig: w.TLSConfig,
+	})
+}
+
+// newRedisClient 创建 Redis 管理客户端，每条命令记录为协调 span 的子 span
+func newRedisClient(options *redis.Options) *redis.Client {
+	redisClient := redis.NewClient(options)
+	tracing.InstrumentRedis(redisClient, options.Addr)
+	return redisClient
+}
+
+// newSentinelClient 创建 Sentinel 管理客户端，每条命令记录为协调 span 的子 span
+func newSentinelClient(options *redis.Options) *redis.SentinelClient {
+	sentinelClient := redis.NewSentinelClient(options)
+	tracing.InstrumentRedis(sentinelClient, options.Addr)
+	return sentinelClient
+}
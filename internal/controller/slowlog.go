@@ -0,0 +1,280 @@
+/*
+Copyright 2025 James.Liu.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	redisv1 "github.com/ybooks240/redis-operator/api/v1"
+	"github.com/ybooks240/redis-operator/internal/metrics"
+)
+
+const (
+	// defaultSlowlogInterval 两次读取慢日志之间的默认间隔
+	defaultSlowlogInterval = time.Minute
+	// defaultSlowlogTopN ConfigMap 中默认保留的慢日志条数
+	defaultSlowlogTopN = 20
+	// slowlogGetAll SLOWLOG GET 的条数参数，-1 表示读取全部慢日志，RESET 前不会遗漏任何条目
+	slowlogGetAll = -1
+
+	// slowlogHarvestedAtAnnotation 记录最近一次读取慢日志的时间，控制读取间隔
+	slowlogHarvestedAtAnnotation = "redis.github.com/slowlog-harvested-at"
+	// slowlogEntriesKey 和 latencyEventsKey 为 ConfigMap 中保存慢日志和延迟事件的键
+	slowlogEntriesKey = "slowlog.json"
+	latencyEventsKey  = "latency.json"
+)
+
+// slowlogConfigMapName 返回保存慢日志和延迟事件的 ConfigMap 名称
+func slowlogConfigMapName(name string) string {
+	return name + "-slowlog"
+}
+
+// slowlogEntry ConfigMap 中保存的一条慢日志
+type slowlogEntry struct {
+	Pod            string    `json:"pod"`
+	ID             int64     `json:"id"`
+	Time           time.Time `json:"time"`
+	DurationMicros int64     `json:"durationMicros"`
+	// Args 由 Redis 记录的命令参数，Redis 会截断过长的参数并隐藏密码
+	Args       []string `json:"args"`
+	ClientAddr string   `json:"clientAddr,omitempty"`
+	ClientName string   `json:"clientName,omitempty"`
+}
+
+// latencyEvent LATENCY LATEST 返回的一个延迟监控事件
+type latencyEvent struct {
+	Pod          string    `json:"pod"`
+	Event        string    `json:"event"`
+	Time         time.Time `json:"time"`
+	LatestMillis int64     `json:"latestMillis"`
+	MaxMillis    int64     `json:"maxMillis"`
+}
+
+// ensureSlowlog 按间隔读取工作负载每个节点的慢日志和延迟事件，记录到指标和 <name>-slowlog ConfigMap，停用后删除 ConfigMap
+// 单个节点读取失败只记录日志，不影响其他节点和协调
+func ensureSlowlog(ctx context.Context, c client.Client, scheme *runtime.Scheme, recorder record.EventRecorder, kind string, owner client.Object, spec *redisv1.SlowlogSpec) error {
+	configMap := &corev1.ConfigMap{}
+	configMap.Name = slowlogConfigMapName(owner.GetName())
+	configMap.Namespace = owner.GetNamespace()
+
+	if spec == nil || !spec.Enabled {
+		metrics.DeleteSlowlogMetrics(owner.GetNamespace(), owner.GetName())
+		if err := c.Delete(ctx, configMap); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete slowlog configmap: %w", err)
+		}
+		return nil
+	}
+
+	interval := defaultSlowlogInterval
+	if spec.Interval != "" {
+		parsed, err := time.ParseDuration(spec.Interval)
+		if err != nil {
+			return specError(fmt.Errorf("invalid slowlog interval %q: %w", spec.Interval, err))
+		}
+		interval = parsed
+	}
+
+	var entries []slowlogEntry
+	err := c.Get(ctx, types.NamespacedName{Name: configMap.Name, Namespace: configMap.Namespace}, configMap)
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		return fmt.Errorf("failed to get slowlog configmap: %w", err)
+	default:
+		harvestedAt, err := time.Parse(time.RFC3339, configMap.Annotations[slowlogHarvestedAtAnnotation])
+		if err == nil && time.Since(harvestedAt) < interval {
+			return nil
+		}
+		// 无法解析的旧数据直接丢弃
+		_ = json.Unmarshal([]byte(configMap.Data[slowlogEntriesKey]), &entries)
+	}
+
+	workload, err := resolveRedisWorkload(ctx, c, kind, owner.GetNamespace(), owner.GetName())
+	if err != nil {
+		return err
+	}
+
+	logs := logf.FromContext(ctx)
+	events := []latencyEvent{}
+	for i := range workload.Pods {
+		pod := &workload.Pods[i]
+		if pod.Status.PodIP == "" {
+			continue
+		}
+		err := withWorkloadNode(ctx, workload, pod, func(nodeCtx context.Context, node *redis.Client) error {
+			slowlogs, latency, err := harvestNode(nodeCtx, node, spec.LatencyMonitorThresholdMillis)
+			if err != nil {
+				return err
+			}
+			for _, slowlog := range slowlogs {
+				cmd := "unknown"
+				if len(slowlog.Args) > 0 {
+					cmd = strings.ToLower(slowlog.Args[0])
+				}
+				metrics.ObserveSlowlogEntry(owner.GetNamespace(), owner.GetName(), pod.Name, cmd, slowlog.Duration.Seconds())
+				entries = append(entries, slowlogEntry{
+					Pod:            pod.Name,
+					ID:             slowlog.ID,
+					Time:           slowlog.Time.UTC(),
+					DurationMicros: slowlog.Duration.Microseconds(),
+					Args:           slowlog.Args,
+					ClientAddr:     slowlog.ClientAddr,
+					ClientName:     slowlog.ClientName,
+				})
+			}
+			for _, event := range latency {
+				event.Pod = pod.Name
+				metrics.SetLatencyEvent(owner.GetNamespace(), owner.GetName(), pod.Name, event.Event,
+					float64(event.LatestMillis)/1000, float64(event.MaxMillis)/1000, float64(event.Time.Unix()))
+				events = append(events, event)
+			}
+			return nil
+		})
+		if err != nil {
+			logs.Error(err, "Failed to harvest slowlog", "pod", pod.Name)
+		}
+	}
+
+	entries = recentSlowlogEntries(entries, slowlogTopN(spec))
+	entriesJSON, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	eventsJSON, err := json.MarshalIndent(events, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	op, err := controllerutil.CreateOrUpdate(ctx, c, configMap, func() error {
+		if configMap.Annotations == nil {
+			configMap.Annotations = map[string]string{}
+		}
+		configMap.Annotations[slowlogHarvestedAtAnnotation] = time.Now().UTC().Format(time.RFC3339)
+		configMap.Data = map[string]string{
+			slowlogEntriesKey: string(entriesJSON),
+			latencyEventsKey:  string(eventsJSON),
+		}
+		return controllerutil.SetControllerReference(owner, configMap, scheme)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to ensure slowlog configmap: %w", err)
+	}
+	if op == controllerutil.OperationResultCreated {
+		recordCreated(recorder, owner, "ConfigMap", configMap.Name)
+	}
+	return nil
+}
+
+// slowlogTopN 返回 ConfigMap 中保留的慢日志条数
+func slowlogTopN(spec *redisv1.SlowlogSpec) int {
+	if spec.TopN <= 0 {
+		return defaultSlowlogTopN
+	}
+	return int(spec.TopN)
+}
+
+// harvestNode 读取并清空节点的慢日志，读取延迟监控事件；配置了阈值时先开启延迟监控
+// 事务失败时返回错误且不记录本次读取的慢日志，下次读取时重新统计
+func harvestNode(ctx context.Context, node *redis.Client, thresholdMillis int32) ([]redis.SlowLog, []latencyEvent, error) {
+	if thresholdMillis > 0 {
+		threshold := strconv.Itoa(int(thresholdMillis))
+		current, err := node.ConfigGet(ctx, "latency-monitor-threshold").Result()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get latency-monitor-threshold: %w", err)
+		}
+		if current["latency-monitor-threshold"] != threshold {
+			if err := node.ConfigSet(ctx, "latency-monitor-threshold", threshold).Err(); err != nil {
+				return nil, nil, fmt.Errorf("failed to set latency-monitor-threshold: %w", err)
+			}
+		}
+	}
+
+	// 在同一个 MULTI 中读取并清空慢日志，避免两条命令之间新增的慢日志未被读取就被清空
+	var get *redis.SlowLogCmd
+	if _, err := node.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		get = pipe.SlowLogGet(ctx, slowlogGetAll)
+		pipe.Do(ctx, "SLOWLOG", "RESET")
+		return nil
+	}); err != nil {
+		return nil, nil, fmt.Errorf("failed to get and reset slowlog: %w", err)
+	}
+	slowlogs := get.Val()
+
+	latest, err := node.Do(ctx, "LATENCY", "LATEST").Slice()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get latency events: %w", err)
+	}
+	return slowlogs, parseLatencyLatest(latest), nil
+}
+
+// parseLatencyLatest 解析 LATENCY LATEST 的结果，每个事件为 [名称, 时间戳, 最近延迟毫秒, 最大延迟毫秒]
+func parseLatencyLatest(reply []interface{}) []latencyEvent {
+	events := make([]latencyEvent, 0, len(reply))
+	for _, item := range reply {
+		fields, ok := item.([]interface{})
+		if !ok || len(fields) < 4 {
+			continue
+		}
+		name, ok := fields[0].(string)
+		timestamp, ok1 := fields[1].(int64)
+		latest, ok2 := fields[2].(int64)
+		maxLatency, ok3 := fields[3].(int64)
+		if !ok || !ok1 || !ok2 || !ok3 {
+			continue
+		}
+		events = append(events, latencyEvent{
+			Event:        name,
+			Time:         time.Unix(timestamp, 0).UTC(),
+			LatestMillis: latest,
+			MaxMillis:    maxLatency,
+		})
+	}
+	return events
+}
+
+// recentSlowlogEntries 按时间从新到旧排序，保留最近的 n 条慢日志
+func recentSlowlogEntries(entries []slowlogEntry, n int) []slowlogEntry {
+	sort.SliceStable(entries, func(i, j int) bool {
+		if !entries[i].Time.Equal(entries[j].Time) {
+			return entries[i].Time.After(entries[j].Time)
+		}
+		return entries[i].ID > entries[j].ID
+	})
+	if len(entries) > n {
+		entries = entries[:n]
+	}
+	if entries == nil {
+		entries = []slowlogEntry{}
+	}
+	return entries
+}
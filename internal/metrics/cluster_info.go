@@ -0,0 +1,134 @@
+package metrics
+
+import (
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ybooks240/redis-operator/internal/utils"
+)
+
+// clusterShardMasters 返回持有槽位的 master，按最小槽位排序，下标即分片序号
+// 序号只取决于槽位分布，不随故障转移变化
+func clusterShardMasters(nodes []utils.ClusterNode) []utils.ClusterNode {
+	var masters []utils.ClusterNode
+	for _, node := range nodes {
+		if node.HasFlag("master") && node.FirstSlot() >= 0 {
+			masters = append(masters, node)
+		}
+	}
+	sort.Slice(masters, func(i, j int) bool { return masters[i].FirstSlot() < masters[j].FirstSlot() })
+	return masters
+}
+
+// clusterShard 根据 CLUSTER NODES 返回本节点所属分片的序号
+// 本节点所属 master 未持有槽位时返回空字符串
+func clusterShard(output string) string {
+	nodes := utils.ParseClusterNodes(output)
+	owner := ""
+	for _, node := range nodes {
+		if node.HasFlag("myself") {
+			owner = node.ID
+			if node.MasterID != "" {
+				owner = node.MasterID
+			}
+		}
+	}
+	for i, master := range clusterShardMasters(nodes) {
+		if master.ID == owner {
+			return strconv.Itoa(i)
+		}
+	}
+	return ""
+}
+
+var (
+	// 集群级别指标，取自 CLUSTER NODES 和 CLUSTER INFO
+	clusterNodesDesc         = prometheus.NewDesc("redis_cluster_nodes_total", "Total number of cluster nodes", []string{"namespace", "name"}, nil)
+	clusterSlotsAssignedDesc = prometheus.NewDesc("redis_cluster_slots_assigned", "Number of assigned slots", []string{"namespace", "name"}, nil)
+	clusterSlotsOKDesc       = prometheus.NewDesc("redis_cluster_slots_ok", "Number of slots served by nodes that are not failing", []string{"namespace", "name"}, nil)
+	clusterSlotsPFailDesc    = prometheus.NewDesc("redis_cluster_slots_pfail", "Number of slots served by nodes flagged as pfail", []string{"namespace", "name"}, nil)
+	clusterSlotsFailDesc     = prometheus.NewDesc("redis_cluster_slots_fail", "Number of slots served by nodes flagged as fail", []string{"namespace", "name"}, nil)
+	clusterStateDesc         = prometheus.NewDesc("redis_cluster_state", "Cluster state (0=fail, 1=ok)", []string{"namespace", "name"}, nil)
+
+	// 节点级别指标
+	clusterNodeLabels          = []string{"namespace", "name", "node_id", "address", "role"}
+	clusterNodeLinkUpDesc      = prometheus.NewDesc("redis_cluster_node_link_up", "Whether the cluster bus link to the node is connected", clusterNodeLabels, nil)
+	clusterNodePFailDesc       = prometheus.NewDesc("redis_cluster_node_pfail", "Whether the node is flagged as pfail", clusterNodeLabels, nil)
+	clusterNodeFailDesc        = prometheus.NewDesc("redis_cluster_node_fail", "Whether the node is flagged as fail", clusterNodeLabels, nil)
+	clusterNodeSlotsDesc       = prometheus.NewDesc("redis_cluster_node_slots", "Number of slots owned by the node", clusterNodeLabels, nil)
+	clusterNodeConfigEpochDesc = prometheus.NewDesc("redis_cluster_node_config_epoch", "Config epoch of the node", clusterNodeLabels, nil)
+
+	// 分片级别指标，shard 与 redis_instance_* 指标的 shard 标签一致
+	clusterShardLabels              = []string{"namespace", "name", "shard"}
+	clusterShardUpDesc              = prometheus.NewDesc("redis_cluster_shard_up", "Whether the master of the shard is not flagged as fail", clusterShardLabels, nil)
+	clusterShardKeysDesc            = prometheus.NewDesc("redis_cluster_shard_keys", "Number of keys stored in the slot ranges of the shard", slices.Concat(clusterShardLabels, []string{"slots"}), nil)
+	clusterShardMemoryDesc          = prometheus.NewDesc("redis_cluster_shard_memory_bytes", "Memory used by the master of the shard in bytes", clusterShardLabels, nil)
+	clusterShardReplicasDesc        = prometheus.NewDesc("redis_cluster_shard_replicas", "Number of replicas of the shard that are not flagged as fail", clusterShardLabels, nil)
+	clusterShardReplicasDesiredDesc = prometheus.NewDesc("redis_cluster_shard_replicas_desired", "Number of replicas per master requested by the RedisCluster", clusterShardLabels, nil)
+)
+
+// describeClusterInfo 输出集群指标的描述
+func describeClusterInfo(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{
+		clusterNodesDesc, clusterSlotsAssignedDesc, clusterSlotsOKDesc, clusterSlotsPFailDesc, clusterSlotsFailDesc, clusterStateDesc,
+		clusterNodeLinkUpDesc, clusterNodePFailDesc, clusterNodeFailDesc, clusterNodeSlotsDesc, clusterNodeConfigEpochDesc,
+		clusterShardUpDesc, clusterShardKeysDesc, clusterShardMemoryDesc, clusterShardReplicasDesc, clusterShardReplicasDesiredDesc,
+	} {
+		ch <- desc
+	}
+}
+
+// collectClusterInfo 将集群拓扑和各 master 的 INFO 转换为常量指标
+// masterInfo 以节点地址为键，desiredReplicas 小于 0 时不输出期望副本数
+func collectClusterInfo(ch chan<- prometheus.Metric, namespace, name string, nodes []utils.ClusterNode, info map[string]string, masterInfo map[string]map[string]string, desiredReplicas int32) {
+	ch <- prometheus.MustNewConstMetric(clusterNodesDesc, prometheus.GaugeValue, float64(len(nodes)), namespace, name)
+	ch <- prometheus.MustNewConstMetric(clusterStateDesc, prometheus.GaugeValue, boolToFloat(info["cluster_state"] == "ok"), namespace, name)
+	emitInfoValue(ch, clusterSlotsAssignedDesc, prometheus.GaugeValue, info["cluster_slots_assigned"], 1, []string{namespace, name})
+	emitInfoValue(ch, clusterSlotsOKDesc, prometheus.GaugeValue, info["cluster_slots_ok"], 1, []string{namespace, name})
+	emitInfoValue(ch, clusterSlotsPFailDesc, prometheus.GaugeValue, info["cluster_slots_pfail"], 1, []string{namespace, name})
+	emitInfoValue(ch, clusterSlotsFailDesc, prometheus.GaugeValue, info["cluster_slots_fail"], 1, []string{namespace, name})
+
+	for _, node := range nodes {
+		role := "master"
+		if node.HasFlag("slave") {
+			role = "replica"
+		}
+		labels := []string{namespace, name, node.ID, node.Addr, role}
+		ch <- prometheus.MustNewConstMetric(clusterNodeLinkUpDesc, prometheus.GaugeValue, boolToFloat(node.LinkState == "connected"), labels...)
+		ch <- prometheus.MustNewConstMetric(clusterNodePFailDesc, prometheus.GaugeValue, boolToFloat(node.HasFlag("fail?")), labels...)
+		ch <- prometheus.MustNewConstMetric(clusterNodeFailDesc, prometheus.GaugeValue, boolToFloat(node.HasFlag("fail")), labels...)
+		ch <- prometheus.MustNewConstMetric(clusterNodeSlotsDesc, prometheus.GaugeValue, float64(node.SlotCount()), labels...)
+		emitInfoValue(ch, clusterNodeConfigEpochDesc, prometheus.GaugeValue, node.ConfigEpoch, 1, labels)
+	}
+
+	for i, master := range clusterShardMasters(nodes) {
+		labels := []string{namespace, name, strconv.Itoa(i)}
+		replicas := 0
+		for _, node := range nodes {
+			if node.MasterID == master.ID && !node.Failed() {
+				replicas++
+			}
+		}
+		ch <- prometheus.MustNewConstMetric(clusterShardUpDesc, prometheus.GaugeValue, boolToFloat(!master.Failed()), labels...)
+		ch <- prometheus.MustNewConstMetric(clusterShardReplicasDesc, prometheus.GaugeValue, float64(replicas), labels...)
+		if desiredReplicas >= 0 {
+			ch <- prometheus.MustNewConstMetric(clusterShardReplicasDesiredDesc, prometheus.GaugeValue, float64(desiredReplicas), labels...)
+		}
+
+		fields, ok := masterInfo[master.Addr]
+		if !ok {
+			continue
+		}
+		emitInfoValue(ch, clusterShardMemoryDesc, prometheus.GaugeValue, fields["used_memory"], 1, labels)
+		// Redis Cluster 只使用 db0
+		if db0, ok := fields["db0"]; ok {
+			emitInfoValue(ch, clusterShardKeysDesc, prometheus.GaugeValue, parseInfoValues(db0)["keys"], 1, labels, strings.Join(master.Slots, ","))
+		} else {
+			ch <- prometheus.MustNewConstMetric(clusterShardKeysDesc, prometheus.GaugeValue, 0, slices.Concat(labels, []string{strings.Join(master.Slots, ",")})...)
+		}
+	}
+}
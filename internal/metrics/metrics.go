@@ -32,61 +32,29 @@ var (
 		[]string{"controller", "namespace", "name", "error_type"},
 	)
 
-	ResourceStatus = prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Name: "redis_operator_resource_status",
-			Help: "Status of Redis resources (0=down, 1=up, 2=pending)",
-		},
-		[]string{"controller", "namespace", "name", "status"},
-	)
-
-	// Redis 实例级别指标
-	RedisInstanceStatus = prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Name: "redis_instance_status",
-			Help: "Redis instance status (0=down, 1=up)",
-		},
-		[]string{"namespace", "name", "role"},
-	)
-
-	RedisInstanceMemoryUsage = prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Name: "redis_instance_memory_usage_bytes",
-			Help: "Redis instance memory usage in bytes",
-		},
-		[]string{"namespace", "name", "role"},
-	)
-
-	RedisInstanceConnectedClients = prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Name: "redis_instance_connected_clients",
-			Help: "Number of connected clients",
-		},
-		[]string{"namespace", "name", "role"},
-	)
-
-	RedisInstanceCommandsProcessed = prometheus.NewCounterVec(
+	PhaseTransitions = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
-			Name: "redis_instance_commands_processed_total",
-			Help: "Total number of commands processed",
+			Name: "redis_operator_phase_transitions_total",
+			Help: "Total number of status phase transitions",
 		},
-		[]string{"namespace", "name", "role"},
+		[]string{"controller", "from", "to"},
 	)
 
-	RedisInstanceKeyspaceHits = prometheus.NewCounterVec(
-		prometheus.CounterOpts{
-			Name: "redis_instance_keyspace_hits_total",
-			Help: "Total number of keyspace hits",
+	TimeToReady = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "redis_operator_time_to_ready_seconds",
+			Help:    "Time from the Ready condition turning false, or from creation, until the resource is ready again",
+			Buckets: prometheus.ExponentialBuckets(5, 2, 11),
 		},
-		[]string{"namespace", "name", "role"},
+		[]string{"controller", "from"},
 	)
 
-	RedisInstanceKeyspaceMisses = prometheus.NewCounterVec(
-		prometheus.CounterOpts{
-			Name: "redis_instance_keyspace_misses_total",
-			Help: "Total number of keyspace misses",
+	ResourceStatus = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "redis_operator_resource_status",
+			Help: "Status of Redis resources (0=down, 1=up, 2=pending)",
 		},
-		[]string{"namespace", "name", "role"},
+		[]string{"controller", "namespace", "name", "status"},
 	)
 
 	// Redis Sentinel 级别指标
@@ -109,7 +77,7 @@ var (
 	RedisSentinelFailovers = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "redis_sentinel_failover_total",
-			Help: "Total number of failovers",
+			Help: "Number of failovers observed as increases of the master config epoch",
 		},
 		[]string{"namespace", "name", "master_name"},
 	)
@@ -122,31 +90,6 @@ var (
 		[]string{"namespace", "name", "master_name"},
 	)
 
-	// Redis Cluster 级别指标
-	RedisClusterNodes = prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Name: "redis_cluster_nodes_total",
-			Help: "Total number of cluster nodes",
-		},
-		[]string{"namespace", "name"},
-	)
-
-	RedisClusterSlotsAssigned = prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Name: "redis_cluster_slots_assigned",
-			Help: "Number of assigned slots",
-		},
-		[]string{"namespace", "name"},
-	)
-
-	RedisClusterState = prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Name: "redis_cluster_state",
-			Help: "Cluster state (0=fail, 1=ok)",
-		},
-		[]string{"namespace", "name"},
-	)
-
 	// 资源使用指标
 	RedisResourceCPUUsage = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
@@ -179,6 +122,98 @@ var (
 		},
 		[]string{"namespace", "name", "operation", "result"},
 	)
+
+	// TLS 证书指标
+	RedisTLSCertificateExpiry = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "redis_tls_certificate_expiry_timestamp_seconds",
+			Help: "Expiry time of the TLS certificate in unix seconds",
+		},
+		[]string{"kind", "namespace", "name", "secret"},
+	)
+
+	// 慢日志和延迟监控指标，由控制器定期从每个节点读取
+	RedisSlowlogEntries = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "redis_slowlog_entries_total",
+			Help: "Total number of slowlog entries harvested per command",
+		},
+		[]string{"namespace", "name", "pod", "cmd"},
+	)
+
+	RedisSlowlogDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "redis_slowlog_duration_seconds",
+			Help:    "Execution time of the harvested slowlog entries per command",
+			Buckets: prometheus.ExponentialBuckets(0.001, 2, 14),
+		},
+		[]string{"namespace", "name", "pod", "cmd"},
+	)
+
+	RedisLatencyLatest = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "redis_latency_latest_seconds",
+			Help: "Latency of the latest spike per latency monitor event",
+		},
+		[]string{"namespace", "name", "pod", "event"},
+	)
+
+	RedisLatencyMax = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "redis_latency_max_seconds",
+			Help: "Maximum latency per latency monitor event since the monitor was reset",
+		},
+		[]string{"namespace", "name", "pod", "event"},
+	)
+
+	RedisLatencyLastEventTimestamp = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "redis_latency_last_event_timestamp_seconds",
+			Help: "Time of the latest spike per latency monitor event in unix seconds",
+		},
+		[]string{"namespace", "name", "pod", "event"},
+	)
+
+	// 定时备份指标
+	RedisBackupLastSuccessTimestamp = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "redis_backup_last_success_timestamp_seconds",
+			Help: "Completion time of the last successful scheduled backup in unix seconds",
+		},
+		[]string{"namespace", "schedule"},
+	)
+
+	RedisBackupLastFailureTimestamp = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "redis_backup_last_failure_timestamp_seconds",
+			Help: "Creation time of the last failed scheduled backup in unix seconds",
+		},
+		[]string{"namespace", "schedule"},
+	)
+
+	RedisBackupLastDuration = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "redis_backup_last_duration_seconds",
+			Help: "Duration of the last successful scheduled backup in seconds",
+		},
+		[]string{"namespace", "schedule"},
+	)
+
+	RedisBackupLastSize = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "redis_backup_last_size_bytes",
+			Help: "Size of the last successful scheduled backup in bytes",
+		},
+		[]string{"namespace", "schedule"},
+	)
+
+	RedisBackupsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "redis_backups_total",
+			Help: "Total number of finished scheduled backups",
+		},
+		[]string{"namespace", "schedule", "result"},
+	)
 )
 
 // init 函数注册所有指标
@@ -188,19 +223,11 @@ func init() {
 		ReconcileTotal,
 		ReconcileDuration,
 		ReconcileErrors,
+		PhaseTransitions,
+		TimeToReady,
 		ResourceStatus,
 	)
 
-	// 注册 Redis 实例级别指标
-	metrics.Registry.MustRegister(
-		RedisInstanceStatus,
-		RedisInstanceMemoryUsage,
-		RedisInstanceConnectedClients,
-		RedisInstanceCommandsProcessed,
-		RedisInstanceKeyspaceHits,
-		RedisInstanceKeyspaceMisses,
-	)
-
 	// 注册 Redis Sentinel 级别指标
 	metrics.Registry.MustRegister(
 		RedisSentinelMasters,
@@ -209,13 +236,6 @@ func init() {
 		RedisSentinelMasterStatus,
 	)
 
-	// 注册 Redis Cluster 级别指标
-	metrics.Registry.MustRegister(
-		RedisClusterNodes,
-		RedisClusterSlotsAssigned,
-		RedisClusterState,
-	)
-
 	// 注册资源使用指标
 	metrics.Registry.MustRegister(
 		RedisResourceCPUUsage,
@@ -223,6 +243,29 @@ func init() {
 		RedisResourceNetworkIO,
 		RedisPersistenceOperations,
 	)
+
+	// 注册 TLS 证书指标
+	metrics.Registry.MustRegister(
+		RedisTLSCertificateExpiry,
+	)
+
+	// 注册慢日志和延迟监控指标
+	metrics.Registry.MustRegister(
+		RedisSlowlogEntries,
+		RedisSlowlogDuration,
+		RedisLatencyLatest,
+		RedisLatencyMax,
+		RedisLatencyLastEventTimestamp,
+	)
+
+	// 注册定时备份指标
+	metrics.Registry.MustRegister(
+		RedisBackupLastSuccessTimestamp,
+		RedisBackupLastFailureTimestamp,
+		RedisBackupLastDuration,
+		RedisBackupLastSize,
+		RedisBackupsTotal,
+	)
 }
 
 // RecordReconcile 记录协调操作指标
@@ -236,39 +279,30 @@ func RecordReconcileError(controller, namespace, name, errorType string) {
 	ReconcileErrors.WithLabelValues(controller, namespace, name, errorType).Inc()
 }
 
-// SetResourceStatus 设置资源状态指标
-func SetResourceStatus(controller, namespace, name, status string, value float64) {
-	ResourceStatus.WithLabelValues(controller, namespace, name, status).Set(value)
-}
-
-// SetRedisInstanceStatus 设置 Redis 实例状态指标
-func SetRedisInstanceStatus(namespace, name, role string, status float64) {
-	RedisInstanceStatus.WithLabelValues(namespace, name, role).Set(status)
-}
-
-// SetRedisInstanceMemoryUsage 设置 Redis 实例内存使用指标
-func SetRedisInstanceMemoryUsage(namespace, name, role string, usage float64) {
-	RedisInstanceMemoryUsage.WithLabelValues(namespace, name, role).Set(usage)
-}
-
-// SetRedisInstanceConnectedClients 设置 Redis 实例连接客户端数指标
-func SetRedisInstanceConnectedClients(namespace, name, role string, clients float64) {
-	RedisInstanceConnectedClients.WithLabelValues(namespace, name, role).Set(clients)
+// RecordPhaseTransition 记录资源状态阶段的变化
+func RecordPhaseTransition(controller, from, to string) {
+	PhaseTransitions.WithLabelValues(controller, from, to).Inc()
 }
 
-// IncRedisInstanceCommandsProcessed 增加 Redis 实例处理命令数指标
-func IncRedisInstanceCommandsProcessed(namespace, name, role string) {
-	RedisInstanceCommandsProcessed.WithLabelValues(namespace, name, role).Inc()
+// ObserveTimeToReady 记录资源从 from 阶段恢复就绪的耗时
+func ObserveTimeToReady(controller, from string, seconds float64) {
+	TimeToReady.WithLabelValues(controller, from).Observe(seconds)
 }
 
-// IncRedisInstanceKeyspaceHits 增加 Redis 实例键空间命中数指标
-func IncRedisInstanceKeyspaceHits(namespace, name, role string) {
-	RedisInstanceKeyspaceHits.WithLabelValues(namespace, name, role).Inc()
+// DeleteResourceMetrics 删除资源的协调和证书指标，kind 与 controller 标签一致
+func DeleteResourceMetrics(kind, namespace, name string) {
+	labels := prometheus.Labels{"controller": kind, "namespace": namespace, "name": name}
+	ReconcileTotal.DeletePartialMatch(labels)
+	ReconcileDuration.DeletePartialMatch(labels)
+	ReconcileErrors.DeletePartialMatch(labels)
+	ResourceStatus.DeletePartialMatch(labels)
+	RedisTLSCertificateExpiry.DeletePartialMatch(prometheus.Labels{"kind": kind, "namespace": namespace, "name": name})
+	DeleteSlowlogMetrics(namespace, name)
 }
 
-// IncRedisInstanceKeyspaceMisses 增加 Redis 实例键空间未命中数指标
-func IncRedisInstanceKeyspaceMisses(namespace, name, role string) {
-	RedisInstanceKeyspaceMisses.WithLabelValues(namespace, name, role).Inc()
+// SetResourceStatus 设置资源状态指标
+func SetResourceStatus(controller, namespace, name, status string, value float64) {
+	ResourceStatus.WithLabelValues(controller, namespace, name, status).Set(value)
 }
 
 // SetRedisSentinelMasters 设置 Sentinel 监控的主节点数指标
@@ -291,21 +325,6 @@ func SetRedisSentinelMasterStatus(namespace, name, masterName string, status flo
 	RedisSentinelMasterStatus.WithLabelValues(namespace, name, masterName).Set(status)
 }
 
-// SetRedisClusterNodes 设置集群节点数指标
-func SetRedisClusterNodes(namespace, name string, nodes float64) {
-	RedisClusterNodes.WithLabelValues(namespace, name).Set(nodes)
-}
-
-// SetRedisClusterSlotsAssigned 设置集群已分配槽位数指标
-func SetRedisClusterSlotsAssigned(namespace, name string, slots float64) {
-	RedisClusterSlotsAssigned.WithLabelValues(namespace, name).Set(slots)
-}
-
-// SetRedisClusterState 设置集群状态指标
-func SetRedisClusterState(namespace, name string, state float64) {
-	RedisClusterState.WithLabelValues(namespace, name).Set(state)
-}
-
 // SetRedisResourceCPUUsage 设置资源 CPU 使用指标
 func SetRedisResourceCPUUsage(namespace, name, pod, container string, usage float64) {
 	RedisResourceCPUUsage.WithLabelValues(namespace, name, pod, container).Set(usage)
@@ -325,3 +344,58 @@ func IncRedisResourceNetworkIO(namespace, name, pod, direction string, bytes flo
 func IncRedisPersistenceOperations(namespace, name, operation, result string) {
 	RedisPersistenceOperations.WithLabelValues(namespace, name, operation, result).Inc()
 }
+
+// SetTLSCertificateExpiry 设置 TLS 证书过期时间
+func SetTLSCertificateExpiry(kind, namespace, name, secret string, expiry float64) {
+	RedisTLSCertificateExpiry.WithLabelValues(kind, namespace, name, secret).Set(expiry)
+}
+
+// ObserveSlowlogEntry 记录一条慢日志
+func ObserveSlowlogEntry(namespace, name, pod, cmd string, seconds float64) {
+	RedisSlowlogEntries.WithLabelValues(namespace, name, pod, cmd).Inc()
+	RedisSlowlogDuration.WithLabelValues(namespace, name, pod, cmd).Observe(seconds)
+}
+
+// SetLatencyEvent 设置延迟监控事件的最近一次延迟、最大延迟和发生时间
+func SetLatencyEvent(namespace, name, pod, event string, latest, max, timestamp float64) {
+	RedisLatencyLatest.WithLabelValues(namespace, name, pod, event).Set(latest)
+	RedisLatencyMax.WithLabelValues(namespace, name, pod, event).Set(max)
+	RedisLatencyLastEventTimestamp.WithLabelValues(namespace, name, pod, event).Set(timestamp)
+}
+
+// DeleteSlowlogMetrics 删除工作负载的慢日志和延迟监控指标
+func DeleteSlowlogMetrics(namespace, name string) {
+	labels := prometheus.Labels{"namespace": namespace, "name": name}
+	RedisSlowlogEntries.DeletePartialMatch(labels)
+	RedisSlowlogDuration.DeletePartialMatch(labels)
+	RedisLatencyLatest.DeletePartialMatch(labels)
+	RedisLatencyMax.DeletePartialMatch(labels)
+	RedisLatencyLastEventTimestamp.DeletePartialMatch(labels)
+}
+
+// SetBackupLastSuccess 设置最近一次成功的定时备份指标
+func SetBackupLastSuccess(namespace, schedule string, completion, duration, size float64) {
+	RedisBackupLastSuccessTimestamp.WithLabelValues(namespace, schedule).Set(completion)
+	RedisBackupLastDuration.WithLabelValues(namespace, schedule).Set(duration)
+	RedisBackupLastSize.WithLabelValues(namespace, schedule).Set(size)
+}
+
+// SetBackupLastFailure 设置最近一次失败的定时备份指标
+func SetBackupLastFailure(namespace, schedule string, timestamp float64) {
+	RedisBackupLastFailureTimestamp.WithLabelValues(namespace, schedule).Set(timestamp)
+}
+
+// IncBackupsTotal 增加结束的定时备份数
+func IncBackupsTotal(namespace, schedule, result string) {
+	RedisBackupsTotal.WithLabelValues(namespace, schedule, result).Inc()
+}
+
+// DeleteBackupScheduleMetrics 删除定时备份的指标
+func DeleteBackupScheduleMetrics(namespace, schedule string) {
+	labels := map[string]string{"namespace": namespace, "schedule": schedule}
+	RedisBackupLastSuccessTimestamp.Delete(labels)
+	RedisBackupLastFailureTimestamp.Delete(labels)
+	RedisBackupLastDuration.Delete(labels)
+	RedisBackupLastSize.Delete(labels)
+	RedisBackupsTotal.DeletePartialMatch(labels)
+}
@@ -2,36 +2,67 @@ package metrics
 
 import (
 	"context"
+	"crypto/tls"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/redis/go-redis/v9"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/ybooks240/redis-operator/internal/utils"
 )
 
-// RedisCollector 用于收集 Redis 实例的指标
+// RedisCollector 用于收集单个 Redis Pod 的指标
+// CollectMetrics 周期读取 INFO，Prometheus 抓取时由 Collect 将最近一次的结果转换为常量指标
 type RedisCollector struct {
 	client    *redis.Client
 	namespace string
 	name      string
-	role      string
+	pod       string
+
+	mu sync.Mutex
+	// collected 是否已完成过一次收集，之前不输出任何指标
+	collected bool
+	// role 和 shard 从节点的 INFO 中读取，读取成功前使用角色提示或旧收集器的标签
+	role  string
+	shard string
+	// roleObserved 是否已从 INFO 读取到角色，之后忽略角色提示
+	roleObserved bool
+	// info 最近一次 INFO all 的字段，节点不可用时为 nil
+	info map[string]string
 }
 
-// NewRedisCollector 创建新的 Redis 指标收集器
-func NewRedisCollector(addr, password, namespace, name, role string) *RedisCollector {
+// NewRedisCollector 创建新的 Redis 指标收集器，addr 为 Pod 的地址
+func NewRedisCollector(addr, password, namespace, name, pod string) *RedisCollector {
 	client := redis.NewClient(&redis.Options{
 		Addr:     addr,
 		Password: password,
 		DB:       0,
+		// 使用收集上下文的超时，避免单个节点阻塞整轮收集
+		ContextTimeoutEnabled: true,
 	})
 
 	return &RedisCollector{
 		client:    client,
 		namespace: namespace,
 		name:      name,
-		role:      role,
+		pod:       pod,
+	}
+}
+
+// WithTLS 使用 TLS 连接 Redis 实例，tlsConfig 为 nil 时保持明文连接
+func (rc *RedisCollector) WithTLS(tlsConfig *tls.Config) *RedisCollector {
+	if tlsConfig == nil {
+		return rc
 	}
+	options := rc.client.Options()
+	options.TLSConfig = tlsConfig
+	_ = rc.client.Close()
+	rc.client = redis.NewClient(options)
+	return rc
 }
 
 // CollectMetrics 收集 Redis 指标
@@ -39,73 +70,100 @@ func (rc *RedisCollector) CollectMetrics(ctx context.Context) error {
 	logger := log.FromContext(ctx)
 
 	// 检查连接状态
-	pong, err := rc.client.Ping(ctx).Result()
-	if err != nil {
-		logger.Error(err, "Failed to ping Redis instance")
-		SetRedisInstanceStatus(rc.namespace, rc.name, rc.role, 0) // down
+	if err := rc.client.Ping(ctx).Err(); err != nil {
+		logger.Error(err, "Failed to ping Redis instance", "pod", rc.pod)
+		rc.setInfo(nil, "", "")
 		return err
 	}
 
-	if pong == "PONG" {
-		SetRedisInstanceStatus(rc.namespace, rc.name, rc.role, 1) // up
-	}
-
-	// 获取 Redis INFO 信息
-	info, err := rc.client.Info(ctx).Result()
+	// 获取 Redis INFO 信息，包含 commandstats 和 latencystats
+	info, err := rc.client.Info(ctx, "all").Result()
 	if err != nil {
-		logger.Error(err, "Failed to get Redis info")
+		logger.Error(err, "Failed to get Redis info", "pod", rc.pod)
+		rc.setInfo(nil, "", "")
 		return err
 	}
+	fields := utils.ParseInfo(info)
 
-	// 解析 INFO 信息并更新指标
-	rc.parseAndUpdateMetrics(info)
+	// 角色以 INFO replication 为准，故障转移后随之变化
+	role := "master"
+	if fields["role"] == "slave" {
+		role = "replica"
+	}
+	shard := "0"
+	if fields["cluster_enabled"] == "1" {
+		nodes, err := rc.client.ClusterNodes(ctx).Result()
+		if err != nil {
+			logger.Error(err, "Failed to get cluster nodes", "pod", rc.pod)
+			return err
+		}
+		shard = clusterShard(nodes)
+	}
+	rc.setInfo(fields, role, shard)
 
 	return nil
 }
 
-// parseAndUpdateMetrics 解析 Redis INFO 信息并更新指标
-func (rc *RedisCollector) parseAndUpdateMetrics(info string) {
-	lines := strings.Split(info, "\r\n")
-	for _, line := range lines {
-		if strings.Contains(line, ":") {
-			parts := strings.SplitN(line, ":", 2)
-			if len(parts) == 2 {
-				key := strings.TrimSpace(parts[0])
-				value := strings.TrimSpace(parts[1])
-				rc.updateMetricByKey(key, value)
-			}
-		}
+// setInfo 保存收集结果，info 为 nil 时表示节点不可用，保留上次的角色和分片标签
+func (rc *RedisCollector) setInfo(info map[string]string, role, shard string) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.collected = true
+	rc.info = info
+	if info != nil {
+		rc.role, rc.shard = role, shard
+		rc.roleObserved = true
 	}
 }
 
-// updateMetricByKey 根据键值更新对应的指标
-func (rc *RedisCollector) updateMetricByKey(key, value string) {
-	switch key {
-	case "used_memory":
-		if val, err := strconv.ParseFloat(value, 64); err == nil {
-			SetRedisInstanceMemoryUsage(rc.namespace, rc.name, rc.role, val)
-		}
-	case "connected_clients":
-		if val, err := strconv.ParseFloat(value, 64); err == nil {
-			SetRedisInstanceConnectedClients(rc.namespace, rc.name, rc.role, val)
-		}
-	case "total_commands_processed":
-		if val, err := strconv.ParseFloat(value, 64); err == nil {
-			// 这是累计值，需要计算增量
-			// 这里简化处理，直接设置为当前值
-			RedisInstanceCommandsProcessed.WithLabelValues(rc.namespace, rc.name, rc.role).Add(val)
-		}
-	case "keyspace_hits":
-		if val, err := strconv.ParseFloat(value, 64); err == nil {
-			RedisInstanceKeyspaceHits.WithLabelValues(rc.namespace, rc.name, rc.role).Add(val)
-		}
-	case "keyspace_misses":
-		if val, err := strconv.ParseFloat(value, 64); err == nil {
-			RedisInstanceKeyspaceMisses.WithLabelValues(rc.namespace, rc.name, rc.role).Add(val)
-		}
+// SetRoleHint 设置从资源状态得到的角色，节点不可用时告警规则仍能按角色匹配；已从 INFO 读取到角色时忽略
+func (rc *RedisCollector) SetRoleHint(role string) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if !rc.roleObserved {
+		rc.role = role
 	}
 }
 
+// inheritLabels 沿用被替换的收集器最后已知的角色和分片标签
+func (rc *RedisCollector) inheritLabels(previous *RedisCollector) {
+	previous.mu.Lock()
+	role, shard := previous.role, previous.shard
+	previous.mu.Unlock()
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.role, rc.shard = role, shard
+}
+
+// Describe 实现 prometheus.Collector
+func (rc *RedisCollector) Describe(ch chan<- *prometheus.Desc) {
+	describeRedisInfo(ch)
+}
+
+// Collect 实现 prometheus.Collector，输出最近一次收集的结果
+func (rc *RedisCollector) Collect(ch chan<- prometheus.Metric) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if !rc.collected {
+		return
+	}
+
+	labels := []string{rc.namespace, rc.name, rc.pod, rc.shard, rc.role}
+	ch <- prometheus.MustNewConstMetric(redisInstanceStatusDesc, prometheus.GaugeValue, boolToFloat(rc.info != nil), labels...)
+	if rc.info != nil {
+		collectRedisInfo(ch, rc.info, labels, time.Now())
+	}
+}
+
+// DeleteMetrics 清除收集结果，之后不再输出该 Pod 的指标
+func (rc *RedisCollector) DeleteMetrics() {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.collected = false
+	rc.info = nil
+}
+
 // Close 关闭 Redis 连接
 func (rc *RedisCollector) Close() error {
 	return rc.client.Close()
@@ -114,21 +172,42 @@ func (rc *RedisCollector) Close() error {
 // SentinelCollector 用于收集 Redis Sentinel 的指标
 type SentinelCollector struct {
 	client    *redis.SentinelClient
+	addr      string
 	namespace string
 	name      string
+
+	// configEpochs 每个 master 上次采集到的 config-epoch，每次故障转移 config-epoch 递增
+	configEpochs map[string]int64
 }
 
 // NewSentinelCollector 创建新的 Sentinel 指标收集器
 func NewSentinelCollector(addrs []string, namespace, name string) *SentinelCollector {
 	client := redis.NewSentinelClient(&redis.Options{
-		Addr: addrs[0], // 使用第一个地址
+		Addr:                  addrs[0], // 使用第一个地址
+		ContextTimeoutEnabled: true,
 	})
 
 	return &SentinelCollector{
-		client:    client,
-		namespace: namespace,
-		name:      name,
+		client:       client,
+		addr:         addrs[0],
+		namespace:    namespace,
+		name:         name,
+		configEpochs: map[string]int64{},
+	}
+}
+
+// WithTLS 使用 TLS 连接 Sentinel，tlsConfig 为 nil 时保持明文连接
+func (sc *SentinelCollector) WithTLS(tlsConfig *tls.Config) *SentinelCollector {
+	if tlsConfig == nil {
+		return sc
 	}
+	_ = sc.client.Close()
+	sc.client = redis.NewSentinelClient(&redis.Options{
+		Addr:                  sc.addr,
+		TLSConfig:             tlsConfig,
+		ContextTimeoutEnabled: true,
+	})
+	return sc
 }
 
 // CollectMetrics 收集 Sentinel 指标
@@ -186,11 +265,9 @@ func (sc *SentinelCollector) CollectMetrics(ctx context.Context) error {
 			}
 		}
 
-		// 获取故障转移次数（从 num-other-sentinels 字段推断）
-		if numFailovers, exists := masterMap["num-other-sentinels"]; exists {
-			if val, err := strconv.ParseFloat(numFailovers, 64); err == nil {
-				// 这里简化处理，实际应该跟踪增量
-				RedisSentinelFailovers.WithLabelValues(sc.namespace, sc.name, masterName).Add(val)
+		if epoch, err := strconv.ParseInt(masterMap["config-epoch"], 10, 64); err == nil {
+			if failovers := sc.observeConfigEpoch(masterName, epoch); failovers > 0 {
+				RedisSentinelFailovers.WithLabelValues(sc.namespace, sc.name, masterName).Add(float64(failovers))
 			}
 		}
 	}
@@ -198,30 +275,81 @@ func (sc *SentinelCollector) CollectMetrics(ctx context.Context) error {
 	return nil
 }
 
+// observeConfigEpoch 记录 master 的 config-epoch，返回与上次采集相比增加的故障转移次数
+// 首次采集只记录基线，config-epoch 变小（master 被移除后重新监控）时重新记录基线
+func (sc *SentinelCollector) observeConfigEpoch(masterName string, epoch int64) int64 {
+	last, seen := sc.configEpochs[masterName]
+	sc.configEpochs[masterName] = epoch
+	if !seen || epoch < last {
+		return 0
+	}
+	return epoch - last
+}
+
+// DeleteMetrics 删除 Sentinel 及其监控的 master 的指标序列
+func (sc *SentinelCollector) DeleteMetrics() {
+	labels := prometheus.Labels{"namespace": sc.namespace, "name": sc.name}
+	RedisSentinelMasters.Delete(labels)
+	RedisSentinelSentinels.DeletePartialMatch(labels)
+	RedisSentinelFailovers.DeletePartialMatch(labels)
+	RedisSentinelMasterStatus.DeletePartialMatch(labels)
+}
+
 // Close 关闭 Sentinel 连接
 func (sc *SentinelCollector) Close() error {
 	return sc.client.Close()
 }
 
-// ClusterCollector 用于收集 Redis Cluster 的指标
+// ClusterCollector 用于收集 Redis Cluster 的拓扑指标
+// CollectMetrics 周期读取 CLUSTER NODES、CLUSTER INFO 和各 master 的 INFO，Prometheus 抓取时由 Collect 输出
 type ClusterCollector struct {
 	client    *redis.ClusterClient
 	namespace string
 	name      string
+
+	mu sync.Mutex
+	// desiredReplicas RedisCluster 期望的每个 master 的副本数，小于 0 时未知
+	desiredReplicas int32
+	collected       bool
+	nodes           []utils.ClusterNode
+	info            map[string]string
+	// masterInfo 以 master 地址为键的 INFO memory 和 keyspace 字段
+	masterInfo map[string]map[string]string
 }
 
 // NewClusterCollector 创建新的 Cluster 指标收集器
 func NewClusterCollector(addrs []string, password, namespace, name string) *ClusterCollector {
 	client := redis.NewClusterClient(&redis.ClusterOptions{
-		Addrs:    addrs,
-		Password: password,
+		Addrs:                 addrs,
+		Password:              password,
+		ContextTimeoutEnabled: true,
 	})
 
 	return &ClusterCollector{
-		client:    client,
-		namespace: namespace,
-		name:      name,
+		client:          client,
+		namespace:       namespace,
+		name:            name,
+		desiredReplicas: -1,
+	}
+}
+
+// WithTLS 使用 TLS 连接 Redis Cluster，tlsConfig 为 nil 时保持明文连接
+func (cc *ClusterCollector) WithTLS(tlsConfig *tls.Config) *ClusterCollector {
+	if tlsConfig == nil {
+		return cc
 	}
+	options := cc.client.Options()
+	options.TLSConfig = tlsConfig
+	_ = cc.client.Close()
+	cc.client = redis.NewClusterClient(options)
+	return cc
+}
+
+// SetDesiredReplicas 设置 RedisCluster 期望的每个 master 的副本数
+func (cc *ClusterCollector) SetDesiredReplicas(replicas int32) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	cc.desiredReplicas = replicas
 }
 
 // CollectMetrics 收集 Cluster 指标
@@ -229,30 +357,12 @@ func (cc *ClusterCollector) CollectMetrics(ctx context.Context) error {
 	logger := log.FromContext(ctx)
 
 	// 获取集群节点信息
-	nodes, err := cc.client.ClusterNodes(ctx).Result()
+	output, err := cc.client.ClusterNodes(ctx).Result()
 	if err != nil {
 		logger.Error(err, "Failed to get cluster nodes")
 		return err
 	}
-
-	// 解析节点信息
-	nodeLines := strings.Split(strings.TrimSpace(nodes), "\n")
-	nodeCount := len(nodeLines)
-	SetRedisClusterNodes(cc.namespace, cc.name, float64(nodeCount))
-
-	// 获取集群槽位信息
-	slots, err := cc.client.ClusterSlots(ctx).Result()
-	if err != nil {
-		logger.Error(err, "Failed to get cluster slots")
-		return err
-	}
-
-	// 计算已分配的槽位数
-	assignedSlots := 0
-	for _, slot := range slots {
-		assignedSlots += int(slot.End - slot.Start + 1)
-	}
-	SetRedisClusterSlotsAssigned(cc.namespace, cc.name, float64(assignedSlots))
+	nodes := utils.ParseClusterNodes(output)
 
 	// 获取集群状态
 	info, err := cc.client.ClusterInfo(ctx).Result()
@@ -261,123 +371,57 @@ func (cc *ClusterCollector) CollectMetrics(ctx context.Context) error {
 		return err
 	}
 
-	// 解析集群状态
-	if strings.Contains(info, "cluster_state:ok") {
-		SetRedisClusterState(cc.namespace, cc.name, 1) // ok
-	} else {
-		SetRedisClusterState(cc.namespace, cc.name, 0) // fail
+	// 读取每个 master 的内存和键数量，单个 master 失败时只缺少该分片的指标
+	var mu sync.Mutex
+	masterInfo := map[string]map[string]string{}
+	err = cc.client.ForEachMaster(ctx, func(ctx context.Context, master *redis.Client) error {
+		fields, err := master.Info(ctx, "memory", "keyspace").Result()
+		if err != nil {
+			logger.Error(err, "Failed to get master info", "address", master.Options().Addr)
+			return nil
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		masterInfo[master.Options().Addr] = utils.ParseInfo(fields)
+		return nil
+	})
+	if err != nil {
+		logger.Error(err, "Failed to get master info")
 	}
 
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	cc.collected = true
+	cc.nodes = nodes
+	cc.info = utils.ParseInfo(info)
+	cc.masterInfo = masterInfo
 	return nil
 }
 
-// Close 关闭 Cluster 连接
-func (cc *ClusterCollector) Close() error {
-	return cc.client.Close()
-}
-
-// MetricsCollectionManager 管理所有指标收集器
-type MetricsCollectionManager struct {
-	redisCollectors    []*RedisCollector
-	sentinelCollectors []*SentinelCollector
-	clusterCollectors  []*ClusterCollector
-	collectionInterval time.Duration
-	stopCh             chan struct{}
+// Describe 实现 prometheus.Collector
+func (cc *ClusterCollector) Describe(ch chan<- *prometheus.Desc) {
+	describeClusterInfo(ch)
 }
 
-// NewMetricsCollectionManager 创建新的指标收集管理器
-func NewMetricsCollectionManager(interval time.Duration) *MetricsCollectionManager {
-	return &MetricsCollectionManager{
-		redisCollectors:    make([]*RedisCollector, 0),
-		sentinelCollectors: make([]*SentinelCollector, 0),
-		clusterCollectors:  make([]*ClusterCollector, 0),
-		collectionInterval: interval,
-		stopCh:             make(chan struct{}),
+// Collect 实现 prometheus.Collector，输出最近一次收集的结果
+func (cc *ClusterCollector) Collect(ch chan<- prometheus.Metric) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	if !cc.collected {
+		return
 	}
+	collectClusterInfo(ch, cc.namespace, cc.name, cc.nodes, cc.info, cc.masterInfo, cc.desiredReplicas)
 }
 
-// AddRedisCollector 添加 Redis 收集器
-func (mcm *MetricsCollectionManager) AddRedisCollector(collector *RedisCollector) {
-	mcm.redisCollectors = append(mcm.redisCollectors, collector)
-}
-
-// AddSentinelCollector 添加 Sentinel 收集器
-func (mcm *MetricsCollectionManager) AddSentinelCollector(collector *SentinelCollector) {
-	mcm.sentinelCollectors = append(mcm.sentinelCollectors, collector)
+// DeleteMetrics 清除收集结果，之后不再输出该集群的指标
+func (cc *ClusterCollector) DeleteMetrics() {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	cc.collected = false
+	cc.nodes, cc.info, cc.masterInfo = nil, nil, nil
 }
 
-// AddClusterCollector 添加 Cluster 收集器
-func (mcm *MetricsCollectionManager) AddClusterCollector(collector *ClusterCollector) {
-	mcm.clusterCollectors = append(mcm.clusterCollectors, collector)
-}
-
-// Start 启动指标收集
-func (mcm *MetricsCollectionManager) Start(ctx context.Context) {
-	ticker := time.NewTicker(mcm.collectionInterval)
-	defer ticker.Stop()
-
-	logger := log.FromContext(ctx)
-	logger.Info("Starting metrics collection", "interval", mcm.collectionInterval)
-
-	for {
-		select {
-		case <-ticker.C:
-			mcm.collectAllMetrics(ctx)
-		case <-mcm.stopCh:
-			logger.Info("Stopping metrics collection")
-			return
-		case <-ctx.Done():
-			logger.Info("Context cancelled, stopping metrics collection")
-			return
-		}
-	}
-}
-
-// collectAllMetrics 收集所有指标
-func (mcm *MetricsCollectionManager) collectAllMetrics(ctx context.Context) {
-	logger := log.FromContext(ctx)
-
-	// 收集 Redis 实例指标
-	for _, collector := range mcm.redisCollectors {
-		if err := collector.CollectMetrics(ctx); err != nil {
-			logger.Error(err, "Failed to collect Redis metrics",
-				"namespace", collector.namespace,
-				"name", collector.name,
-				"role", collector.role)
-		}
-	}
-
-	// 收集 Sentinel 指标
-	for _, collector := range mcm.sentinelCollectors {
-		if err := collector.CollectMetrics(ctx); err != nil {
-			logger.Error(err, "Failed to collect Sentinel metrics",
-				"namespace", collector.namespace,
-				"name", collector.name)
-		}
-	}
-
-	// 收集 Cluster 指标
-	for _, collector := range mcm.clusterCollectors {
-		if err := collector.CollectMetrics(ctx); err != nil {
-			logger.Error(err, "Failed to collect Cluster metrics",
-				"namespace", collector.namespace,
-				"name", collector.name)
-		}
-	}
-}
-
-// Stop 停止指标收集
-func (mcm *MetricsCollectionManager) Stop() {
-	close(mcm.stopCh)
-
-	// 关闭所有收集器
-	for _, collector := range mcm.redisCollectors {
-		collector.Close()
-	}
-	for _, collector := range mcm.sentinelCollectors {
-		collector.Close()
-	}
-	for _, collector := range mcm.clusterCollectors {
-		collector.Close()
-	}
+// Close 关闭 Cluster 连接
+func (cc *ClusterCollector) Close() error {
+	return cc.client.Close()
 }
@@ -0,0 +1,197 @@
+package metrics
+
+import (
+	"net"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// instanceLabels Redis 节点指标的公共标签
+var instanceLabels = []string{"namespace", "name", "pod", "shard", "role"}
+
+func newInstanceDesc(name, help string, extraLabels ...string) *prometheus.Desc {
+	return prometheus.NewDesc(name, help, slices.Concat(instanceLabels, extraLabels), nil)
+}
+
+// infoMetric 由单个 INFO 数值字段直接转换的指标
+type infoMetric struct {
+	field     string
+	desc      *prometheus.Desc
+	valueType prometheus.ValueType
+}
+
+// infoStatusMetric 由 INFO 状态字段转换的指标，字段等于 ok 时为 1，否则为 0
+type infoStatusMetric struct {
+	field string
+	ok    string
+	desc  *prometheus.Desc
+}
+
+var (
+	redisInstanceStatusDesc = newInstanceDesc("redis_instance_status", "Redis instance status (0=down, 1=up)")
+
+	// infoMetrics INFO 数值字段与指标的对应关系，计数器直接使用 Redis 自身的累计值
+	infoMetrics = []infoMetric{
+		// 内存
+		{"used_memory", newInstanceDesc("redis_instance_memory_usage_bytes", "Redis instance memory usage in bytes"), prometheus.GaugeValue},
+		{"used_memory_rss", newInstanceDesc("redis_instance_memory_rss_bytes", "Memory allocated by the operating system in bytes"), prometheus.GaugeValue},
+		{"used_memory_peak", newInstanceDesc("redis_instance_memory_peak_bytes", "Peak memory consumed in bytes"), prometheus.GaugeValue},
+		{"mem_fragmentation_ratio", newInstanceDesc("redis_instance_memory_fragmentation_ratio", "Ratio between RSS and used memory"), prometheus.GaugeValue},
+		{"maxmemory", newInstanceDesc("redis_instance_memory_max_bytes", "Value of the maxmemory directive in bytes"), prometheus.GaugeValue},
+		// 客户端
+		{"connected_clients", newInstanceDesc("redis_instance_connected_clients", "Number of connected clients"), prometheus.GaugeValue},
+		{"blocked_clients", newInstanceDesc("redis_instance_blocked_clients", "Number of clients pending on a blocking call"), prometheus.GaugeValue},
+		{"total_connections_received", newInstanceDesc("redis_instance_connections_received_total", "Total number of connections accepted"), prometheus.CounterValue},
+		{"rejected_connections", newInstanceDesc("redis_instance_rejected_connections_total", "Total number of connections rejected because of maxclients"), prometheus.CounterValue},
+		// 命令和键空间
+		{"total_commands_processed", newInstanceDesc("redis_instance_commands_processed_total", "Total number of commands processed"), prometheus.CounterValue},
+		{"keyspace_hits", newInstanceDesc("redis_instance_keyspace_hits_total", "Total number of keyspace hits"), prometheus.CounterValue},
+		{"keyspace_misses", newInstanceDesc("redis_instance_keyspace_misses_total", "Total number of keyspace misses"), prometheus.CounterValue},
+		{"expired_keys", newInstanceDesc("redis_instance_expired_keys_total", "Total number of key expiration events"), prometheus.CounterValue},
+		{"evicted_keys", newInstanceDesc("redis_instance_evicted_keys_total", "Total number of keys evicted because of maxmemory"), prometheus.CounterValue},
+		// 持久化
+		{"rdb_changes_since_last_save", newInstanceDesc("redis_instance_rdb_changes_since_last_save", "Number of changes since the last RDB save"), prometheus.GaugeValue},
+		{"rdb_bgsave_in_progress", newInstanceDesc("redis_instance_rdb_bgsave_in_progress", "Whether an RDB save is in progress"), prometheus.GaugeValue},
+		{"aof_enabled", newInstanceDesc("redis_instance_aof_enabled", "Whether AOF persistence is enabled"), prometheus.GaugeValue},
+		{"aof_rewrite_in_progress", newInstanceDesc("redis_instance_aof_rewrite_in_progress", "Whether an AOF rewrite is in progress"), prometheus.GaugeValue},
+		// 复制
+		{"connected_slaves", newInstanceDesc("redis_instance_connected_replicas", "Number of connected replicas"), prometheus.GaugeValue},
+		{"master_repl_offset", newInstanceDesc("redis_instance_master_repl_offset", "Replication offset of the master"), prometheus.GaugeValue},
+		{"slave_repl_offset", newInstanceDesc("redis_instance_replica_repl_offset", "Replication offset processed by the replica"), prometheus.GaugeValue},
+		{"master_last_io_seconds_ago", newInstanceDesc("redis_instance_master_last_io_seconds", "Seconds since the replica last interacted with its master"), prometheus.GaugeValue},
+	}
+
+	// infoStatusMetrics INFO 状态字段与指标的对应关系
+	infoStatusMetrics = []infoStatusMetric{
+		{"rdb_last_bgsave_status", "ok", newInstanceDesc("redis_instance_rdb_last_bgsave_success", "Whether the last RDB save succeeded")},
+		{"aof_last_bgrewrite_status", "ok", newInstanceDesc("redis_instance_aof_last_bgrewrite_success", "Whether the last AOF rewrite succeeded")},
+		{"aof_last_write_status", "ok", newInstanceDesc("redis_instance_aof_last_write_success", "Whether the last AOF write succeeded")},
+		{"master_link_status", "up", newInstanceDesc("redis_instance_master_link_up", "Whether the replica is connected to its master")},
+	}
+
+	rdbLastSaveAgeDesc = newInstanceDesc("redis_instance_rdb_last_save_age_seconds", "Seconds since the last successful RDB save")
+
+	// INFO commandstats 和 latencystats
+	commandCallsDesc    = newInstanceDesc("redis_instance_command_calls_total", "Total number of calls per command", "cmd")
+	commandDurationDesc = newInstanceDesc("redis_instance_command_duration_seconds_total", "Total time spent executing each command in seconds", "cmd")
+	commandRejectedDesc = newInstanceDesc("redis_instance_command_rejected_calls_total", "Total number of rejected calls per command", "cmd")
+	commandFailedDesc   = newInstanceDesc("redis_instance_command_failed_calls_total", "Total number of failed calls per command", "cmd")
+	commandLatencyDesc  = newInstanceDesc("redis_instance_command_latency_seconds", "Command latency percentiles reported by INFO latencystats", "cmd", "quantile")
+
+	// INFO keyspace
+	dbKeysDesc         = newInstanceDesc("redis_instance_db_keys", "Number of keys per database", "db")
+	dbKeysExpiringDesc = newInstanceDesc("redis_instance_db_keys_expiring", "Number of keys with an expiration per database", "db")
+	dbAvgTTLDesc       = newInstanceDesc("redis_instance_db_avg_ttl_seconds", "Average TTL of keys with an expiration per database", "db")
+
+	// INFO replication 中 master 记录的每个副本
+	replicaLagDesc       = newInstanceDesc("redis_instance_replica_lag_seconds", "Seconds since the last ack from each connected replica", "replica")
+	replicaOffsetLagDesc = newInstanceDesc("redis_instance_replica_offset_lag_bytes", "Replication offset difference between the master and each connected replica", "replica")
+
+	dbFieldPattern      = regexp.MustCompile(`^db\d+$`)
+	replicaFieldPattern = regexp.MustCompile(`^slave\d+$`)
+)
+
+// describeRedisInfo 输出 Redis 节点指标的描述
+func describeRedisInfo(ch chan<- *prometheus.Desc) {
+	ch <- redisInstanceStatusDesc
+	for _, metric := range infoMetrics {
+		ch <- metric.desc
+	}
+	for _, metric := range infoStatusMetrics {
+		ch <- metric.desc
+	}
+	for _, desc := range []*prometheus.Desc{
+		rdbLastSaveAgeDesc,
+		commandCallsDesc, commandDurationDesc, commandRejectedDesc, commandFailedDesc, commandLatencyDesc,
+		dbKeysDesc, dbKeysExpiringDesc, dbAvgTTLDesc,
+		replicaLagDesc, replicaOffsetLagDesc,
+	} {
+		ch <- desc
+	}
+}
+
+// collectRedisInfo 将 INFO all 的字段转换为常量指标，labels 为 instanceLabels 对应的取值
+func collectRedisInfo(ch chan<- prometheus.Metric, info map[string]string, labels []string, now time.Time) {
+	for _, metric := range infoMetrics {
+		emitInfoValue(ch, metric.desc, metric.valueType, info[metric.field], 1, labels)
+	}
+	for _, metric := range infoStatusMetrics {
+		if value, ok := info[metric.field]; ok {
+			ch <- prometheus.MustNewConstMetric(metric.desc, prometheus.GaugeValue, boolToFloat(value == metric.ok), labels...)
+		}
+	}
+	if lastSave, err := strconv.ParseInt(info["rdb_last_save_time"], 10, 64); err == nil && lastSave > 0 {
+		ch <- prometheus.MustNewConstMetric(rdbLastSaveAgeDesc, prometheus.GaugeValue, now.Sub(time.Unix(lastSave, 0)).Seconds(), labels...)
+	}
+
+	masterOffset, masterOffsetErr := strconv.ParseFloat(info["master_repl_offset"], 64)
+	for key, value := range info {
+		switch {
+		case strings.HasPrefix(key, "cmdstat_"):
+			// cmdstat_get:calls=10,usec=20,usec_per_call=2.00,rejected_calls=0,failed_calls=0
+			cmd := strings.TrimPrefix(key, "cmdstat_")
+			stats := parseInfoValues(value)
+			emitInfoValue(ch, commandCallsDesc, prometheus.CounterValue, stats["calls"], 1, labels, cmd)
+			emitInfoValue(ch, commandDurationDesc, prometheus.CounterValue, stats["usec"], 1e-6, labels, cmd)
+			emitInfoValue(ch, commandRejectedDesc, prometheus.CounterValue, stats["rejected_calls"], 1, labels, cmd)
+			emitInfoValue(ch, commandFailedDesc, prometheus.CounterValue, stats["failed_calls"], 1, labels, cmd)
+		case strings.HasPrefix(key, "latency_percentiles_usec_"):
+			// latency_percentiles_usec_get:p50=1.003,p99=3.007,p99.9=10.047
+			cmd := strings.TrimPrefix(key, "latency_percentiles_usec_")
+			for percentile, latency := range parseInfoValues(value) {
+				p, err := strconv.ParseFloat(strings.TrimPrefix(percentile, "p"), 64)
+				if err != nil {
+					continue
+				}
+				quantile := strconv.FormatFloat(p/100, 'g', 6, 64)
+				emitInfoValue(ch, commandLatencyDesc, prometheus.GaugeValue, latency, 1e-6, labels, cmd, quantile)
+			}
+		case dbFieldPattern.MatchString(key):
+			// db0:keys=1,expires=0,avg_ttl=0
+			stats := parseInfoValues(value)
+			emitInfoValue(ch, dbKeysDesc, prometheus.GaugeValue, stats["keys"], 1, labels, key)
+			emitInfoValue(ch, dbKeysExpiringDesc, prometheus.GaugeValue, stats["expires"], 1, labels, key)
+			emitInfoValue(ch, dbAvgTTLDesc, prometheus.GaugeValue, stats["avg_ttl"], 1e-3, labels, key)
+		case replicaFieldPattern.MatchString(key):
+			// slave0:ip=10.0.0.2,port=6379,state=online,offset=100,lag=0
+			stats := parseInfoValues(value)
+			replica := net.JoinHostPort(stats["ip"], stats["port"])
+			emitInfoValue(ch, replicaLagDesc, prometheus.GaugeValue, stats["lag"], 1, labels, replica)
+			if offset, err := strconv.ParseFloat(stats["offset"], 64); err == nil && masterOffsetErr == nil {
+				ch <- prometheus.MustNewConstMetric(replicaOffsetLagDesc, prometheus.GaugeValue, masterOffset-offset, slices.Concat(labels, []string{replica})...)
+			}
+		}
+	}
+}
+
+// emitInfoValue 解析 INFO 中的数值并乘以 scale 输出指标，无法解析时跳过
+func emitInfoValue(ch chan<- prometheus.Metric, desc *prometheus.Desc, valueType prometheus.ValueType, raw string, scale float64, labels []string, extraLabels ...string) {
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(desc, valueType, value*scale, slices.Concat(labels, extraLabels)...)
+}
+
+// parseInfoValues 解析 INFO 字段中以逗号分隔的 key=value 列表
+func parseInfoValues(value string) map[string]string {
+	values := map[string]string{}
+	for _, item := range strings.Split(value, ",") {
+		if key, val, found := strings.Cut(item, "="); found {
+			values[key] = val
+		}
+	}
+	return values
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,270 @@
+package metrics
+
+import (
+	"context"
+	"crypto/tls"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Collector 由 MetricsCollectionManager 周期调用的指标收集器
+type Collector interface {
+	// CollectMetrics 收集一次指标
+	CollectMetrics(ctx context.Context) error
+	// DeleteMetrics 删除收集器写入的指标序列
+	DeleteMetrics()
+	// Close 关闭连接
+	Close() error
+}
+
+// CollectorKey 标识一个收集器，同一资源的多个收集器通过 Pod 区分
+type CollectorKey struct {
+	// UID 所属自定义资源的 UID，资源删除后按 UID 回收收集器
+	UID types.UID
+	// Pod 采集的 Pod 名称，通过 Service 采集时为 Service 名称
+	Pod string
+}
+
+// Endpoint 收集器的连接参数，变化时重建收集器
+type Endpoint struct {
+	Addrs     []string
+	Password  string
+	TLSConfig *tls.Config
+}
+
+// Equal 比较连接参数，TLS 配置按证书和校验选项比较，每次协调重新加载的相同配置视为相等
+func (e Endpoint) Equal(other Endpoint) bool {
+	return slices.Equal(e.Addrs, other.Addrs) && e.Password == other.Password && sameTLSConfig(e.TLSConfig, other.TLSConfig)
+}
+
+func sameTLSConfig(a, b *tls.Config) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if a.ServerName != b.ServerName || a.InsecureSkipVerify != b.InsecureSkipVerify || len(a.Certificates) != len(b.Certificates) {
+		return false
+	}
+	if (a.RootCAs == nil) != (b.RootCAs == nil) || a.RootCAs != nil && !a.RootCAs.Equal(b.RootCAs) {
+		return false
+	}
+	for i := range a.Certificates {
+		if !slices.EqualFunc(a.Certificates[i].Certificate, b.Certificates[i].Certificate, slices.Equal[[]byte]) {
+			return false
+		}
+	}
+	return true
+}
+
+// registeredCollector 已注册的收集器及创建它时使用的连接参数
+type registeredCollector struct {
+	collector Collector
+	endpoint  Endpoint
+}
+
+// MetricsCollectionManager 管理所有指标收集器，可被多个控制器并发调用
+// 同时实现 prometheus.Collector，注册后在抓取时输出 Redis 节点和集群指标
+type MetricsCollectionManager struct {
+	mu                 sync.Mutex
+	collectors         map[CollectorKey]*registeredCollector
+	collectionInterval time.Duration
+	// collectTimeout 单个收集器每轮收集的超时时间
+	collectTimeout time.Duration
+	stopCh         chan struct{}
+	stopOnce       sync.Once
+}
+
+// defaultCollectTimeout 单个收集器的默认收集超时时间
+const defaultCollectTimeout = 10 * time.Second
+
+// NewMetricsCollectionManager 创建新的指标收集管理器，单个收集器的超时不超过收集间隔
+func NewMetricsCollectionManager(interval time.Duration) *MetricsCollectionManager {
+	return &MetricsCollectionManager{
+		collectors:         make(map[CollectorKey]*registeredCollector),
+		collectionInterval: interval,
+		collectTimeout:     min(interval, defaultCollectTimeout),
+		stopCh:             make(chan struct{}),
+	}
+}
+
+// SyncRedisCollector 确保 key 对应 Pod 的 Redis 收集器存在且使用最新的连接参数
+// Pod IP 变化重建收集器时沿用旧收集器的角色和分片标签；role 为控制器从资源状态得到的角色，
+// 在收集器首次成功读取 INFO 之前使用，为空时保持原有角色
+func (mcm *MetricsCollectionManager) SyncRedisCollector(key CollectorKey, endpoint Endpoint, namespace, name, role string) {
+	collector := mcm.sync(key, endpoint, func(previous Collector) Collector {
+		collector := NewRedisCollector(endpoint.Addrs[0], endpoint.Password, namespace, name, key.Pod).WithTLS(endpoint.TLSConfig)
+		if previous, ok := previous.(*RedisCollector); ok {
+			collector.inheritLabels(previous)
+		}
+		return collector
+	})
+	if redisCollector, ok := collector.(*RedisCollector); ok && role != "" {
+		redisCollector.SetRoleHint(role)
+	}
+}
+
+// SyncSentinelCollector 确保 key 对应的 Sentinel 收集器存在且使用最新的连接参数
+func (mcm *MetricsCollectionManager) SyncSentinelCollector(key CollectorKey, endpoint Endpoint, namespace, name string) {
+	mcm.sync(key, endpoint, func(Collector) Collector {
+		return NewSentinelCollector(endpoint.Addrs, namespace, name).WithTLS(endpoint.TLSConfig)
+	})
+}
+
+// SyncClusterCollector 确保 key 对应的 Cluster 收集器存在且使用最新的连接参数
+// desiredReplicas 为每个 master 期望的副本数，用于分片副本数指标
+func (mcm *MetricsCollectionManager) SyncClusterCollector(key CollectorKey, endpoint Endpoint, namespace, name string, desiredReplicas int32) {
+	collector := mcm.sync(key, endpoint, func(Collector) Collector {
+		return NewClusterCollector(endpoint.Addrs, endpoint.Password, namespace, name).WithTLS(endpoint.TLSConfig)
+	})
+	if cluster, ok := collector.(*ClusterCollector); ok {
+		cluster.SetDesiredReplicas(desiredReplicas)
+	}
+}
+
+// sync 连接参数未变化时复用已有收集器，否则关闭旧收集器并创建新的收集器，返回当前的收集器
+// create 的参数为被替换的旧收集器，首次创建时为 nil
+func (mcm *MetricsCollectionManager) sync(key CollectorKey, endpoint Endpoint, create func(previous Collector) Collector) Collector {
+	if len(endpoint.Addrs) == 0 {
+		return nil
+	}
+
+	mcm.mu.Lock()
+	defer mcm.mu.Unlock()
+	var previous Collector
+	if existing, ok := mcm.collectors[key]; ok {
+		if existing.endpoint.Equal(endpoint) {
+			return existing.collector
+		}
+		_ = existing.collector.Close()
+		previous = existing.collector
+	}
+	collector := create(previous)
+	mcm.collectors[key] = &registeredCollector{collector: collector, endpoint: endpoint}
+	return collector
+}
+
+// RetainCollectors 删除资源下不在 pods 中的收集器，用于回收已缩容的 Pod
+func (mcm *MetricsCollectionManager) RetainCollectors(uid types.UID, pods ...string) {
+	mcm.mu.Lock()
+	defer mcm.mu.Unlock()
+	for key, registered := range mcm.collectors {
+		if key.UID == uid && !slices.Contains(pods, key.Pod) {
+			mcm.remove(key, registered)
+		}
+	}
+}
+
+// RemoveCollectors 关闭资源的所有收集器并删除它们写入的指标序列
+func (mcm *MetricsCollectionManager) RemoveCollectors(uid types.UID) {
+	mcm.RetainCollectors(uid)
+}
+
+// remove 调用方需持有 mcm.mu
+func (mcm *MetricsCollectionManager) remove(key CollectorKey, registered *registeredCollector) {
+	delete(mcm.collectors, key)
+	registered.collector.DeleteMetrics()
+	_ = registered.collector.Close()
+}
+
+// Len 返回已注册的收集器数量
+func (mcm *MetricsCollectionManager) Len() int {
+	mcm.mu.Lock()
+	defer mcm.mu.Unlock()
+	return len(mcm.collectors)
+}
+
+// Start 启动指标收集
+func (mcm *MetricsCollectionManager) Start(ctx context.Context) {
+	ticker := time.NewTicker(mcm.collectionInterval)
+	defer ticker.Stop()
+
+	logger := log.FromContext(ctx)
+	logger.Info("Starting metrics collection", "interval", mcm.collectionInterval)
+
+	for {
+		select {
+		case <-ticker.C:
+			mcm.collectAllMetrics(ctx)
+		case <-mcm.stopCh:
+			logger.Info("Stopping metrics collection")
+			return
+		case <-ctx.Done():
+			logger.Info("Context cancelled, stopping metrics collection")
+			return
+		}
+	}
+}
+
+// collectAllMetrics 并行收集所有指标，每个收集器单独超时，慢节点不会阻塞其他节点
+// 收集期间不持有锁，控制器可以并发注册和删除收集器
+func (mcm *MetricsCollectionManager) collectAllMetrics(ctx context.Context) {
+	logger := log.FromContext(ctx)
+
+	mcm.mu.Lock()
+	snapshot := make(map[CollectorKey]*registeredCollector, len(mcm.collectors))
+	for key, registered := range mcm.collectors {
+		snapshot[key] = registered
+	}
+	mcm.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for key, registered := range snapshot {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			collectCtx, cancel := context.WithTimeout(ctx, mcm.collectTimeout)
+			defer cancel()
+			if err := registered.collector.CollectMetrics(collectCtx); err != nil {
+				logger.Error(err, "Failed to collect metrics", "uid", key.UID, "pod", key.Pod)
+			}
+
+			// 收集期间资源被删除时，删除本次收集重新写入的指标序列
+			mcm.mu.Lock()
+			defer mcm.mu.Unlock()
+			if _, ok := mcm.collectors[key]; !ok {
+				registered.collector.DeleteMetrics()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// Describe 实现 prometheus.Collector
+func (mcm *MetricsCollectionManager) Describe(ch chan<- *prometheus.Desc) {
+	describeRedisInfo(ch)
+	describeClusterInfo(ch)
+}
+
+// Collect 实现 prometheus.Collector，在抓取时输出各收集器最近一次的收集结果
+func (mcm *MetricsCollectionManager) Collect(ch chan<- prometheus.Metric) {
+	mcm.mu.Lock()
+	collectors := make([]prometheus.Collector, 0, len(mcm.collectors))
+	for _, registered := range mcm.collectors {
+		if collector, ok := registered.collector.(prometheus.Collector); ok {
+			collectors = append(collectors, collector)
+		}
+	}
+	mcm.mu.Unlock()
+
+	for _, collector := range collectors {
+		collector.Collect(ch)
+	}
+}
+
+// Stop 停止指标收集并关闭所有收集器
+func (mcm *MetricsCollectionManager) Stop() {
+	mcm.stopOnce.Do(func() {
+		close(mcm.stopCh)
+	})
+
+	mcm.mu.Lock()
+	defer mcm.mu.Unlock()
+	for key, registered := range mcm.collectors {
+		delete(mcm.collectors, key)
+		_ = registered.collector.Close()
+	}
+}
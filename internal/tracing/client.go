@@ -0,0 +1,119 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// WrapClient 返回为每次 Kubernetes API 调用创建子 span 的客户端
+func WrapClient(c client.WithWatch) client.WithWatch {
+	return interceptor.NewClient(c, interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			ctx, span := startAPISpan(ctx, c, "get", obj, key.Namespace, key.Name)
+			err := c.Get(ctx, key, obj, opts...)
+			endAPISpan(span, err)
+			return err
+		},
+		List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+			listOpts := &client.ListOptions{}
+			listOpts.ApplyOptions(opts)
+			ctx, span := startAPISpan(ctx, c, "list", list, listOpts.Namespace, "")
+			err := c.List(ctx, list, opts...)
+			endAPISpan(span, err)
+			return err
+		},
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			ctx, span := startAPISpan(ctx, c, "create", obj, obj.GetNamespace(), obj.GetName())
+			err := c.Create(ctx, obj, opts...)
+			endAPISpan(span, err)
+			return err
+		},
+		Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			ctx, span := startAPISpan(ctx, c, "update", obj, obj.GetNamespace(), obj.GetName())
+			err := c.Update(ctx, obj, opts...)
+			endAPISpan(span, err)
+			return err
+		},
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			ctx, span := startAPISpan(ctx, c, "patch", obj, obj.GetNamespace(), obj.GetName())
+			err := c.Patch(ctx, obj, patch, opts...)
+			endAPISpan(span, err)
+			return err
+		},
+		Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+			ctx, span := startAPISpan(ctx, c, "delete", obj, obj.GetNamespace(), obj.GetName())
+			err := c.Delete(ctx, obj, opts...)
+			endAPISpan(span, err)
+			return err
+		},
+		DeleteAllOf: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteAllOfOption) error {
+			deleteOpts := &client.DeleteAllOfOptions{}
+			deleteOpts.ApplyOptions(opts)
+			ctx, span := startAPISpan(ctx, c, "deletecollection", obj, deleteOpts.Namespace, "")
+			err := c.DeleteAllOf(ctx, obj, opts...)
+			endAPISpan(span, err)
+			return err
+		},
+		SubResourceGet: func(ctx context.Context, c client.Client, subResource string, obj client.Object, subObj client.Object, opts ...client.SubResourceGetOption) error {
+			ctx, span := startAPISpan(ctx, c, "get/"+subResource, obj, obj.GetNamespace(), obj.GetName())
+			err := c.SubResource(subResource).Get(ctx, obj, subObj, opts...)
+			endAPISpan(span, err)
+			return err
+		},
+		SubResourceCreate: func(ctx context.Context, c client.Client, subResource string, obj client.Object, subObj client.Object, opts ...client.SubResourceCreateOption) error {
+			ctx, span := startAPISpan(ctx, c, "create/"+subResource, obj, obj.GetNamespace(), obj.GetName())
+			err := c.SubResource(subResource).Create(ctx, obj, subObj, opts...)
+			endAPISpan(span, err)
+			return err
+		},
+		SubResourceUpdate: func(ctx context.Context, c client.Client, subResource string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+			ctx, span := startAPISpan(ctx, c, "update/"+subResource, obj, obj.GetNamespace(), obj.GetName())
+			err := c.SubResource(subResource).Update(ctx, obj, opts...)
+			endAPISpan(span, err)
+			return err
+		},
+		SubResourcePatch: func(ctx context.Context, c client.Client, subResource string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+			ctx, span := startAPISpan(ctx, c, "patch/"+subResource, obj, obj.GetNamespace(), obj.GetName())
+			err := c.SubResource(subResource).Patch(ctx, obj, patch, opts...)
+			endAPISpan(span, err)
+			return err
+		},
+	})
+}
+
+// startAPISpan 创建 Kubernetes API 调用的子 span，名称为动作和资源类型，例如 "k8s update StatefulSet"
+func startAPISpan(ctx context.Context, c client.Client, verb string, obj runtime.Object, namespace, name string) (context.Context, trace.Span) {
+	kind := "Unknown"
+	if gvk, err := apiutil.GVKForObject(obj, c.Scheme()); err == nil {
+		kind = strings.TrimSuffix(gvk.Kind, "List")
+	}
+	attrs := []attribute.KeyValue{
+		attribute.String("k8s.verb", verb),
+		attribute.String("k8s.kind", kind),
+	}
+	if namespace != "" {
+		attrs = append(attrs, semconv.K8SNamespaceName(namespace))
+	}
+	if name != "" {
+		attrs = append(attrs, attribute.String("k8s.object.name", name))
+	}
+	return startClientSpan(ctx, "k8s "+verb+" "+kind, attrs...)
+}
+
+// endAPISpan 结束 Kubernetes API 调用的 span，资源不存在是协调中的正常情况，只作为属性记录
+func endAPISpan(span trace.Span, err error) {
+	if apierrors.IsNotFound(err) {
+		span.SetAttributes(attribute.Bool("k8s.not_found", true))
+		err = nil
+	}
+	EndSpan(span, err)
+}
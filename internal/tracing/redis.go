@@ -0,0 +1,89 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// redisContainerCommands 带子命令的 Redis 命令，span 名称包含子命令，例如 "redis CLUSTER MEET"
+var redisContainerCommands = map[string]bool{
+	"acl": true, "client": true, "cluster": true, "command": true, "config": true,
+	"debug": true, "function": true, "latency": true, "memory": true, "module": true,
+	"object": true, "script": true, "sentinel": true, "slowlog": true,
+}
+
+// InstrumentRedis 为 Redis 客户端添加 hook，每条命令记录为当前协调 span 的子 span
+// span 只记录命令名称和子命令，不记录参数，避免密码等敏感参数写入 trace
+func InstrumentRedis(c interface{ AddHook(redis.Hook) }, addr string) {
+	c.AddHook(redisHook{addr: addr})
+}
+
+// redisHook 为 Redis 命令创建 span 的 hook
+type redisHook struct {
+	addr string
+}
+
+// DialHook 实现 redis.Hook，建立连接不单独记录 span
+func (h redisHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+// ProcessHook 实现 redis.Hook
+func (h redisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		operation := redisOperation(cmd)
+		ctx, span := startClientSpan(ctx, "redis "+operation, h.attributes(operation)...)
+		err := next(ctx, cmd)
+		endRedisSpan(span, err)
+		return err
+	}
+}
+
+// ProcessPipelineHook 实现 redis.Hook，整个 pipeline 记录为一个 span
+func (h redisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		operations := make([]string, 0, len(cmds))
+		for _, cmd := range cmds {
+			operations = append(operations, redisOperation(cmd))
+		}
+		ctx, span := startClientSpan(ctx, "redis pipeline", h.attributes(strings.Join(operations, ", "))...)
+		err := next(ctx, cmds)
+		endRedisSpan(span, err)
+		return err
+	}
+}
+
+// attributes 返回 Redis 命令 span 的属性
+func (h redisHook) attributes(operation string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		semconv.DBSystemRedis,
+		semconv.DBOperationName(operation),
+		semconv.ServerAddress(h.addr),
+	}
+}
+
+// redisOperation 返回命令名称，带子命令的命令包含第一个参数
+func redisOperation(cmd redis.Cmder) string {
+	name := cmd.Name()
+	args := cmd.Args()
+	if redisContainerCommands[name] && len(args) > 1 {
+		if sub, ok := args[1].(string); ok {
+			return strings.ToUpper(name + " " + sub)
+		}
+	}
+	return strings.ToUpper(name)
+}
+
+// endRedisSpan 结束 Redis 命令的 span，key 不存在（redis.Nil）不视为错误
+func endRedisSpan(span trace.Span, err error) {
+	if errors.Is(err, redis.Nil) {
+		err = nil
+	}
+	EndSpan(span, err)
+}
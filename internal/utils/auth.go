@@ -0,0 +1,207 @@
+package utils
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	redisv1 "github.com/ybooks240/redis-operator/api/v1"
+)
+
+const (
+	// PasswordSecretKey 自动生成的密码 Secret 中保存密码的键
+	PasswordSecretKey = "password"
+	// AuthSecretAnnotation Pod 模板上记录当前使用的密码 Secret，变更时触发滚动更新
+	AuthSecretAnnotation = "redis.github.com/auth-secret"
+
+	authVolumeName    = "redis-auth"
+	authMountPath     = "/etc/redis-auth"
+	authFileName      = "password"
+	authIncludeFile   = "/tmp/redis-auth.conf"
+	generatedPassword = 32
+	passwordAlphabet  = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+)
+
+// PasswordSecretName 返回自动生成的密码 Secret 名称
+func PasswordSecretName(name string) string {
+	return name + "-redis-auth"
+}
+
+// ActivePasswordSecretName 返回记录 Redis 当前生效密码的 Secret 名称，密码轮换时用于连接尚未切换的节点
+func ActivePasswordSecretName(name string) string {
+	return name + "-redis-auth-active"
+}
+
+// PasswordSecretRef 返回工作负载使用的密码 Secret 引用，未启用认证时返回 nil
+// 未显式引用 Secret 时使用自动生成的 <name>-redis-auth
+func PasswordSecretRef(security redisv1.SecuritySpec, name string) *corev1.SecretKeySelector {
+	if !security.AuthEnabled {
+		return nil
+	}
+	if security.PasswordSecret != nil {
+		return security.PasswordSecret
+	}
+	return &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: PasswordSecretName(name)},
+		Key:                  PasswordSecretKey,
+	}
+}
+
+// GeneratePassword 使用加密随机数生成指定长度的字母数字密码
+func GeneratePassword(length int) (string, error) {
+	var builder strings.Builder
+	max := big.NewInt(int64(len(passwordAlphabet)))
+	for i := 0; i < length; i++ {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		builder.WriteByte(passwordAlphabet[n.Int64()])
+	}
+	return builder.String(), nil
+}
+
+// EnsurePasswordSecret 启用认证且未引用密码 Secret 时生成随机密码 Secret
+// 已存在的 Secret 不会被修改，避免重新生成密码导致客户端失效
+func EnsurePasswordSecret(ctx context.Context, c client.Client, scheme *runtime.Scheme, owner client.Object, security redisv1.SecuritySpec) error {
+	if !security.AuthEnabled || security.PasswordSecret != nil {
+		return nil
+	}
+
+	secret := &corev1.Secret{}
+	name := PasswordSecretName(owner.GetName())
+	err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: owner.GetNamespace()}, secret)
+	if err == nil || !errors.IsNotFound(err) {
+		return err
+	}
+
+	password, err := GeneratePassword(generatedPassword)
+	if err != nil {
+		return fmt.Errorf("failed to generate password: %w", err)
+	}
+	secret = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: owner.GetNamespace(),
+			Labels: map[string]string{
+				"app.kubernetes.io/instance":   owner.GetName(),
+				"app.kubernetes.io/managed-by": "redis-operator",
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			PasswordSecretKey: []byte(password),
+		},
+	}
+	if err := controllerutil.SetControllerReference(owner, secret, scheme); err != nil {
+		return err
+	}
+	return c.Create(ctx, secret)
+}
+
+// ReadPassword 读取工作负载的密码，未启用认证时返回空字符串
+func ReadPassword(ctx context.Context, c client.Client, namespace string, security redisv1.SecuritySpec, name string) (string, error) {
+	ref := PasswordSecretRef(security, name)
+	if ref == nil {
+		return "", nil
+	}
+	return ReadSecretValue(ctx, c, namespace, ref)
+}
+
+// ReadSecretValue 读取 Secret 中指定键的值
+// 与 authIncludeScript 一致去掉末尾的一个换行，使 operator 与 Redis 使用的密码相同
+func ReadSecretValue(ctx context.Context, c client.Client, namespace string, selector *corev1.SecretKeySelector) (string, error) {
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Name: selector.Name, Namespace: namespace}, secret); err != nil {
+		return "", err
+	}
+	value, ok := secret.Data[selector.Key]
+	if !ok {
+		return "", fmt.Errorf("key %s not found in secret %s/%s", selector.Key, namespace, selector.Name)
+	}
+	return strings.TrimSuffix(string(value), "\n"), nil
+}
+
+// ApplyAuth 为 Pod 模板中的 Redis 容器注入密码认证
+// 密码 Secret 以文件形式挂载，启动时生成包含 requirepass/masterauth 的临时配置并通过 include 加载，
+// 密码不会写入 ConfigMap；redis-cli 探针每次执行时从挂载文件读取密码，
+// 密码轮换后 kubelet 刷新文件即可生效，无需重启容器
+func ApplyAuth(template *corev1.PodTemplateSpec, containerName string, secretRef *corev1.SecretKeySelector) {
+	if secretRef == nil {
+		return
+	}
+
+	if template.Annotations == nil {
+		template.Annotations = map[string]string{}
+	}
+	template.Annotations[AuthSecretAnnotation] = secretRef.Name + "/" + secretRef.Key
+
+	template.Spec.Volumes = append(template.Spec.Volumes, corev1.Volume{
+		Name: authVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: secretRef.Name,
+				Items:      []corev1.KeyToPath{{Key: secretRef.Key, Path: authFileName}},
+			},
+		},
+	})
+
+	for i := range template.Spec.Containers {
+		container := &template.Spec.Containers[i]
+		if container.Name != containerName {
+			continue
+		}
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      authVolumeName,
+			MountPath: authMountPath,
+			ReadOnly:  true,
+		})
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name: "REDISCLI_AUTH",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: secretRef,
+			},
+		})
+		container.Command = authCommand(container.Command)
+		for _, probe := range []*corev1.Probe{container.ReadinessProbe, container.LivenessProbe} {
+			if probe == nil || probe.Exec == nil || len(probe.Exec.Command) == 0 || probe.Exec.Command[0] != "redis-cli" {
+				continue
+			}
+			probe.Exec.Command = []string{"sh", "-c", fmt.Sprintf(`REDISCLI_AUTH="$(cat %s/%s)" exec %s`,
+				authMountPath, authFileName, strings.Join(probe.Exec.Command, " "))}
+		}
+	}
+}
+
+// AuthSecretChanged 检查 Pod 模板当前使用的密码 Secret 是否与期望不一致
+func AuthSecretChanged(template *corev1.PodTemplateSpec, secretRef *corev1.SecretKeySelector) bool {
+	expected := ""
+	if secretRef != nil {
+		expected = secretRef.Name + "/" + secretRef.Key
+	}
+	return template.Annotations[AuthSecretAnnotation] != expected
+}
+
+// authCommand 将 redis-server 启动命令包装为先生成认证配置再启动的 shell 命令
+func authCommand(command []string) []string {
+	return []string{"sh", "-c", fmt.Sprintf(`%s && exec %s --include %s`,
+		authIncludeScript(authMountPath+"/"+authFileName, authIncludeFile), strings.Join(command, " "), authIncludeFile)}
+}
+
+// authIncludeScript 返回从 passwordFile 读取密码并写入 includeFile 的 shell 命令
+// 密码按 redis.conf 双引号字符串的语法转义反斜杠、双引号和换行，任意字符的密码都能原样加载
+func authIncludeScript(passwordFile, includeFile string) string {
+	return fmt.Sprintf(
+		`PASS="$(sed -e ':a' -e '$!N' -e '$!ba' -e 's/[\\"]/\\&/g' -e 's/\n/\\n/g' %s)" && printf 'requirepass "%%s"\nmasterauth "%%s"\n' "$PASS" "$PASS" > %s`,
+		passwordFile, includeFile)
+}
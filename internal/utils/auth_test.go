@@ -0,0 +1,81 @@
+/*
+Copyright 2025 James.Liu.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Auth", func() {
+	DescribeTable("writing the password into the redis.conf include",
+		func(content, password string) {
+			dir := GinkgoT().TempDir()
+			passwordFile := filepath.Join(dir, "password")
+			includeFile := filepath.Join(dir, "redis-auth.conf")
+			Expect(os.WriteFile(passwordFile, []byte(content), 0o600)).To(Succeed())
+
+			output, err := exec.Command("sh", "-c", authIncludeScript(passwordFile, includeFile)).CombinedOutput()
+			Expect(err).NotTo(HaveOccurred(), string(output))
+			include, err := os.ReadFile(includeFile)
+			Expect(err).NotTo(HaveOccurred())
+
+			// redis.conf 的双引号字符串与 Go 字符串字面量对 \\、\" 和 \n 的转义规则相同
+			lines := strings.Split(strings.TrimSuffix(string(include), "\n"), "\n")
+			Expect(lines).To(HaveLen(2))
+			for i, directive := range []string{"requirepass", "masterauth"} {
+				value, found := strings.CutPrefix(lines[i], directive+" ")
+				Expect(found).To(BeTrue(), lines[i])
+				Expect(strconv.Unquote(value)).To(Equal(password))
+			}
+		},
+		Entry("a plain password", "s3cret", "s3cret"),
+		Entry("quotes, backslashes and spaces", `p"a\ss 'w"d`, `p"a\ss 'w"d`),
+		Entry("a trailing backslash", `secret\`, `secret\`),
+		Entry("a shell expansion", `$(id) $HOME`, `$(id) $HOME`),
+		Entry("an embedded newline", "line1\nline2", "line1\nline2"),
+		Entry("the trailing newline of a file created with echo", "secret\n", "secret"),
+	)
+
+	DescribeTable("reading a password from a Secret",
+		func(content, password string) {
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "redis-auth", Namespace: "default"},
+				Data:       map[string][]byte{PasswordSecretKey: []byte(content)},
+			}
+			c := fake.NewClientBuilder().WithObjects(secret).Build()
+			selector := &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "redis-auth"},
+				Key:                  PasswordSecretKey,
+			}
+			Expect(ReadSecretValue(context.Background(), c, "default", selector)).To(Equal(password))
+		},
+		Entry("a plain password", "s3cret", "s3cret"),
+		Entry("an embedded newline", "line1\nline2", "line1\nline2"),
+		Entry("the trailing newline of a file created with echo", "secret\n", "secret"),
+	)
+})
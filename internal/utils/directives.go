@@ -0,0 +1,544 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DirectiveType redis.conf 指令值的类型
+type DirectiveType string
+
+const (
+	DirectiveBool            DirectiveType = "bool"
+	DirectiveInt             DirectiveType = "int"
+	DirectiveMemory          DirectiveType = "memory"
+	DirectiveMemoryOrPercent DirectiveType = "memory-or-percent"
+	DirectiveEnum            DirectiveType = "enum"
+	DirectiveSavePoints      DirectiveType = "save-points"
+	DirectiveString          DirectiveType = "string"
+)
+
+// RedisDirective 描述一条 redis.conf 指令
+type RedisDirective struct {
+	Name string
+	Type DirectiveType
+	// Values 枚举类型指令允许的取值
+	Values []string
+	// Bounded 为 true 时整数类型指令的取值范围为 [Min, Max]
+	Min, Max int64
+	Bounded  bool
+	// Runtime 指令是否可以通过 CONFIG SET 在线修改，无需重启
+	Runtime bool
+	// Since 提供该指令的最低主版本
+	Since int
+}
+
+// SupportedRedisMajorVersions 提供指令目录的 Redis 主版本，按升序排列
+var SupportedRedisMajorVersions = []int{6, 7}
+
+var (
+	memoryPattern  = regexp.MustCompile(`^(?i)\d+([kmg]b?|b)?$`)
+	percentPattern = regexp.MustCompile(`^\d+%$`)
+)
+
+func boolDirective(name string, runtime bool, since int) RedisDirective {
+	return RedisDirective{Name: name, Type: DirectiveBool, Runtime: runtime, Since: since}
+}
+
+func intDirective(name string, min, max int64, runtime bool, since int) RedisDirective {
+	return RedisDirective{Name: name, Type: DirectiveInt, Min: min, Max: max, Bounded: true, Runtime: runtime, Since: since}
+}
+
+func memoryDirective(name string, runtime bool, since int) RedisDirective {
+	return RedisDirective{Name: name, Type: DirectiveMemory, Runtime: runtime, Since: since}
+}
+
+func enumDirective(name string, runtime bool, since int, values ...string) RedisDirective {
+	return RedisDirective{Name: name, Type: DirectiveEnum, Values: values, Runtime: runtime, Since: since}
+}
+
+func stringDirective(name string, runtime bool, since int) RedisDirective {
+	return RedisDirective{Name: name, Type: DirectiveString, Runtime: runtime, Since: since}
+}
+
+const maxInt = int64(1<<63 - 1)
+
+// redisDirectives 按 redis.conf 的章节排列，slave 开头的旧名称作为别名保留
+var redisDirectives = []RedisDirective{
+	// 网络
+	stringDirective("bind", false, 6),
+	boolDirective("protected-mode", true, 6),
+	intDirective("port", 0, 65535, false, 6),
+	intDirective("tcp-backlog", 0, maxInt, false, 6),
+	stringDirective("unixsocket", false, 6),
+	intDirective("unixsocketperm", 0, 777, false, 6),
+	intDirective("timeout", 0, maxInt, true, 6),
+	intDirective("tcp-keepalive", 0, maxInt, true, 6),
+
+	// TLS
+	intDirective("tls-port", 0, 65535, false, 6),
+	stringDirective("tls-cert-file", true, 6),
+	stringDirective("tls-key-file", true, 6),
+	stringDirective("tls-key-file-pass", true, 6),
+	stringDirective("tls-client-cert-file", true, 7),
+	stringDirective("tls-client-key-file", true, 7),
+	stringDirective("tls-client-key-file-pass", true, 7),
+	stringDirective("tls-dh-params-file", true, 6),
+	stringDirective("tls-ca-cert-file", true, 6),
+	stringDirective("tls-ca-cert-dir", true, 6),
+	enumDirective("tls-auth-clients", true, 6, "yes", "no", "optional"),
+	boolDirective("tls-replication", true, 6),
+	boolDirective("tls-cluster", true, 6),
+	stringDirective("tls-protocols", true, 6),
+	stringDirective("tls-ciphers", true, 6),
+	stringDirective("tls-ciphersuites", true, 6),
+	boolDirective("tls-prefer-server-ciphers", true, 6),
+	boolDirective("tls-session-caching", true, 6),
+	intDirective("tls-session-cache-size", 0, maxInt, true, 6),
+	intDirective("tls-session-cache-timeout", 0, maxInt, true, 6),
+
+	// 通用
+	boolDirective("daemonize", false, 6),
+	enumDirective("supervised", false, 6, "no", "upstart", "systemd", "auto"),
+	stringDirective("pidfile", false, 6),
+	enumDirective("loglevel", true, 6, "debug", "verbose", "notice", "warning"),
+	stringDirective("logfile", false, 6),
+	boolDirective("syslog-enabled", false, 6),
+	stringDirective("syslog-ident", false, 6),
+	enumDirective("syslog-facility", false, 6, "user", "local0", "local1", "local2", "local3", "local4", "local5", "local6", "local7"),
+	boolDirective("crash-log-enabled", true, 6),
+	boolDirective("crash-memcheck-enabled", true, 6),
+	intDirective("databases", 1, maxInt, false, 6),
+	boolDirective("always-show-logo", false, 6),
+	boolDirective("set-proc-title", false, 6),
+	stringDirective("proc-title-template", true, 6),
+	enumDirective("enable-protected-configs", false, 7, "no", "yes", "local"),
+	enumDirective("enable-debug-command", false, 7, "no", "yes", "local"),
+	enumDirective("enable-module-command", false, 7, "no", "yes", "local"),
+	stringDirective("include", false, 6),
+	stringDirective("loadmodule", false, 6),
+	stringDirective("ignore-warnings", false, 6),
+
+	// 快照
+	{Name: "save", Type: DirectiveSavePoints, Runtime: true, Since: 6},
+	boolDirective("stop-writes-on-bgsave-error", true, 6),
+	boolDirective("rdbcompression", true, 6),
+	boolDirective("rdbchecksum", true, 6),
+	enumDirective("sanitize-dump-payload", true, 6, "no", "yes", "clients"),
+	stringDirective("dbfilename", true, 6),
+	boolDirective("rdb-del-sync-files", true, 6),
+	stringDirective("dir", true, 6),
+
+	// 复制
+	stringDirective("replicaof", false, 6),
+	stringDirective("slaveof", false, 6),
+	stringDirective("masterauth", true, 6),
+	stringDirective("masteruser", true, 6),
+	boolDirective("replica-serve-stale-data", true, 6),
+	boolDirective("slave-serve-stale-data", true, 6),
+	boolDirective("replica-read-only", true, 6),
+	boolDirective("slave-read-only", true, 6),
+	boolDirective("repl-diskless-sync", true, 6),
+	intDirective("repl-diskless-sync-delay", 0, maxInt, true, 6),
+	intDirective("repl-diskless-sync-max-replicas", 0, maxInt, true, 7),
+	enumDirective("repl-diskless-load", true, 6, "disabled", "on-empty-db", "swapdb"),
+	intDirective("repl-ping-replica-period", 1, maxInt, true, 6),
+	intDirective("repl-ping-slave-period", 1, maxInt, true, 6),
+	intDirective("repl-timeout", 1, maxInt, true, 6),
+	boolDirective("repl-disable-tcp-nodelay", true, 6),
+	memoryDirective("repl-backlog-size", true, 6),
+	intDirective("repl-backlog-ttl", 0, maxInt, true, 6),
+	intDirective("replica-priority", 0, maxInt, true, 6),
+	intDirective("slave-priority", 0, maxInt, true, 6),
+	boolDirective("replica-announced", true, 6),
+	intDirective("min-replicas-to-write", 0, maxInt, true, 6),
+	intDirective("min-slaves-to-write", 0, maxInt, true, 6),
+	intDirective("min-replicas-max-lag", 0, maxInt, true, 6),
+	intDirective("min-slaves-max-lag", 0, maxInt, true, 6),
+	stringDirective("replica-announce-ip", true, 6),
+	stringDirective("slave-announce-ip", true, 6),
+	intDirective("replica-announce-port", 0, 65535, true, 6),
+	intDirective("slave-announce-port", 0, 65535, true, 6),
+
+	// 客户端跟踪与安全
+	intDirective("tracking-table-max-keys", 0, maxInt, true, 6),
+	intDirective("acllog-max-len", 0, maxInt, true, 6),
+	stringDirective("aclfile", false, 6),
+	enumDirective("acl-pubsub-default", true, 6, "allchannels", "resetchannels"),
+	stringDirective("requirepass", true, 6),
+	stringDirective("rename-command", false, 6),
+
+	// 客户端与内存
+	intDirective("maxclients", 1, maxInt, true, 6),
+	memoryDirective("maxmemory", true, 6),
+	enumDirective("maxmemory-policy", true, 6,
+		"volatile-lru", "allkeys-lru", "volatile-lfu", "allkeys-lfu",
+		"volatile-random", "allkeys-random", "volatile-ttl", "noeviction"),
+	intDirective("maxmemory-samples", 1, 64, true, 6),
+	intDirective("maxmemory-eviction-tenacity", 0, 100, true, 6),
+	{Name: "maxmemory-clients", Type: DirectiveMemoryOrPercent, Runtime: true, Since: 7},
+	boolDirective("replica-ignore-maxmemory", true, 6),
+	boolDirective("slave-ignore-maxmemory", true, 6),
+	intDirective("active-expire-effort", 1, 10, true, 6),
+
+	// 惰性释放
+	boolDirective("lazyfree-lazy-eviction", true, 6),
+	boolDirective("lazyfree-lazy-expire", true, 6),
+	boolDirective("lazyfree-lazy-server-del", true, 6),
+	boolDirective("lazyfree-lazy-user-del", true, 6),
+	boolDirective("lazyfree-lazy-user-flush", true, 6),
+	boolDirective("replica-lazy-flush", true, 6),
+	boolDirective("slave-lazy-flush", true, 6),
+
+	// 线程与内核
+	intDirective("io-threads", 1, 128, false, 6),
+	boolDirective("io-threads-do-reads", false, 6),
+	enumDirective("oom-score-adj", true, 6, "no", "yes", "relative", "absolute"),
+	stringDirective("oom-score-adj-values", true, 6),
+	boolDirective("disable-thp", false, 6),
+
+	// AOF
+	boolDirective("appendonly", true, 6),
+	stringDirective("appendfilename", false, 6),
+	stringDirective("appenddirname", false, 7),
+	enumDirective("appendfsync", true, 6, "always", "everysec", "no"),
+	boolDirective("no-appendfsync-on-rewrite", true, 6),
+	intDirective("auto-aof-rewrite-percentage", 0, maxInt, true, 6),
+	memoryDirective("auto-aof-rewrite-min-size", true, 6),
+	boolDirective("aof-load-truncated", true, 6),
+	boolDirective("aof-use-rdb-preamble", true, 6),
+	boolDirective("aof-timestamp-enabled", true, 7),
+	boolDirective("aof-rewrite-incremental-fsync", true, 6),
+	boolDirective("rdb-save-incremental-fsync", true, 6),
+
+	// 关闭与脚本
+	intDirective("shutdown-timeout", 0, maxInt, true, 7),
+	stringDirective("shutdown-on-sigint", true, 7),
+	stringDirective("shutdown-on-sigterm", true, 7),
+	intDirective("lua-time-limit", 0, maxInt, true, 6),
+	intDirective("busy-reply-threshold", 0, maxInt, true, 7),
+
+	// 集群
+	boolDirective("cluster-enabled", false, 6),
+	stringDirective("cluster-config-file", false, 6),
+	intDirective("cluster-node-timeout", 1, maxInt, true, 6),
+	intDirective("cluster-port", 0, 65535, false, 7),
+	intDirective("cluster-replica-validity-factor", 0, maxInt, true, 6),
+	intDirective("cluster-slave-validity-factor", 0, maxInt, true, 6),
+	intDirective("cluster-migration-barrier", 0, maxInt, true, 6),
+	boolDirective("cluster-allow-replica-migration", true, 6),
+	boolDirective("cluster-require-full-coverage", true, 6),
+	boolDirective("cluster-replica-no-failover", true, 6),
+	boolDirective("cluster-slave-no-failover", true, 6),
+	boolDirective("cluster-allow-reads-when-down", true, 6),
+	boolDirective("cluster-allow-pubsubshard-when-down", true, 7),
+	memoryDirective("cluster-link-sendbuf-limit", true, 7),
+	stringDirective("cluster-announce-hostname", true, 7),
+	enumDirective("cluster-preferred-endpoint-type", true, 7, "ip", "hostname", "unknown-endpoint"),
+	stringDirective("cluster-announce-ip", true, 6),
+	intDirective("cluster-announce-port", 0, 65535, true, 6),
+	intDirective("cluster-announce-tls-port", 0, 65535, true, 6),
+	intDirective("cluster-announce-bus-port", 0, 65535, true, 6),
+
+	// 慢日志、延迟监控与事件通知
+	intDirective("slowlog-log-slower-than", -1, maxInt, true, 6),
+	intDirective("slowlog-max-len", 0, maxInt, true, 6),
+	intDirective("latency-monitor-threshold", 0, maxInt, true, 6),
+	boolDirective("latency-tracking", true, 7),
+	stringDirective("latency-tracking-info-percentiles", true, 7),
+	stringDirective("notify-keyspace-events", true, 6),
+
+	// 数据结构编码
+	intDirective("hash-max-ziplist-entries", 0, maxInt, true, 6),
+	intDirective("hash-max-ziplist-value", 0, maxInt, true, 6),
+	intDirective("hash-max-listpack-entries", 0, maxInt, true, 7),
+	intDirective("hash-max-listpack-value", 0, maxInt, true, 7),
+	intDirective("list-max-ziplist-size", -5, maxInt, true, 6),
+	intDirective("list-max-listpack-size", -5, maxInt, true, 7),
+	intDirective("list-compress-depth", 0, maxInt, true, 6),
+	intDirective("set-max-intset-entries", 0, maxInt, true, 6),
+	intDirective("zset-max-ziplist-entries", 0, maxInt, true, 6),
+	intDirective("zset-max-ziplist-value", 0, maxInt, true, 6),
+	intDirective("zset-max-listpack-entries", 0, maxInt, true, 7),
+	intDirective("zset-max-listpack-value", 0, maxInt, true, 7),
+	intDirective("hll-sparse-max-bytes", 0, maxInt, true, 6),
+	memoryDirective("stream-node-max-bytes", true, 6),
+	intDirective("stream-node-max-entries", 0, maxInt, true, 6),
+
+	// 高级配置
+	boolDirective("activerehashing", true, 6),
+	stringDirective("client-output-buffer-limit", true, 6),
+	memoryDirective("client-query-buffer-limit", true, 6),
+	memoryDirective("proto-max-bulk-len", true, 6),
+	intDirective("hz", 1, 500, true, 6),
+	boolDirective("dynamic-hz", true, 6),
+	intDirective("lfu-log-factor", 0, maxInt, true, 6),
+	intDirective("lfu-decay-time", 0, maxInt, true, 6),
+
+	// 碎片整理
+	boolDirective("activedefrag", true, 6),
+	memoryDirective("active-defrag-ignore-bytes", true, 6),
+	intDirective("active-defrag-threshold-lower", 0, 1000, true, 6),
+	intDirective("active-defrag-threshold-upper", 0, 1000, true, 6),
+	intDirective("active-defrag-cycle-min", 1, 99, true, 6),
+	intDirective("active-defrag-cycle-max", 1, 99, true, 6),
+	intDirective("active-defrag-max-scan-fields", 1, maxInt, true, 6),
+	boolDirective("jemalloc-bg-thread", true, 6),
+}
+
+// sentinelDirectives sentinel.conf 中以 sentinel 开头的指令，按 master 配置的指令值以 master 名称开头
+var sentinelDirectives = []RedisDirective{
+	boolDirective("sentinel resolve-hostnames", false, 6),
+	boolDirective("sentinel announce-hostnames", false, 6),
+	boolDirective("sentinel deny-scripts-reconfig", false, 6),
+	stringDirective("sentinel announce-ip", true, 6),
+	intDirective("sentinel announce-port", 0, 65535, true, 6),
+	stringDirective("sentinel sentinel-user", true, 6),
+	stringDirective("sentinel sentinel-pass", true, 6),
+	stringDirective("sentinel down-after-milliseconds", true, 6),
+	stringDirective("sentinel failover-timeout", true, 6),
+	stringDirective("sentinel parallel-syncs", true, 6),
+	stringDirective("sentinel auth-pass", true, 6),
+	stringDirective("sentinel auth-user", true, 6),
+	stringDirective("sentinel notification-script", true, 6),
+	stringDirective("sentinel client-reconfig-script", true, 6),
+	stringDirective("sentinel master-reboot-down-after-period", true, 7),
+}
+
+// RedisMajorVersion 根据镜像标签推断 Redis 主版本，无法识别时使用最新的目录，
+// 超出支持范围的版本使用最接近的目录
+func RedisMajorVersion(image string) int {
+	latest := SupportedRedisMajorVersions[len(SupportedRedisMajorVersions)-1]
+	if at := strings.Index(image, "@"); at >= 0 {
+		image = image[:at]
+	}
+	colon := strings.LastIndex(image, ":")
+	if colon < 0 || strings.Contains(image[colon:], "/") {
+		return latest
+	}
+	tag := image[colon+1:]
+	end := 0
+	for end < len(tag) && tag[end] >= '0' && tag[end] <= '9' {
+		end++
+	}
+	major, err := strconv.Atoi(tag[:end])
+	if err != nil {
+		return latest
+	}
+	if major < SupportedRedisMajorVersions[0] {
+		return SupportedRedisMajorVersions[0]
+	}
+	if major > latest {
+		return latest
+	}
+	return major
+}
+
+// RedisDirectiveCatalogue 返回指定主版本支持的 redis.conf 指令
+func RedisDirectiveCatalogue(major int) map[string]RedisDirective {
+	return directiveCatalogue(redisDirectives, major)
+}
+
+func directiveCatalogue(directives []RedisDirective, major int) map[string]RedisDirective {
+	catalogue := make(map[string]RedisDirective, len(directives))
+	for _, directive := range directives {
+		if directive.Since <= major {
+			catalogue[directive.Name] = directive
+		}
+	}
+	return catalogue
+}
+
+// ValidateRedisConfig 按主版本的指令目录校验 redis.conf 指令，错误以 path 和指令名开头
+func ValidateRedisConfig(major int, config map[string]string, path string) []string {
+	return validateDirectives(config, path, func(key, value string) string {
+		return ValidateRedisDirective(major, key, value)
+	})
+}
+
+// ValidateSentinelConfig 校验 sentinel.conf 指令，非 sentinel 开头的指令按 redis.conf 指令校验
+func ValidateSentinelConfig(major int, config map[string]string, path string) []string {
+	return validateDirectives(config, path, func(key, value string) string {
+		return ValidateSentinelDirective(major, key, value)
+	})
+}
+
+// ValidateRedisDirective 校验单条 redis.conf 指令，合法时返回空字符串
+func ValidateRedisDirective(major int, key, value string) string {
+	return directiveError(RedisDirectiveCatalogue(major), major, key, value)
+}
+
+// ValidateSentinelDirective 校验单条 sentinel.conf 指令，合法时返回空字符串
+func ValidateSentinelDirective(major int, key, value string) string {
+	catalogue := RedisDirectiveCatalogue(major)
+	for name, directive := range directiveCatalogue(sentinelDirectives, major) {
+		catalogue[name] = directive
+	}
+	return directiveError(catalogue, major, key, value)
+}
+
+func validateDirectives(config map[string]string, path string, validate func(key, value string) string) []string {
+	keys := make([]string, 0, len(config))
+	for key := range config {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var errs []string
+	for _, key := range keys {
+		if err := validate(key, config[key]); err != "" {
+			errs = append(errs, fmt.Sprintf("%s[%s]: %s", path, key, err))
+		}
+	}
+	return errs
+}
+
+func directiveError(catalogue map[string]RedisDirective, major int, key, value string) string {
+	name := normalizeDirective(key)
+	directive, ok := catalogue[name]
+	if !ok {
+		if since := directiveSince(name); since > major {
+			return fmt.Sprintf("directive requires Redis %d or later, image is Redis %d", since, major)
+		}
+		message := fmt.Sprintf("unknown directive for Redis %d", major)
+		if suggestion := closestDirective(catalogue, name); suggestion != "" {
+			message += fmt.Sprintf(", did you mean %q?", suggestion)
+		}
+		return message
+	}
+	return validateDirectiveValue(directive, value)
+}
+
+// normalizeDirective 统一指令名的大小写和空白，sentinel 指令由多个单词组成
+func normalizeDirective(key string) string {
+	return strings.ToLower(strings.Join(strings.Fields(key), " "))
+}
+
+// operatorOwnedRedisDirectives 由 operator 生成的 redis.conf 指令，用户覆盖会破坏网络、
+// 数据目录、认证或集群拓扑，tls- 开头的指令同样由 operator 管理
+var operatorOwnedRedisDirectives = map[string]bool{
+	"port": true, "bind": true, "dir": true, "daemonize": true, "supervised": true,
+	"pidfile": true, "include": true, "requirepass": true, "masterauth": true, "masteruser": true,
+	"replicaof": true, "slaveof": true,
+	"replica-announce-ip": true, "slave-announce-ip": true, "replica-announce-port": true, "slave-announce-port": true,
+	"cluster-enabled": true, "cluster-config-file": true, "cluster-port": true,
+	"cluster-announce-ip": true, "cluster-announce-port": true, "cluster-announce-bus-port": true,
+	"cluster-announce-tls-port": true, "cluster-announce-hostname": true,
+}
+
+// operatorOwnedSentinelDirectives 由 operator 根据 spec 生成的 sentinel.conf 指令
+var operatorOwnedSentinelDirectives = map[string]bool{
+	"port": true, "bind": true, "dir": true, "daemonize": true, "requirepass": true,
+	"sentinel monitor": true, "sentinel auth-pass": true, "sentinel auth-user": true,
+	"sentinel down-after-milliseconds": true, "sentinel failover-timeout": true, "sentinel parallel-syncs": true,
+	"sentinel announce-ip": true, "sentinel announce-port": true,
+}
+
+// OperatorOwnedRedisDirective 判断 redis.conf 指令是否由 operator 管理
+func OperatorOwnedRedisDirective(key string) bool {
+	name := normalizeDirective(key)
+	return operatorOwnedRedisDirectives[name] || strings.HasPrefix(name, "tls-")
+}
+
+// OperatorOwnedSentinelDirective 判断 sentinel.conf 指令是否由 operator 管理
+func OperatorOwnedSentinelDirective(key string) bool {
+	name := normalizeDirective(key)
+	return operatorOwnedSentinelDirectives[name] || strings.HasPrefix(name, "tls-")
+}
+
+// directiveSince 返回指令首次出现的主版本，未知指令返回 0
+func directiveSince(name string) int {
+	for _, directives := range [][]RedisDirective{redisDirectives, sentinelDirectives} {
+		for _, directive := range directives {
+			if directive.Name == name {
+				return directive.Since
+			}
+		}
+	}
+	return 0
+}
+
+// validateDirectiveValue 校验指令的值，合法时返回空字符串
+func validateDirectiveValue(directive RedisDirective, value string) string {
+	value = strings.TrimSpace(value)
+	switch directive.Type {
+	case DirectiveBool:
+		if v := strings.ToLower(value); v != "yes" && v != "no" {
+			return fmt.Sprintf("invalid value %q, must be yes or no", value)
+		}
+	case DirectiveInt:
+		number, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Sprintf("invalid value %q, must be an integer", value)
+		}
+		if directive.Bounded && (number < directive.Min || number > directive.Max) {
+			if directive.Max == maxInt {
+				return fmt.Sprintf("invalid value %d, must be at least %d", number, directive.Min)
+			}
+			return fmt.Sprintf("invalid value %d, must be between %d and %d", number, directive.Min, directive.Max)
+		}
+	case DirectiveMemory:
+		if !memoryPattern.MatchString(value) {
+			return fmt.Sprintf("invalid value %q, must be a memory size such as 100mb or 1gb", value)
+		}
+	case DirectiveMemoryOrPercent:
+		if !memoryPattern.MatchString(value) && !percentPattern.MatchString(value) {
+			return fmt.Sprintf("invalid value %q, must be a memory size such as 100mb or a percentage such as 10%%", value)
+		}
+	case DirectiveEnum:
+		for _, allowed := range directive.Values {
+			if strings.EqualFold(value, allowed) {
+				return ""
+			}
+		}
+		return fmt.Sprintf("invalid value %q, must be one of %s", value, strings.Join(directive.Values, ", "))
+	case DirectiveSavePoints:
+		fields := strings.Fields(value)
+		if value == "" || value == `""` {
+			return ""
+		}
+		if len(fields)%2 != 0 {
+			return fmt.Sprintf("invalid value %q, must be pairs of <seconds> <changes>", value)
+		}
+		for _, field := range fields {
+			if number, err := strconv.ParseInt(field, 10, 64); err != nil || number < 0 {
+				return fmt.Sprintf("invalid value %q, must be pairs of <seconds> <changes>", value)
+			}
+		}
+	}
+	return ""
+}
+
+// closestDirective 返回编辑距离不超过 2 的最接近的指令名，用于提示拼写错误
+func closestDirective(catalogue map[string]RedisDirective, name string) string {
+	best, bestDistance := "", 3
+	for candidate := range catalogue {
+		if distance := editDistance(name, candidate); distance < bestDistance || (distance == bestDistance && best != "" && candidate < best) {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best
+}
+
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
@@ -0,0 +1,171 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	redisv1 "github.com/ybooks240/redis-operator/api/v1"
+)
+
+const (
+	// ExporterContainerName redis_exporter sidecar 容器名称
+	ExporterContainerName = "redis-exporter"
+	// ExporterPortName exporter 容器端口和 metrics Service 端口的名称
+	ExporterPortName = "metrics"
+	// DefaultExporterImage 未指定镜像时使用的 redis_exporter 镜像
+	DefaultExporterImage = "oliver006/redis_exporter:v1.62.0"
+	// DefaultExporterPort 未指定端口时 redis_exporter 的监听端口
+	DefaultExporterPort int32 = 9121
+
+	// ExporterHashAnnotation Pod 模板上记录 exporter 容器配置的哈希，变化后触发滚动更新
+	ExporterHashAnnotation = "redis.github.com/exporter-hash"
+	// ExporterPasswordFileKey exporter 密码 Secret 中保存密码文件的键，内容为 redis_exporter 要求的 {"<地址>": "<密码>"} JSON
+	ExporterPasswordFileKey = "passwords.json"
+
+	exporterAuthVolumeName = "redis-exporter-auth"
+	exporterAuthMountPath  = "/etc/redis-exporter-auth"
+)
+
+// ExporterAuthSecretName 返回 exporter 密码文件 Secret 的名称
+func ExporterAuthSecretName(name string) string {
+	return name + "-exporter-auth"
+}
+
+// ExporterAuthSecret 返回 exporter 使用的密码文件 Secret 名称，未启用认证时返回空字符串
+func ExporterAuthSecret(security redisv1.SecuritySpec, name string) string {
+	if !security.AuthEnabled {
+		return ""
+	}
+	return ExporterAuthSecretName(name)
+}
+
+// ExporterRedisAddr 返回 exporter 连接同一 Pod 中 Redis 的地址，也是密码文件中的键
+func ExporterRedisAddr(tlsSpec *redisv1.TLSSpec) string {
+	if tlsSpec != nil && tlsSpec.Enabled {
+		return "rediss://localhost:6379"
+	}
+	return "redis://localhost:6379"
+}
+
+// ExporterPasswordFile 生成 exporter 密码文件的内容
+func ExporterPasswordFile(tlsSpec *redisv1.TLSSpec, password string) ([]byte, error) {
+	return json.Marshal(map[string]string{ExporterRedisAddr(tlsSpec): password})
+}
+
+// MonitoringEnabled 检查是否启用了 exporter sidecar
+func MonitoringEnabled(monitoring *redisv1.MonitoringSpec) bool {
+	return monitoring != nil && monitoring.Enabled
+}
+
+// ExporterPort 返回 exporter 的监听端口
+func ExporterPort(monitoring *redisv1.MonitoringSpec) int32 {
+	if monitoring == nil || monitoring.Port == 0 {
+		return DefaultExporterPort
+	}
+	return monitoring.Port
+}
+
+// ApplyExporter 为 Pod 模板注入 redis_exporter sidecar，通过 localhost 采集同一 Pod 中的 Redis
+// 密码通过 authSecret 中的密码文件挂载，不使用环境变量，密码轮换后 kubelet 刷新文件，exporter 无需重启；
+// 需要在 ApplyTLS 之前调用，使 exporter 挂载证书并通过 TLS 连接
+func ApplyExporter(template *corev1.PodTemplateSpec, monitoring *redisv1.MonitoringSpec, authSecret string, tlsSpec *redisv1.TLSSpec) {
+	if !MonitoringEnabled(monitoring) {
+		return
+	}
+
+	image := monitoring.Image
+	if image == "" {
+		image = DefaultExporterImage
+	}
+	port := ExporterPort(monitoring)
+	container := corev1.Container{
+		Name:  ExporterContainerName,
+		Image: image,
+		Ports: []corev1.ContainerPort{{
+			Name:          ExporterPortName,
+			ContainerPort: port,
+			Protocol:      corev1.ProtocolTCP,
+		}},
+		Env: []corev1.EnvVar{
+			{Name: "REDIS_ADDR", Value: ExporterRedisAddr(tlsSpec)},
+			{Name: "REDIS_EXPORTER_WEB_LISTEN_ADDRESS", Value: fmt.Sprintf(":%d", port)},
+		},
+		Resources: monitoring.Resources,
+	}
+	if authSecret != "" {
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name:  "REDIS_PASSWORD_FILE",
+			Value: exporterAuthMountPath + "/" + ExporterPasswordFileKey,
+		})
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      exporterAuthVolumeName,
+			MountPath: exporterAuthMountPath,
+			ReadOnly:  true,
+		})
+		template.Spec.Volumes = append(template.Spec.Volumes, corev1.Volume{
+			Name: exporterAuthVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{SecretName: authSecret},
+			},
+		})
+	}
+	if tlsSpec != nil && tlsSpec.Enabled {
+		container.Env = append(container.Env,
+			corev1.EnvVar{Name: "REDIS_EXPORTER_TLS_CLIENT_CERT_FILE", Value: tlsMountPath + "/" + TLSCertKey},
+			corev1.EnvVar{Name: "REDIS_EXPORTER_TLS_CLIENT_KEY_FILE", Value: tlsMountPath + "/" + TLSKeyKey},
+			corev1.EnvVar{Name: "REDIS_EXPORTER_TLS_CA_CERT_FILE", Value: tlsMountPath + "/" + TLSCAKey},
+			// 证书不一定包含 localhost，连接不离开 Pod，跳过主机名校验
+			corev1.EnvVar{Name: "REDIS_EXPORTER_SKIP_TLS_VERIFICATION", Value: "true"},
+		)
+	}
+
+	if template.Annotations == nil {
+		template.Annotations = map[string]string{}
+	}
+	template.Annotations[ExporterHashAnnotation] = exporterHash(container)
+	template.Spec.Containers = append(template.Spec.Containers, container)
+}
+
+// ReplaceExporter 在已有的 Pod 模板中替换、添加或移除 exporter sidecar，用于原地更新 StatefulSet
+func ReplaceExporter(template *corev1.PodTemplateSpec, monitoring *redisv1.MonitoringSpec, authSecret string, tlsSpec *redisv1.TLSSpec) {
+	var containers []corev1.Container
+	for _, container := range template.Spec.Containers {
+		if container.Name != ExporterContainerName {
+			containers = append(containers, container)
+		}
+	}
+	template.Spec.Containers = containers
+	var volumes []corev1.Volume
+	for _, volume := range template.Spec.Volumes {
+		if volume.Name != exporterAuthVolumeName {
+			volumes = append(volumes, volume)
+		}
+	}
+	template.Spec.Volumes = volumes
+	delete(template.Annotations, ExporterHashAnnotation)
+
+	ApplyExporter(template, monitoring, authSecret, tlsSpec)
+	// 模板中已有证书卷，只需为新的 exporter 容器挂载
+	if MonitoringEnabled(monitoring) && tlsSpec != nil && tlsSpec.Enabled {
+		exporter := &template.Spec.Containers[len(template.Spec.Containers)-1]
+		exporter.VolumeMounts = append(exporter.VolumeMounts, corev1.VolumeMount{
+			Name:      tlsVolumeName,
+			MountPath: tlsMountPath,
+			ReadOnly:  true,
+		})
+	}
+}
+
+// ExporterChanged 检查 Pod 模板的 exporter sidecar 是否与期望不一致，包括启用和停用
+func ExporterChanged(current, desired *corev1.PodTemplateSpec) bool {
+	return current.Annotations[ExporterHashAnnotation] != desired.Annotations[ExporterHashAnnotation]
+}
+
+// exporterHash 计算 exporter 容器配置的哈希
+func exporterHash(container corev1.Container) string {
+	data, _ := json.Marshal(container)
+	return fmt.Sprintf("%x", sha256.Sum256(data))
+}
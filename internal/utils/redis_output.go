@@ -0,0 +1,114 @@
+package utils
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ClusterNode CLUSTER NODES 输出中的一个节点
+type ClusterNode struct {
+	ID string
+	// Addr 节点地址 ip:port，不包含集群总线端口和主机名
+	Addr        string
+	Flags       []string
+	MasterID    string
+	ConfigEpoch string
+	LinkState   string
+	// Slots 节点持有的槽位范围，不包含迁移中的槽位
+	Slots []string
+}
+
+// HasFlag 检查节点是否带有指定标记
+func (n ClusterNode) HasFlag(flag string) bool {
+	return slices.Contains(n.Flags, flag)
+}
+
+// Failed 节点被集群判定为下线
+func (n ClusterNode) Failed() bool {
+	return n.HasFlag("fail") || n.HasFlag("noaddr")
+}
+
+// IsMaster 节点是未下线的 master
+func (n ClusterNode) IsMaster() bool {
+	return n.HasFlag("master") && !n.Failed()
+}
+
+// IP 返回节点地址中的 IP 部分
+func (n ClusterNode) IP() string {
+	if colon := strings.LastIndex(n.Addr, ":"); colon >= 0 {
+		return n.Addr[:colon]
+	}
+	return n.Addr
+}
+
+// SlotCount 节点持有的槽位数量
+func (n ClusterNode) SlotCount() int {
+	count := 0
+	for _, slots := range n.Slots {
+		start, end, found := strings.Cut(slots, "-")
+		if !found {
+			end = start
+		}
+		first, err1 := strconv.Atoi(start)
+		last, err2 := strconv.Atoi(end)
+		if err1 == nil && err2 == nil && last >= first {
+			count += last - first + 1
+		}
+	}
+	return count
+}
+
+// FirstSlot 节点持有的最小槽位，未持有槽位时返回 -1
+func (n ClusterNode) FirstSlot() int {
+	first := -1
+	for _, slots := range n.Slots {
+		start, _, _ := strings.Cut(slots, "-")
+		if slot, err := strconv.Atoi(start); err == nil && (first < 0 || slot < first) {
+			first = slot
+		}
+	}
+	return first
+}
+
+// ParseClusterNodes 解析 CLUSTER NODES 的输出
+func ParseClusterNodes(output string) []ClusterNode {
+	var nodes []ClusterNode
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 8 {
+			continue
+		}
+		// ip:port@cport[,hostname]
+		addr, _, _ := strings.Cut(fields[1], "@")
+		node := ClusterNode{
+			ID:          fields[0],
+			Addr:        addr,
+			Flags:       strings.Split(fields[2], ","),
+			ConfigEpoch: fields[6],
+			LinkState:   fields[7],
+		}
+		if fields[3] != "-" {
+			node.MasterID = fields[3]
+		}
+		for _, slots := range fields[8:] {
+			// 跳过迁移中的槽位标记 [slot->-node]
+			if !strings.HasPrefix(slots, "[") {
+				node.Slots = append(node.Slots, slots)
+			}
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+// ParseInfo 解析 INFO 命令输出的字段，忽略分节标题和空行
+func ParseInfo(info string) map[string]string {
+	fields := map[string]string{}
+	for _, line := range strings.Split(info, "\r\n") {
+		if key, value, found := strings.Cut(line, ":"); found {
+			fields[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	return fields
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2025 James.Liu.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Redis command output", func() {
+	DescribeTable("parsing CLUSTER NODES",
+		func(output string, expected []ClusterNode) {
+			Expect(ParseClusterNodes(output)).To(Equal(expected))
+		},
+		Entry("empty output", "", nil),
+		Entry("masters with slots and a replica",
+			"a1 10.0.0.1:6379@16379 myself,master - 0 0 1 connected 0-5460 [93->-b2]\n"+
+				"b2 10.0.0.2:6379@16379 master - 0 0 2 connected 5461-10922 10923\n"+
+				"c3 10.0.0.3:6379@16379 slave a1 0 0 1 connected\n",
+			[]ClusterNode{
+				{ID: "a1", Addr: "10.0.0.1:6379", Flags: []string{"myself", "master"}, ConfigEpoch: "1", LinkState: "connected", Slots: []string{"0-5460"}},
+				{ID: "b2", Addr: "10.0.0.2:6379", Flags: []string{"master"}, ConfigEpoch: "2", LinkState: "connected", Slots: []string{"5461-10922", "10923"}},
+				{ID: "c3", Addr: "10.0.0.3:6379", Flags: []string{"slave"}, MasterID: "a1", ConfigEpoch: "1", LinkState: "connected"},
+			}),
+		Entry("a hostname after the cluster bus port and a failed node",
+			"d4 10.0.0.4:6379@16379,redis-0.redis-headless master,fail - 0 0 3 disconnected\n"+
+				"truncated line\n",
+			[]ClusterNode{
+				{ID: "d4", Addr: "10.0.0.4:6379", Flags: []string{"master", "fail"}, ConfigEpoch: "3", LinkState: "disconnected"},
+			}),
+	)
+
+	DescribeTable("inspecting a cluster node",
+		func(node ClusterNode, isMaster, failed bool, ip string, slotCount, firstSlot int) {
+			Expect(node.IsMaster()).To(Equal(isMaster))
+			Expect(node.Failed()).To(Equal(failed))
+			Expect(node.IP()).To(Equal(ip))
+			Expect(node.SlotCount()).To(Equal(slotCount))
+			Expect(node.FirstSlot()).To(Equal(firstSlot))
+		},
+		Entry("a master with slot ranges",
+			ClusterNode{Addr: "10.0.0.1:6379", Flags: []string{"myself", "master"}, Slots: []string{"5461-10922", "0-10"}},
+			true, false, "10.0.0.1", 5473, 0),
+		Entry("a master without an address",
+			ClusterNode{Addr: ":0", Flags: []string{"master", "noaddr"}},
+			false, true, "", 0, -1),
+		Entry("a replica with an IPv6 address",
+			ClusterNode{Addr: "fd00::3:6379", Flags: []string{"slave"}},
+			false, false, "fd00::3", 0, -1),
+	)
+
+	DescribeTable("parsing INFO",
+		func(info string, expected map[string]string) {
+			Expect(ParseInfo(info)).To(Equal(expected))
+		},
+		Entry("empty output", "", map[string]string{}),
+		Entry("sections with fields",
+			"# Replication\r\nrole:slave\r\nmaster_link_status:up\r\n\r\n# Keyspace\r\ndb0:keys=1,expires=0,avg_ttl=0\r\n",
+			map[string]string{"role": "slave", "master_link_status": "up", "db0": "keys=1,expires=0,avg_ttl=0"}),
+		Entry("values containing colons",
+			"executable:/usr/local/bin/redis-server\r\nconfig_file:\r\n",
+			map[string]string{"executable": "/usr/local/bin/redis-server", "config_file": ""}),
+	)
+})
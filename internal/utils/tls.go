@@ -0,0 +1,269 @@
+package utils
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	redisv1 "github.com/ybooks240/redis-operator/api/v1"
+)
+
+const (
+	// TLSCertHashAnnotation Pod 模板上记录证书内容的哈希，证书轮换后触发滚动更新
+	TLSCertHashAnnotation = "redis.github.com/tls-cert-hash"
+	// TLSCertificateConditionType 证书有效期状态条件
+	TLSCertificateConditionType = "TLSCertificateValid"
+
+	// TLS Secret 中的键名，与 cert-manager 生成的 Secret 保持一致
+	TLSCertKey = "tls.crt"
+	TLSKeyKey  = "tls.key"
+	TLSCAKey   = "ca.crt"
+
+	tlsVolumeName = "redis-tls"
+	tlsMountPath  = "/etc/redis-tls"
+
+	// 证书剩余有效期低于该值时状态条件变为 False
+	tlsExpiryWarning = 14 * 24 * time.Hour
+)
+
+// TLSEnabled 检查是否启用了 TLS
+func TLSEnabled(security redisv1.SecuritySpec) bool {
+	return security.TLS != nil && security.TLS.Enabled
+}
+
+// TLSServerArgs 返回 redis-server/redis-sentinel 的 TLS 启动参数
+// TLS 使用原有的服务端口，仅在配置了 plaintextPort 时保留明文端口
+func TLSServerArgs(tlsSpec *redisv1.TLSSpec, port int32, cluster bool) []string {
+	authClients := "no"
+	if tlsSpec.ClientAuth {
+		authClients = "yes"
+	}
+	args := []string{
+		"--port", fmt.Sprintf("%d", tlsSpec.PlaintextPort),
+		"--tls-port", fmt.Sprintf("%d", port),
+		"--tls-cert-file", tlsMountPath + "/" + TLSCertKey,
+		"--tls-key-file", tlsMountPath + "/" + TLSKeyKey,
+		"--tls-ca-cert-file", tlsMountPath + "/" + TLSCAKey,
+		"--tls-auth-clients", authClients,
+		"--tls-replication", "yes",
+	}
+	if cluster {
+		args = append(args, "--tls-cluster", "yes")
+	}
+	return args
+}
+
+// TLSCLIArgs 返回 redis-cli 使用 TLS 连接所需的参数
+func TLSCLIArgs() []string {
+	return []string{
+		"--tls",
+		"--cacert", tlsMountPath + "/" + TLSCAKey,
+		"--cert", tlsMountPath + "/" + TLSCertKey,
+		"--key", tlsMountPath + "/" + TLSKeyKey,
+	}
+}
+
+// ApplyTLS 为 Pod 模板中的服务容器启用 TLS
+// 证书 Secret 挂载到所有容器，服务容器追加 TLS 启动参数，redis-cli 探针使用 TLS 连接
+// 需要在 ApplyAuth 之前调用，使认证包装后的启动命令保留 TLS 参数
+func ApplyTLS(template *corev1.PodTemplateSpec, containerName string, tlsSpec *redisv1.TLSSpec, port int32, cluster bool) {
+	if tlsSpec == nil || !tlsSpec.Enabled {
+		return
+	}
+
+	MountTLS(template, tlsSpec)
+
+	for i := range template.Spec.Containers {
+		container := &template.Spec.Containers[i]
+		if container.Name != containerName {
+			continue
+		}
+
+		container.Command = append(container.Command, TLSServerArgs(tlsSpec, port, cluster)...)
+		if tlsSpec.PlaintextPort > 0 {
+			container.Ports = append(container.Ports, corev1.ContainerPort{
+				ContainerPort: tlsSpec.PlaintextPort,
+				Name:          "plaintext",
+			})
+		}
+		for _, probe := range []*corev1.Probe{container.ReadinessProbe, container.LivenessProbe} {
+			if probe == nil || probe.Exec == nil || len(probe.Exec.Command) == 0 || probe.Exec.Command[0] != "redis-cli" {
+				continue
+			}
+			command := append([]string{"redis-cli"}, TLSCLIArgs()...)
+			probe.Exec.Command = append(command, probe.Exec.Command[1:]...)
+		}
+	}
+}
+
+// MountTLS 将证书 Secret 挂载到 Pod 模板的所有容器，供 redis-cli 使用 TLSCLIArgs 连接
+func MountTLS(template *corev1.PodTemplateSpec, tlsSpec *redisv1.TLSSpec) {
+	if tlsSpec == nil || !tlsSpec.Enabled {
+		return
+	}
+
+	template.Spec.Volumes = append(template.Spec.Volumes, corev1.Volume{
+		Name: tlsVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: tlsSpec.SecretName,
+			},
+		},
+	})
+
+	mount := corev1.VolumeMount{
+		Name:      tlsVolumeName,
+		MountPath: tlsMountPath,
+		ReadOnly:  true,
+	}
+	for i := range template.Spec.InitContainers {
+		template.Spec.InitContainers[i].VolumeMounts = append(template.Spec.InitContainers[i].VolumeMounts, mount)
+	}
+	for i := range template.Spec.Containers {
+		template.Spec.Containers[i].VolumeMounts = append(template.Spec.Containers[i].VolumeMounts, mount)
+	}
+}
+
+// LoadTLSSecret 读取 TLS 证书 Secret，未启用 TLS 时返回 nil
+func LoadTLSSecret(ctx context.Context, c client.Client, namespace string, tlsSpec *redisv1.TLSSpec) (*corev1.Secret, error) {
+	if tlsSpec == nil || !tlsSpec.Enabled {
+		return nil, nil
+	}
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Name: tlsSpec.SecretName, Namespace: namespace}, secret); err != nil {
+		return nil, err
+	}
+	for _, key := range []string{TLSCertKey, TLSKeyKey, TLSCAKey} {
+		if len(secret.Data[key]) == 0 {
+			return nil, fmt.Errorf("key %s not found in TLS secret %s/%s", key, namespace, tlsSpec.SecretName)
+		}
+	}
+	return secret, nil
+}
+
+// TLSCertHash 计算证书 Secret 内容和 TLS 选项的哈希
+func TLSCertHash(secret *corev1.Secret, tlsSpec *redisv1.TLSSpec) string {
+	hash := sha256.New()
+	for _, key := range []string{TLSCertKey, TLSKeyKey, TLSCAKey} {
+		hash.Write(secret.Data[key])
+	}
+	fmt.Fprintf(hash, "clientAuth=%t,plaintextPort=%d", tlsSpec.ClientAuth, tlsSpec.PlaintextPort)
+	return fmt.Sprintf("%x", hash.Sum(nil))
+}
+
+// AnnotateTLSCertHash 将当前证书的哈希写入 Pod 模板，证书轮换或 TLS 选项变化后触发滚动更新
+func AnnotateTLSCertHash(ctx context.Context, c client.Client, namespace string, tlsSpec *redisv1.TLSSpec, template *corev1.PodTemplateSpec) error {
+	secret, err := LoadTLSSecret(ctx, c, namespace, tlsSpec)
+	if err != nil || secret == nil {
+		return err
+	}
+	if template.Annotations == nil {
+		template.Annotations = map[string]string{}
+	}
+	template.Annotations[TLSCertHashAnnotation] = TLSCertHash(secret, tlsSpec)
+	return nil
+}
+
+// TLSCertChanged 检查 Pod 模板的证书哈希是否与期望不一致
+func TLSCertChanged(current, desired *corev1.PodTemplateSpec) bool {
+	return current.Annotations[TLSCertHashAnnotation] != desired.Annotations[TLSCertHashAnnotation]
+}
+
+// ParseTLSCertificate 解析 Secret 中的服务端证书
+func ParseTLSCertificate(secret *corev1.Secret) (*x509.Certificate, error) {
+	block, _ := pem.Decode(secret.Data[TLSCertKey])
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode PEM data in %s", TLSCertKey)
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// ClientTLSConfig 返回 Operator 连接 Redis 使用的 TLS 配置，未启用 TLS 时返回 nil
+// Operator 通过 Pod IP 连接节点，因此只校验证书链，不校验主机名
+func ClientTLSConfig(ctx context.Context, c client.Client, namespace string, tlsSpec *redisv1.TLSSpec) (*tls.Config, error) {
+	secret, err := LoadTLSSecret(ctx, c, namespace, tlsSpec)
+	if err != nil || secret == nil {
+		return nil, err
+	}
+
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(secret.Data[TLSCAKey]) {
+		return nil, fmt.Errorf("failed to parse %s in TLS secret %s/%s", TLSCAKey, namespace, tlsSpec.SecretName)
+	}
+	certificate, err := tls.X509KeyPair(secret.Data[TLSCertKey], secret.Data[TLSKeyKey])
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		Certificates:       []tls.Certificate{certificate},
+		InsecureSkipVerify: true, //nolint:gosec // 证书链在 VerifyPeerCertificate 中校验
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) == 0 {
+				return fmt.Errorf("no server certificate presented")
+			}
+			intermediates := x509.NewCertPool()
+			var leaf *x509.Certificate
+			for i, raw := range rawCerts {
+				cert, err := x509.ParseCertificate(raw)
+				if err != nil {
+					return err
+				}
+				if i == 0 {
+					leaf = cert
+				} else {
+					intermediates.AddCert(cert)
+				}
+			}
+			_, err := leaf.Verify(x509.VerifyOptions{Roots: roots, Intermediates: intermediates})
+			return err
+		},
+	}, nil
+}
+
+// TLSCertificateCondition 根据证书有效期生成状态条件
+func TLSCertificateCondition(cert *x509.Certificate, err error, generation int64) metav1.Condition {
+	condition := metav1.Condition{
+		Type:               TLSCertificateConditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Valid",
+		ObservedGeneration: generation,
+	}
+
+	switch {
+	case err != nil:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "Invalid"
+		condition.Message = err.Error()
+	case time.Now().After(cert.NotAfter):
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "Expired"
+		condition.Message = fmt.Sprintf("Certificate expired at %s", cert.NotAfter.Format(time.RFC3339))
+	case time.Until(cert.NotAfter) < tlsExpiryWarning:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "ExpiringSoon"
+		condition.Message = fmt.Sprintf("Certificate expires at %s", cert.NotAfter.Format(time.RFC3339))
+	default:
+		condition.Message = fmt.Sprintf("Certificate valid until %s", cert.NotAfter.Format(time.RFC3339))
+	}
+
+	return condition
+}
+
+// ReferencesSecret 检查安全配置是否引用了指定的 Secret（密码或 TLS 证书）
+func ReferencesSecret(security redisv1.SecuritySpec, name, secretName string) bool {
+	if ref := PasswordSecretRef(security, name); ref != nil && ref.Name == secretName {
+		return true
+	}
+	return TLSEnabled(security) && security.TLS.SecretName == secretName
+}
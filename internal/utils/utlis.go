@@ -64,3 +64,31 @@ func GenerateRedisConfig(config map[string]string) string {
 
 	return strings.Join(configLines, "\n")
 }
+
+// AppendRedisConfig appends directives to a configuration file in sorted order,
+// Redis applies the last occurrence of a directive so they override the base file
+func AppendRedisConfig(base string, config map[string]string) string {
+	if len(config) == 0 {
+		return base
+	}
+
+	keys := make([]string, 0, len(config))
+	for key := range config {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var builder strings.Builder
+	builder.WriteString(base)
+	if base != "" && !strings.HasSuffix(base, "\n") {
+		builder.WriteString("\n")
+	}
+	for _, key := range keys {
+		value := config[key]
+		if value == "" {
+			value = `""`
+		}
+		fmt.Fprintf(&builder, "%s %s\n", key, value)
+	}
+	return builder.String()
+}
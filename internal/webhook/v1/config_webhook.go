@@ -0,0 +1,98 @@
+/*
+Copyright 2025 James.Liu.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	redisv1 "github.com/ybooks240/redis-operator/api/v1"
+	"github.com/ybooks240/redis-operator/internal/utils"
+)
+
+// nolint:unused
+// log is for logging in this package.
+var configlog = logf.Log.WithName("config-resource")
+
+// SetupConfigWebhookWithManager registers the webhook for Config in the manager.
+func SetupConfigWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&redisv1.Config{}).
+		WithValidator(&ConfigCustomValidator{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-redis-github-com-v1-config,mutating=false,failurePolicy=fail,sideEffects=None,groups=redis.github.com,resources=configs,verbs=create;update,versions=v1,name=vconfig-v1.kb.io,admissionReviewVersions=v1
+
+// ConfigCustomValidator 校验 Config 的创建和更新
+type ConfigCustomValidator struct{}
+
+var _ webhook.CustomValidator = &ConfigCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type Config.
+func (v *ConfigCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	config, ok := obj.(*redisv1.Config)
+	if !ok {
+		return nil, fmt.Errorf("expected a Config object but got %T", obj)
+	}
+	configlog.Info("Validation for Config upon creation", "name", config.GetName())
+
+	return nil, validateConfig(config, nil)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type Config.
+func (v *ConfigCustomValidator) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	config, ok := newObj.(*redisv1.Config)
+	if !ok {
+		return nil, fmt.Errorf("expected a Config object for the newObj but got %T", newObj)
+	}
+	oldConfig, ok := oldObj.(*redisv1.Config)
+	if !ok {
+		return nil, fmt.Errorf("expected a Config object for the oldObj but got %T", oldObj)
+	}
+	configlog.Info("Validation for Config upon update", "name", config.GetName())
+
+	// 删除中的对象只会移除 finalizer，跳过校验避免已不满足新规则的对象无法完成删除
+	if config.GetDeletionTimestamp() != nil {
+		return nil, nil
+	}
+
+	return nil, validateConfig(config, oldConfig)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type Config.
+func (v *ConfigCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateConfig 配置模板可能被不同版本的工作负载引用，按最新的指令目录校验，
+// 工作负载会按各自的 Redis 版本再次校验
+func validateConfig(config, _ *redisv1.Config) error {
+	spec := field.NewPath("spec")
+	major := utils.SupportedRedisMajorVersions[len(utils.SupportedRedisMajorVersions)-1]
+
+	errs := validateRedisConfig(major, spec.Child("redis"), config.Spec.Redis)
+	errs = append(errs, validateRedisConfig(major, spec.Child("cluster"), config.Spec.Cluster)...)
+	errs = append(errs, validateSentinelConfig(major, spec.Child("sentinel"), config.Spec.Sentinel)...)
+	return invalidError("Config", config.Name, errs)
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2025 James.Liu.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	redisv1 "github.com/ybooks240/redis-operator/api/v1"
+)
+
+var _ = Describe("Config Webhook", func() {
+	var (
+		obj       *redisv1.Config
+		validator ConfigCustomValidator
+	)
+
+	BeforeEach(func() {
+		obj = &redisv1.Config{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-config", Namespace: "default"},
+			Spec: redisv1.ConfigSpec{
+				Redis:    map[string]string{"maxmemory-policy": "allkeys-lru"},
+				Sentinel: map[string]string{"sentinel resolve-hostnames": "yes"},
+				Cluster:  map[string]string{"cluster-allow-reads-when-down": "yes"},
+			},
+		}
+		validator = ConfigCustomValidator{}
+	})
+
+	Context("When creating Config under Validating Webhook", func() {
+		It("Should admit a valid profile", func() {
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should report every invalid directive with its section", func() {
+			obj.Spec.Redis["appendfsync"] = "sometimes"
+			obj.Spec.Cluster["cluster-config-file"] = "other.conf"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.redis[appendfsync]: Invalid value")))
+			Expect(err).To(MatchError(ContainSubstring("spec.cluster[cluster-config-file]: Forbidden")))
+		})
+	})
+})
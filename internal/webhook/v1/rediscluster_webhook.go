@@ -0,0 +1,130 @@
+/*
+Copyright 2025 James.Liu.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	redisv1 "github.com/ybooks240/redis-operator/api/v1"
+	"github.com/ybooks240/redis-operator/internal/utils"
+)
+
+// nolint:unused
+// log is for logging in this package.
+var redisclusterlog = logf.Log.WithName("rediscluster-resource")
+
+// SetupRedisClusterWebhookWithManager registers the webhook for RedisCluster in the manager.
+func SetupRedisClusterWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&redisv1.RedisCluster{}).
+		WithValidator(&RedisClusterCustomValidator{}).
+		WithDefaulter(&RedisClusterCustomDefaulter{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-redis-github-com-v1-rediscluster,mutating=true,failurePolicy=fail,sideEffects=None,groups=redis.github.com,resources=redisclusters,verbs=create;update,versions=v1,name=mrediscluster-v1.kb.io,admissionReviewVersions=v1
+
+// RedisClusterCustomDefaulter 为 RedisCluster 填充默认值
+type RedisClusterCustomDefaulter struct{}
+
+var _ webhook.CustomDefaulter = &RedisClusterCustomDefaulter{}
+
+// Default 未指定 master 数量时使用最小的 3 个 master
+func (d *RedisClusterCustomDefaulter) Default(_ context.Context, obj runtime.Object) error {
+	rediscluster, ok := obj.(*redisv1.RedisCluster)
+	if !ok {
+		return fmt.Errorf("expected a RedisCluster object but got %T", obj)
+	}
+	redisclusterlog.Info("Defaulting for RedisCluster", "name", rediscluster.GetName())
+
+	if rediscluster.Spec.Masters == 0 {
+		rediscluster.Spec.Masters = minClusterMasters
+	}
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate-redis-github-com-v1-rediscluster,mutating=false,failurePolicy=fail,sideEffects=None,groups=redis.github.com,resources=redisclusters,verbs=create;update,versions=v1,name=vrediscluster-v1.kb.io,admissionReviewVersions=v1
+
+// RedisClusterCustomValidator 校验 RedisCluster 的创建和更新
+type RedisClusterCustomValidator struct{}
+
+var _ webhook.CustomValidator = &RedisClusterCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type RedisCluster.
+func (v *RedisClusterCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	rediscluster, ok := obj.(*redisv1.RedisCluster)
+	if !ok {
+		return nil, fmt.Errorf("expected a RedisCluster object but got %T", obj)
+	}
+	redisclusterlog.Info("Validation for RedisCluster upon creation", "name", rediscluster.GetName())
+
+	return nil, validateRedisCluster(rediscluster, nil)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type RedisCluster.
+func (v *RedisClusterCustomValidator) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	rediscluster, ok := newObj.(*redisv1.RedisCluster)
+	if !ok {
+		return nil, fmt.Errorf("expected a RedisCluster object for the newObj but got %T", newObj)
+	}
+	oldRedisCluster, ok := oldObj.(*redisv1.RedisCluster)
+	if !ok {
+		return nil, fmt.Errorf("expected a RedisCluster object for the oldObj but got %T", oldObj)
+	}
+	redisclusterlog.Info("Validation for RedisCluster upon update", "name", rediscluster.GetName())
+
+	// 删除中的对象只会移除 finalizer，跳过校验避免已不满足新规则的对象无法完成删除
+	if rediscluster.GetDeletionTimestamp() != nil {
+		return nil, nil
+	}
+
+	return nil, validateRedisCluster(rediscluster, oldRedisCluster)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type RedisCluster.
+func (v *RedisClusterCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// minClusterMasters Redis Cluster 进行故障转移投票所需的最少 master 数量
+const minClusterMasters = 3
+
+// validateRedisCluster old 为 nil 表示创建
+func validateRedisCluster(rediscluster, old *redisv1.RedisCluster) error {
+	spec := field.NewPath("spec")
+	var errs field.ErrorList
+	if rediscluster.Spec.Masters < minClusterMasters {
+		errs = append(errs, field.Invalid(spec.Child("masters"), rediscluster.Spec.Masters,
+			fmt.Sprintf("must be at least %d", minClusterMasters)))
+	}
+
+	var oldStorage *redisv1.StorageSpec
+	if old != nil {
+		oldStorage = &old.Spec.Storage
+	}
+	errs = append(errs, validateStorage(spec.Child("storage"), rediscluster.Spec.Storage, oldStorage)...)
+	errs = append(errs, validateRedisConfig(utils.RedisMajorVersion(rediscluster.Spec.Image),
+		spec.Child("config", "additionalConfig"), rediscluster.Spec.Config.AdditionalConfig)...)
+	return invalidError("RedisCluster", rediscluster.Name, errs)
+}
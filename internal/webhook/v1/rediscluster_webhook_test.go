@@ -0,0 +1,68 @@
+/*
+Copyright 2025 James.Liu.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	redisv1 "github.com/ybooks240/redis-operator/api/v1"
+)
+
+var _ = Describe("RedisCluster Webhook", func() {
+	var (
+		obj       *redisv1.RedisCluster
+		validator RedisClusterCustomValidator
+		defaulter RedisClusterCustomDefaulter
+	)
+
+	BeforeEach(func() {
+		obj = &redisv1.RedisCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"},
+			Spec: redisv1.RedisClusterSpec{
+				Image:   "redis:7.0",
+				Masters: 3,
+			},
+		}
+		validator = RedisClusterCustomValidator{}
+		defaulter = RedisClusterCustomDefaulter{}
+	})
+
+	Context("When creating RedisCluster under Defaulting Webhook", func() {
+		It("Should default to three masters", func() {
+			obj.Spec.Masters = 0
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.Masters).To(Equal(int32(3)))
+		})
+	})
+
+	Context("When creating RedisCluster under Validating Webhook", func() {
+		It("Should deny fewer than three masters", func() {
+			obj.Spec.Masters = 2
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.masters: Invalid value: 2: must be at least 3")))
+		})
+
+		It("Should deny cluster topology directives", func() {
+			obj.Spec.Config.AdditionalConfig = map[string]string{"cluster-enabled": "no", "cluster-allow-reads-when-down": "yes"}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.config.additionalConfig[cluster-enabled]: Forbidden")))
+			Expect(err).NotTo(MatchError(ContainSubstring("cluster-allow-reads-when-down")))
+		})
+	})
+})
@@ -0,0 +1,122 @@
+/*
+Copyright 2025 James.Liu.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	redisv1 "github.com/ybooks240/redis-operator/api/v1"
+	"github.com/ybooks240/redis-operator/internal/utils"
+)
+
+// nolint:unused
+// log is for logging in this package.
+var redisinstancelog = logf.Log.WithName("redisinstance-resource")
+
+// SetupRedisInstanceWebhookWithManager registers the webhook for RedisInstance in the manager.
+func SetupRedisInstanceWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&redisv1.RedisInstance{}).
+		WithValidator(&RedisInstanceCustomValidator{}).
+		WithDefaulter(&RedisInstanceCustomDefaulter{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-redis-github-com-v1-redisinstance,mutating=true,failurePolicy=fail,sideEffects=None,groups=redis.github.com,resources=redisinstances,verbs=create;update,versions=v1,name=mredisinstance-v1.kb.io,admissionReviewVersions=v1
+
+// RedisInstanceCustomDefaulter 为 RedisInstance 填充默认值
+type RedisInstanceCustomDefaulter struct{}
+
+var _ webhook.CustomDefaulter = &RedisInstanceCustomDefaulter{}
+
+// Default 未指定存储大小时使用默认大小，RedisInstance 总是为数据创建 PVC
+func (d *RedisInstanceCustomDefaulter) Default(_ context.Context, obj runtime.Object) error {
+	redisinstance, ok := obj.(*redisv1.RedisInstance)
+	if !ok {
+		return fmt.Errorf("expected a RedisInstance object but got %T", obj)
+	}
+	redisinstancelog.Info("Defaulting for RedisInstance", "name", redisinstance.GetName())
+
+	if redisinstance.Spec.Storage.Size == "" {
+		redisinstance.Spec.Storage.Size = defaultStorageSize
+	}
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate-redis-github-com-v1-redisinstance,mutating=false,failurePolicy=fail,sideEffects=None,groups=redis.github.com,resources=redisinstances,verbs=create;update,versions=v1,name=vredisinstance-v1.kb.io,admissionReviewVersions=v1
+
+// RedisInstanceCustomValidator 校验 RedisInstance 的创建和更新
+type RedisInstanceCustomValidator struct{}
+
+var _ webhook.CustomValidator = &RedisInstanceCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type RedisInstance.
+func (v *RedisInstanceCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	redisinstance, ok := obj.(*redisv1.RedisInstance)
+	if !ok {
+		return nil, fmt.Errorf("expected a RedisInstance object but got %T", obj)
+	}
+	redisinstancelog.Info("Validation for RedisInstance upon creation", "name", redisinstance.GetName())
+
+	return nil, validateRedisInstance(redisinstance, nil)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type RedisInstance.
+func (v *RedisInstanceCustomValidator) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	redisinstance, ok := newObj.(*redisv1.RedisInstance)
+	if !ok {
+		return nil, fmt.Errorf("expected a RedisInstance object for the newObj but got %T", newObj)
+	}
+	oldRedisInstance, ok := oldObj.(*redisv1.RedisInstance)
+	if !ok {
+		return nil, fmt.Errorf("expected a RedisInstance object for the oldObj but got %T", oldObj)
+	}
+	redisinstancelog.Info("Validation for RedisInstance upon update", "name", redisinstance.GetName())
+
+	// 删除中的对象只会移除 finalizer，跳过校验避免已不满足新规则的对象无法完成删除
+	if redisinstance.GetDeletionTimestamp() != nil {
+		return nil, nil
+	}
+
+	return nil, validateRedisInstance(redisinstance, oldRedisInstance)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type RedisInstance.
+func (v *RedisInstanceCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateRedisInstance old 为 nil 表示创建
+func validateRedisInstance(redisinstance, old *redisv1.RedisInstance) error {
+	spec := field.NewPath("spec")
+	var oldStorage *redisv1.StorageSpec
+	if old != nil {
+		oldStorage = &old.Spec.Storage
+	}
+
+	errs := validateStorage(spec.Child("storage"), redisinstance.Spec.Storage, oldStorage)
+	errs = append(errs, validateRedisConfig(utils.RedisMajorVersion(redisinstance.Spec.Image),
+		spec.Child("config"), redisinstance.Spec.Config)...)
+	return invalidError("RedisInstance", redisinstance.Name, errs)
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2025 James.Liu.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	redisv1 "github.com/ybooks240/redis-operator/api/v1"
+)
+
+var _ = Describe("RedisInstance Webhook", func() {
+	var (
+		obj       *redisv1.RedisInstance
+		oldObj    *redisv1.RedisInstance
+		validator RedisInstanceCustomValidator
+		defaulter RedisInstanceCustomDefaulter
+	)
+
+	BeforeEach(func() {
+		obj = &redisv1.RedisInstance{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-instance", Namespace: "default"},
+			Spec: redisv1.RedisInstanceSpec{
+				Image:   "redis:7.0",
+				Storage: redisv1.StorageSpec{Size: "1Gi", StorageClassName: "standard"},
+				Config:  map[string]string{"maxmemory": "100mb"},
+			},
+		}
+		oldObj = obj.DeepCopy()
+		validator = RedisInstanceCustomValidator{}
+		defaulter = RedisInstanceCustomDefaulter{}
+	})
+
+	Context("When creating RedisInstance under Defaulting Webhook", func() {
+		It("Should apply the default storage size", func() {
+			obj.Spec.Storage.Size = ""
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.Storage.Size).To(Equal("1Gi"))
+		})
+	})
+
+	Context("When creating or updating RedisInstance under Validating Webhook", func() {
+		It("Should admit a valid instance", func() {
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should deny an unparsable storage size", func() {
+			obj.Spec.Storage.Size = "10 gigs"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.storage.size")))
+		})
+
+		It("Should deny operator-owned and unknown directives", func() {
+			obj.Spec.Config = map[string]string{"port": "6380", "maxmemroy": "100mb"}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.config[port]: Forbidden: directive is managed by the operator")))
+			Expect(err).To(MatchError(ContainSubstring(`did you mean "maxmemory"?`)))
+		})
+
+		It("Should deny shrinking storage and changing the storage class", func() {
+			obj.Spec.Storage = redisv1.StorageSpec{Size: "512Mi", StorageClassName: "fast"}
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).To(MatchError(ContainSubstring("storage cannot shrink from 1Gi to 512Mi")))
+			Expect(err).To(MatchError(ContainSubstring("storageClassName is immutable")))
+		})
+
+		It("Should admit expanding storage", func() {
+			obj.Spec.Storage.Size = "2Gi"
+			Expect(validator.ValidateUpdate(ctx, oldObj, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should not block finalizer removal of an object being deleted", func() {
+			oldObj.Spec.Storage.Size = "10 gigs"
+			obj.Spec.Storage.Size = "10 gigs"
+			now := metav1.Now()
+			obj.DeletionTimestamp = &now
+			Expect(validator.ValidateUpdate(ctx, oldObj, obj)).Error().NotTo(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,105 @@
+/*
+Copyright 2025 James.Liu.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	redisv1 "github.com/ybooks240/redis-operator/api/v1"
+	"github.com/ybooks240/redis-operator/internal/utils"
+)
+
+// nolint:unused
+// log is for logging in this package.
+var redismasterreplicalog = logf.Log.WithName("redismasterreplica-resource")
+
+// SetupRedisMasterReplicaWebhookWithManager registers the webhook for RedisMasterReplica in the manager.
+func SetupRedisMasterReplicaWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&redisv1.RedisMasterReplica{}).
+		WithValidator(&RedisMasterReplicaCustomValidator{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-redis-github-com-v1-redismasterreplica,mutating=false,failurePolicy=fail,sideEffects=None,groups=redis.github.com,resources=redismasterreplicas,verbs=create;update,versions=v1,name=vredismasterreplica-v1.kb.io,admissionReviewVersions=v1
+
+// RedisMasterReplicaCustomValidator 校验 RedisMasterReplica 的创建和更新
+type RedisMasterReplicaCustomValidator struct{}
+
+var _ webhook.CustomValidator = &RedisMasterReplicaCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type RedisMasterReplica.
+func (v *RedisMasterReplicaCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	redismasterreplica, ok := obj.(*redisv1.RedisMasterReplica)
+	if !ok {
+		return nil, fmt.Errorf("expected a RedisMasterReplica object but got %T", obj)
+	}
+	redismasterreplicalog.Info("Validation for RedisMasterReplica upon creation", "name", redismasterreplica.GetName())
+
+	return nil, validateRedisMasterReplica(redismasterreplica, nil)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type RedisMasterReplica.
+func (v *RedisMasterReplicaCustomValidator) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	redismasterreplica, ok := newObj.(*redisv1.RedisMasterReplica)
+	if !ok {
+		return nil, fmt.Errorf("expected a RedisMasterReplica object for the newObj but got %T", newObj)
+	}
+	oldRedisMasterReplica, ok := oldObj.(*redisv1.RedisMasterReplica)
+	if !ok {
+		return nil, fmt.Errorf("expected a RedisMasterReplica object for the oldObj but got %T", oldObj)
+	}
+	redismasterreplicalog.Info("Validation for RedisMasterReplica upon update", "name", redismasterreplica.GetName())
+
+	// 删除中的对象只会移除 finalizer，跳过校验避免已不满足新规则的对象无法完成删除
+	if redismasterreplica.GetDeletionTimestamp() != nil {
+		return nil, nil
+	}
+
+	return nil, validateRedisMasterReplica(redismasterreplica, oldRedisMasterReplica)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type RedisMasterReplica.
+func (v *RedisMasterReplicaCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateRedisMasterReplica old 为 nil 表示创建
+func validateRedisMasterReplica(redismasterreplica, old *redisv1.RedisMasterReplica) error {
+	spec := field.NewPath("spec")
+	var oldStorage, oldMasterStorage, oldReplicaStorage *redisv1.StorageSpec
+	if old != nil {
+		oldStorage, oldMasterStorage, oldReplicaStorage = &old.Spec.Storage, &old.Spec.Master.Storage, &old.Spec.Replica.Storage
+	}
+
+	errs := validateStorage(spec.Child("storage"), redismasterreplica.Spec.Storage, oldStorage)
+	errs = append(errs, validateStorage(spec.Child("master", "storage"), redismasterreplica.Spec.Master.Storage, oldMasterStorage)...)
+	errs = append(errs, validateStorage(spec.Child("replica", "storage"), redismasterreplica.Spec.Replica.Storage, oldReplicaStorage)...)
+
+	major := utils.RedisMajorVersion(redismasterreplica.Spec.Image)
+	errs = append(errs, validateRedisConfig(major, spec.Child("config"), redismasterreplica.Spec.Config)...)
+	errs = append(errs, validateRedisConfig(major, spec.Child("master", "config"), redismasterreplica.Spec.Master.Config)...)
+	errs = append(errs, validateRedisConfig(major, spec.Child("replica", "config"), redismasterreplica.Spec.Replica.Config)...)
+	return invalidError("RedisMasterReplica", redismasterreplica.Name, errs)
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2025 James.Liu.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	redisv1 "github.com/ybooks240/redis-operator/api/v1"
+)
+
+var _ = Describe("RedisMasterReplica Webhook", func() {
+	var (
+		obj       *redisv1.RedisMasterReplica
+		oldObj    *redisv1.RedisMasterReplica
+		validator RedisMasterReplicaCustomValidator
+	)
+
+	BeforeEach(func() {
+		obj = &redisv1.RedisMasterReplica{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-masterreplica", Namespace: "default"},
+			Spec: redisv1.RedisMasterReplicaSpec{
+				Image:   "redis:6.2",
+				Storage: redisv1.StorageSpec{Size: "1Gi", StorageClassName: "standard"},
+				Replica: redisv1.ReplicaSpec{Replicas: 2},
+			},
+		}
+		oldObj = obj.DeepCopy()
+		validator = RedisMasterReplicaCustomValidator{}
+	})
+
+	Context("When creating or updating RedisMasterReplica under Validating Webhook", func() {
+		It("Should validate role configuration against the image's Redis version", func() {
+			obj.Spec.Replica.Config = map[string]string{"replicaof": "other 6379", "hash-max-listpack-entries": "128"}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.replica.config[replicaof]: Forbidden")))
+			Expect(err).To(MatchError(ContainSubstring("directive requires Redis 7 or later")))
+		})
+
+		It("Should deny removing provisioned storage", func() {
+			obj.Spec.Storage.Size = ""
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).To(MatchError(ContainSubstring("storage cannot be removed once provisioned")))
+		})
+	})
+})
@@ -0,0 +1,146 @@
+/*
+Copyright 2025 James.Liu.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	redisv1 "github.com/ybooks240/redis-operator/api/v1"
+	"github.com/ybooks240/redis-operator/internal/utils"
+)
+
+// nolint:unused
+// log is for logging in this package.
+var redissentinellog = logf.Log.WithName("redissentinel-resource")
+
+// SetupRedisSentinelWebhookWithManager registers the webhook for RedisSentinel in the manager.
+func SetupRedisSentinelWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&redisv1.RedisSentinel{}).
+		WithValidator(&RedisSentinelCustomValidator{}).
+		WithDefaulter(&RedisSentinelCustomDefaulter{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-redis-github-com-v1-redissentinel,mutating=true,failurePolicy=fail,sideEffects=None,groups=redis.github.com,resources=redissentinels,verbs=create;update,versions=v1,name=mredissentinel-v1.kb.io,admissionReviewVersions=v1
+
+// RedisSentinelCustomDefaulter 为 RedisSentinel 填充默认值
+type RedisSentinelCustomDefaulter struct{}
+
+var _ webhook.CustomDefaulter = &RedisSentinelCustomDefaulter{}
+
+// Default 未指定 quorum 时使用 sentinel 的多数派，使用嵌入式 Redis 时填充默认存储大小
+func (d *RedisSentinelCustomDefaulter) Default(_ context.Context, obj runtime.Object) error {
+	redissentinel, ok := obj.(*redisv1.RedisSentinel)
+	if !ok {
+		return fmt.Errorf("expected a RedisSentinel object but got %T", obj)
+	}
+	redissentinellog.Info("Defaulting for RedisSentinel", "name", redissentinel.GetName())
+
+	if redissentinel.Spec.Config.Quorum == 0 {
+		redissentinel.Spec.Config.Quorum = redissentinel.Spec.Replicas/2 + 1
+	}
+	embedded := len(redissentinel.Spec.Masters) == 0 && redissentinel.Spec.MasterReplicaRef == nil
+	if embedded && redissentinel.Spec.Redis.Master.Storage.Size == "" {
+		redissentinel.Spec.Redis.Master.Storage.Size = defaultStorageSize
+	}
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate-redis-github-com-v1-redissentinel,mutating=false,failurePolicy=fail,sideEffects=None,groups=redis.github.com,resources=redissentinels,verbs=create;update,versions=v1,name=vredissentinel-v1.kb.io,admissionReviewVersions=v1
+
+// RedisSentinelCustomValidator 校验 RedisSentinel 的创建和更新
+type RedisSentinelCustomValidator struct{}
+
+var _ webhook.CustomValidator = &RedisSentinelCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type RedisSentinel.
+func (v *RedisSentinelCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	redissentinel, ok := obj.(*redisv1.RedisSentinel)
+	if !ok {
+		return nil, fmt.Errorf("expected a RedisSentinel object but got %T", obj)
+	}
+	redissentinellog.Info("Validation for RedisSentinel upon creation", "name", redissentinel.GetName())
+
+	return nil, validateRedisSentinel(redissentinel, nil)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type RedisSentinel.
+func (v *RedisSentinelCustomValidator) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	redissentinel, ok := newObj.(*redisv1.RedisSentinel)
+	if !ok {
+		return nil, fmt.Errorf("expected a RedisSentinel object for the newObj but got %T", newObj)
+	}
+	oldRedisSentinel, ok := oldObj.(*redisv1.RedisSentinel)
+	if !ok {
+		return nil, fmt.Errorf("expected a RedisSentinel object for the oldObj but got %T", oldObj)
+	}
+	redissentinellog.Info("Validation for RedisSentinel upon update", "name", redissentinel.GetName())
+
+	// 删除中的对象只会移除 finalizer，跳过校验避免已不满足新规则的对象无法完成删除
+	if redissentinel.GetDeletionTimestamp() != nil {
+		return nil, nil
+	}
+
+	return nil, validateRedisSentinel(redissentinel, oldRedisSentinel)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type RedisSentinel.
+func (v *RedisSentinelCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateRedisSentinel old 为 nil 表示创建
+func validateRedisSentinel(redissentinel, old *redisv1.RedisSentinel) error {
+	spec := field.NewPath("spec")
+	var errs field.ErrorList
+
+	// quorum 超过 sentinel 数量时永远无法达成一致，故障转移不会发生
+	replicas := redissentinel.Spec.Replicas
+	if quorum := redissentinel.Spec.Config.Quorum; quorum > replicas {
+		errs = append(errs, field.Invalid(spec.Child("config", "quorum"), quorum,
+			fmt.Sprintf("must not exceed the number of sentinels (%d)", replicas)))
+	}
+	for i, master := range redissentinel.Spec.Masters {
+		if master.Quorum > replicas {
+			errs = append(errs, field.Invalid(spec.Child("masters").Index(i).Child("quorum"), master.Quorum,
+				fmt.Sprintf("must not exceed the number of sentinels (%d)", replicas)))
+		}
+	}
+
+	var oldStorage, oldMasterStorage, oldReplicaStorage *redisv1.StorageSpec
+	if old != nil {
+		oldStorage, oldMasterStorage, oldReplicaStorage = &old.Spec.Storage, &old.Spec.Redis.Master.Storage, &old.Spec.Redis.Replica.Storage
+	}
+	errs = append(errs, validateStorage(spec.Child("storage"), redissentinel.Spec.Storage, oldStorage)...)
+	errs = append(errs, validateStorage(spec.Child("redis", "master", "storage"), redissentinel.Spec.Redis.Master.Storage, oldMasterStorage)...)
+	errs = append(errs, validateStorage(spec.Child("redis", "replica", "storage"), redissentinel.Spec.Redis.Replica.Storage, oldReplicaStorage)...)
+
+	major := utils.RedisMajorVersion(redissentinel.Spec.Image)
+	errs = append(errs, validateSentinelConfig(major, spec.Child("config", "additionalConfig"), redissentinel.Spec.Config.AdditionalConfig)...)
+	errs = append(errs, validateRedisConfig(major, spec.Child("redis", "config"), redissentinel.Spec.Redis.Config)...)
+	errs = append(errs, validateRedisConfig(major, spec.Child("redis", "master", "config"), redissentinel.Spec.Redis.Master.Config)...)
+	errs = append(errs, validateRedisConfig(major, spec.Child("redis", "replica", "config"), redissentinel.Spec.Redis.Replica.Config)...)
+	return invalidError("RedisSentinel", redissentinel.Name, errs)
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2025 James.Liu.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	redisv1 "github.com/ybooks240/redis-operator/api/v1"
+)
+
+var _ = Describe("RedisSentinel Webhook", func() {
+	var (
+		obj       *redisv1.RedisSentinel
+		validator RedisSentinelCustomValidator
+		defaulter RedisSentinelCustomDefaulter
+	)
+
+	BeforeEach(func() {
+		obj = &redisv1.RedisSentinel{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-sentinel", Namespace: "default"},
+			Spec: redisv1.RedisSentinelSpec{
+				Image:    "redis:7.0",
+				Replicas: 5,
+			},
+		}
+		validator = RedisSentinelCustomValidator{}
+		defaulter = RedisSentinelCustomDefaulter{}
+	})
+
+	Context("When creating RedisSentinel under Defaulting Webhook", func() {
+		It("Should default the quorum to a majority and the embedded Redis storage", func() {
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.Config.Quorum).To(Equal(int32(3)))
+			Expect(obj.Spec.Redis.Master.Storage.Size).To(Equal("1Gi"))
+		})
+
+		It("Should not default storage when monitoring external masters", func() {
+			obj.Spec.Masters = []redisv1.MonitoredMasterSpec{{
+				Name:     "external",
+				External: &redisv1.ExternalMasterSpec{Host: "redis.example.com", Port: 6379},
+			}}
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.Redis.Master.Storage.Size).To(BeEmpty())
+		})
+	})
+
+	Context("When creating RedisSentinel under Validating Webhook", func() {
+		It("Should deny a quorum greater than the number of sentinels", func() {
+			obj.Spec.Config.Quorum = 6
+			obj.Spec.Masters = []redisv1.MonitoredMasterSpec{{
+				Name:     "external",
+				External: &redisv1.ExternalMasterSpec{Host: "redis.example.com", Port: 6379},
+				Quorum:   7,
+			}}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.config.quorum")))
+			Expect(err).To(MatchError(ContainSubstring("spec.masters[0].quorum")))
+		})
+
+		It("Should deny sentinel directives managed by the operator", func() {
+			obj.Spec.Config.Quorum = 3
+			obj.Spec.Config.AdditionalConfig = map[string]string{
+				"sentinel monitor":           "other 10.0.0.1 6379 2",
+				"sentinel resolve-hostnames": "yes",
+			}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.config.additionalConfig[sentinel monitor]: Forbidden")))
+			Expect(err).NotTo(MatchError(ContainSubstring("resolve-hostnames")))
+		})
+	})
+})
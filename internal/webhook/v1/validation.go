@@ -0,0 +1,101 @@
+package v1
+
+import (
+	"fmt"
+	"sort"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	redisv1 "github.com/ybooks240/redis-operator/api/v1"
+	"github.com/ybooks240/redis-operator/internal/utils"
+)
+
+// defaultStorageSize 未指定存储大小时使用的默认值，与控制器中的默认值保持一致
+const defaultStorageSize = "1Gi"
+
+// invalidError 将校验错误转换为准入响应，没有错误时返回 nil
+func invalidError(kind, name string, errs field.ErrorList) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(redisv1.GroupVersion.WithKind(kind).GroupKind(), name, errs)
+}
+
+// validateStorage 校验存储大小可以解析，更新时不允许缩容、移除存储或修改存储类
+// old 为 nil 表示创建
+func validateStorage(path *field.Path, storage redisv1.StorageSpec, old *redisv1.StorageSpec) field.ErrorList {
+	var errs field.ErrorList
+	var size resource.Quantity
+	if storage.Size != "" {
+		parsed, err := resource.ParseQuantity(storage.Size)
+		switch {
+		case err != nil:
+			errs = append(errs, field.Invalid(path.Child("size"), storage.Size, "must be a quantity such as 1Gi"))
+		case parsed.Sign() <= 0:
+			errs = append(errs, field.Invalid(path.Child("size"), storage.Size, "must be greater than zero"))
+		default:
+			size = parsed
+		}
+	}
+	if old == nil {
+		return errs
+	}
+
+	if storage.StorageClassName != old.StorageClassName {
+		errs = append(errs, field.Forbidden(path.Child("storageClassName"),
+			fmt.Sprintf("storageClassName is immutable, was %q", old.StorageClassName)))
+	}
+	if old.Size == "" {
+		return errs
+	}
+	if storage.Size == "" {
+		return append(errs, field.Forbidden(path.Child("size"), "storage cannot be removed once provisioned"))
+	}
+	oldSize, err := resource.ParseQuantity(old.Size)
+	if err == nil && !size.IsZero() && size.Cmp(oldSize) < 0 {
+		errs = append(errs, field.Forbidden(path.Child("size"),
+			fmt.Sprintf("storage cannot shrink from %s to %s", old.Size, storage.Size)))
+	}
+	return errs
+}
+
+// validateRedisConfig 拒绝由 operator 管理的指令，并按 Redis 主版本的指令目录校验其余指令
+func validateRedisConfig(major int, path *field.Path, config map[string]string) field.ErrorList {
+	var errs field.ErrorList
+	for _, key := range sortedKeys(config) {
+		if utils.OperatorOwnedRedisDirective(key) {
+			errs = append(errs, field.Forbidden(path.Key(key), "directive is managed by the operator"))
+			continue
+		}
+		if message := utils.ValidateRedisDirective(major, key, config[key]); message != "" {
+			errs = append(errs, field.Invalid(path.Key(key), config[key], message))
+		}
+	}
+	return errs
+}
+
+// validateSentinelConfig 拒绝由 operator 管理的 sentinel 指令，并校验其余指令
+func validateSentinelConfig(major int, path *field.Path, config map[string]string) field.ErrorList {
+	var errs field.ErrorList
+	for _, key := range sortedKeys(config) {
+		if utils.OperatorOwnedSentinelDirective(key) {
+			errs = append(errs, field.Forbidden(path.Key(key), "directive is managed by the operator"))
+			continue
+		}
+		if message := utils.ValidateSentinelDirective(major, key, config[key]); message != "" {
+			errs = append(errs, field.Invalid(path.Key(key), config[key], message))
+		}
+	}
+	return errs
+}
+
+func sortedKeys(config map[string]string) []string {
+	keys := make([]string, 0, len(config))
+	for key := range config {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}